@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/de-tools/data-atlas/pkg/services/render"
+	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	duckdbaudit "github.com/de-tools/data-atlas/pkg/store/duckdb/audit"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+)
+
+const (
+	cliDateLayout      = "02-01-2006"
+	defaultAuditDays   = 7
+	defaultAuditFormat = string(render.FormatMarkdown)
+)
+
+type auditOptions struct {
+	workspace string
+	resources []string
+	rules     []string
+	from      string
+	to        string
+	format    string
+	output    string
+}
+
+func newAuditCmd() *cobra.Command {
+	opts := auditOptions{}
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit a workspace and render the report",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runAudit(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", "Name of the workspace profile to audit")
+	cmd.Flags().StringSliceVar(&opts.resources, "resource", nil,
+		"Resource types to audit (warehouse, cluster, endpoint, job, dlt_pipeline), all when not given")
+	cmd.Flags().StringSliceVar(&opts.rules, "rule", nil,
+		"Audit rules to run, all rules of the resource types when not given")
+	cmd.Flags().StringVar(&opts.from, "from", "", "Start of the audited period, DD-MM-YYYY (default is a week ago)")
+	cmd.Flags().StringVar(&opts.to, "to", "", "End of the audited period, DD-MM-YYYY (default is now)")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", defaultAuditFormat, "Report format: json, html, markdown or csv")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "File to write the report to (default is stdout)")
+	_ = cmd.MarkFlagRequired("workspace")
+
+	return cmd
+}
+
+func runAudit(cmd *cobra.Command, opts auditOptions) error {
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger().Level(zerolog.WarnLevel)
+	ctx := logger.WithContext(cmd.Context())
+
+	format, err := render.ParseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+	endTime, err := parseDateFlag("to", opts.to, time.Now())
+	if err != nil {
+		return err
+	}
+	startTime, err := parseDateFlag("from", opts.from, time.Now().AddDate(0, 0, -defaultAuditDays))
+	if err != nil {
+		return err
+	}
+	rules, err := workspace.DefaultAuditRegistry().Rules(opts.resources, opts.rules)
+	if err != nil {
+		return err
+	}
+	appConfig, err := loadAppConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	registry, err := newRegistry(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := duckdb.NewDB(duckdb.Settings{DbPath: appConfig.Storage.DuckDBPath})
+	if err != nil {
+		return fmt.Errorf("failed to create DuckDB instance: %w", err)
+	}
+	accountExplorer := newAccountExplorer(registry, appConfig, db)
+	defer accountExplorer.Close()
+
+	ws := domain.Workspace{Name: opts.workspace}
+	costManager, err := accountExplorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		return fmt.Errorf("failed to get cost manager: %w", err)
+	}
+	explorer, err := accountExplorer.GetWorkspaceExplorer(ctx, ws)
+	if err != nil {
+		return fmt.Errorf("failed to get workspace explorer: %w", err)
+	}
+
+	report := workspace.RunAudit(ctx, &workspace.AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Explorer:    explorer,
+		Settings:    appConfig.AuditSettings().ForWorkspace(ws.Name),
+	}, rules)
+
+	if err := applySuppressions(ctx, db, &report); err != nil {
+		return err
+	}
+
+	return writeOutput(cmd, opts.output, func(out io.Writer) error {
+		if err := render.AuditReport(out, report, format); err != nil {
+			return fmt.Errorf("failed to render audit report: %w", err)
+		}
+		return nil
+	})
+}
+
+// writeOutput writes to the file at path, or to stdout when path is empty
+func writeOutput(cmd *cobra.Command, path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(cmd.OutOrStdout())
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// applySuppressions moves the suppressed findings out of the report like the audit endpoints do
+func applySuppressions(ctx context.Context, db *sql.DB, report *domain.AuditReport) error {
+	suppressionStore, err := duckdbaudit.NewSuppressionStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create audit suppression store: %w", err)
+	}
+	return workspace.NewAuditSuppressions(suppressionStore).Apply(ctx, report)
+}
+
+func parseDateFlag(name, value string, defaultDate time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultDate, nil
+	}
+	parsed, err := time.Parse(cliDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s date format. Expected format: DD-MM-YYYY", name)
+	}
+	return parsed, nil
+}
@@ -1,27 +1,43 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"net"
-	"net/http"
 	"os"
+	"os/signal"
 	"os/user"
+	"syscall"
+	"time"
 
+	"github.com/de-tools/data-atlas/pkg/services/appconfig"
+	"github.com/de-tools/data-atlas/pkg/services/delivery"
+	"github.com/de-tools/data-atlas/pkg/services/reporting"
 	"github.com/de-tools/data-atlas/pkg/services/workflow"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/pricing"
 	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	duckdbaudit "github.com/de-tools/data-atlas/pkg/store/duckdb/audit"
+	duckdbdelivery "github.com/de-tools/data-atlas/pkg/store/duckdb/delivery"
 	duckdbusage "github.com/de-tools/data-atlas/pkg/store/duckdb/usage"
 	duckdbworkflow "github.com/de-tools/data-atlas/pkg/store/duckdb/workflow"
 
 	"github.com/de-tools/data-atlas/pkg/server"
 	"github.com/de-tools/data-atlas/pkg/services/account"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
 	"github.com/de-tools/data-atlas/pkg/services/config"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 )
 
+// deliveryInterval is how often the scheduler checks for reports due to be sent
+const deliveryInterval = 15 * time.Minute
+
 var cfgPath string
+var appConfigPath string
 var syncEnabled bool
+var syncMode string
+var auditSettingsPath string
 
 func main() {
 	var rootCmd = &cobra.Command{
@@ -33,9 +49,18 @@ func main() {
 	usr, _ := user.Current()
 	defaultPath := fmt.Sprintf("%s/.databrickscfg", usr.HomeDir)
 
-	rootCmd.Flags().StringVarP(&cfgPath, "config", "c", defaultPath,
+	rootCmd.PersistentFlags().StringVarP(&cfgPath, "config", "c", defaultPath,
 		"Path to the .databrickscfg file (default is $HOME/.databrickscfg)")
+	rootCmd.PersistentFlags().StringVar(&appConfigPath, "app-config", os.Getenv("DATA_ATLAS_CONFIG"),
+		"Path to the YAML application config file (default is $DATA_ATLAS_CONFIG)")
 	rootCmd.Flags().BoolVar(&syncEnabled, "sync", false, "Start the syncing flow for workflows")
+	rootCmd.Flags().StringVar(&syncMode, "sync-mode", "",
+		"Sync usage per workspace (workspace) or read the account billing once for every workspace (account). "+
+			"In account mode a workspace added after the sync started is only synced from then on, "+
+			"its earlier usage is not backfilled")
+	rootCmd.PersistentFlags().StringVar(&auditSettingsPath, "audit-config", "",
+		"Path to a YAML file with audit settings defaults and per-workspace overrides")
+	rootCmd.AddCommand(newAuditCmd(), newReportCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -49,28 +74,33 @@ func runServer(cmd *cobra.Command, _ []string) error {
 	}
 
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
-	ctx := logger.WithContext(cmd.Context())
-
-	registry, err := config.NewRegistry(cfgPath)
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx = logger.WithContext(ctx)
 
+	appConfig, err := loadAppConfig(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to create config registry: %w", err)
+		return err
+	}
+	if appConfigPath != "" {
+		logger.Info().Msgf("Application config found at `%s` successfully loaded.", appConfigPath)
 	}
 
-	err = registry.Init(ctx)
+	registry, err := newRegistry(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to initialize config registry: %w", err)
+		return err
 	}
 
-	accountExplorer := account.NewExplorer(registry)
-
 	db, err := duckdb.NewDB(duckdb.Settings{
-		DbPath: "data-atlas.db",
+		DbPath: appConfig.Storage.DuckDBPath,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create DuckDB instance: %w", err)
 	}
 
+	accountExplorer := newAccountExplorer(registry, appConfig, db)
+	defer accountExplorer.Close()
+
 	workflowStore, err := duckdbworkflow.NewStore(db)
 	if err != nil {
 		return fmt.Errorf("failed to create workflow store: %w", err)
@@ -79,39 +109,146 @@ func runServer(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create usage store: %w", err)
 	}
-	workflowCtrl := workflow.NewController(db, accountExplorer, workflowStore, usageStore)
-	err = workflowCtrl.Init(ctx, syncEnabled)
+	auditStore, err := duckdbaudit.NewStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create audit store: %w", err)
+	}
+	suppressionStore, err := duckdbaudit.NewSuppressionStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create audit suppression store: %w", err)
+	}
+	subscriptionStore, err := duckdbdelivery.NewStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create report subscription store: %w", err)
+	}
+	workflowCtrl := workflow.NewController(db, accountExplorer, workflowStore, usageStore, appConfig.SyncSettings())
+	err = workflowCtrl.Init(ctx, appConfig.Sync.Enabled)
 	if err != nil {
 		return fmt.Errorf("failed to initialize workflow controller: %w", err)
 	}
 
+	registry.OnChange(workflowCtrl.ProfilesChanged)
+	if err := registry.Watch(ctx); err != nil {
+		logger.Warn().Err(err).Msgf("Configuration at `%s` is not reloaded on change.", cfgPath)
+	}
+
 	logger.Info().Msgf("Configuration found at `%s` successfully loaded.", cfgPath)
 	logger.Info().Msgf("Found the following profiles:")
 	profiles, _ := registry.GetProfiles(ctx)
 	for _, profile := range profiles {
+		if profile.Account != "" {
+			logger.Info().Msgf("Name: `%s`, Type: `%s`, Account: `%s`", profile.Name, profile.Type, profile.Account)
+			continue
+		}
 		logger.Info().Msgf("Name: `%s`, Type: `%s`", profile.Name, profile.Type)
 	}
 
-	mux := server.ConfigureRouter(server.Config{
+	if auditSettingsPath != "" {
+		logger.Info().Msgf("Audit settings found at `%s` successfully loaded.", auditSettingsPath)
+	}
+
+	auditHistory := workspace.NewAuditHistory(auditStore)
+	reports := reporting.NewBuilder(accountExplorer, auditHistory, reportSettings(appConfig))
+	subscriptions, err := newSubscriptions(ctx, subscriptionStore, reports)
+	if err != nil {
+		return err
+	}
+
+	serverConfig := server.Config{
+		Addr:            appConfig.Addr(),
+		ShutdownTimeout: appConfig.Server.ShutdownTimeout,
 		Dependencies: server.Dependencies{
 			Account:            accountExplorer,
 			WorkflowController: workflowCtrl,
+			AuditSettings:      appConfig.AuditSettings(),
+			AnomalySettings:    &appConfig.Anomalies,
+			ForecastSettings:   &appConfig.Forecast,
+			ExplainSettings:    &appConfig.Explain,
+			AuditHistory:       auditHistory,
+			AuditSuppressions:  workspace.NewAuditSuppressions(suppressionStore),
+			Reports:            reports,
+			Subscriptions:      subscriptions,
+			ConfigStatus:       registry,
 			Logger:             logger,
 		},
-	})
+	}
 
-	host := orDefault(os.Getenv("SERVER_HOST"), "localhost")
-	port := orDefault(os.Getenv("SERVER_PORT"), "8080")
+	logger.Info().Msgf("starting server on %s", serverConfig.Addr)
+	return server.ListenAndServe(ctx, serverConfig)
+}
 
-	addr := net.JoinHostPort(host, port)
-	logger.Info().Msgf("starting server on %s", addr)
+// loadAppConfig loads the application config, the flags set on the command line take precedence over it
+func loadAppConfig(cmd *cobra.Command) (*appconfig.Config, error) {
+	appConfig, err := appconfig.Load(appConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
 
-	return http.ListenAndServe(addr, mux)
+	if flag := cmd.Flags().Lookup("sync"); flag != nil && flag.Changed {
+		appConfig.Sync.Enabled = syncEnabled
+	}
+	if flag := cmd.Flags().Lookup("sync-mode"); flag != nil && flag.Changed {
+		if _, err := workflow.ParseSyncMode(syncMode); err != nil {
+			return nil, err
+		}
+		appConfig.Sync.Mode = syncMode
+	}
+	if auditSettingsPath != "" {
+		auditSettings, err := workspace.LoadAuditSettings(auditSettingsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audit settings: %w", err)
+		}
+		appConfig.SetAuditSettings(auditSettings)
+	}
+	return appConfig, nil
+}
+
+// reportSettings returns the cost report settings, forecasting like the forecast endpoint
+func reportSettings(appConfig *appconfig.Config) reporting.Settings {
+	settings := reporting.DefaultSettings()
+	settings.Forecast = appConfig.Forecast
+	return settings
+}
+
+func newAccountExplorer(registry *config.CfgRegistry, appConfig *appconfig.Config, db *sql.DB) account.Explorer {
+	return account.NewExplorer(registry, account.Settings{
+		CacheDB: db,
+		Prices:  pricing.NewStoreWithSettings(appConfig.PricingSettings()),
+	})
 }
 
-func orDefault(v, def string) string {
-	if v == "" {
-		return def
+func newRegistry(ctx context.Context) (*config.CfgRegistry, error) {
+	registry, err := config.NewRegistry(cfgPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config registry: %w", err)
+	}
+
+	if err := registry.Init(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize config registry: %w", err)
+	}
+	return registry, nil
+}
+
+// newSubscriptions creates the report subscription service and, when SMTP_HOST is set,
+// starts sending the scheduled reports in the background
+func newSubscriptions(
+	ctx context.Context,
+	subscriptionStore duckdbdelivery.Store,
+	reports reporting.Builder,
+) (delivery.Service, error) {
+	smtpSettings, ok, err := delivery.SMTPSettingsFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SMTP settings: %w", err)
 	}
-	return v
+	if !ok {
+		zerolog.Ctx(ctx).Info().Msg("SMTP_HOST is not set, scheduled report delivery is disabled")
+		return delivery.NewService(subscriptionStore, reports, nil, delivery.DefaultSettings()), nil
+	}
+
+	subscriptions := delivery.NewService(subscriptionStore, reports, delivery.NewSMTPMailer(smtpSettings),
+		delivery.DefaultSettings())
+	zerolog.Ctx(ctx).Info().Msgf("Sending scheduled reports through SMTP server `%s:%d`.",
+		smtpSettings.Host, smtpSettings.Port)
+	go delivery.RunScheduler(ctx, subscriptions, deliveryInterval)
+	return subscriptions, nil
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/de-tools/data-atlas/pkg/services/render"
+	"github.com/de-tools/data-atlas/pkg/services/reporting"
+	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	duckdbaudit "github.com/de-tools/data-atlas/pkg/store/duckdb/audit"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+)
+
+type reportOptions struct {
+	workspaces []string
+	format     string
+	output     string
+}
+
+func newReportCmd() *cobra.Command {
+	opts := reportOptions{}
+	cmd := &cobra.Command{
+		Use:   "report [week|month|quarter|YYYY-MM|YYYY-Qn]",
+		Short: "Build the cost report of a period",
+		Long: "Build the cost report of a period with total spend, spend by resource type, top movers, " +
+			"open audit findings, budget status and forecast. Named periods are the last complete ones.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			period := reporting.PeriodMonth
+			if len(args) > 0 {
+				period = args[0]
+			}
+			return runReport(cmd, period, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.workspaces, "workspace", "w", nil,
+		"Workspace profiles to report on, all workspaces when not given")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", string(render.FormatMarkdown),
+		"Report format: json, html or markdown")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "File to write the report to (default is stdout)")
+
+	return cmd
+}
+
+func runReport(cmd *cobra.Command, periodName string, opts reportOptions) error {
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger().Level(zerolog.WarnLevel)
+	ctx := logger.WithContext(cmd.Context())
+
+	format, err := render.ParseFormat(opts.format)
+	if err != nil {
+		return err
+	}
+	if format == render.FormatCSV {
+		return fmt.Errorf("reports are rendered as json, html or markdown")
+	}
+	period, err := reporting.ParsePeriod(periodName, time.Now())
+	if err != nil {
+		return err
+	}
+
+	registry, err := newRegistry(ctx)
+	if err != nil {
+		return err
+	}
+
+	appConfig, err := loadAppConfig(cmd)
+	if err != nil {
+		return err
+	}
+	db, err := duckdb.NewDB(duckdb.Settings{DbPath: appConfig.Storage.DuckDBPath})
+	if err != nil {
+		return fmt.Errorf("failed to create DuckDB instance: %w", err)
+	}
+	auditStore, err := duckdbaudit.NewStore(db)
+	if err != nil {
+		return fmt.Errorf("failed to create audit store: %w", err)
+	}
+
+	accountExplorer := newAccountExplorer(registry, appConfig, db)
+	defer accountExplorer.Close()
+	builder := reporting.NewBuilder(accountExplorer, workspace.NewAuditHistory(auditStore), reportSettings(appConfig))
+
+	workspaces := make([]domain.Workspace, 0, len(opts.workspaces))
+	for _, name := range opts.workspaces {
+		workspaces = append(workspaces, domain.Workspace{Name: name})
+	}
+	report, err := builder.Build(ctx, period, workspaces)
+	if err != nil {
+		return fmt.Errorf("failed to build report: %w", err)
+	}
+
+	return writeOutput(cmd, opts.output, func(out io.Writer) error {
+		if err := render.Report(out, report, format); err != nil {
+			return fmt.Errorf("failed to render report: %w", err)
+		}
+		return nil
+	})
+}
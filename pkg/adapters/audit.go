@@ -1,8 +1,11 @@
 package adapters
 
 import (
+	"time"
+
 	"github.com/de-tools/data-atlas/pkg/models/api"
 	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
 )
 
 func MapSeverityDomainToApi(s domain.Severity) api.Severity {
@@ -34,6 +37,14 @@ func MapAuditFindingDomainToApi(f domain.AuditFinding) api.AuditFinding {
 		Description:    f.Description,
 		Recommendation: f.Recommendation,
 		Severity:       MapSeverityDomainToApi(f.Severity),
+		Status:         string(f.Status),
+		SuppressionID:  f.SuppressionID,
+		Rule:           f.Rule,
+		EstimatedMonthlySavings: api.SavingsEstimate{
+			Amount:     f.EstimatedMonthlySavings.Amount,
+			Currency:   f.EstimatedMonthlySavings.Currency,
+			Confidence: string(f.EstimatedMonthlySavings.Confidence),
+		},
 	}
 }
 
@@ -44,6 +55,7 @@ func MapAuditReportDomainToApi(r domain.AuditReport) api.AuditReport {
 		Period:       MapTimePeriodDomainToApi(r.Period),
 		Summary:      map[string]any{},
 		Findings:     make([]api.AuditFinding, 0, len(r.Findings)),
+		RunID:        r.RunID,
 	}
 	// copy summary as-is
 	for k, v := range r.Summary {
@@ -52,5 +64,241 @@ func MapAuditReportDomainToApi(r domain.AuditReport) api.AuditReport {
 	for _, f := range r.Findings {
 		res.Findings = append(res.Findings, MapAuditFindingDomainToApi(f))
 	}
+	for _, rule := range r.Rules {
+		res.Rules = append(res.Rules, MapAuditRuleResultDomainToApi(rule))
+	}
+	for _, f := range r.Resolved {
+		res.Resolved = append(res.Resolved, MapAuditFindingDomainToApi(f))
+	}
+	for _, f := range r.Suppressed {
+		res.Suppressed = append(res.Suppressed, MapAuditFindingDomainToApi(f))
+	}
 	return res
 }
+
+func MapAuditRuleResultDomainToApi(r domain.AuditRuleResult) api.AuditRuleResult {
+	return api.AuditRuleResult{
+		Rule:         r.Rule,
+		ResourceType: r.ResourceType,
+		Findings:     r.Findings,
+		DurationMs:   float64(r.Duration.Microseconds()) / 1000,
+		Error:        r.Error,
+	}
+}
+
+func MapAuditRunDomainToApi(r domain.AuditRun) api.AuditRun {
+	return api.AuditRun{
+		ID:            r.ID,
+		PreviousRunID: r.PreviousRunID,
+		Scope:         r.Scope,
+		CreatedAt:     r.CreatedAt,
+		Report:        MapAuditReportDomainToApi(r.Report),
+	}
+}
+
+// MapAuditRunDomainToStore stores the settings echoed in the summary separately
+// and the resolved findings along with the reported ones
+func MapAuditRunDomainToStore(r domain.AuditRun) store.AuditRun {
+	res := store.AuditRun{
+		ID:            r.ID,
+		PreviousRunID: r.PreviousRunID,
+		Workspace:     r.Report.Workspace,
+		ResourceType:  r.Report.ResourceType,
+		Scope:         r.Scope,
+		PeriodStart:   r.Report.Period.Start,
+		PeriodEnd:     r.Report.Period.End,
+		CreatedAt:     r.CreatedAt,
+		Summary:       map[string]any{},
+		Settings:      r.Report.Summary["settings"],
+		Findings:      make([]store.AuditFinding, 0, len(r.Report.Findings)+len(r.Report.Resolved)+len(r.Report.Suppressed)),
+	}
+	for k, v := range r.Report.Summary {
+		if k != "settings" {
+			res.Summary[k] = v
+		}
+	}
+	for _, rule := range r.Report.Rules {
+		res.Rules = append(res.Rules, store.AuditRuleResult{
+			Rule:         rule.Rule,
+			ResourceType: rule.ResourceType,
+			Findings:     rule.Findings,
+			DurationMs:   float64(rule.Duration.Microseconds()) / 1000,
+			Error:        rule.Error,
+		})
+	}
+	for _, f := range r.Report.Findings {
+		res.Findings = append(res.Findings, MapAuditFindingDomainToStore(f))
+	}
+	for _, f := range r.Report.Resolved {
+		f.Status = domain.FindingStatusResolved
+		res.Findings = append(res.Findings, MapAuditFindingDomainToStore(f))
+	}
+	for _, f := range r.Report.Suppressed {
+		f.Status = domain.FindingStatusSuppressed
+		res.Findings = append(res.Findings, MapAuditFindingDomainToStore(f))
+	}
+	return res
+}
+
+func MapAuditFindingDomainToStore(f domain.AuditFinding) store.AuditFinding {
+	return store.AuditFinding{
+		ID:                  f.Id,
+		Status:              string(f.Status),
+		Issue:               f.Issue,
+		Severity:            int(f.Severity),
+		Description:         f.Description,
+		Recommendation:      f.Recommendation,
+		Platform:            f.Resource.Platform,
+		Service:             f.Resource.Service,
+		ResourceName:        f.Resource.Name,
+		ResourceDescription: f.Resource.Description,
+		Metadata:            f.Resource.Metadata,
+		SavingsAmount:       f.EstimatedMonthlySavings.Amount,
+		SavingsCurrency:     f.EstimatedMonthlySavings.Currency,
+		SavingsConfidence:   string(f.EstimatedMonthlySavings.Confidence),
+		Rule:                f.Rule,
+	}
+}
+
+func MapAuditRunStoreToDomain(r store.AuditRun) domain.AuditRun {
+	report := domain.AuditReport{
+		Workspace:    r.Workspace,
+		ResourceType: r.ResourceType,
+		Period: domain.TimePeriod{
+			Start:    r.PeriodStart,
+			End:      r.PeriodEnd,
+			Duration: int(r.PeriodEnd.Sub(r.PeriodStart).Hours() / 24),
+		},
+		Summary:  map[string]any{},
+		Findings: []domain.AuditFinding{},
+		RunID:    r.ID,
+	}
+	for k, v := range r.Summary {
+		report.Summary[k] = v
+	}
+	if r.Settings != nil {
+		report.Summary["settings"] = r.Settings
+	}
+	for _, rule := range r.Rules {
+		report.Rules = append(report.Rules, domain.AuditRuleResult{
+			Rule:         rule.Rule,
+			ResourceType: rule.ResourceType,
+			Findings:     rule.Findings,
+			Duration:     time.Duration(rule.DurationMs * float64(time.Millisecond)),
+			Error:        rule.Error,
+		})
+	}
+	for _, f := range r.Findings {
+		finding := MapAuditFindingStoreToDomain(f)
+		switch finding.Status {
+		case domain.FindingStatusResolved:
+			report.Resolved = append(report.Resolved, finding)
+		case domain.FindingStatusSuppressed:
+			report.Suppressed = append(report.Suppressed, finding)
+		default:
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	return domain.AuditRun{
+		ID:            r.ID,
+		PreviousRunID: r.PreviousRunID,
+		Scope:         r.Scope,
+		CreatedAt:     r.CreatedAt,
+		Report:        report,
+	}
+}
+
+func MapAuditFindingStoreToDomain(f store.AuditFinding) domain.AuditFinding {
+	return domain.AuditFinding{
+		Id: f.ID,
+		Resource: domain.ResourceDef{
+			Platform:    f.Platform,
+			Service:     f.Service,
+			Name:        f.ResourceName,
+			Description: f.ResourceDescription,
+			Metadata:    f.Metadata,
+		},
+		Issue:          f.Issue,
+		Description:    f.Description,
+		Recommendation: f.Recommendation,
+		Severity:       domain.Severity(f.Severity),
+		Status:         domain.FindingStatus(f.Status),
+		Rule:           f.Rule,
+		EstimatedMonthlySavings: domain.SavingsEstimate{
+			Amount:     f.SavingsAmount,
+			Currency:   f.SavingsCurrency,
+			Confidence: domain.SavingsConfidence(f.SavingsConfidence),
+		},
+	}
+}
+
+func MapAuditHistoryFilterDomainToStore(f domain.AuditHistoryFilter) store.AuditRunFilter {
+	return store.AuditRunFilter{
+		Workspace:    f.Workspace,
+		ResourceType: f.ResourceType,
+		From:         f.From,
+		To:           f.To,
+		Limit:        f.Limit,
+	}
+}
+
+func MapAuditSuppressionDomainToApi(s domain.AuditSuppression) api.AuditSuppression {
+	return api.AuditSuppression{
+		ID:              s.ID,
+		Workspace:       s.Workspace,
+		FindingID:       s.FindingID,
+		Issue:           s.Issue,
+		ResourcePattern: s.ResourcePattern,
+		Action:          string(s.Action),
+		Reason:          s.Reason,
+		Owner:           s.Owner,
+		CreatedAt:       s.CreatedAt,
+		ExpiresAt:       s.ExpiresAt,
+	}
+}
+
+func MapAuditSuppressionApiToDomain(s api.AuditSuppression) domain.AuditSuppression {
+	return domain.AuditSuppression{
+		ID:              s.ID,
+		Workspace:       s.Workspace,
+		FindingID:       s.FindingID,
+		Issue:           s.Issue,
+		ResourcePattern: s.ResourcePattern,
+		Action:          domain.SuppressionAction(s.Action),
+		Reason:          s.Reason,
+		Owner:           s.Owner,
+		CreatedAt:       s.CreatedAt,
+		ExpiresAt:       s.ExpiresAt,
+	}
+}
+
+func MapAuditSuppressionDomainToStore(s domain.AuditSuppression) store.AuditSuppression {
+	return store.AuditSuppression{
+		ID:              s.ID,
+		Workspace:       s.Workspace,
+		FindingID:       s.FindingID,
+		Issue:           s.Issue,
+		ResourcePattern: s.ResourcePattern,
+		Action:          string(s.Action),
+		Reason:          s.Reason,
+		Owner:           s.Owner,
+		CreatedAt:       s.CreatedAt,
+		ExpiresAt:       s.ExpiresAt,
+	}
+}
+
+func MapAuditSuppressionStoreToDomain(s store.AuditSuppression) domain.AuditSuppression {
+	return domain.AuditSuppression{
+		ID:              s.ID,
+		Workspace:       s.Workspace,
+		FindingID:       s.FindingID,
+		Issue:           s.Issue,
+		ResourcePattern: s.ResourcePattern,
+		Action:          domain.SuppressionAction(s.Action),
+		Reason:          s.Reason,
+		Owner:           s.Owner,
+		CreatedAt:       s.CreatedAt,
+		ExpiresAt:       s.ExpiresAt,
+	}
+}
@@ -0,0 +1,56 @@
+package adapters
+
+import (
+	"strconv"
+
+	"github.com/databricks/databricks-sdk-go/service/billing"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// MapBudgetConfigurationToBudget converts a Databricks SDK budget configuration to a domain Budget
+func MapBudgetConfigurationToBudget(b billing.BudgetConfiguration) domain.Budget {
+	budget := domain.Budget{
+		ID:   b.BudgetConfigurationId,
+		Name: b.DisplayName,
+		Tags: map[string][]string{},
+	}
+	if b.Filter != nil {
+		if b.Filter.WorkspaceId != nil {
+			budget.WorkspaceIDs = b.Filter.WorkspaceId.Values
+		}
+		for _, tag := range b.Filter.Tags {
+			if tag.Value != nil {
+				budget.Tags[tag.Key] = tag.Value.Values
+			}
+		}
+	}
+	for _, alert := range b.AlertConfigurations {
+		budget.AlertRecipients += len(alert.ActionConfigurations)
+		if alert.TimePeriod != billing.AlertConfigurationTimePeriodMonth ||
+			alert.QuantityType != billing.AlertConfigurationQuantityTypeListPriceDollarsUsd {
+			continue
+		}
+		threshold, err := strconv.ParseFloat(alert.QuantityThreshold, 64)
+		if err != nil || threshold <= 0 {
+			continue
+		}
+		if budget.MonthlyLimit == 0 || threshold < budget.MonthlyLimit {
+			budget.MonthlyLimit = threshold
+		}
+	}
+	return budget
+}
+
+// MapBudgetPolicyToDomain converts a Databricks SDK budget policy to a domain BudgetPolicy
+func MapBudgetPolicyToDomain(p billing.BudgetPolicy) domain.BudgetPolicy {
+	policy := domain.BudgetPolicy{
+		ID:           p.PolicyId,
+		Name:         p.PolicyName,
+		WorkspaceIDs: p.BindingWorkspaceIds,
+		Tags:         make(map[string]string, len(p.CustomTags)),
+	}
+	for _, tag := range p.CustomTags {
+		policy.Tags[tag.Key] = tag.Value
+	}
+	return policy
+}
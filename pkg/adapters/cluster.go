@@ -0,0 +1,46 @@
+package adapters
+
+import (
+	"github.com/databricks/databricks-sdk-go/service/compute"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+)
+
+// MapClusterDetailsToClusterMetadata converts Databricks SDK ClusterDetails to our ClusterMetadata,
+// gpusPerNodeType maps node type ids to the number of GPUs of the node type
+func MapClusterDetailsToClusterMetadata(
+	cluster compute.ClusterDetails,
+	gpusPerNodeType map[string]int,
+) domain.ClusterMetadata {
+	metadata := domain.ClusterMetadata{
+		ID:                     cluster.ClusterId,
+		Name:                   cluster.ClusterName,
+		State:                  string(cluster.State),
+		Source:                 string(cluster.ClusterSource),
+		Creator:                cluster.CreatorUserName,
+		NodeTypeID:             cluster.NodeTypeId,
+		DriverNodeTypeID:       cluster.DriverNodeTypeId,
+		NumWorkers:             cluster.NumWorkers,
+		AutoterminationMinutes: cluster.AutoterminationMinutes,
+		SparkVersion:           cluster.SparkVersion,
+		NumGpus:                gpusPerNodeType[cluster.NodeTypeId],
+	}
+	if cluster.Autoscale != nil {
+		metadata.AutoscalingEnabled = true
+		metadata.MinWorkers = cluster.Autoscale.MinWorkers
+		metadata.MaxWorkers = cluster.Autoscale.MaxWorkers
+	}
+	return metadata
+}
+
+func MapClusterUtilizationStoreToDomain(u store.ClusterUtilization) domain.ClusterUtilization {
+	return domain.ClusterUtilization{
+		ClusterID:         u.ClusterID,
+		NodeCount:         u.NodeCount,
+		NodeHours:         u.NodeHours,
+		AvgCPUPercent:     u.AvgCPUPercent,
+		PeakCPUPercent:    u.PeakCPUPercent,
+		AvgMemoryPercent:  u.AvgMemoryPercent,
+		PeakMemoryPercent: u.PeakMemoryPercent,
+	}
+}
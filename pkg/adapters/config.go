@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+func MapConfigReloadStatusDomainToApi(status domain.ConfigReloadStatus) api.ConfigReloadStatus {
+	return api.ConfigReloadStatus{
+		Path:     status.Path,
+		Watching: status.Watching,
+		LoadedAt: status.LoadedAt,
+		Reloads:  status.Reloads,
+		Profiles: mapConfigProfilesDomainToApi(status.Profiles),
+		LastChanges: api.ProfileChanges{
+			Added:   mapConfigProfilesDomainToApi(status.LastChanges.Added),
+			Removed: mapConfigProfilesDomainToApi(status.LastChanges.Removed),
+			Changed: mapConfigProfilesDomainToApi(status.LastChanges.Changed),
+		},
+		LastError:   status.LastError,
+		LastErrorAt: status.LastErrorAt,
+	}
+}
+
+func mapConfigProfilesDomainToApi(profiles []domain.ConfigProfile) []api.ConfigProfile {
+	result := make([]api.ConfigProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		result = append(result, api.ConfigProfile{
+			Name:    profile.Name,
+			Type:    string(profile.Type),
+			Account: profile.Account,
+		})
+	}
+	return result
+}
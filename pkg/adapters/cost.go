@@ -3,6 +3,7 @@ package adapters
 import (
 	"fmt"
 	"maps"
+	"time"
 
 	"github.com/de-tools/data-atlas/pkg/models/api"
 	"github.com/de-tools/data-atlas/pkg/models/domain"
@@ -19,6 +20,7 @@ func MapStoreUsageRecordToDomainCost(usage store.UsageRecord) domain.ResourceCos
 			Name:        usage.ResourceID,
 			Service:     usage.ResourceType,
 			Description: fmt.Sprintf("Databricks %s %s", usage.ResourceType, usage.ResourceID),
+			Tags:        maps.Clone(usage.Tags),
 			Metadata:    maps.Clone(usage.Metadata),
 		},
 		Costs: []domain.CostComponent{{
@@ -49,6 +51,7 @@ func MapDomainResourceCostToStoreUsageRecord(cost domain.ResourceCost) store.Usa
 		Currency:     computeCost.Currency,
 		SKU:          computeCost.SKU,
 		Metadata:     maps.Clone(cost.Resource.Metadata),
+		Tags:         maps.Clone(cost.Resource.Tags),
 	}
 }
 
@@ -85,6 +88,7 @@ func MapResourceDefinitionDomainToApi(def domain.ResourceDef) api.ResourceDef {
 		Name:        def.Name,
 		Service:     def.Service,
 		Description: def.Description,
+		Tags:        def.Tags,
 		Metadata:    def.Metadata,
 	}
 }
@@ -99,3 +103,112 @@ func MapUsageStatsStoreToDomain(stats *store.UsageStats) *domain.UsageStats {
 		FirstRecordTime: stats.FirstRecordTime,
 	}
 }
+
+func MapDailyUsageAggregateStoreToDomain(
+	agg store.DailyUsageAggregate,
+	dimension domain.CostDimension,
+) domain.DailyCost {
+	return domain.DailyCost{
+		Date:         agg.Date,
+		Dimension:    dimension,
+		Key:          agg.Resource,
+		ResourceType: agg.ResourceType,
+		TotalUsage:   agg.TotalUsage,
+		TotalCost:    agg.TotalCost,
+		Currency:     agg.Currency,
+	}
+}
+
+func MapCostComparisonStoreToDomain(c store.CostComparison) domain.CostComparison {
+	res := domain.CostComparison{
+		Key:          c.Resource,
+		ResourceType: c.ResourceType,
+		Cost:         c.Cost,
+		BaselineCost: c.BaselineCost,
+		Delta:        c.Cost - c.BaselineCost,
+		DeltaPercent: domain.DeltaPercent(c.Cost, c.BaselineCost),
+	}
+
+	switch {
+	case c.InPeriod && !c.InBaseline:
+		res.Status = domain.ComparisonStatusNew
+	case !c.InPeriod && c.InBaseline:
+		res.Status = domain.ComparisonStatusDisappeared
+	case res.Delta == 0:
+		res.Status = domain.ComparisonStatusUnchanged
+	default:
+		res.Status = domain.ComparisonStatusChanged
+	}
+	return res
+}
+
+func MapCostComparisonDomainToApi(c domain.CostComparison) api.CostComparison {
+	return api.CostComparison{
+		Key:          c.Key,
+		ResourceType: c.ResourceType,
+		Cost:         c.Cost,
+		BaselineCost: c.BaselineCost,
+		Delta:        c.Delta,
+		DeltaPercent: c.DeltaPercent,
+		Status:       string(c.Status),
+	}
+}
+
+func MapCostComparisonReportDomainToApi(r domain.CostComparisonReport) api.CostComparisonReport {
+	res := api.CostComparisonReport{
+		Workspace:         r.Workspace,
+		GroupBy:           string(r.GroupBy),
+		Period:            MapTimePeriodDomainToApi(r.Period),
+		BaselinePeriod:    MapTimePeriodDomainToApi(r.BaselinePeriod),
+		TotalCost:         r.TotalCost,
+		BaselineTotalCost: r.BaselineTotalCost,
+		Delta:             r.Delta,
+		DeltaPercent:      r.DeltaPercent,
+		Currency:          r.Currency,
+		Items:             make([]api.CostComparison, 0, len(r.Items)),
+		New:               append([]string{}, r.New...),
+		Disappeared:       append([]string{}, r.Disappeared...),
+	}
+	for _, item := range r.Items {
+		res.Items = append(res.Items, MapCostComparisonDomainToApi(item))
+	}
+	return res
+}
+
+func MapCostFilterDomainToStore(f domain.CostFilter) store.DimensionFilter {
+	return store.DimensionFilter{
+		Dimension: string(f.Dimension),
+		Value:     f.Value,
+	}
+}
+
+func MapCostDriverDomainToApi(d domain.CostDriver) api.CostDriver {
+	return api.CostDriver{
+		Key:           d.Key,
+		ResourceType:  d.ResourceType,
+		Cost:          d.Cost,
+		Share:         d.Share,
+		PreviousCost:  d.PreviousCost,
+		Change:        d.Change,
+		ChangePercent: d.ChangePercent,
+		Daily:         append([]float64{}, d.Daily...),
+	}
+}
+
+func MapCostDriversReportDomainToApi(r domain.CostDriversReport) api.CostDriversReport {
+	res := api.CostDriversReport{
+		Workspace:      r.Workspace,
+		By:             string(r.By),
+		Period:         MapTimePeriodDomainToApi(r.Period),
+		PreviousPeriod: MapTimePeriodDomainToApi(r.PreviousPeriod),
+		TotalCost:      r.TotalCost,
+		OtherCost:      r.OtherCost,
+		Currency:       r.Currency,
+		Days:           append([]time.Time{}, r.Days...),
+		Drivers:        make([]api.CostDriver, 0, len(r.Drivers)),
+	}
+	for _, d := range r.Drivers {
+		res.Drivers = append(res.Drivers, MapCostDriverDomainToApi(d))
+	}
+	return res
+}
@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+)
+
+func MapReportSubscriptionDomainToApi(s domain.ReportSubscription) api.ReportSubscription {
+	return api.ReportSubscription{
+		ID:         s.ID,
+		Recipient:  s.Recipient,
+		Workspaces: s.Workspaces,
+		Cadence:    string(s.Cadence),
+		Format:     s.Format,
+		CreatedAt:  s.CreatedAt,
+		LastSentAt: s.LastSentAt,
+	}
+}
+
+func MapReportSubscriptionApiToDomain(s api.ReportSubscription) domain.ReportSubscription {
+	return domain.ReportSubscription{
+		ID:         s.ID,
+		Recipient:  s.Recipient,
+		Workspaces: s.Workspaces,
+		Cadence:    domain.DeliveryCadence(s.Cadence),
+		Format:     s.Format,
+		CreatedAt:  s.CreatedAt,
+		LastSentAt: s.LastSentAt,
+	}
+}
+
+func MapReportSubscriptionDomainToStore(s domain.ReportSubscription) store.ReportSubscription {
+	return store.ReportSubscription{
+		ID:         s.ID,
+		Recipient:  s.Recipient,
+		Workspaces: s.Workspaces,
+		Cadence:    string(s.Cadence),
+		Format:     s.Format,
+		CreatedAt:  s.CreatedAt,
+		LastSentAt: s.LastSentAt,
+	}
+}
+
+func MapReportSubscriptionStoreToDomain(s store.ReportSubscription) domain.ReportSubscription {
+	return domain.ReportSubscription{
+		ID:         s.ID,
+		Recipient:  s.Recipient,
+		Workspaces: s.Workspaces,
+		Cadence:    domain.DeliveryCadence(s.Cadence),
+		Format:     s.Format,
+		CreatedAt:  s.CreatedAt,
+		LastSentAt: s.LastSentAt,
+	}
+}
@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+func MapCostContributorDomainToApi(c domain.CostContributor) api.CostContributor {
+	res := api.CostContributor{
+		Dimension:    string(c.Dimension),
+		Key:          c.Key,
+		ResourceType: c.ResourceType,
+		Cost:         c.Cost,
+		BaselineCost: c.BaselineCost,
+		Delta:        c.Delta,
+		DeltaPercent: c.DeltaPercent,
+		Share:        c.Share,
+		SplitBy:      string(c.SplitBy),
+		Children:     make([]api.CostContributor, 0, len(c.Children)),
+	}
+	for _, child := range c.Children {
+		res.Children = append(res.Children, MapCostContributorDomainToApi(child))
+	}
+	return res
+}
+
+func MapCostExplanationDomainToApi(e domain.CostExplanation) api.CostExplanation {
+	return api.CostExplanation{
+		Workspace:      e.Workspace,
+		Period:         MapTimePeriodDomainToApi(e.Period),
+		BaselinePeriod: MapTimePeriodDomainToApi(e.BaselinePeriod),
+		Currency:       e.Currency,
+		Root:           MapCostContributorDomainToApi(e.Root),
+	}
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+func MapForecastPointDomainToApi(p domain.ForecastPoint) api.ForecastPoint {
+	return api.ForecastPoint{
+		Date:  p.Date,
+		Value: p.Value,
+		Lower: p.Lower,
+		Upper: p.Upper,
+	}
+}
+
+func MapForecastEstimateDomainToApi(e domain.ForecastEstimate) api.ForecastEstimate {
+	return api.ForecastEstimate{
+		End:    e.End,
+		Actual: e.Actual,
+		Value:  e.Value,
+		Lower:  e.Lower,
+		Upper:  e.Upper,
+	}
+}
+
+func MapCostForecastDomainToApi(f domain.CostForecast) api.CostForecast {
+	res := api.CostForecast{
+		GroupBy:    string(f.Dimension),
+		Key:        f.Key,
+		Currency:   f.Currency,
+		Points:     make([]api.ForecastPoint, 0, len(f.Points)),
+		Total:      MapForecastEstimateDomainToApi(f.Total),
+		MonthEnd:   MapForecastEstimateDomainToApi(f.MonthEnd),
+		QuarterEnd: MapForecastEstimateDomainToApi(f.QuarterEnd),
+	}
+	for _, p := range f.Points {
+		res.Points = append(res.Points, MapForecastPointDomainToApi(p))
+	}
+	return res
+}
+
+func MapCostForecastReportDomainToApi(r domain.CostForecastReport) api.CostForecastReport {
+	res := api.CostForecastReport{
+		Workspace:       r.Workspace,
+		Start:           r.Start,
+		HorizonDays:     r.HorizonDays,
+		HistoryDays:     r.HistoryDays,
+		ConfidenceLevel: r.ConfidenceLevel,
+		SkippedSeries:   r.SkippedSeries,
+		Forecasts:       make([]api.CostForecast, 0, len(r.Forecasts)),
+	}
+	for _, f := range r.Forecasts {
+		res.Forecasts = append(res.Forecasts, MapCostForecastDomainToApi(f))
+	}
+	return res
+}
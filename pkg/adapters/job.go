@@ -0,0 +1,20 @@
+package adapters
+
+import (
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+)
+
+func MapJobRunStoreToDomain(r store.JobRun) domain.JobRun {
+	return domain.JobRun{
+		JobID:           r.JobID,
+		RunID:           r.RunID,
+		RunName:         r.RunName,
+		TriggerType:     r.TriggerType,
+		StartTime:       r.StartTime,
+		EndTime:         r.EndTime,
+		ResultState:     r.ResultState,
+		TerminationCode: r.TerminationCode,
+		Retries:         r.Retries,
+	}
+}
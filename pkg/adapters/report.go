@@ -0,0 +1,42 @@
+package adapters
+
+import (
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+func MapReportDetailDomainToApi(d domain.ReportDetail) api.ReportDetail {
+	return api.ReportDetail{
+		Name:        d.Name,
+		Value:       d.Value,
+		Unit:        d.Unit,
+		Description: d.Description,
+	}
+}
+
+func MapReportSectionDomainToApi(s domain.ReportSection) api.ReportSection {
+	res := api.ReportSection{
+		Title:    s.Title,
+		Summary:  s.Summary,
+		Details:  make([]api.ReportDetail, 0, len(s.Details)),
+		Metadata: s.Metadata,
+	}
+	for _, d := range s.Details {
+		res.Details = append(res.Details, MapReportDetailDomainToApi(d))
+	}
+	return res
+}
+
+func MapReportDomainToApi(r domain.Report) api.Report {
+	res := api.Report{
+		Title:       r.Title,
+		Period:      MapTimePeriodDomainToApi(r.Period),
+		TotalAmount: r.TotalAmount,
+		Currency:    r.Currency,
+		Sections:    make([]api.ReportSection, 0, len(r.Sections)),
+	}
+	for _, s := range r.Sections {
+		res.Sections = append(res.Sections, MapReportSectionDomainToApi(s))
+	}
+	return res
+}
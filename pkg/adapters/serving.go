@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"github.com/databricks/databricks-sdk-go/service/serving"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+)
+
+// MapServingEndpointDetailedToMetadata converts Databricks SDK ServingEndpointDetailed to our ServingEndpointMetadata
+func MapServingEndpointDetailedToMetadata(endpoint *serving.ServingEndpointDetailed) domain.ServingEndpointMetadata {
+	metadata := domain.ServingEndpointMetadata{
+		ID:             endpoint.Id,
+		Name:           endpoint.Name,
+		Creator:        endpoint.Creator,
+		Task:           endpoint.Task,
+		ServedEntities: []domain.ServedEntityMetadata{},
+	}
+	if endpoint.State != nil {
+		metadata.Ready = string(endpoint.State.Ready)
+		metadata.ConfigUpdate = string(endpoint.State.ConfigUpdate)
+	}
+	if endpoint.Config == nil {
+		return metadata
+	}
+
+	for _, entity := range endpoint.Config.ServedEntities {
+		served := domain.ServedEntityMetadata{
+			Name:                      entity.Name,
+			EntityName:                entity.EntityName,
+			EntityVersion:             entity.EntityVersion,
+			External:                  entity.ExternalModel != nil || entity.FoundationModel != nil,
+			WorkloadSize:              entity.WorkloadSize,
+			WorkloadType:              string(entity.WorkloadType),
+			ScaleToZeroEnabled:        entity.ScaleToZeroEnabled,
+			MinProvisionedConcurrency: entity.MinProvisionedConcurrency,
+			MaxProvisionedConcurrency: entity.MaxProvisionedConcurrency,
+			MinProvisionedThroughput:  entity.MinProvisionedThroughput,
+			MaxProvisionedThroughput:  entity.MaxProvisionedThroughput,
+		}
+		if entity.State != nil {
+			served.DeploymentState = string(entity.State.Deployment)
+			served.DeploymentStateMessage = entity.State.DeploymentStateMessage
+		}
+		metadata.ServedEntities = append(metadata.ServedEntities, served)
+	}
+
+	// Legacy endpoints only describe served models
+	if len(endpoint.Config.ServedEntities) > 0 {
+		return metadata
+	}
+	for _, model := range endpoint.Config.ServedModels {
+		served := domain.ServedEntityMetadata{
+			Name:                      model.Name,
+			EntityName:                model.ModelName,
+			EntityVersion:             model.ModelVersion,
+			WorkloadSize:              model.WorkloadSize,
+			WorkloadType:              string(model.WorkloadType),
+			ScaleToZeroEnabled:        model.ScaleToZeroEnabled,
+			MinProvisionedConcurrency: model.MinProvisionedConcurrency,
+			MaxProvisionedConcurrency: model.MaxProvisionedConcurrency,
+		}
+		if model.State != nil {
+			served.DeploymentState = string(model.State.Deployment)
+			served.DeploymentStateMessage = model.State.DeploymentStateMessage
+		}
+		metadata.ServedEntities = append(metadata.ServedEntities, served)
+	}
+
+	return metadata
+}
+
+func MapServingEndpointUsageStoreToDomain(u store.ServingEndpointUsage) domain.ServingEndpointUsage {
+	return domain.ServingEndpointUsage{
+		EndpointID:    u.EndpointID,
+		EndpointName:  u.EndpointName,
+		Requests:      u.Requests,
+		ErrorRequests: u.ErrorRequests,
+		ActiveHours:   u.ActiveHours,
+	}
+}
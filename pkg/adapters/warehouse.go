@@ -3,6 +3,7 @@ package adapters
 import (
 	"github.com/databricks/databricks-sdk-go/service/sql"
 	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
 )
 
 // MapEndpointInfoToWarehouseMetadata converts Databricks SDK GetWarehouseResponse to our WarehouseMetadata
@@ -10,6 +11,12 @@ func MapEndpointInfoToWarehouseMetadata(warehouse *sql.GetWarehouseResponse) *do
 	if warehouse == nil {
 		return nil
 	}
+	tags := make(map[string]string)
+	if warehouse.Tags != nil {
+		for _, tag := range warehouse.Tags.CustomTags {
+			tags[tag.Key] = tag.Value
+		}
+	}
 	return &domain.WarehouseMetadata{
 		ID:               warehouse.Id,
 		Name:             warehouse.Name,
@@ -19,5 +26,46 @@ func MapEndpointInfoToWarehouseMetadata(warehouse *sql.GetWarehouseResponse) *do
 		MaxNumClusters:   warehouse.MaxNumClusters,
 		AutoStopMins:     warehouse.AutoStopMins,
 		EnableServerless: warehouse.EnableServerlessCompute,
+		Tags:             tags,
+	}
+}
+
+func MapWarehouseQueryStatsStoreToDomain(s store.WarehouseQueryStats) domain.WarehouseQueryStats {
+	return domain.WarehouseQueryStats{
+		WarehouseID:        s.WarehouseID,
+		QueryCount:         s.QueryCount,
+		FailedQueries:      s.FailedQueries,
+		P50DurationSeconds: s.P50DurationSeconds,
+		P95DurationSeconds: s.P95DurationSeconds,
+		MaxDurationSeconds: s.MaxDurationSeconds,
+		AvgQueuedSeconds:   s.AvgQueuedSeconds,
+		ExecutionHours:     s.ExecutionHours,
+		ReadBytes:          s.ReadBytes,
+		SpilledBytes:       s.SpilledBytes,
+	}
+}
+
+func MapWarehouseEventStoreToDomain(e store.WarehouseEvent) domain.WarehouseEvent {
+	return domain.WarehouseEvent{
+		WarehouseID:  e.WarehouseID,
+		EventType:    e.EventType,
+		ClusterCount: e.ClusterCount,
+		EventTime:    e.EventTime,
+	}
+}
+
+// MapWarehouseActiveMinutesStoreToDomain groups the active minutes by warehouse, keeping their order
+func MapWarehouseActiveMinutesStoreToDomain(minutes []store.WarehouseActiveMinute) []domain.WarehouseQueryActivity {
+	var activity []domain.WarehouseQueryActivity
+	indexByID := make(map[string]int)
+	for _, m := range minutes {
+		i, ok := indexByID[m.WarehouseID]
+		if !ok {
+			i = len(activity)
+			indexByID[m.WarehouseID] = i
+			activity = append(activity, domain.WarehouseQueryActivity{WarehouseID: m.WarehouseID})
+		}
+		activity[i].ActiveMinutes = append(activity[i].ActiveMinutes, m.Minute)
 	}
+	return activity
 }
@@ -1,13 +1,22 @@
 package workspace
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/de-tools/data-atlas/pkg/services/config"
+	"github.com/de-tools/data-atlas/pkg/services/delivery"
+	"github.com/de-tools/data-atlas/pkg/services/render"
+	"github.com/de-tools/data-atlas/pkg/services/reporting"
 	"github.com/de-tools/data-atlas/pkg/services/workflow"
 
 	"github.com/de-tools/data-atlas/pkg/adapters"
@@ -21,20 +30,76 @@ import (
 )
 
 const (
-	defaultInterval = 7 // 7 days ~ 1 week
-	dateLayout      = "02-01-2006"
+	defaultInterval          = 7  // 7 days ~ 1 week
+	defaultForecastHorizon   = 30 // 30 days ~ 1 month
+	defaultTopCostDrivers    = 20
+	defaultAuditHistoryLimit = 20
+	dateLayout               = "02-01-2006"
 )
 
 type Router struct {
-	explorer     account.Explorer
-	workflowCtrl workflow.Controller
+	explorer      account.Explorer
+	reports       reporting.Builder
+	subscriptions delivery.Service
+	configStatus  config.StatusProvider
+	workflowCtrl  workflow.Controller
+	auditSettings *workspace.AuditSettingsProvider
+	auditHistory  workspace.AuditHistory
+	suppressions  workspace.AuditSuppressions
+
+	anomalySettings  workspace.AnomalyDetectionSettings
+	forecastSettings workspace.ForecastSettings
+	explainSettings  workspace.CostExplanationSettings
+}
+
+// Dependencies of the workspace routes
+type Dependencies struct {
+	Explorer           account.Explorer
+	WorkflowController workflow.Controller
+	AuditSettings      *workspace.AuditSettingsProvider // optional, built-in audit settings are used when nil
+	AuditHistory       workspace.AuditHistory           // optional, audit reports are not recorded when nil
+	AuditSuppressions  workspace.AuditSuppressions      // optional, findings are not suppressed when nil
+	Reports            reporting.Builder                // optional, built from the explorer and audit history when nil
+	Subscriptions      delivery.Service                 // optional, reports are not delivered by email when nil
+	ConfigStatus       config.StatusProvider            // optional, the config status is not exposed when nil
+
+	AnomalySettings  *workspace.AnomalyDetectionSettings // optional, built-in anomaly settings are used when nil
+	ForecastSettings *workspace.ForecastSettings         // optional, built-in forecast settings are used when nil
+	ExplainSettings  *workspace.CostExplanationSettings  // optional, built-in explain settings are used when nil
 }
 
-func NewWorkspaceRouter(explorer account.Explorer, workflowController workflow.Controller) *Router {
-	return &Router{
-		explorer:     explorer,
-		workflowCtrl: workflowController,
+func NewWorkspaceRouter(deps Dependencies) *Router {
+	auditSettings := deps.AuditSettings
+	if auditSettings == nil {
+		auditSettings = workspace.NewAuditSettingsProvider()
+	}
+	reports := deps.Reports
+	if reports == nil {
+		reports = reporting.NewBuilder(deps.Explorer, deps.AuditHistory, reporting.DefaultSettings())
+	}
+	router := &Router{
+		explorer:         deps.Explorer,
+		reports:          reports,
+		subscriptions:    deps.Subscriptions,
+		configStatus:     deps.ConfigStatus,
+		workflowCtrl:     deps.WorkflowController,
+		auditSettings:    auditSettings,
+		auditHistory:     deps.AuditHistory,
+		suppressions:     deps.AuditSuppressions,
+		anomalySettings:  workspace.DefaultAnomalyDetectionSettings(),
+		forecastSettings: workspace.DefaultForecastSettings(),
+		explainSettings:  workspace.DefaultCostExplanationSettings(),
+	}
+	if deps.AnomalySettings != nil {
+		router.anomalySettings = *deps.AnomalySettings
+	}
+	if deps.ForecastSettings != nil {
+		router.forecastSettings = *deps.ForecastSettings
 	}
+	if deps.ExplainSettings != nil {
+		router.explainSettings = *deps.ExplainSettings
+	}
+	return router
 }
 
 func (r *Router) Routes() chi.Router {
@@ -44,12 +109,29 @@ func (r *Router) Routes() chi.Router {
 	router.Get("/workspaces/{workspace}/resources/{resource}/cost", r.GetResourceCost)
 	router.Get("/workspaces/{workspace}/resources/cost", r.GetWorkspaceResourcesCost)
 	router.Post("/workspaces/{workspace}/sync", r.SyncWorkspace)
+	router.Get("/config/status", r.GetConfigStatus)
+	router.Get("/workspaces/{workspace}/anomalies", r.GetCostAnomalies)
+	router.Get("/workspaces/{workspace}/forecast", r.GetCostForecast)
+	router.Get("/workspaces/{workspace}/cost/compare", r.GetCostComparison)
+	router.Get("/workspaces/{workspace}/cost/explain", r.ExplainCostChange)
+	router.Get("/workspaces/{workspace}/cost/top", r.GetTopCostDrivers)
 
 	// Audit endpoints - WIP
+	router.Get("/workspaces/{workspace}/audit", r.RunAudit)
 	router.Get("/workspaces/{workspace}/resources/warehouse/audit", r.GetWarehouseAudit)
 	router.Get("/workspaces/{workspace}/resources/cluster/audit", r.GetClusterAudit)
 	router.Get("/workspaces/{workspace}/resources/dlt_pipeline/audit", r.GetDLTAudit)
 	router.Get("/workspaces/{workspace}/resources/endpoint/audit", r.GetModelServingAudit)
+	router.Get("/workspaces/{workspace}/resources/job/audit", r.GetJobAudit)
+	router.Get("/audits/history", r.GetAuditHistory)
+	router.Get("/reports/{period}", r.GetReport)
+	router.Get("/reports/subscriptions", r.ListReportSubscriptions)
+	router.Post("/reports/subscriptions", r.CreateReportSubscription)
+	router.Delete("/reports/subscriptions/{id}", r.DeleteReportSubscription)
+	router.Post("/reports/subscriptions/{id}/send", r.SendReportSubscription)
+	router.Get("/audits/suppressions", r.ListAuditSuppressions)
+	router.Post("/audits/suppressions", r.CreateAuditSuppression)
+	router.Delete("/audits/suppressions/{id}", r.DeleteAuditSuppression)
 
 	return router
 }
@@ -182,6 +264,19 @@ func (r *Router) GetWorkspaceResourcesCost(w http.ResponseWriter, req *http.Requ
 	}
 }
 
+// GetConfigStatus returns the outcome of the latest reload of the profile config file
+func (r *Router) GetConfigStatus(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.configStatus == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("config status is not available"))
+		return
+	}
+
+	if err := jsonResponse(w, adapters.MapConfigReloadStatusDomainToApi(r.configStatus.Status())); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+	}
+}
+
 func (r *Router) SyncWorkspace(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 
@@ -204,10 +299,16 @@ func (r *Router) SyncWorkspace(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func (r *Router) GetWarehouseAudit(w http.ResponseWriter, req *http.Request) {
+func (r *Router) GetCostAnomalies(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	ws := getWorkspaceFromPath(req)
 
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	endTime, err := parseDateParam(req, "to", time.Now())
 	if err != nil {
 		handleError(ctx, w, http.StatusBadRequest, err)
@@ -220,43 +321,155 @@ func (r *Router) GetWarehouseAudit(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	settings := r.anomalySettings
+	switch method := workspace.AnomalyBaselineMethod(req.URL.Query().Get("method")); method {
+	case "":
+	case workspace.AnomalyBaselineRolling, workspace.AnomalyBaselineWeekday:
+		settings.Method = method
+	default:
+		handleError(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid 'method' %q. Expected rolling or weekday", method))
+		return
+	}
+
+	analyzer, err := r.explorer.GetWorkspaceCostAnalyzer(ctx, ws)
 	if err != nil {
 		handleError(ctx, w, http.StatusNotFound, err)
 		return
 	}
 
-	explorer, err := r.explorer.GetWorkspaceExplorer(ctx, ws)
+	report, err := workspace.GetCostAnomalies(ctx, ws, startTime, endTime, analyzer, settings)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
+}
+
+func (r *Router) GetCostForecast(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	horizon, err := parseHorizonParam(req, "horizon", defaultForecastHorizon)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	groupBy, err := parseDimensionParam(req, "group_by")
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	settings := r.forecastSettings
+	if horizon > settings.MaxHorizonDays {
+		handleError(ctx, w, http.StatusBadRequest,
+			fmt.Errorf("'horizon' must not exceed %d days", settings.MaxHorizonDays))
+		return
+	}
+
+	analyzer, err := r.explorer.GetWorkspaceCostAnalyzer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	report, err := workspace.GetCostForecast(ctx, ws, time.Now(), horizon, groupBy, analyzer, settings)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := jsonResponse(w, adapters.MapCostForecastReportDomainToApi(report)); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+	}
+}
+
+func (r *Router) GetCostComparison(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	period, baseline, err := parseComparisonPeriods(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	groupBy, err := parseDimensionParam(req, "group_by")
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if groupBy == "" {
+		groupBy = domain.CostDimensionResourceID
+	}
+
+	analyzer, err := r.explorer.GetWorkspaceCostAnalyzer(ctx, ws)
 	if err != nil {
 		handleError(ctx, w, http.StatusNotFound, err)
 		return
 	}
 
-	// For now construct audit settings here; later can be provided via DI
-	settings := workspace.DefaultWarehouseAuditSettings()
-	report, err := workspace.GetWarehouseAudit(ctx, ws, startTime, endTime, costManager, explorer, settings)
+	report, err := analyzer.CompareCost(ctx, groupBy, period, baseline, nil)
 	if err != nil {
 		handleError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
+	report.Workspace = ws.Name
 
-	if err := jsonResponse(w, adapters.MapAuditReportDomainToApi(report)); err != nil {
+	if err := jsonResponse(w, adapters.MapCostComparisonReportDomainToApi(report)); err != nil {
 		handleError(ctx, w, http.StatusInternalServerError, err)
 	}
 }
 
-func (r *Router) GetClusterAudit(writer http.ResponseWriter, request *http.Request) {
-	// TODO:
-	// - Billing per cluster / workspace
-	// - Autoscalling / auto-terminations are on / off
-	// - Cluster Utilisation, low average CPU and memory usage
-	// - ID underutilized / overutilized clusters TOP N
-	// - Cluster runtime duration - e.g. ran for 15 hours etc.
-	// - GPU enabled costs, check if we have them [???]
-	panic("Implement me")
+func (r *Router) ExplainCostChange(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	period, baseline, err := parseComparisonPeriods(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	settings := r.explainSettings
+	depth, err := parsePositiveIntParam(req, "depth", settings.MaxDepth)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	// every level compares every dimension for each contributor kept, so the depth is capped by the settings
+	if depth > settings.MaxDepth {
+		handleError(ctx, w, http.StatusBadRequest,
+			fmt.Errorf("'depth' must not exceed %d", settings.MaxDepth))
+		return
+	}
+	settings.MaxDepth = depth
+
+	analyzer, err := r.explorer.GetWorkspaceCostAnalyzer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	explanation, err := workspace.ExplainCostChange(ctx, ws, period, baseline, analyzer, settings)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := jsonResponse(w, adapters.MapCostExplanationDomainToApi(explanation)); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+	}
 }
 
-func (r *Router) GetDLTAudit(w http.ResponseWriter, req *http.Request) {
+func (r *Router) GetTopCostDrivers(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	ws := getWorkspaceFromPath(req)
 
@@ -272,67 +485,789 @@ func (r *Router) GetDLTAudit(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	n, err := parsePositiveIntParam(req, "n", defaultTopCostDrivers)
 	if err != nil {
-		handleError(ctx, w, http.StatusNotFound, err)
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	by, err := parseDimensionParam(req, "by")
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
+	if by == "" {
+		by = domain.CostDimensionResourceID
+	}
 
-	// For now construct audit settings here; later can be provided via DI
-	settings := workspace.DLTAuditSettings{
-		MaintenanceRatioThreshold:  0.3,
-		MinMaintenanceEvents:       3,
-		LongRunAvgSecondsThreshold: 2 * 3600,
+	analyzer, err := r.explorer.GetWorkspaceCostAnalyzer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
 	}
-	report, err := workspace.GetDLTAudit(ctx, ws, startTime, endTime, costManager, settings)
+
+	report, err := workspace.GetTopCostDrivers(ctx, ws, newTimePeriod(startTime, endTime), n, by, analyzer)
 	if err != nil {
 		handleError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 
-	if err := jsonResponse(w, adapters.MapAuditReportDomainToApi(report)); err != nil {
+	if err := jsonResponse(w, adapters.MapCostDriversReportDomainToApi(report)); err != nil {
 		handleError(ctx, w, http.StatusInternalServerError, err)
 	}
 }
 
-func (r *Router) GetModelServingAudit(writer http.ResponseWriter, request *http.Request) {
-	// Serving endpoints
-	// TODO:
-	// - Autoscaling & long time to provision endpoints
-	// - Not working endpoints
-	// - Utilization of endpoints
-	// - Active compute time
-	// - Golden Signals
-	panic("Implement me")
-}
+// RunAudit evaluates the registered audit rules selected by the resource and rule query parameters
+// and returns their merged findings
+func (r *Router) RunAudit(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
 
-func handleError(ctx context.Context, w http.ResponseWriter, statusCode int, err error) {
-	if err == nil {
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
 
-	l := zerolog.Ctx(ctx)
-	l.Error().Err(err).Msg("handler error")
-	http.Error(w, err.Error(), statusCode)
+	endTime, err := parseDateParam(req, "to", time.Now())
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	startTime, err := parseDateParam(req, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	rules, err := workspace.DefaultAuditRegistry().Rules(req.URL.Query()["resource"], req.URL.Query()["rule"])
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	settings, err := r.getAuditSettings(req, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	explorer, err := r.explorer.GetWorkspaceExplorer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	input := &workspace.AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Explorer:    explorer,
+		Settings:    settings,
+	}
+	report := workspace.RunAudit(ctx, input, rules)
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
 }
 
-func jsonResponse(w http.ResponseWriter, data interface{}) error {
-	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(data)
+func (r *Router) GetWarehouseAudit(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	endTime, err := parseDateParam(req, "to", time.Now())
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	startTime, err := parseDateParam(req, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	explorer, err := r.explorer.GetWorkspaceExplorer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	settings, err := r.getAuditSettings(req, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := workspace.GetWarehouseAudit(ctx, ws, startTime, endTime, costManager, explorer, settings.Warehouse)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
 }
 
-func parseDateParam(r *http.Request, paramName string, defaultDate time.Time) (time.Time, error) {
-	param := r.URL.Query().Get(paramName)
+func (r *Router) GetClusterAudit(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
 
-	if param == "" {
-		return defaultDate, nil
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
 	}
 
-	parsed, err := time.Parse(dateLayout, param)
+	endTime, err := parseDateParam(req, "to", time.Now())
 	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid '%s' date format. Expected format: DD-MM-YYYY", paramName)
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	startTime, err := parseDateParam(req, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	explorer, err := r.explorer.GetWorkspaceExplorer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	settings, err := r.getAuditSettings(req, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := workspace.GetClusterAudit(ctx, ws, startTime, endTime, costManager, explorer, settings.Cluster)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
+}
+
+func (r *Router) GetDLTAudit(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	endTime, err := parseDateParam(req, "to", time.Now())
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	startTime, err := parseDateParam(req, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	settings, err := r.getAuditSettings(req, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := workspace.GetDLTAudit(ctx, ws, startTime, endTime, costManager, settings.DLT)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
+}
+
+func (r *Router) GetModelServingAudit(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	endTime, err := parseDateParam(req, "to", time.Now())
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	startTime, err := parseDateParam(req, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	explorer, err := r.explorer.GetWorkspaceExplorer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	settings, err := r.getAuditSettings(req, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := workspace.GetModelServingAudit(ctx, ws, startTime, endTime, costManager, explorer, settings.Endpoint)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
+}
+
+func (r *Router) GetJobAudit(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ws := getWorkspaceFromPath(req)
+
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	endTime, err := parseDateParam(req, "to", time.Now())
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	startTime, err := parseDateParam(req, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	costManager, err := r.explorer.GetWorkspaceCostManagerCached(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	explorer, err := r.explorer.GetWorkspaceExplorer(ctx, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	settings, err := r.getAuditSettings(req, ws)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	report, err := workspace.GetJobAudit(ctx, ws, startTime, endTime, costManager, explorer, settings.Job)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := r.finishAudit(ctx, &report); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	auditResponse(ctx, w, report, format)
+}
+
+// GetReport builds the cost report of a period across the workspaces given as query parameters,
+// every workspace when none are given
+func (r *Router) GetReport(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	format, err := parseFormatParam(req)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	if format == render.FormatCSV {
+		handleError(ctx, w, http.StatusBadRequest,
+			fmt.Errorf("invalid 'format': reports are rendered as json, html or markdown"))
+		return
+	}
+
+	period, err := reporting.ParsePeriod(chi.URLParam(req, "period"), time.Now())
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var workspaces []domain.Workspace
+	for _, name := range req.URL.Query()["workspace"] {
+		workspaces = append(workspaces, domain.Workspace{Name: name})
+	}
+
+	report, err := r.reports.Build(ctx, period, workspaces)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	renderResponse(ctx, w, format, func(out io.Writer) error {
+		return render.Report(out, report, format)
+	})
+}
+
+func (r *Router) GetAuditHistory(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.auditHistory == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("audit history is not configured"))
+		return
+	}
+
+	filter := domain.AuditHistoryFilter{
+		Workspace:    req.URL.Query().Get("workspace"),
+		ResourceType: req.URL.Query().Get("resource"),
+	}
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		if req.URL.Query().Get(param.name) == "" {
+			continue
+		}
+		date, err := parseDateParam(req, param.name, time.Time{})
+		if err != nil {
+			handleError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		*param.target = &date
+	}
+
+	var err error
+	filter.Limit, err = parsePositiveIntParam(req, "limit", defaultAuditHistoryLimit)
+	if err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	runs, err := r.auditHistory.List(ctx, filter)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := make([]api.AuditRun, 0, len(runs))
+	for _, run := range runs {
+		response = append(response, adapters.MapAuditRunDomainToApi(run))
+	}
+	if err := jsonResponse(w, response); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+	}
+}
+
+func (r *Router) ListAuditSuppressions(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.suppressions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("audit suppressions are not configured"))
+		return
+	}
+
+	suppressions, err := r.suppressions.List(ctx, req.URL.Query().Get("workspace"))
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := make([]api.AuditSuppression, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		response = append(response, adapters.MapAuditSuppressionDomainToApi(suppression))
+	}
+	if err := jsonResponse(w, response); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+	}
+}
+
+func (r *Router) CreateAuditSuppression(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.suppressions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("audit suppressions are not configured"))
+		return
+	}
+
+	var request api.AuditSuppression
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		handleError(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid suppression: %w", err))
+		return
+	}
+
+	suppression := adapters.MapAuditSuppressionApiToDomain(request)
+	if suppression.Action == "" {
+		suppression.Action = domain.SuppressionActionSuppress
+	}
+	if err := workspace.ValidateAuditSuppression(suppression); err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := r.suppressions.Create(ctx, suppression)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(adapters.MapAuditSuppressionDomainToApi(created)); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("failed to write response")
+	}
+}
+
+func (r *Router) DeleteAuditSuppression(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.suppressions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("audit suppressions are not configured"))
+		return
+	}
+
+	err := r.suppressions.Delete(ctx, chi.URLParam(req, "id"))
+	if errors.Is(err, workspace.ErrSuppressionNotFound) {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *Router) ListReportSubscriptions(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.subscriptions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("report subscriptions are not configured"))
+		return
+	}
+
+	subscriptions, err := r.subscriptions.List(ctx)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	response := make([]api.ReportSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, adapters.MapReportSubscriptionDomainToApi(subscription))
+	}
+	if err := jsonResponse(w, response); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+	}
+}
+
+func (r *Router) CreateReportSubscription(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.subscriptions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("report subscriptions are not configured"))
+		return
+	}
+
+	var request api.ReportSubscription
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		handleError(ctx, w, http.StatusBadRequest, fmt.Errorf("invalid subscription: %w", err))
+		return
+	}
+
+	subscription := adapters.MapReportSubscriptionApiToDomain(request)
+	if subscription.Format == "" {
+		subscription.Format = string(render.FormatHTML)
+	}
+	if err := delivery.ValidateSubscription(subscription); err != nil {
+		handleError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	created, err := r.subscriptions.Create(ctx, subscription)
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(adapters.MapReportSubscriptionDomainToApi(created)); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("failed to write response")
+	}
+}
+
+func (r *Router) DeleteReportSubscription(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.subscriptions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("report subscriptions are not configured"))
+		return
+	}
+
+	err := r.subscriptions.Delete(ctx, chi.URLParam(req, "id"))
+	if errors.Is(err, delivery.ErrSubscriptionNotFound) {
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SendReportSubscription sends the report of the subscription now, e.g. to check the email setup
+func (r *Router) SendReportSubscription(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if r.subscriptions == nil {
+		handleError(ctx, w, http.StatusServiceUnavailable, fmt.Errorf("report subscriptions are not configured"))
+		return
+	}
+
+	err := r.subscriptions.Send(ctx, chi.URLParam(req, "id"))
+	switch {
+	case errors.Is(err, delivery.ErrSubscriptionNotFound):
+		handleError(ctx, w, http.StatusNotFound, err)
+		return
+	case errors.Is(err, delivery.ErrDeliveryNotConfigured):
+		handleError(ctx, w, http.StatusServiceUnavailable, err)
+		return
+	case err != nil:
+		handleError(ctx, w, http.StatusBadGateway, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// finishAudit applies the suppressions to the report and records it in the audit history,
+// a failure to record the report is logged and does not fail the audit
+func (r *Router) finishAudit(ctx context.Context, report *domain.AuditReport) error {
+	if r.suppressions != nil {
+		if err := r.suppressions.Apply(ctx, report); err != nil {
+			return err
+		}
+	}
+	if r.auditHistory != nil {
+		if _, err := r.auditHistory.Record(ctx, report); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("failed to record audit report")
+		}
+	}
+	return nil
+}
+
+// getAuditSettings returns the audit settings of the workspace with the overrides given as query parameters,
+// e.g. ?cluster.low_cpu_percent=10
+func (r *Router) getAuditSettings(req *http.Request, ws domain.Workspace) (workspace.AuditSettings, error) {
+	settings := r.auditSettings.ForWorkspace(ws.Name)
+	for key, values := range req.URL.Query() {
+		if !workspace.IsAuditSettingKey(key) {
+			continue
+		}
+		if err := settings.Override(key, values[len(values)-1]); err != nil {
+			return workspace.AuditSettings{}, err
+		}
+	}
+	return settings, nil
+}
+
+func handleError(ctx context.Context, w http.ResponseWriter, statusCode int, err error) {
+	if err == nil {
+		return
+	}
+
+	l := zerolog.Ctx(ctx)
+	l.Error().Err(err).Msg("handler error")
+	http.Error(w, err.Error(), statusCode)
+}
+
+func jsonResponse(w http.ResponseWriter, data interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(data)
+}
+
+// auditResponse writes the audit report in the requested format
+func auditResponse(ctx context.Context, w http.ResponseWriter, report domain.AuditReport, format render.Format) {
+	renderResponse(ctx, w, format, func(out io.Writer) error {
+		return render.AuditReport(out, report, format)
+	})
+}
+
+// renderResponse renders the response body before writing it, so that rendering errors are still reported
+// with an error status
+func renderResponse(ctx context.Context, w http.ResponseWriter, format render.Format, write func(io.Writer) error) {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		handleError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	if _, err := buf.WriteTo(w); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("failed to write response")
+	}
+}
+
+// parseFormatParam parses the format audit reports are rendered in, JSON when not given
+func parseFormatParam(r *http.Request) (render.Format, error) {
+	format, err := render.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		return "", fmt.Errorf("invalid 'format': %w", err)
+	}
+	return format, nil
+}
+
+func parseDateParam(r *http.Request, paramName string, defaultDate time.Time) (time.Time, error) {
+	param := r.URL.Query().Get(paramName)
+
+	if param == "" {
+		return defaultDate, nil
+	}
+
+	parsed, err := time.Parse(dateLayout, param)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid '%s' date format. Expected format: DD-MM-YYYY", paramName)
+	}
+	return parsed, nil
+}
+
+// parseHorizonParam parses a horizon in days, given either as a number or with a "d" suffix (e.g. 30d)
+func parseHorizonParam(r *http.Request, paramName string, defaultDays int) (int, error) {
+	param := r.URL.Query().Get(paramName)
+
+	if param == "" {
+		return defaultDays, nil
+	}
+
+	days, err := strconv.Atoi(strings.TrimSuffix(param, "d"))
+	if err != nil || days <= 0 {
+		return 0, fmt.Errorf("invalid '%s' format. Expected a positive number of days, e.g. 30d", paramName)
+	}
+	return days, nil
+}
+
+// parsePositiveIntParam parses an optional positive integer
+func parsePositiveIntParam(r *http.Request, paramName string, defaultValue int) (int, error) {
+	param := r.URL.Query().Get(paramName)
+
+	if param == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(param)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid '%s' value %q. Expected a positive number", paramName, param)
+	}
+	return value, nil
+}
+
+// parseComparisonPeriods parses the compared period (from, to) and the baseline period (baseline_from, baseline_to).
+// By default the last week is compared against the window of the same length right before it.
+func parseComparisonPeriods(r *http.Request) (domain.TimePeriod, domain.TimePeriod, error) {
+	endTime, err := parseDateParam(r, "to", time.Now())
+	if err != nil {
+		return domain.TimePeriod{}, domain.TimePeriod{}, err
+	}
+
+	startTime, err := parseDateParam(r, "from", time.Now().AddDate(0, 0, -defaultInterval))
+	if err != nil {
+		return domain.TimePeriod{}, domain.TimePeriod{}, err
+	}
+
+	baselineEndTime, err := parseDateParam(r, "baseline_to", startTime)
+	if err != nil {
+		return domain.TimePeriod{}, domain.TimePeriod{}, err
+	}
+
+	baselineStartTime, err := parseDateParam(r, "baseline_from", baselineEndTime.Add(-endTime.Sub(startTime)))
+	if err != nil {
+		return domain.TimePeriod{}, domain.TimePeriod{}, err
+	}
+
+	return newTimePeriod(startTime, endTime), newTimePeriod(baselineStartTime, baselineEndTime), nil
+}
+
+// parseDimensionParam parses an optional cost dimension, an empty value means no grouping
+func parseDimensionParam(r *http.Request, paramName string) (domain.CostDimension, error) {
+	dimension := domain.CostDimension(r.URL.Query().Get(paramName))
+
+	if dimension != "" && !dimension.IsValid() {
+		return "", fmt.Errorf("invalid '%s' value %q. Expected one of: resource_id, resource_type, sku, usage_type, tag:<key>",
+			paramName, dimension)
+	}
+	return dimension, nil
+}
+
+func newTimePeriod(startTime, endTime time.Time) domain.TimePeriod {
+	return domain.TimePeriod{
+		Start:    startTime,
+		End:      endTime,
+		Duration: int(endTime.Sub(startTime).Hours() / 24),
 	}
-	return parsed, nil
 }
 
 func getWorkspaceFromPath(r *http.Request) domain.Workspace {
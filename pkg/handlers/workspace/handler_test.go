@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -60,6 +61,34 @@ func (m *mockAccountExplorer) GetWorkspaceCostManagerRemote(
 	return args.Get(0).(workspace.CostManager), args.Error(1)
 }
 
+func (m *mockAccountExplorer) GetWorkspaceCostAnalyzer(
+	ctx context.Context,
+	ws domain.Workspace,
+) (workspace.CostAnalyzer, error) {
+	args := m.Called(ctx, ws)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.CostAnalyzer), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetAccountCostManagerRemote(ctx context.Context) (workspace.CostManager, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.CostManager), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetWorkspaceID(ctx context.Context, ws domain.Workspace) (string, error) {
+	args := m.Called(ctx, ws)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockAccountExplorer) Close() error {
+	return nil
+}
+
 type mockWorkspaceExplorer struct {
 	mock.Mock
 }
@@ -69,6 +98,140 @@ func (m *mockWorkspaceExplorer) ListSupportedResources(ctx context.Context) ([]d
 	return args.Get(0).([]domain.WorkspaceResource), args.Error(1)
 }
 
+func (m *mockWorkspaceExplorer) GetWarehouseMetadata(
+	ctx context.Context,
+	warehouseID string,
+) (*domain.WarehouseMetadata, error) {
+	args := m.Called(ctx, warehouseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WarehouseMetadata), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) ListWarehouses(ctx context.Context) ([]domain.WarehouseMetadata, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.WarehouseMetadata), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetWarehouseQueryStats(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseQueryStats, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WarehouseQueryStats), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetWarehouseEvents(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseEvent, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WarehouseEvent), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetWarehouseQueryActivity(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseQueryActivity, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WarehouseQueryActivity), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetBudgetControls(ctx context.Context) (*domain.BudgetControls, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetControls), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) ListClusters(ctx context.Context) ([]domain.ClusterMetadata, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ClusterMetadata), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetClusterUtilization(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.ClusterUtilization, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ClusterUtilization), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) ListServingEndpoints(ctx context.Context) ([]domain.ServingEndpointMetadata, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServingEndpointMetadata), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetServingEndpointUsage(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.ServingEndpointUsage, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServingEndpointUsage), args.Error(1)
+}
+
+func (m *mockWorkspaceExplorer) GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]domain.JobRun, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.JobRun), args.Error(1)
+}
+
+type mockCostAnalyzer struct {
+	mock.Mock
+}
+
+func (m *mockCostAnalyzer) GetDailyCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	startTime, endTime time.Time,
+) ([]domain.DailyCost, error) {
+	args := m.Called(ctx, dimension, startTime, endTime)
+	return args.Get(0).([]domain.DailyCost), args.Error(1)
+}
+
+func (m *mockCostAnalyzer) CompareCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	period, baseline domain.TimePeriod,
+	filters []domain.CostFilter,
+) (domain.CostComparisonReport, error) {
+	args := m.Called(ctx, dimension, period, baseline, filters)
+	return args.Get(0).(domain.CostComparisonReport), args.Error(1)
+}
+
+func (m *mockCostAnalyzer) ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 type mockWorkspaceCostManager struct {
 	mock.Mock
 }
@@ -95,8 +258,25 @@ type mockWorkflowController struct{}
 func (m *mockWorkflowController) Start(ctx context.Context, workspace string) error  { return nil }
 func (m *mockWorkflowController) Cancel(ctx context.Context, workspace string) error { return nil }
 
+type mockAuditHistory struct {
+	mock.Mock
+}
+
+func (m *mockAuditHistory) Record(ctx context.Context, report *domain.AuditReport) (domain.AuditRun, error) {
+	args := m.Called(ctx, report)
+	return args.Get(0).(domain.AuditRun), args.Error(1)
+}
+
+func (m *mockAuditHistory) List(ctx context.Context, filter domain.AuditHistoryFilter) ([]domain.AuditRun, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditRun), args.Error(1)
+}
+
 func setupRouter(explorer *mockAccountExplorer, workflowController *mockWorkflowController) *Router {
-	return NewWorkspaceRouter(explorer, workflowController)
+	return NewWorkspaceRouter(Dependencies{Explorer: explorer, WorkflowController: workflowController})
 }
 
 func TestListWorkspaces(t *testing.T) {
@@ -401,6 +581,612 @@ func TestGetResourceCost(t *testing.T) {
 	}
 }
 
+func TestGetCostAnomalies(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockAccountExplorer, *mockCostAnalyzer)
+		expectedStatus int
+	}{
+		{
+			name:  "successful response",
+			query: "?from=01-07-2025&to=13-07-2025&method=weekday",
+			setupMock: func(me *mockAccountExplorer, ca *mockCostAnalyzer) {
+				me.On("GetWorkspaceCostAnalyzer", mock.Anything, domain.Workspace{Name: "test-workspace"}).
+					Return(ca, nil)
+				ca.On("GetDailyCost", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return([]domain.DailyCost{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid method",
+			query:          "?method=mean",
+			setupMock:      func(me *mockAccountExplorer, ca *mockCostAnalyzer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "analyzer error",
+			query: "",
+			setupMock: func(me *mockAccountExplorer, ca *mockCostAnalyzer) {
+				me.On("GetWorkspaceCostAnalyzer", mock.Anything, domain.Workspace{Name: "test-workspace"}).
+					Return(nil, fmt.Errorf("workspace not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExplorer := new(mockAccountExplorer)
+			analyzer := new(mockCostAnalyzer)
+			tt.setupMock(mockExplorer, analyzer)
+			suppressions := new(mockAuditSuppressions)
+			if tt.expectedStatus == http.StatusOK {
+				suppressions.On("Apply", mock.Anything, mock.Anything).Return(nil)
+			}
+			router := NewWorkspaceRouter(Dependencies{
+				Explorer:           mockExplorer,
+				WorkflowController: new(mockWorkflowController),
+				AuditSuppressions:  suppressions,
+			})
+
+			req := httptest.NewRequest("GET", "/workspaces/test-workspace/anomalies"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add("workspace", "test-workspace")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			router.GetCostAnomalies(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response api.AuditReport
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "cost_anomaly", response.ResourceType)
+				assert.Equal(t, "weekday", response.Summary["baseline_method"])
+			}
+
+			mockExplorer.AssertExpectations(t)
+			analyzer.AssertExpectations(t)
+			suppressions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExplainCostChange_Depth(t *testing.T) {
+	periods := "?from=08-07-2025&to=15-07-2025&baseline_from=01-07-2025&baseline_to=08-07-2025"
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockAccountExplorer)
+		expectedStatus int
+	}{
+		{
+			name:  "depth within the configured maximum",
+			query: periods + "&depth=2",
+			setupMock: func(me *mockAccountExplorer) {
+				me.On("GetWorkspaceCostAnalyzer", mock.Anything, domain.Workspace{Name: "test-workspace"}).
+					Return(nil, fmt.Errorf("workspace not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "depth above the configured maximum",
+			query:          periods + "&depth=50",
+			setupMock:      func(me *mockAccountExplorer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExplorer := new(mockAccountExplorer)
+			tt.setupMock(mockExplorer)
+			router := setupRouter(mockExplorer, new(mockWorkflowController))
+
+			req := httptest.NewRequest("GET", "/workspaces/test-workspace/cost/explain"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add("workspace", "test-workspace")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			router.ExplainCostChange(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockExplorer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTopCostDrivers(t *testing.T) {
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)
+	period := domain.TimePeriod{Start: from, End: to, Duration: 7}
+	previous := domain.TimePeriod{Start: from.AddDate(0, 0, -7), End: from, Duration: 7}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockAccountExplorer, *mockCostAnalyzer)
+		expectedStatus int
+	}{
+		{
+			name:  "successful response",
+			query: "?from=01-07-2025&to=08-07-2025&n=1&by=sku",
+			setupMock: func(me *mockAccountExplorer, ca *mockCostAnalyzer) {
+				me.On("GetWorkspaceCostAnalyzer", mock.Anything, domain.Workspace{Name: "test-workspace"}).
+					Return(ca, nil)
+				ca.On("CompareCost", mock.Anything, domain.CostDimensionSKU, period, previous, []domain.CostFilter(nil)).
+					Return(domain.CostComparisonReport{
+						TotalCost: 100,
+						Currency:  "USD",
+						Items: []domain.CostComparison{
+							{Key: "JOBS", Cost: 80, BaselineCost: 40, Delta: 40},
+							{Key: "SQL", Cost: 20, BaselineCost: 20},
+						},
+					}, nil)
+				ca.On("GetDailyCost", mock.Anything, domain.CostDimensionSKU, from, to).
+					Return([]domain.DailyCost{{Date: from, Key: "JOBS", TotalCost: 80}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid n",
+			query:          "?n=0",
+			setupMock:      func(me *mockAccountExplorer, ca *mockCostAnalyzer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid dimension",
+			query:          "?by=workspace",
+			setupMock:      func(me *mockAccountExplorer, ca *mockCostAnalyzer) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExplorer := new(mockAccountExplorer)
+			analyzer := new(mockCostAnalyzer)
+			tt.setupMock(mockExplorer, analyzer)
+			router := setupRouter(mockExplorer, new(mockWorkflowController))
+
+			req := httptest.NewRequest("GET", "/workspaces/test-workspace/cost/top"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add("workspace", "test-workspace")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			router.GetTopCostDrivers(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response api.CostDriversReport
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "sku", response.By)
+				assert.Len(t, response.Days, 7)
+				if assert.Len(t, response.Drivers, 1) {
+					assert.Equal(t, "JOBS", response.Drivers[0].Key)
+					assert.InDelta(t, 0.8, response.Drivers[0].Share, 0.001)
+					assert.InDelta(t, 80.0, response.Drivers[0].Daily[0], 0.001)
+				}
+				assert.InDelta(t, 20.0, response.OtherCost, 0.001)
+			}
+
+			mockExplorer.AssertExpectations(t)
+			analyzer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRunAudit(t *testing.T) {
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockAccountExplorer, *mockWorkspaceExplorer, *mockWorkspaceCostManager)
+		expectedStatus int
+		expectedCSV    bool
+	}{
+		{
+			name:  "successful response",
+			query: "?from=01-07-2025&to=08-07-2025&resource=dlt_pipeline&dlt_pipeline.min_maintenance_events=5",
+			setupMock: func(me *mockAccountExplorer, we *mockWorkspaceExplorer, cm *mockWorkspaceCostManager) {
+				ws := domain.Workspace{Name: "test-workspace"}
+				me.On("GetWorkspaceCostManagerCached", mock.Anything, ws).Return(cm, nil)
+				me.On("GetWorkspaceExplorer", mock.Anything, ws).Return(we, nil)
+				cm.On("GetResourcesCost", mock.Anything, mock.AnythingOfType("domain.WorkspaceResources"), from, to).
+					Return([]domain.ResourceCost{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "csv format",
+			query: "?from=01-07-2025&to=08-07-2025&resource=dlt_pipeline&format=csv",
+			setupMock: func(me *mockAccountExplorer, we *mockWorkspaceExplorer, cm *mockWorkspaceCostManager) {
+				ws := domain.Workspace{Name: "test-workspace"}
+				me.On("GetWorkspaceCostManagerCached", mock.Anything, ws).Return(cm, nil)
+				me.On("GetWorkspaceExplorer", mock.Anything, ws).Return(we, nil)
+				cm.On("GetResourcesCost", mock.Anything, mock.AnythingOfType("domain.WorkspaceResources"), from, to).
+					Return([]domain.ResourceCost{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCSV:    true,
+		},
+		{
+			name:           "unknown format",
+			query:          "?format=pdf",
+			setupMock:      func(*mockAccountExplorer, *mockWorkspaceExplorer, *mockWorkspaceCostManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown rule",
+			query:          "?rule=does_not_exist",
+			setupMock:      func(*mockAccountExplorer, *mockWorkspaceExplorer, *mockWorkspaceCostManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown resource",
+			query:          "?resource=metastore",
+			setupMock:      func(*mockAccountExplorer, *mockWorkspaceExplorer, *mockWorkspaceCostManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid settings override",
+			query:          "?cluster.low_cpu_percent=-5",
+			setupMock:      func(*mockAccountExplorer, *mockWorkspaceExplorer, *mockWorkspaceCostManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown settings override",
+			query:          "?cluster.max_cpu=5",
+			setupMock:      func(*mockAccountExplorer, *mockWorkspaceExplorer, *mockWorkspaceCostManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExplorer := new(mockAccountExplorer)
+			wsExplorer := new(mockWorkspaceExplorer)
+			costManager := new(mockWorkspaceCostManager)
+			tt.setupMock(mockExplorer, wsExplorer, costManager)
+			router := setupRouter(mockExplorer, new(mockWorkflowController))
+
+			req := httptest.NewRequest("GET", "/workspaces/test-workspace/audit"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add("workspace", "test-workspace")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			router.RunAudit(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedCSV {
+				assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+				assert.True(t, strings.HasPrefix(rec.Body.String(), "severity,service,resource,issue,"))
+			} else if tt.expectedStatus == http.StatusOK {
+				var response api.AuditReport
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "dlt_pipeline", response.ResourceType)
+				if assert.Len(t, response.Rules, 1) {
+					assert.Equal(t, "dlt_pipeline_health", response.Rules[0].Rule)
+					assert.Empty(t, response.Rules[0].Error)
+				}
+				assert.Empty(t, response.Findings)
+
+				settings := response.Summary["settings"].(map[string]any)
+				dlt := settings["dlt_pipeline"].(map[string]any)
+				assert.Equal(t, 5.0, dlt["min_maintenance_events"])
+				assert.Equal(t, 0.3, dlt["maintenance_ratio_threshold"])
+			}
+
+			mockExplorer.AssertExpectations(t)
+			costManager.AssertExpectations(t)
+		})
+	}
+}
+
+type mockReportBuilder struct {
+	mock.Mock
+}
+
+func (m *mockReportBuilder) Build(
+	ctx context.Context,
+	period domain.TimePeriod,
+	workspaces []domain.Workspace,
+) (domain.Report, error) {
+	args := m.Called(ctx, period, workspaces)
+	return args.Get(0).(domain.Report), args.Error(1)
+}
+
+func TestGetReport(t *testing.T) {
+	tests := []struct {
+		name                string
+		period              string
+		query               string
+		setupMock           func(*mockReportBuilder)
+		expectedStatus      int
+		expectedContentType string
+	}{
+		{
+			name:   "markdown report of two workspaces",
+			period: "2025-06",
+			query:  "?workspace=ws1&workspace=ws2&format=markdown",
+			setupMock: func(b *mockReportBuilder) {
+				period := domain.TimePeriod{
+					Start:    time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+					End:      time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+					Duration: 30,
+				}
+				b.On("Build", mock.Anything, period, []domain.Workspace{{Name: "ws1"}, {Name: "ws2"}}).
+					Return(domain.Report{Title: "Cost report: ws1, ws2", Period: period}, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/markdown; charset=utf-8",
+		},
+		{
+			name:           "invalid period",
+			period:         "fortnight",
+			setupMock:      func(*mockReportBuilder) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "csv format",
+			period:         "month",
+			query:          "?format=csv",
+			setupMock:      func(*mockReportBuilder) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "builder error",
+			period: "month",
+			setupMock: func(b *mockReportBuilder) {
+				b.On("Build", mock.Anything, mock.Anything, []domain.Workspace(nil)).
+					Return(domain.Report{}, fmt.Errorf("no workspaces to report on"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := new(mockReportBuilder)
+			tt.setupMock(builder)
+			router := NewWorkspaceRouter(Dependencies{Explorer: new(mockAccountExplorer), Reports: builder})
+
+			req := httptest.NewRequest("GET", "/reports/"+tt.period+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add("period", tt.period)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			router.GetReport(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, rec.Header().Get("Content-Type"))
+				assert.True(t, strings.HasPrefix(rec.Body.String(), "# Cost report: ws1, ws2"))
+			}
+
+			builder.AssertExpectations(t)
+		})
+	}
+}
+
+type mockAuditSuppressions struct {
+	mock.Mock
+}
+
+func (m *mockAuditSuppressions) Create(
+	ctx context.Context,
+	suppression domain.AuditSuppression,
+) (domain.AuditSuppression, error) {
+	args := m.Called(ctx, suppression)
+	return args.Get(0).(domain.AuditSuppression), args.Error(1)
+}
+
+func (m *mockAuditSuppressions) List(ctx context.Context, workspace string) ([]domain.AuditSuppression, error) {
+	args := m.Called(ctx, workspace)
+	return args.Get(0).([]domain.AuditSuppression), args.Error(1)
+}
+
+func (m *mockAuditSuppressions) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockAuditSuppressions) Apply(ctx context.Context, report *domain.AuditReport) error {
+	return m.Called(ctx, report).Error(0)
+}
+
+func TestCreateAuditSuppression(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mockAuditSuppressions)
+		expectedStatus int
+	}{
+		{
+			name: "successful response",
+			body: `{"issue":"auto_stop_disabled","resource_pattern":"bi-*","reason":"dashboards","owner":"bi-team"}`,
+			setupMock: func(m *mockAuditSuppressions) {
+				m.On("Create", mock.Anything, domain.AuditSuppression{
+					Issue: "auto_stop_disabled", ResourcePattern: "bi-*", Action: domain.SuppressionActionSuppress,
+					Reason: "dashboards", Owner: "bi-team",
+				}).Return(domain.AuditSuppression{ID: "s1", Issue: "auto_stop_disabled"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "invalid body",
+			body:           `{"issue":`,
+			setupMock:      func(*mockAuditSuppressions) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing owner",
+			body:           `{"finding_id":"wh-1_auto_stop_disabled","reason":"dashboards"}`,
+			setupMock:      func(*mockAuditSuppressions) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppressions := new(mockAuditSuppressions)
+			tt.setupMock(suppressions)
+			router := NewWorkspaceRouter(Dependencies{AuditSuppressions: suppressions})
+
+			req := httptest.NewRequest("POST", "/audits/suppressions", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			router.CreateAuditSuppression(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var response api.AuditSuppression
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "s1", response.ID)
+			}
+			suppressions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteAuditSuppression(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "deleted", expectedStatus: http.StatusNoContent},
+		{name: "not found", err: workspace.ErrSuppressionNotFound, expectedStatus: http.StatusNotFound},
+		{name: "store error", err: fmt.Errorf("db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suppressions := new(mockAuditSuppressions)
+			suppressions.On("Delete", mock.Anything, "s1").Return(tt.err)
+			router := NewWorkspaceRouter(Dependencies{AuditSuppressions: suppressions})
+
+			req := httptest.NewRequest("DELETE", "/audits/suppressions/s1", nil)
+			rec := httptest.NewRecorder()
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add("id", "s1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			router.DeleteAuditSuppression(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			suppressions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetAuditHistory(t *testing.T) {
+	from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2025, 7, 8, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mockAuditHistory)
+		expectedStatus int
+	}{
+		{
+			name:  "successful response",
+			query: "?workspace=test-workspace&resource=cluster&from=01-07-2025&limit=5",
+			setupMock: func(m *mockAuditHistory) {
+				m.On("List", mock.Anything, domain.AuditHistoryFilter{
+					Workspace: "test-workspace", ResourceType: "cluster", From: &from, Limit: 5,
+				}).Return([]domain.AuditRun{{
+					ID:            "run-2",
+					PreviousRunID: "run-1",
+					Scope:         "cluster",
+					CreatedAt:     createdAt,
+					Report: domain.AuditReport{
+						Workspace:    "test-workspace",
+						ResourceType: "cluster",
+						RunID:        "run-2",
+						Findings: []domain.AuditFinding{
+							{Id: "c1_long_runtime", Issue: "long_runtime", Status: domain.FindingStatusOpen},
+						},
+						Resolved: []domain.AuditFinding{
+							{Id: "c2_gpu_cost", Issue: "gpu_cost", Status: domain.FindingStatusResolved},
+						},
+					},
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid limit",
+			query:          "?limit=0",
+			setupMock:      func(*mockAuditHistory) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "store error",
+			query: "",
+			setupMock: func(m *mockAuditHistory) {
+				m.On("List", mock.Anything, domain.AuditHistoryFilter{Limit: defaultAuditHistoryLimit}).
+					Return(nil, fmt.Errorf("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := new(mockAuditHistory)
+			tt.setupMock(history)
+			router := NewWorkspaceRouter(Dependencies{AuditHistory: history})
+
+			req := httptest.NewRequest("GET", "/audits/history"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			router.GetAuditHistory(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []api.AuditRun
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				if assert.Len(t, response, 1) {
+					assert.Equal(t, "run-1", response[0].PreviousRunID)
+					assert.Equal(t, "open", response[0].Report.Findings[0].Status)
+					assert.Equal(t, "resolved", response[0].Report.Resolved[0].Status)
+				}
+			}
+
+			history.AssertExpectations(t)
+		})
+	}
+
+	t.Run("history not configured", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		setupRouter(new(mockAccountExplorer), new(mockWorkflowController)).
+			GetAuditHistory(rec, httptest.NewRequest("GET", "/audits/history", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
 func TestParseDataParam(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -450,3 +1236,32 @@ func TestParseDataParam(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHorizonParam(t *testing.T) {
+	tests := []struct {
+		name         string
+		paramValue   string
+		expectedDays int
+		expectError  bool
+	}{
+		{name: "days suffix", paramValue: "30d", expectedDays: 30},
+		{name: "plain number", paramValue: "90", expectedDays: 90},
+		{name: "empty value", paramValue: "", expectedDays: defaultForecastHorizon},
+		{name: "negative value", paramValue: "-5d", expectError: true},
+		{name: "invalid unit", paramValue: "2w", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/?horizon="+tt.paramValue, nil)
+			result, err := parseHorizonParam(req, "horizon", defaultForecastHorizon)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedDays, result)
+			}
+		})
+	}
+}
@@ -17,6 +17,17 @@ type AuditFinding struct {
 	Description    string      `json:"description"`
 	Recommendation string      `json:"recommendation"`
 	Severity       Severity    `json:"severity"`
+	Status         string      `json:"status,omitempty"`
+	SuppressionID  string      `json:"suppression_id,omitempty"`
+	Rule           string      `json:"rule,omitempty"`
+
+	EstimatedMonthlySavings SavingsEstimate `json:"estimated_monthly_savings"`
+}
+
+type SavingsEstimate struct {
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency,omitempty"`
+	Confidence string  `json:"confidence,omitempty"`
 }
 
 type TimePeriod struct {
@@ -31,4 +42,37 @@ type AuditReport struct {
 	Period       TimePeriod             `json:"period"`
 	Summary      map[string]interface{} `json:"summary"`
 	Findings     []AuditFinding         `json:"findings"`
+	Rules        []AuditRuleResult      `json:"rules,omitempty"`
+	RunID        string                 `json:"run_id,omitempty"`
+	Resolved     []AuditFinding         `json:"resolved,omitempty"`
+	Suppressed   []AuditFinding         `json:"suppressed,omitempty"`
+}
+
+type AuditRun struct {
+	ID            string      `json:"id"`
+	PreviousRunID string      `json:"previous_run_id,omitempty"`
+	Scope         string      `json:"scope"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Report        AuditReport `json:"report"`
+}
+
+type AuditRuleResult struct {
+	Rule         string  `json:"rule"`
+	ResourceType string  `json:"resource_type"`
+	Findings     int     `json:"findings"`
+	DurationMs   float64 `json:"duration_ms"`
+	Error        string  `json:"error,omitempty"`
+}
+
+type AuditSuppression struct {
+	ID              string     `json:"id"`
+	Workspace       string     `json:"workspace,omitempty"`
+	FindingID       string     `json:"finding_id,omitempty"`
+	Issue           string     `json:"issue,omitempty"`
+	ResourcePattern string     `json:"resource_pattern,omitempty"`
+	Action          string     `json:"action"`
+	Reason          string     `json:"reason"`
+	Owner           string     `json:"owner"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
 }
@@ -0,0 +1,26 @@
+package api
+
+import "time"
+
+type ConfigProfile struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Account string `json:"account,omitempty"`
+}
+
+type ProfileChanges struct {
+	Added   []ConfigProfile `json:"added"`
+	Removed []ConfigProfile `json:"removed"`
+	Changed []ConfigProfile `json:"changed"`
+}
+
+type ConfigReloadStatus struct {
+	Path        string          `json:"path"`
+	Watching    bool            `json:"watching"`
+	LoadedAt    time.Time       `json:"loaded_at"`
+	Reloads     int             `json:"reloads"`
+	Profiles    []ConfigProfile `json:"profiles"`
+	LastChanges ProfileChanges  `json:"last_changes"`
+	LastError   string          `json:"last_error,omitempty"`
+	LastErrorAt *time.Time      `json:"last_error_at,omitempty"`
+}
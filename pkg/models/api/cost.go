@@ -27,3 +27,51 @@ type ResourceCost struct {
 	Resource  ResourceDef
 	Costs     []CostComponent
 }
+
+type CostComparison struct {
+	Key          string   `json:"key"`
+	ResourceType string   `json:"resource_type,omitempty"`
+	Cost         float64  `json:"cost"`
+	BaselineCost float64  `json:"baseline_cost"`
+	Delta        float64  `json:"delta"`
+	DeltaPercent *float64 `json:"delta_percent"`
+	Status       string   `json:"status"`
+}
+
+type CostComparisonReport struct {
+	Workspace         string           `json:"workspace"`
+	GroupBy           string           `json:"group_by"`
+	Period            TimePeriod       `json:"period"`
+	BaselinePeriod    TimePeriod       `json:"baseline_period"`
+	TotalCost         float64          `json:"total_cost"`
+	BaselineTotalCost float64          `json:"baseline_total_cost"`
+	Delta             float64          `json:"delta"`
+	DeltaPercent      *float64         `json:"delta_percent"`
+	Currency          string           `json:"currency"`
+	Items             []CostComparison `json:"items"`
+	New               []string         `json:"new"`
+	Disappeared       []string         `json:"disappeared"`
+}
+
+type CostDriver struct {
+	Key           string    `json:"key"`
+	ResourceType  string    `json:"resource_type,omitempty"`
+	Cost          float64   `json:"cost"`
+	Share         float64   `json:"share"`
+	PreviousCost  float64   `json:"previous_cost"`
+	Change        float64   `json:"change"`
+	ChangePercent *float64  `json:"change_percent"`
+	Daily         []float64 `json:"daily"`
+}
+
+type CostDriversReport struct {
+	Workspace      string       `json:"workspace"`
+	By             string       `json:"by"`
+	Period         TimePeriod   `json:"period"`
+	PreviousPeriod TimePeriod   `json:"previous_period"`
+	TotalCost      float64      `json:"total_cost"`
+	OtherCost      float64      `json:"other_cost"`
+	Currency       string       `json:"currency"`
+	Days           []time.Time  `json:"days"`
+	Drivers        []CostDriver `json:"drivers"`
+}
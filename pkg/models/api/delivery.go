@@ -0,0 +1,13 @@
+package api
+
+import "time"
+
+type ReportSubscription struct {
+	ID         string     `json:"id"`
+	Recipient  string     `json:"recipient"`
+	Workspaces []string   `json:"workspaces,omitempty"`
+	Cadence    string     `json:"cadence"`
+	Format     string     `json:"format,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
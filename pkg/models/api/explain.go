@@ -0,0 +1,22 @@
+package api
+
+type CostContributor struct {
+	Dimension    string            `json:"dimension,omitempty"`
+	Key          string            `json:"key,omitempty"`
+	ResourceType string            `json:"resource_type,omitempty"`
+	Cost         float64           `json:"cost"`
+	BaselineCost float64           `json:"baseline_cost"`
+	Delta        float64           `json:"delta"`
+	DeltaPercent *float64          `json:"delta_percent"`
+	Share        float64           `json:"share"`
+	SplitBy      string            `json:"split_by,omitempty"`
+	Children     []CostContributor `json:"children"`
+}
+
+type CostExplanation struct {
+	Workspace      string          `json:"workspace"`
+	Period         TimePeriod      `json:"period"`
+	BaselinePeriod TimePeriod      `json:"baseline_period"`
+	Currency       string          `json:"currency"`
+	Root           CostContributor `json:"root"`
+}
@@ -0,0 +1,38 @@
+package api
+
+import "time"
+
+type ForecastPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+	Lower float64   `json:"lower"`
+	Upper float64   `json:"upper"`
+}
+
+type ForecastEstimate struct {
+	End    time.Time `json:"end"`
+	Actual float64   `json:"actual"`
+	Value  float64   `json:"value"`
+	Lower  float64   `json:"lower"`
+	Upper  float64   `json:"upper"`
+}
+
+type CostForecast struct {
+	GroupBy    string           `json:"group_by,omitempty"`
+	Key        string           `json:"key"`
+	Currency   string           `json:"currency"`
+	Points     []ForecastPoint  `json:"points"`
+	Total      ForecastEstimate `json:"total"`
+	MonthEnd   ForecastEstimate `json:"month_end"`
+	QuarterEnd ForecastEstimate `json:"quarter_end"`
+}
+
+type CostForecastReport struct {
+	Workspace       string         `json:"workspace"`
+	Start           time.Time      `json:"start"`
+	HorizonDays     int            `json:"horizon_days"`
+	HistoryDays     int            `json:"history_days"`
+	ConfidenceLevel float64        `json:"confidence_level"`
+	SkippedSeries   int            `json:"skipped_series"`
+	Forecasts       []CostForecast `json:"forecasts"`
+}
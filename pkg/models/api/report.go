@@ -0,0 +1,23 @@
+package api
+
+type Report struct {
+	Title       string          `json:"title"`
+	Period      TimePeriod      `json:"period"`
+	TotalAmount float64         `json:"total_amount"`
+	Currency    string          `json:"currency"`
+	Sections    []ReportSection `json:"sections"`
+}
+
+type ReportSection struct {
+	Title    string                 `json:"title"`
+	Summary  map[string]interface{} `json:"summary,omitempty"`
+	Details  []ReportDetail         `json:"details"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type ReportDetail struct {
+	Name        string      `json:"name"`
+	Value       interface{} `json:"value"`
+	Unit        string      `json:"unit,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
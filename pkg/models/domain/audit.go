@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Severity int
 
 const (
@@ -14,13 +16,103 @@ type AuditFinding struct {
 	Issue          string // code of the issue, (e.g., auto_stop_disabled)
 	Description    string // human-readable description
 	Recommendation string
-	Severity       Severity // low/medium/high
+	Severity       Severity      // low/medium/high
+	Status         FindingStatus // set when the report is recorded in the audit history
+	SuppressionID  string        // set when the finding is acknowledged or suppressed
+	Rule           string        // audit rule reporting the finding, empty for single resource audits
+	// EstimatedMonthlySavings is the spend a month the fix is expected to save, zero for findings without direct cost impact
+	EstimatedMonthlySavings SavingsEstimate
+}
+
+// SavingsConfidence tells how directly a savings estimate follows from the usage data
+type SavingsConfidence string
+
+const (
+	// SavingsConfidenceLow estimates rely on assumptions about the workload, e.g. the share of idle time
+	SavingsConfidenceLow SavingsConfidence = "low"
+	// SavingsConfidenceMedium estimates are derived from utilization or sizing of the resource
+	SavingsConfidenceMedium SavingsConfidence = "medium"
+	// SavingsConfidenceHigh estimates are the billed cost of the resource, e.g. unused resources and failed runs
+	SavingsConfidenceHigh SavingsConfidence = "high"
+)
+
+type SavingsEstimate struct {
+	Amount     float64
+	Currency   string
+	Confidence SavingsConfidence
 }
 
+// FindingStatus classifies a finding against the previous audit run of the same scope
+type FindingStatus string
+
+const (
+	FindingStatusNew        FindingStatus = "new"
+	FindingStatusOpen       FindingStatus = "open"
+	FindingStatusResolved   FindingStatus = "resolved"
+	FindingStatusSuppressed FindingStatus = "suppressed"
+	// FindingStatusUnknown is a finding of the previous run whose rule failed in this run, so it is neither open nor resolved
+	FindingStatusUnknown FindingStatus = "unknown"
+)
+
 type AuditReport struct {
 	Workspace    string
 	ResourceType string
 	Period       TimePeriod
 	Summary      map[string]any // issue -> recommendation
 	Findings     []AuditFinding
+	Rules        []AuditRuleResult // rules evaluated by the rule engine, empty for single resource audits
+	RunID        string            // set when the report is recorded in the audit history
+	Resolved     []AuditFinding    // findings of the previous run of the same scope that are no longer reported
+	Suppressed   []AuditFinding    // findings matching a suppression, left out of Findings
+}
+
+// AuditRun is an audit report recorded in the audit history
+type AuditRun struct {
+	ID            string
+	PreviousRunID string // empty for the first run of the scope
+	Scope         string // resource types and rules audited, runs are compared within a scope
+	CreatedAt     time.Time
+	Report        AuditReport
+}
+
+// AuditHistoryFilter selects recorded audit runs, zero values match everything
+type AuditHistoryFilter struct {
+	Workspace    string
+	ResourceType string
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+}
+
+// AuditRuleResult records the evaluation of a single audit rule
+type AuditRuleResult struct {
+	Rule         string
+	ResourceType string
+	Findings     int
+	Duration     time.Duration
+	Error        string // empty when the rule succeeded
+}
+
+// SuppressionAction is what happens to the findings matching a suppression
+type SuppressionAction string
+
+const (
+	// SuppressionActionAcknowledge keeps the finding in the report, marked with the suppression
+	SuppressionActionAcknowledge SuppressionAction = "acknowledge"
+	// SuppressionActionSuppress moves the finding out of the report findings
+	SuppressionActionSuppress SuppressionAction = "suppress"
+)
+
+// AuditSuppression matches findings either by id or by issue and a glob pattern over the resource name
+type AuditSuppression struct {
+	ID              string
+	Workspace       string // empty matches every workspace
+	FindingID       string
+	Issue           string
+	ResourcePattern string // e.g. etl-*, empty matches every resource
+	Action          SuppressionAction
+	Reason          string
+	Owner           string
+	CreatedAt       time.Time
+	ExpiresAt       *time.Time // nil never expires
 }
@@ -0,0 +1,98 @@
+package domain
+
+import "slices"
+
+// Budget is an account budget and the usage it is scoped to
+type Budget struct {
+	ID              string
+	Name            string
+	WorkspaceIDs    []int64             // empty when the budget covers every workspace
+	Tags            map[string][]string // custom tag values the usage must carry, empty when not scoped by tags
+	AlertRecipients int                 // actions notified when the budget alert triggers
+	MonthlyLimit    float64             // lowest monthly alert threshold in list price USD, zero when not set
+}
+
+// AppliesTo reports whether the budget is scoped to the workspace
+func (b Budget) AppliesTo(workspaceID int64) bool {
+	return len(b.WorkspaceIDs) == 0 || slices.Contains(b.WorkspaceIDs, workspaceID)
+}
+
+// Covers reports whether usage of the workspace carrying the tags counts towards the budget
+func (b Budget) Covers(workspaceID int64, tags map[string]string) bool {
+	if !b.AppliesTo(workspaceID) {
+		return false
+	}
+	for key, values := range b.Tags {
+		value, ok := tags[key]
+		if !ok || !slices.Contains(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAlert reports whether anyone is notified when the budget is exceeded
+func (b Budget) HasAlert() bool {
+	return b.AlertRecipients > 0
+}
+
+// IsTagScoped reports whether only usage carrying certain tags counts towards the budget
+func (b Budget) IsTagScoped() bool {
+	return len(b.Tags) > 0
+}
+
+// BudgetPolicy attributes usage to its custom tags so the spending can be capped and charged back
+type BudgetPolicy struct {
+	ID           string
+	Name         string
+	WorkspaceIDs []int64 // empty when the policy can be used by every workspace
+	Tags         map[string]string
+}
+
+// AppliesTo reports whether the policy can be used by the workspace
+func (p BudgetPolicy) AppliesTo(workspaceID int64) bool {
+	return len(p.WorkspaceIDs) == 0 || slices.Contains(p.WorkspaceIDs, workspaceID)
+}
+
+// Covers reports whether a resource of the workspace carrying the tags is governed by the policy,
+// a policy without tags attributes nothing
+func (p BudgetPolicy) Covers(workspaceID int64, tags map[string]string) bool {
+	if len(p.Tags) == 0 || !p.AppliesTo(workspaceID) {
+		return false
+	}
+	for key, value := range p.Tags {
+		if tags[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// BudgetControls holds the budgets and budget policies applying to a workspace
+type BudgetControls struct {
+	WorkspaceID int64
+	Budgets     []Budget
+	Policies    []BudgetPolicy
+}
+
+// AlertingBudgets returns the budgets with alerts covering a resource carrying the tags
+func (c BudgetControls) AlertingBudgets(tags map[string]string) []Budget {
+	var budgets []Budget
+	for _, budget := range c.Budgets {
+		if budget.HasAlert() && budget.Covers(c.WorkspaceID, tags) {
+			budgets = append(budgets, budget)
+		}
+	}
+	return budgets
+}
+
+// GoverningPolicies returns the budget policies covering a resource carrying the tags
+func (c BudgetControls) GoverningPolicies(tags map[string]string) []BudgetPolicy {
+	var policies []BudgetPolicy
+	for _, policy := range c.Policies {
+		if policy.Covers(c.WorkspaceID, tags) {
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
@@ -0,0 +1,30 @@
+package domain
+
+// ClusterMetadata represents the configuration of an all-purpose or job cluster
+type ClusterMetadata struct {
+	ID                     string
+	Name                   string
+	State                  string
+	Source                 string // UI, API, JOB, PIPELINE, ...
+	Creator                string
+	NodeTypeID             string
+	DriverNodeTypeID       string
+	NumWorkers             int // fixed size clusters
+	MinWorkers             int // autoscaling clusters
+	MaxWorkers             int // autoscaling clusters
+	AutoscalingEnabled     bool
+	AutoterminationMinutes int // 0 means auto-termination is disabled
+	SparkVersion           string
+	NumGpus                int // GPUs per worker node, 0 for CPU-only node types
+}
+
+// ClusterUtilization aggregates the node timeline metrics of a cluster over a period
+type ClusterUtilization struct {
+	ClusterID         string
+	NodeCount         int
+	NodeHours         float64
+	AvgCPUPercent     float64
+	PeakCPUPercent    float64
+	AvgMemoryPercent  float64
+	PeakMemoryPercent float64
+}
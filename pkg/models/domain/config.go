@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"time"
 )
 
 type ProfileType string
@@ -14,8 +15,66 @@ const (
 type ConfigProfile struct {
 	Name string
 	Type ProfileType
+	// Account is the account profile a workspace was discovered through, empty for profiles of the config file
+	Account string
 }
 
 func (c ConfigProfile) String() string {
 	return fmt.Sprintf("%s:%s", c.Type, c.Name)
 }
+
+// WarehousePolicy is how the SQL warehouse running the sync queries of a workspace is picked
+// when the profile does not set one
+type WarehousePolicy string
+
+const (
+	// WarehousePolicyPreferRunning picks a running warehouse so sync queries do not start one, the smallest first
+	WarehousePolicyPreferRunning WarehousePolicy = "prefer_running"
+	// WarehousePolicyPreferServerless picks a serverless warehouse, which starts in seconds, the smallest first
+	WarehousePolicyPreferServerless WarehousePolicy = "prefer_serverless"
+	// WarehousePolicySmallest picks the smallest warehouse
+	WarehousePolicySmallest WarehousePolicy = "smallest"
+)
+
+// ParseWarehousePolicy parses the policy name, an empty name is the prefer_running policy
+func ParseWarehousePolicy(name string) (WarehousePolicy, error) {
+	switch policy := WarehousePolicy(name); policy {
+	case "":
+		return WarehousePolicyPreferRunning, nil
+	case WarehousePolicyPreferRunning, WarehousePolicyPreferServerless, WarehousePolicySmallest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid warehouse policy %q. Expected prefer_running, prefer_serverless or smallest", name)
+	}
+}
+
+// SyncSettings are the settings of a profile for reading usage from the workspace
+type SyncSettings struct {
+	// WarehouseID is the SQL warehouse sync queries run on, picked by WarehousePolicy when empty
+	// or when the warehouse is deleted or stopped
+	WarehouseID     string
+	WarehousePolicy WarehousePolicy
+}
+
+// ProfileChanges are the profiles added, removed or changed by a reload of the config file
+type ProfileChanges struct {
+	Added   []ConfigProfile
+	Removed []ConfigProfile
+	Changed []ConfigProfile // profiles whose values changed, e.g. a new host or token
+}
+
+func (c ProfileChanges) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// ConfigReloadStatus is the outcome of the loads of the config file
+type ConfigReloadStatus struct {
+	Path        string
+	Watching    bool      // whether the file is watched for changes
+	LoadedAt    time.Time // last successful load
+	Reloads     int       // successful reloads since the start
+	Profiles    []ConfigProfile
+	LastChanges ProfileChanges // changes of the last successful reload
+	LastError   string         // error of the last reload, empty when it succeeded
+	LastErrorAt *time.Time
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"maps"
 	"slices"
+	"strings"
 	"time"
 )
 
@@ -59,3 +60,124 @@ var SupportedResources = map[string]string{
 }
 
 var SupportedResourcesList = slices.Collect(maps.Keys(SupportedResources))
+
+// CostDimension is a usage attribute that cost can be grouped by.
+type CostDimension string
+
+const (
+	CostDimensionResourceID   CostDimension = "resource_id"
+	CostDimensionResourceType CostDimension = "resource_type"
+	CostDimensionSKU          CostDimension = "sku"
+	CostDimensionUsageType    CostDimension = "usage_type"
+
+	// tagDimensionPrefix prefixes the tag key of tag dimensions, e.g. tag:team
+	tagDimensionPrefix = "tag:"
+)
+
+// TagDimension returns the dimension grouping cost by the value of the given custom tag
+func TagDimension(key string) CostDimension {
+	return CostDimension(tagDimensionPrefix + key)
+}
+
+// TagKey returns the tag key of a tag dimension
+func (d CostDimension) TagKey() (string, bool) {
+	return strings.CutPrefix(string(d), tagDimensionPrefix)
+}
+
+// IsValid reports whether cost can be grouped by the dimension
+func (d CostDimension) IsValid() bool {
+	switch d {
+	case CostDimensionResourceID, CostDimensionResourceType, CostDimensionSKU, CostDimensionUsageType:
+		return true
+	}
+	key, ok := d.TagKey()
+	// quotes and backslashes cannot be addressed in a JSON path, such tag keys are not supported
+	return ok && key != "" && !strings.ContainsAny(key, `"\`)
+}
+
+// CostFilter restricts cost to a single value of a dimension
+type CostFilter struct {
+	Dimension CostDimension
+	Value     string
+}
+
+// DailyCost is the cost of a single dimension value (e.g. one resource or one SKU) over one day.
+type DailyCost struct {
+	Date         time.Time
+	Dimension    CostDimension
+	Key          string // value of the dimension, e.g. resource id or sku name
+	ResourceType string
+	TotalUsage   float64
+	TotalCost    float64
+	Currency     string
+}
+
+type ComparisonStatus string
+
+const (
+	ComparisonStatusNew         ComparisonStatus = "new"         // only has cost in the compared period
+	ComparisonStatusDisappeared ComparisonStatus = "disappeared" // only has cost in the baseline period
+	ComparisonStatusChanged     ComparisonStatus = "changed"
+	ComparisonStatusUnchanged   ComparisonStatus = "unchanged"
+)
+
+// CostComparison is the cost of a single dimension value in the compared and the baseline period
+type CostComparison struct {
+	Key          string
+	ResourceType string
+	Cost         float64
+	BaselineCost float64
+	Delta        float64  // Cost - BaselineCost
+	DeltaPercent *float64 // nil when there is no baseline cost
+	Status       ComparisonStatus
+}
+
+// CostComparisonReport compares cost between two periods grouped by a dimension
+type CostComparisonReport struct {
+	Workspace         string
+	GroupBy           CostDimension
+	Period            TimePeriod
+	BaselinePeriod    TimePeriod
+	TotalCost         float64
+	BaselineTotalCost float64
+	Delta             float64
+	DeltaPercent      *float64
+	Currency          string
+	Items             []CostComparison // ordered by absolute delta, largest first
+	New               []string         // keys only present in the compared period
+	Disappeared       []string         // keys only present in the baseline period
+}
+
+// DeltaPercent returns the relative change from baseline to value in percent, nil without a baseline
+func DeltaPercent(value, baseline float64) *float64 {
+	if baseline == 0 {
+		return nil
+	}
+	p := (value - baseline) / baseline * 100
+	return &p
+}
+
+// CostDriver is one of the most expensive values of a dimension in a period
+type CostDriver struct {
+	Key           string
+	ResourceType  string
+	Cost          float64
+	Share         float64 // fraction of the total cost of the period
+	PreviousCost  float64 // cost in the preceding period of the same length
+	Change        float64
+	ChangePercent *float64  // nil when there was no cost in the preceding period
+	Daily         []float64 // cost per day of the period, oldest first
+}
+
+// CostDriversReport ranks the values of a dimension by cost
+type CostDriversReport struct {
+	Workspace      string
+	By             CostDimension
+	Period         TimePeriod
+	PreviousPeriod TimePeriod
+	TotalCost      float64
+	OtherCost      float64 // cost of all values outside the top drivers
+	Currency       string
+	Days           []time.Time // days of the Daily series
+	Drivers        []CostDriver
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// DeliveryCadence is how often a subscribed report is sent
+type DeliveryCadence string
+
+const (
+	// DeliveryCadenceWeekly sends the report of the previous week every Monday
+	DeliveryCadenceWeekly DeliveryCadence = "weekly"
+	// DeliveryCadenceMonthly sends the report of the previous month on the first day of the month
+	DeliveryCadenceMonthly DeliveryCadence = "monthly"
+	// DeliveryCadenceQuarterly sends the report of the previous quarter on the first day of the quarter
+	DeliveryCadenceQuarterly DeliveryCadence = "quarterly"
+)
+
+// ReportSubscription sends the cost report of its workspaces to a recipient by email
+type ReportSubscription struct {
+	ID         string
+	Recipient  string   // email address
+	Workspaces []string // empty for every workspace
+	Cadence    DeliveryCadence
+	Format     string // html or markdown
+	CreatedAt  time.Time
+	LastSentAt *time.Time // nil until the first report is sent
+}
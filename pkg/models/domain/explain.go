@@ -0,0 +1,25 @@
+package domain
+
+// CostContributor is a node of a cost change explanation. The root covers the whole workspace,
+// every child narrows its parent down to a single value of the parent's SplitBy dimension.
+type CostContributor struct {
+	Dimension    CostDimension // dimension of Key, empty for the root
+	Key          string
+	ResourceType string // set when the dimension is resource_id
+	Cost         float64
+	BaselineCost float64
+	Delta        float64
+	DeltaPercent *float64
+	Share        float64       // fraction of the parent delta explained by this contributor
+	SplitBy      CostDimension // dimension the children are grouped by, empty for leaves
+	Children     []CostContributor
+}
+
+// CostExplanation decomposes the cost change between two periods into a tree of contributors
+type CostExplanation struct {
+	Workspace      string
+	Period         TimePeriod
+	BaselinePeriod TimePeriod
+	Currency       string
+	Root           CostContributor
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// ForecastPoint is a point forecast with its prediction interval
+type ForecastPoint struct {
+	Date  time.Time
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// ForecastEstimate is the projected spend of a period ending at End,
+// combining the observed spend before the forecast start with the forecasted spend
+type ForecastEstimate struct {
+	End    time.Time
+	Actual float64 // observed spend in the period before the forecast start
+	Value  float64 // Actual + forecasted spend
+	Lower  float64
+	Upper  float64
+}
+
+// CostForecast is a daily cost forecast for a single dimension value (or the workspace total)
+type CostForecast struct {
+	Dimension  CostDimension // empty for the workspace total
+	Key        string
+	Currency   string
+	Points     []ForecastPoint
+	Total      ForecastEstimate // spend over the whole horizon
+	MonthEnd   ForecastEstimate // spend of the month of the forecast start, whatever the horizon
+	QuarterEnd ForecastEstimate // spend of the quarter of the forecast start, whatever the horizon
+}
+
+// CostForecastReport holds the forecasts of a workspace over a horizon
+type CostForecastReport struct {
+	Workspace       string
+	Start           time.Time // first forecasted day
+	HorizonDays     int
+	HistoryDays     int
+	ConfidenceLevel float64
+	SkippedSeries   int // series without enough history to fit a model
+	Forecasts       []CostForecast
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// JobRun represents the outcome of a job run
+type JobRun struct {
+	JobID           string
+	RunID           string
+	RunName         string
+	TriggerType     string
+	StartTime       time.Time
+	EndTime         time.Time
+	ResultState     string // SUCCEEDED, FAILED, ERROR, TIMED_OUT, CANCELLED, SKIPPED, empty while running
+	TerminationCode string
+	Retries         int // task attempts beyond the first one
+}
+
+// Duration returns the wall clock time of the run
+func (r JobRun) Duration() time.Duration {
+	return r.EndTime.Sub(r.StartTime)
+}
+
+// Failed reports whether the run ended without completing its work
+func (r JobRun) Failed() bool {
+	switch r.ResultState {
+	case "FAILED", "ERROR", "TIMED_OUT":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package domain
+
+// ServingEndpointMetadata represents the state and configuration of a model serving endpoint
+type ServingEndpointMetadata struct {
+	ID             string
+	Name           string
+	Creator        string
+	Task           string
+	Ready          string // READY or NOT_READY
+	ConfigUpdate   string // NOT_UPDATING, IN_PROGRESS, UPDATE_FAILED, UPDATE_CANCELED
+	ServedEntities []ServedEntityMetadata
+}
+
+// ServedEntityMetadata represents the compute configuration of a model served by an endpoint
+type ServedEntityMetadata struct {
+	Name                      string
+	EntityName                string
+	EntityVersion             string
+	External                  bool // external and pay-per-token foundation models have no provisioned compute
+	WorkloadSize              string
+	WorkloadType              string
+	ScaleToZeroEnabled        bool
+	MinProvisionedConcurrency int
+	MaxProvisionedConcurrency int
+	MinProvisionedThroughput  int
+	MaxProvisionedThroughput  int
+	DeploymentState           string
+	DeploymentStateMessage    string
+}
+
+// AlwaysOn reports whether the served entity keeps compute provisioned without traffic
+func (e ServedEntityMetadata) AlwaysOn() bool {
+	if e.External {
+		return false
+	}
+	return !e.ScaleToZeroEnabled || e.MinProvisionedConcurrency > 0 || e.MinProvisionedThroughput > 0
+}
+
+// ServingEndpointUsage aggregates the requests served by an endpoint over a period
+type ServingEndpointUsage struct {
+	EndpointID    string
+	EndpointName  string
+	Requests      int64
+	ErrorRequests int64
+	ActiveHours   float64 // hours with at least one request
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // WarehouseMetadata represents detailed warehouse configuration information
 type WarehouseMetadata struct {
 	ID               string
@@ -10,4 +12,101 @@ type WarehouseMetadata struct {
 	MaxNumClusters   int
 	AutoStopMins     int
 	EnableServerless bool
+	Tags             map[string]string // custom tags, attributing usage to budgets and budget policies
+}
+
+// WarehouseQueryStats aggregates the queries run on a warehouse over a period
+type WarehouseQueryStats struct {
+	WarehouseID        string
+	QueryCount         int64
+	FailedQueries      int64
+	P50DurationSeconds float64
+	P95DurationSeconds float64
+	MaxDurationSeconds float64
+	AvgQueuedSeconds   float64 // waiting for compute to start or for capacity to free up
+	ExecutionHours     float64 // sum of the execution time of all queries
+	ReadBytes          int64
+	SpilledBytes       int64 // written to local disk when queries ran out of memory
+}
+
+// ReadBytesPerQuery returns the average bytes scanned by a query
+func (s WarehouseQueryStats) ReadBytesPerQuery() float64 {
+	if s.QueryCount == 0 {
+		return 0
+	}
+	return float64(s.ReadBytes) / float64(s.QueryCount)
+}
+
+// SpillRatio returns the bytes spilled to disk per byte scanned
+func (s WarehouseQueryStats) SpillRatio() float64 {
+	if s.ReadBytes == 0 {
+		return 0
+	}
+	return float64(s.SpilledBytes) / float64(s.ReadBytes)
+}
+
+// Warehouse event types of the system.compute.warehouse_events table
+const (
+	WarehouseEventStarting   = "STARTING"
+	WarehouseEventRunning    = "RUNNING"
+	WarehouseEventScaledUp   = "SCALED_UP"
+	WarehouseEventScaledDown = "SCALED_DOWN"
+	WarehouseEventStopping   = "STOPPING"
+	WarehouseEventStopped    = "STOPPED"
+)
+
+// WarehouseEvent is a state change of a warehouse with the number of clusters running after it
+type WarehouseEvent struct {
+	WarehouseID  string
+	EventType    string
+	ClusterCount int
+	EventTime    time.Time
+}
+
+// WarehouseQueryActivity lists the minutes in which a warehouse was executing queries
+type WarehouseQueryActivity struct {
+	WarehouseID   string
+	ActiveMinutes []time.Time // start of each minute, ascending
+}
+
+// WarehouseUptimeInterval is a period in which a warehouse ran with a constant number of clusters
+type WarehouseUptimeInterval struct {
+	Start        time.Time
+	End          time.Time
+	ClusterCount int
+}
+
+// WarehouseUptime is the running time of a warehouse reconstructed from its events
+type WarehouseUptime struct {
+	WarehouseID      string
+	Intervals        []WarehouseUptimeInterval
+	IdleHours        float64 // running without executing any query
+	ActivityMeasured bool    // IdleHours is measured from the query history
+}
+
+// Hours returns the wall clock hours the warehouse was running
+func (u WarehouseUptime) Hours() float64 {
+	hours := 0.0
+	for _, interval := range u.Intervals {
+		hours += interval.End.Sub(interval.Start).Hours()
+	}
+	return hours
+}
+
+// ClusterHours returns the hours the warehouse clusters were running, summed over all clusters
+func (u WarehouseUptime) ClusterHours() float64 {
+	hours := 0.0
+	for _, interval := range u.Intervals {
+		hours += interval.End.Sub(interval.Start).Hours() * float64(interval.ClusterCount)
+	}
+	return hours
+}
+
+// PeakClusters returns the largest number of clusters the warehouse ran with
+func (u WarehouseUptime) PeakClusters() int {
+	peak := 0
+	for _, interval := range u.Intervals {
+		peak = max(peak, interval.ClusterCount)
+	}
+	return peak
 }
@@ -0,0 +1,65 @@
+package store
+
+import "time"
+
+type AuditRun struct {
+	ID            string
+	PreviousRunID string
+	Workspace     string
+	ResourceType  string
+	Scope         string
+	PeriodStart   time.Time
+	PeriodEnd     time.Time
+	CreatedAt     time.Time
+	Summary       map[string]any
+	Settings      any
+	Rules         []AuditRuleResult
+	Findings      []AuditFinding
+}
+
+type AuditRuleResult struct {
+	Rule         string  `json:"rule"`
+	ResourceType string  `json:"resource_type"`
+	Findings     int     `json:"findings"`
+	DurationMs   float64 `json:"duration_ms"`
+	Error        string  `json:"error,omitempty"`
+}
+
+type AuditFinding struct {
+	ID                  string
+	Status              string
+	Issue               string
+	Severity            int
+	Description         string
+	Recommendation      string
+	Platform            string
+	Service             string
+	ResourceName        string
+	ResourceDescription string
+	Metadata            map[string]string
+	SavingsAmount       float64
+	SavingsCurrency     string
+	SavingsConfidence   string
+	Rule                string
+}
+
+type AuditRunFilter struct {
+	Workspace    string
+	ResourceType string
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+}
+
+type AuditSuppression struct {
+	ID              string
+	Workspace       string
+	FindingID       string
+	Issue           string
+	ResourcePattern string
+	Action          string
+	Reason          string
+	Owner           string
+	CreatedAt       time.Time
+	ExpiresAt       *time.Time
+}
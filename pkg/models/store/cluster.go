@@ -0,0 +1,11 @@
+package store
+
+type ClusterUtilization struct {
+	ClusterID         string
+	NodeCount         int
+	NodeHours         float64
+	AvgCPUPercent     float64
+	PeakCPUPercent    float64
+	AvgMemoryPercent  float64
+	PeakMemoryPercent float64
+}
@@ -12,6 +12,7 @@ type UsageRecord struct {
 	ResourceID   string
 	ResourceType string
 	Metadata     map[string]string
+	Tags         map[string]string
 	Quantity     float64
 	Unit         string
 	SKU          string
@@ -22,12 +23,13 @@ type UsageRecord struct {
 }
 
 type DailyUsageAggregate struct {
-	Date       time.Time
-	Resource   string
-	TotalUsage float64
-	TotalCost  float64
-	Unit       string
-	Currency   string
+	Date         time.Time
+	Resource     string // value of the grouping dimension, e.g. resource id, resource type or sku
+	ResourceType string
+	TotalUsage   float64
+	TotalCost    float64
+	Unit         string
+	Currency     string
 }
 
 type MonthlyUsageAggregate struct {
@@ -39,3 +41,19 @@ type MonthlyUsageAggregate struct {
 	Unit       string
 	Currency   string
 }
+
+type CostComparison struct {
+	Resource     string // value of the grouping dimension, e.g. resource id, resource type or sku
+	ResourceType string
+	Cost         float64
+	BaselineCost float64
+	Currency     string
+	InPeriod     bool
+	InBaseline   bool
+}
+
+// DimensionFilter restricts usage records to a single value of a grouping dimension
+type DimensionFilter struct {
+	Dimension string
+	Value     string
+}
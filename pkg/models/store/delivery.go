@@ -0,0 +1,13 @@
+package store
+
+import "time"
+
+type ReportSubscription struct {
+	ID         string
+	Recipient  string
+	Workspaces []string
+	Cadence    string
+	Format     string
+	CreatedAt  time.Time
+	LastSentAt *time.Time
+}
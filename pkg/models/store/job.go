@@ -0,0 +1,15 @@
+package store
+
+import "time"
+
+type JobRun struct {
+	JobID           string
+	RunID           string
+	RunName         string
+	TriggerType     string
+	StartTime       time.Time
+	EndTime         time.Time
+	ResultState     string
+	TerminationCode string
+	Retries         int
+}
@@ -0,0 +1,9 @@
+package store
+
+type ServingEndpointUsage struct {
+	EndpointID    string
+	EndpointName  string
+	Requests      int64
+	ErrorRequests int64
+	ActiveHours   float64
+}
@@ -1,12 +1,40 @@
 package store
 
+import "time"
+
 type Warehouse struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Size  string `json:"size"`
-	State string `json:"state"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Size       string `json:"cluster_size"`
+	State      string `json:"state"`
+	Serverless bool   `json:"enable_serverless_compute"`
 }
 
 type WarehousesResponse struct {
 	Warehouses []Warehouse `json:"warehouses"`
 }
+
+type WarehouseQueryStats struct {
+	WarehouseID        string
+	QueryCount         int64
+	FailedQueries      int64
+	P50DurationSeconds float64
+	P95DurationSeconds float64
+	MaxDurationSeconds float64
+	AvgQueuedSeconds   float64
+	ExecutionHours     float64
+	ReadBytes          int64
+	SpilledBytes       int64
+}
+
+type WarehouseEvent struct {
+	WarehouseID  string
+	EventType    string
+	ClusterCount int
+	EventTime    time.Time
+}
+
+type WarehouseActiveMinute struct {
+	WarehouseID string
+	Minute      time.Time
+}
@@ -1,12 +1,19 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/de-tools/data-atlas/pkg/services/config"
+	"github.com/de-tools/data-atlas/pkg/services/delivery"
+	"github.com/de-tools/data-atlas/pkg/services/reporting"
 	"github.com/de-tools/data-atlas/pkg/services/workflow"
 
 	"github.com/de-tools/data-atlas/pkg/services/account"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
 
 	handlers "github.com/de-tools/data-atlas/pkg/handlers/workspace"
 
@@ -19,7 +26,17 @@ import (
 type Dependencies struct {
 	Account            account.Explorer
 	WorkflowController workflow.Controller
-	Logger             zerolog.Logger
+	AuditSettings      *workspace.AuditSettingsProvider // optional, built-in audit settings are used when nil
+	AuditHistory       workspace.AuditHistory           // optional, audit reports are not recorded when nil
+	AuditSuppressions  workspace.AuditSuppressions      // optional, findings are not suppressed when nil
+	Reports            reporting.Builder                // optional, built from the account and audit history when nil
+	Subscriptions      delivery.Service                 // optional, reports are not delivered by email when nil
+	ConfigStatus       config.StatusProvider            // optional, the config status is not exposed when nil
+
+	AnomalySettings  *workspace.AnomalyDetectionSettings // optional, built-in anomaly settings are used when nil
+	ForecastSettings *workspace.ForecastSettings         // optional, built-in forecast settings are used when nil
+	ExplainSettings  *workspace.CostExplanationSettings  // optional, built-in explain settings are used when nil
+	Logger           zerolog.Logger
 }
 type Config struct {
 	Addr            string
@@ -37,8 +54,56 @@ func ConfigureRouter(config Config) *chi.Mux {
 		w.Write([]byte("hello world"))
 	})
 
-	workspaces := handlers.NewWorkspaceRouter(config.Dependencies.Account, config.Dependencies.WorkflowController)
+	workspaces := handlers.NewWorkspaceRouter(handlers.Dependencies{
+		Explorer:           config.Dependencies.Account,
+		WorkflowController: config.Dependencies.WorkflowController,
+		AuditSettings:      config.Dependencies.AuditSettings,
+		AuditHistory:       config.Dependencies.AuditHistory,
+		AuditSuppressions:  config.Dependencies.AuditSuppressions,
+		Reports:            config.Dependencies.Reports,
+		Subscriptions:      config.Dependencies.Subscriptions,
+		ConfigStatus:       config.Dependencies.ConfigStatus,
+		AnomalySettings:    config.Dependencies.AnomalySettings,
+		ForecastSettings:   config.Dependencies.ForecastSettings,
+		ExplainSettings:    config.Dependencies.ExplainSettings,
+	})
 	router.Mount("/api/v1", workspaces.Routes())
 
 	return router
 }
+
+// ListenAndServe serves the router on config.Addr until ctx is done,
+// then waits up to config.ShutdownTimeout for the in-flight requests to complete
+func ListenAndServe(ctx context.Context, config Config) error {
+	srv := &http.Server{
+		Addr:    config.Addr,
+		Handler: ConfigureRouter(config),
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	if config.ShutdownTimeout <= 0 {
+		// in-flight requests are not waited for
+		_ = srv.Close()
+		<-serveErr
+		return nil
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the server: %w", err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/config"
+	dbsql "github.com/databricks/databricks-sql-go"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/rs/zerolog"
+)
+
+// warehouseConnector opens Databricks SQL connections through a SQL warehouse of the workspace.
+// The warehouse is resolved when a connection is opened, so creating a database handle does not call
+// the Databricks API, and every request authenticates with a fresh token of the profile.
+// The warehouse is picked by the sync settings, and a connection fails over to the next candidate
+// when the warehouse cannot be connected to, e.g. after it was deleted.
+type warehouseConnector struct {
+	cfg      *config.Config
+	settings domain.SyncSettings
+}
+
+// newWarehouseDB returns a database handle for querying the workspace system tables. Idle connections
+// are closed after a while so the pool does not hold warehouse sessions between syncs.
+func newWarehouseDB(cfg *config.Config, settings domain.SyncSettings) *sql.DB {
+	db := sql.OpenDB(&warehouseConnector{cfg: cfg, settings: settings})
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxIdleTime(15 * time.Minute)
+	return db
+}
+
+// tokenAuthenticator authenticates Databricks SQL requests with a token of the profile. OAuth tokens come from
+// the token source of the config, which refreshes them before they expire, so long syncs keep working.
+type tokenAuthenticator struct {
+	cfg *config.Config
+}
+
+func (a *tokenAuthenticator) Authenticate(req *http.Request) error {
+	token, err := getToken(req.Context(), a.cfg)
+	if err != nil {
+		return fmt.Errorf("getting token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (c *warehouseConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	logger := zerolog.Ctx(ctx)
+
+	warehouses, err := listWarehouses(ctx, c.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("listing warehouses: %w", err)
+	}
+	candidates := warehouseCandidates(warehouses, c.settings)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no warehouses found for host %s", c.cfg.Host)
+	}
+	if c.settings.WarehouseID != "" && candidates[0].ID != c.settings.WarehouseID {
+		logger.Warn().Msgf("warehouse %s of host %s is deleted or stopped, failing over to warehouse %s",
+			c.settings.WarehouseID, c.cfg.Host, candidates[0].ID)
+	}
+
+	hostname, port, err := splitHostPort(c.cfg.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, warehouse := range candidates {
+		connector, err := dbsql.NewConnector(
+			dbsql.WithServerHostname(hostname),
+			dbsql.WithPort(port),
+			dbsql.WithHTTPPath(fmt.Sprintf("/sql/1.0/warehouses/%s", warehouse.ID)),
+			dbsql.WithAuthenticator(&tokenAuthenticator{cfg: c.cfg}),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating connector: %w", err)
+		}
+
+		conn, err := connector.Connect(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logger.Warn().Err(err).Msgf("failed to connect to warehouse %s of host %s", warehouse.ID, c.cfg.Host)
+		errs = append(errs, fmt.Errorf("warehouse %s: %w", warehouse.ID, err))
+	}
+	return nil, fmt.Errorf("connecting to warehouses of host %s: %w", c.cfg.Host, errors.Join(errs...))
+}
+
+func (c *warehouseConnector) Driver() driver.Driver {
+	connector, _ := dbsql.NewConnector()
+	return connector.Driver()
+}
+
+// splitHostPort removes any protocol prefix from the host and returns the hostname and the port (443 by default)
+func splitHostPort(host string) (string, int, error) {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+
+	hostname, rawPort, found := strings.Cut(host, ":")
+	if !found {
+		return hostname, 443, nil
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in host %s: %w", host, err)
+	}
+	return hostname, port, nil
+}
@@ -4,22 +4,30 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/de-tools/data-atlas/pkg/store/databrickssdk/client"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/compute"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/lakeflow"
 	"github.com/de-tools/data-atlas/pkg/store/databrickssql/pricing"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/query"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/serving"
 	databricksusage "github.com/de-tools/data-atlas/pkg/store/databrickssql/usage"
-	"github.com/de-tools/data-atlas/pkg/store/duckdb"
 	duckdbusage "github.com/de-tools/data-atlas/pkg/store/duckdb/usage"
 
+	"github.com/databricks/databricks-sdk-go"
 	"github.com/databricks/databricks-sdk-go/config"
-	_ "github.com/databricks/databricks-sql-go" // Required for databricks sql
 	"github.com/de-tools/data-atlas/pkg/models/domain"
 	"github.com/de-tools/data-atlas/pkg/models/store"
 	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
 	dataatlasconfig "github.com/de-tools/data-atlas/pkg/services/config"
+	"github.com/rs/zerolog"
 )
 
 type Explorer interface {
@@ -27,16 +35,71 @@ type Explorer interface {
 	GetWorkspaceExplorer(ctx context.Context, ws domain.Workspace) (workspace.Explorer, error)
 	// GetWorkspaceCostManagerCached returns a DuckDB-backed cost manager
 	GetWorkspaceCostManagerCached(ctx context.Context, ws domain.Workspace) (workspace.CostManager, error)
-	// GetWorkspaceCostManagerRemote returns a Databricks-backed cost manager
+	// GetWorkspaceCostManagerRemote returns a Databricks-backed cost manager of the usage of the workspace
 	GetWorkspaceCostManagerRemote(ctx context.Context, ws domain.Workspace) (workspace.CostManager, error)
+	// GetAccountCostManagerRemote returns a Databricks-backed cost manager of the usage of every workspace
+	// of the account, records keep their workspace id in the workspace_id metadata
+	GetAccountCostManagerRemote(ctx context.Context) (workspace.CostManager, error)
+	// GetWorkspaceID returns the id of the workspace, as used in the system tables
+	GetWorkspaceID(ctx context.Context, ws domain.Workspace) (string, error)
+	// GetWorkspaceCostAnalyzer returns a DuckDB-backed cost analyzer
+	GetWorkspaceCostAnalyzer(ctx context.Context, ws domain.Workspace) (workspace.CostAnalyzer, error)
+	// Close closes the remote connections pooled per workspace
+	Close() error
+}
+
+// Settings are the dependencies of the explorer shared by every workspace
+type Settings struct {
+	// CacheDB is the DuckDB database the usage is synced to
+	CacheDB *sql.DB
+	// Prices prices the usage read from the system tables, the default prices when nil
+	Prices pricing.Store
 }
 
 type accountExplorer struct {
 	registry dataatlasconfig.Registry
+	cacheDB  *sql.DB
+	prices   pricing.Store
+	// openDB opens the connection pool of a workspace, newWarehouseDB outside of tests
+	openDB func(cfg *config.Config, settings domain.SyncSettings) *sql.DB
+
+	// the caches are keyed by profile name and keep the config they were created for,
+	// entries are replaced when the registry reloads the profile with another config
+	mu             sync.Mutex
+	workspaceIDs   map[string]cachedWorkspaceID
+	warehouseDBs   map[string]pooledWarehouseDB
+	accountClients map[string]cachedAccountClient
+}
+
+type cachedWorkspaceID struct {
+	cfg *config.Config
+	id  string
+}
+
+type pooledWarehouseDB struct {
+	cfg *config.Config
+	db  *sql.DB
 }
 
-func NewExplorer(registry dataatlasconfig.Registry) Explorer {
-	return &accountExplorer{registry: registry}
+type cachedAccountClient struct {
+	cfg    *config.Config
+	client *client.Client
+}
+
+func NewExplorer(registry dataatlasconfig.Registry, settings Settings) Explorer {
+	prices := settings.Prices
+	if prices == nil {
+		prices = pricing.NewStore()
+	}
+	return &accountExplorer{
+		registry:       registry,
+		cacheDB:        settings.CacheDB,
+		prices:         prices,
+		openDB:         newWarehouseDB,
+		workspaceIDs:   make(map[string]cachedWorkspaceID),
+		warehouseDBs:   make(map[string]pooledWarehouseDB),
+		accountClients: make(map[string]cachedAccountClient),
+	}
 }
 
 func (a *accountExplorer) ListWorkspaces(ctx context.Context) ([]domain.Workspace, error) {
@@ -46,18 +109,101 @@ func (a *accountExplorer) ListWorkspaces(ctx context.Context) ([]domain.Workspac
 	}
 	var workspaces []domain.Workspace
 	for _, profile := range profiles {
+		if profile.Type != domain.ProfileTypeWorkspace {
+			continue
+		}
 		workspaces = append(workspaces, domain.Workspace{Name: profile.Name})
 	}
 	return workspaces, nil
 }
 
 func (a *accountExplorer) GetWorkspaceExplorer(ctx context.Context, ws domain.Workspace) (workspace.Explorer, error) {
-	cfg, err := a.registry.GetConfig(ctx, domain.ConfigProfile{Name: ws.Name, Type: domain.ProfileTypeWorkspace})
+	// the system tables hold the telemetry of every workspace of the account, so their readers are
+	// restricted to the workspace id; without it they fail and only the rules reading them do
+	workspaceID, err := a.GetWorkspaceID(ctx, ws)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msgf("failed to resolve the id of workspace %s, skipping its system tables", ws.Name)
+	}
+	cfg, db, err := a.warehouseDB(ctx, ws)
 	if err != nil {
 		return nil, err
 	}
 
-	return workspace.NewExplorer(cfg, ws), nil
+	return workspace.NewExplorer(cfg, ws, compute.NewStore(db, workspaceID), serving.NewStore(db, workspaceID),
+		lakeflow.NewStore(db, workspaceID), query.NewStore(db, workspaceID), a.budgetStore(ctx, ws)), nil
+}
+
+// budgetStore returns a client of the account profile of the workspace to read budgets with,
+// nil when the account is unknown, as the budgets of another account would not apply to the workspace
+func (a *accountExplorer) budgetStore(ctx context.Context, ws domain.Workspace) workspace.BudgetStore {
+	logger := zerolog.Ctx(ctx)
+
+	account, err := a.accountProfile(ctx, ws)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("account of workspace %s is unknown, skipping its budget checks", ws.Name)
+		return nil
+	}
+	cfg, err := a.registry.GetConfig(ctx, account)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("failed to get config of account profile %s", account.Name)
+		return nil
+	}
+
+	a.mu.Lock()
+	cached, ok := a.accountClients[account.Name]
+	a.mu.Unlock()
+	if ok && cached.cfg == cfg {
+		return cached.client
+	}
+	accountClient, err := client.NewAccountClient(cfg)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("failed to create account client for profile %s", account.Name)
+		return nil
+	}
+
+	a.mu.Lock()
+	a.accountClients[account.Name] = cachedAccountClient{cfg: cfg, client: accountClient}
+	a.mu.Unlock()
+	return accountClient
+}
+
+// accountProfile returns the account profile the workspace was discovered through,
+// or the one of the account id set in the workspace profile
+func (a *accountExplorer) accountProfile(ctx context.Context, ws domain.Workspace) (domain.ConfigProfile, error) {
+	profiles, err := a.registry.GetProfiles(ctx)
+	if err != nil {
+		return domain.ConfigProfile{}, err
+	}
+
+	var accountName, accountID string
+	for _, profile := range profiles {
+		if profile.Type == domain.ProfileTypeWorkspace && profile.Name == ws.Name {
+			accountName = profile.Account
+			break
+		}
+	}
+	if accountName == "" {
+		cfg, err := a.registry.GetConfig(ctx, domain.ConfigProfile{Name: ws.Name, Type: domain.ProfileTypeWorkspace})
+		if err != nil {
+			return domain.ConfigProfile{}, err
+		}
+		accountID = cfg.AccountID
+	}
+
+	for _, profile := range profiles {
+		if profile.Type != domain.ProfileTypeAccount {
+			continue
+		}
+		if accountName != "" && profile.Name == accountName {
+			return profile, nil
+		}
+		if accountName == "" && accountID != "" {
+			if cfg, err := a.registry.GetConfig(ctx, profile); err == nil && cfg.AccountID == accountID {
+				return profile, nil
+			}
+		}
+	}
+	return domain.ConfigProfile{}, fmt.Errorf("no account profile of workspace %s", ws.Name)
 }
 
 func (a *accountExplorer) GetWorkspaceCostManagerCached(
@@ -65,58 +211,159 @@ func (a *accountExplorer) GetWorkspaceCostManagerCached(
 	ws domain.Workspace,
 ) (workspace.CostManager, error) {
 	// DuckDB-backed CostManager for API read paths
-	db, err := duckdb.NewDB(duckdb.Settings{DbPath: "data-atlas.db"})
+	usageStore, err := a.cachedUsageStore(ws)
+	if err != nil {
+		return nil, err
+	}
+	return workspace.NewCostManager(usageStore), nil
+}
+
+func (a *accountExplorer) GetWorkspaceCostAnalyzer(
+	ctx context.Context,
+	ws domain.Workspace,
+) (workspace.CostAnalyzer, error) {
+	usageStore, err := a.cachedUsageStore(ws)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create DuckDB instance: %w", err)
+		return nil, err
 	}
-	usageStore, err := duckdbusage.NewWorkspaceStore(db, ws.Name)
+	return workspace.NewCostAnalyzer(usageStore), nil
+}
+
+func (a *accountExplorer) cachedUsageStore(ws domain.Workspace) (duckdbusage.Store, error) {
+	usageStore, err := duckdbusage.NewWorkspaceStore(a.cacheDB, ws.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create DuckDB usage store: %w", err)
 	}
-	return workspace.NewCostManager(usageStore), nil
+	return usageStore, nil
 }
 
 func (a *accountExplorer) GetWorkspaceCostManagerRemote(
 	ctx context.Context,
 	ws domain.Workspace,
 ) (workspace.CostManager, error) {
-	cfg, err := a.registry.GetConfig(ctx, domain.ConfigProfile{Name: ws.Name, Type: domain.ProfileTypeWorkspace})
+	workspaceID, err := a.GetWorkspaceID(ctx, ws)
 	if err != nil {
 		return nil, err
 	}
 
-	warehouses, err := listWarehouses(ctx, cfg)
+	_, db, err := a.warehouseDB(ctx, ws)
 	if err != nil {
-		return nil, fmt.Errorf("listing warehouses: %w", err)
+		return nil, err
 	}
 
-	if len(warehouses) == 0 {
-		return nil, fmt.Errorf("no warehouses found for workspace %s", ws.Name)
+	usageStore := databricksusage.NewWorkspaceStore(db, a.prices, workspaceID)
+	costManager := workspace.NewCostManager(usageStore)
+	return costManager, nil
+}
+
+// GetAccountCostManagerRemote reads the account-wide billing through a warehouse of the first workspace by name
+func (a *accountExplorer) GetAccountCostManagerRemote(ctx context.Context) (workspace.CostManager, error) {
+	workspaces, err := a.ListWorkspaces(ctx)
+	if err != nil {
+		return nil, err
 	}
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("no workspaces configured to read the account usage through")
+	}
+	sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Name < workspaces[j].Name })
 
-	defaultWarehouse := warehouses[0]
-	// Prepare the DSN for the Databricks SQL connection, removing any protocol prefix
-	host := strings.TrimPrefix(strings.TrimPrefix(cfg.Host, "https://"), "http://")
-	// Check if the host already has a port number, if not, add the default port for HTTPS
-	if !strings.Contains(host, ":") {
-		host = host + ":443"
+	_, db, err := a.warehouseDB(ctx, workspaces[0])
+	if err != nil {
+		return nil, err
 	}
 
-	token, err := getToken(ctx, cfg)
+	usageStore := databricksusage.NewStore(db, a.prices)
+	return workspace.NewCostManager(usageStore), nil
+}
+
+// warehouseDB returns the config of the workspace and the connection pool to its SQL warehouses,
+// the pool is created on first use and reused by every explorer and cost manager of the workspace
+func (a *accountExplorer) warehouseDB(ctx context.Context, ws domain.Workspace) (*config.Config, *sql.DB, error) {
+	profile := domain.ConfigProfile{Name: ws.Name, Type: domain.ProfileTypeWorkspace}
+	cfg, err := a.registry.GetConfig(ctx, profile)
 	if err != nil {
-		return nil, fmt.Errorf("getting token: %w", err)
+		return nil, nil, err
 	}
 
-	dsn := fmt.Sprintf("token:%s@%s%s", token, host, fmt.Sprintf("/sql/1.0/warehouses/%s", defaultWarehouse.ID))
+	a.mu.Lock()
+	pooled, ok := a.warehouseDBs[ws.Name]
+	a.mu.Unlock()
+	if ok && pooled.cfg == cfg {
+		return cfg, pooled.db, nil
+	}
 
-	db, err := sql.Open("databricks", dsn)
+	syncSettings, err := a.registry.GetSyncSettings(ctx, profile)
 	if err != nil {
-		log.Fatalf("failed to connect to Databricks: %v", err)
+		return nil, nil, err
 	}
 
-	usageStore := databricksusage.NewStore(db, pricing.NewStore())
-	costManager := workspace.NewCostManager(usageStore)
-	return costManager, nil
+	a.mu.Lock()
+	pooled, ok = a.warehouseDBs[ws.Name]
+	if ok && pooled.cfg == cfg {
+		// another caller opened the pool of the config meanwhile
+		a.mu.Unlock()
+		return cfg, pooled.db, nil
+	}
+	db := a.openDB(cfg, syncSettings)
+	a.warehouseDBs[ws.Name] = pooledWarehouseDB{cfg: cfg, db: db}
+	a.mu.Unlock()
+
+	if ok {
+		// the profile was reloaded, closing the old pool waits for its running queries,
+		// so it is closed in the background without holding up the callers
+		logger := zerolog.Ctx(ctx)
+		go func() {
+			if err := pooled.db.Close(); err != nil {
+				logger.Warn().Err(err).Msgf("failed to close connections of workspace %s", ws.Name)
+			}
+		}()
+	}
+	return cfg, db, nil
+}
+
+// Close closes the pooled warehouse connections
+func (a *accountExplorer) Close() error {
+	a.mu.Lock()
+	pools := a.warehouseDBs
+	a.warehouseDBs = make(map[string]pooledWarehouseDB)
+	a.mu.Unlock()
+
+	var errs []error
+	for name, pooled := range pools {
+		if err := pooled.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing connections of workspace %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetWorkspaceID resolves the id of the workspace from the workspace API, ids are cached by workspace name
+func (a *accountExplorer) GetWorkspaceID(ctx context.Context, ws domain.Workspace) (string, error) {
+	cfg, err := a.registry.GetConfig(ctx, domain.ConfigProfile{Name: ws.Name, Type: domain.ProfileTypeWorkspace})
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	cached, ok := a.workspaceIDs[ws.Name]
+	a.mu.Unlock()
+	if ok && cached.cfg == cfg {
+		return cached.id, nil
+	}
+	client, err := databricks.NewWorkspaceClient((*databricks.Config)(cfg))
+	if err != nil {
+		return "", fmt.Errorf("creating workspace client: %w", err)
+	}
+	id, err := client.CurrentWorkspaceID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolving id of workspace %s: %w", ws.Name, err)
+	}
+
+	workspaceID := strconv.FormatInt(id, 10)
+	a.mu.Lock()
+	a.workspaceIDs[ws.Name] = cachedWorkspaceID{cfg: cfg, id: workspaceID}
+	a.mu.Unlock()
+	return workspaceID, nil
 }
 
 func listWarehouses(ctx context.Context, cfg *config.Config) ([]store.Warehouse, error) {
@@ -0,0 +1,276 @@
+package account
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/databricks/databricks-sdk-go/config"
+	"github.com/databricks/databricks-sdk-go/config/experimental/auth"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	dataatlasconfig "github.com/de-tools/data-atlas/pkg/services/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// fakeConnector opens connections that do nothing, counting them
+type fakeConnector struct {
+	connects atomic.Int32
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	c.connects.Add(1)
+	return fakeConn{}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver { return nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+// newTestExplorer returns an explorer whose workspace pools connect through fake connectors
+func newTestExplorer(registry dataatlasconfig.Registry) (*accountExplorer, *fakeConnector) {
+	connector := &fakeConnector{}
+	explorer := NewExplorer(registry, Settings{}).(*accountExplorer)
+	explorer.openDB = func(*config.Config, domain.SyncSettings) *sql.DB {
+		return sql.OpenDB(connector)
+	}
+	return explorer, connector
+}
+
+func TestWarehouseDB_ReloadKeepsUnchangedPools(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), ".databrickscfg")
+	content := `
+[dev]
+host  = https://dev.cloud.databricks.com
+token = dapi-dev
+
+[prod]
+host  = https://prod.cloud.databricks.com
+token = dapi-prod
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	registry, err := dataatlasconfig.NewRegistry(path)
+	require.NoError(t, err)
+	require.NoError(t, registry.Init(ctx))
+	explorer, _ := newTestExplorer(registry)
+
+	_, dev, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+	require.NoError(t, err)
+	_, prod, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "prod"})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(content+`
+[staging]
+host  = https://staging.cloud.databricks.com
+token = dapi-staging
+`), 0o600))
+	_, err = registry.Reload(ctx)
+	require.NoError(t, err)
+
+	_, reloaded, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+	require.NoError(t, err)
+	assert.Same(t, dev, reloaded, "unchanged profiles keep their pool")
+	assert.NoError(t, dev.PingContext(ctx), "the pool of an unchanged profile stays open")
+	assert.NoError(t, prod.PingContext(ctx))
+}
+
+// fakeRegistry serves the configs of profiles by name, every profile is a workspace profile unless profiles is set
+type fakeRegistry struct {
+	configs  map[string]*config.Config
+	profiles []domain.ConfigProfile
+}
+
+func (r *fakeRegistry) GetProfiles(context.Context) ([]domain.ConfigProfile, error) {
+	if r.profiles != nil {
+		return r.profiles, nil
+	}
+	profiles := make([]domain.ConfigProfile, 0, len(r.configs))
+	for name := range r.configs {
+		profiles = append(profiles, domain.ConfigProfile{Name: name, Type: domain.ProfileTypeWorkspace})
+	}
+	return profiles, nil
+}
+
+func (r *fakeRegistry) GetConfig(_ context.Context, profile domain.ConfigProfile) (*config.Config, error) {
+	cfg, ok := r.configs[profile.Name]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found", profile.Name)
+	}
+	return cfg, nil
+}
+
+func (r *fakeRegistry) GetSyncSettings(context.Context, domain.ConfigProfile) (domain.SyncSettings, error) {
+	return domain.SyncSettings{WarehousePolicy: domain.WarehousePolicyPreferRunning}, nil
+}
+
+func TestWarehouseDB_PoolPerWorkspace(t *testing.T) {
+	ctx := context.Background()
+	registry := &fakeRegistry{configs: map[string]*config.Config{
+		"dev":  {Host: "https://dev.cloud.databricks.com", Token: "dapi-dev"},
+		"prod": {Host: "https://prod.cloud.databricks.com", Token: "dapi-prod"},
+	}}
+	explorer, connector := newTestExplorer(registry)
+
+	_, dev, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+	require.NoError(t, err)
+	_, reused, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+	require.NoError(t, err)
+	assert.Same(t, dev, reused, "the pool of a workspace is reused")
+
+	_, prod, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "prod"})
+	require.NoError(t, err)
+	assert.NotSame(t, dev, prod, "every workspace has its own pool")
+
+	require.NoError(t, dev.PingContext(ctx))
+	require.NoError(t, dev.PingContext(ctx))
+	assert.Equal(t, int32(1), connector.connects.Load(), "idle connections are reused")
+
+	t.Run("changed config replaces the pool", func(t *testing.T) {
+		registry.configs["dev"] = &config.Config{Host: "https://dev.cloud.databricks.com", Token: "dapi-rotated"}
+
+		cfg, replaced, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+		require.NoError(t, err)
+		assert.Equal(t, "dapi-rotated", cfg.Token)
+		assert.NotSame(t, dev, replaced)
+		assert.Eventually(t, func() bool { return dev.PingContext(ctx) != nil }, time.Second, time.Millisecond,
+			"the old pool is closed in the background")
+		assert.NoError(t, replaced.PingContext(ctx))
+		assert.NoError(t, prod.PingContext(ctx), "pools of other workspaces are kept")
+	})
+
+	t.Run("a busy old pool does not block the other workspaces", func(t *testing.T) {
+		_, busy, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+		require.NoError(t, err)
+		conn, err := busy.Conn(ctx)
+		require.NoError(t, err)
+
+		registry.configs["dev"] = &config.Config{Host: "https://dev.cloud.databricks.com", Token: "dapi-rotated-again"}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+			assert.NoError(t, err)
+			_, _, err = explorer.warehouseDB(ctx, domain.Workspace{Name: "prod"})
+			assert.NoError(t, err)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("replacing a pool with a connection in use blocked the explorer")
+		}
+		require.NoError(t, conn.Close())
+	})
+
+	t.Run("close closes every pool", func(t *testing.T) {
+		_, dev, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+		require.NoError(t, err)
+
+		require.NoError(t, explorer.Close())
+		assert.ErrorContains(t, dev.PingContext(ctx), "database is closed")
+		assert.ErrorContains(t, prod.PingContext(ctx), "database is closed")
+
+		_, reopened, err := explorer.warehouseDB(ctx, domain.Workspace{Name: "dev"})
+		require.NoError(t, err)
+		assert.NoError(t, reopened.PingContext(ctx), "pools are opened again after close")
+	})
+}
+
+func TestBudgetStore_AccountOfWorkspace(t *testing.T) {
+	ctx := context.Background()
+	registry := &fakeRegistry{
+		configs: map[string]*config.Config{
+			"acme":       {Host: "https://accounts.cloud.databricks.com", AccountID: "acme-id", Token: "dapi-acme"},
+			"other":      {Host: "https://accounts.cloud.databricks.com", AccountID: "other-id", Token: "dapi-other"},
+			"discovered": {Host: "https://discovered.cloud.databricks.com", Token: "dapi-discovered"},
+			"configured": {Host: "https://configured.cloud.databricks.com", AccountID: "acme-id", Token: "dapi-configured"},
+			"unknown":    {Host: "https://unknown.cloud.databricks.com", Token: "dapi-unknown"},
+		},
+		profiles: []domain.ConfigProfile{
+			{Name: "other", Type: domain.ProfileTypeAccount},
+			{Name: "acme", Type: domain.ProfileTypeAccount},
+			{Name: "discovered", Type: domain.ProfileTypeWorkspace, Account: "acme"},
+			{Name: "configured", Type: domain.ProfileTypeWorkspace},
+			{Name: "unknown", Type: domain.ProfileTypeWorkspace},
+		},
+	}
+	explorer, _ := newTestExplorer(registry)
+
+	discovered := explorer.budgetStore(ctx, domain.Workspace{Name: "discovered"})
+	require.NotNil(t, discovered, "the account a workspace was discovered through is used")
+	assert.Same(t, explorer.accountClients["acme"].client, discovered)
+	assert.Same(t, discovered, explorer.budgetStore(ctx, domain.Workspace{Name: "discovered"}),
+		"the account client is created once")
+
+	assert.Same(t, discovered, explorer.budgetStore(ctx, domain.Workspace{Name: "configured"}),
+		"the account id of the workspace profile selects the account")
+	assert.Nil(t, explorer.budgetStore(ctx, domain.Workspace{Name: "unknown"}),
+		"budgets of another account are not checked")
+	assert.NotContains(t, explorer.accountClients, "other")
+
+	registry.configs["acme"] = &config.Config{
+		Host: "https://accounts.cloud.databricks.com", AccountID: "acme-id", Token: "dapi-rotated",
+	}
+	assert.NotSame(t, discovered, explorer.budgetStore(ctx, domain.Workspace{Name: "discovered"}),
+		"a changed config replaces the account client")
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	t.Run("personal access token", func(t *testing.T) {
+		authenticator := &tokenAuthenticator{cfg: &config.Config{Host: "https://dev.cloud.databricks.com", Token: "dapi-dev"}}
+		req := httptest.NewRequest(http.MethodPost, "https://dev.cloud.databricks.com/sql/1.0/warehouses/1", nil)
+
+		require.NoError(t, authenticator.Authenticate(req))
+		assert.Equal(t, "Bearer dapi-dev", req.Header.Get("Authorization"))
+	})
+
+	t.Run("a new token is fetched once the previous one expired", func(t *testing.T) {
+		var fetched atomic.Int32
+		tokens := auth.TokenSourceFn(func(context.Context) (*oauth2.Token, error) {
+			n := fetched.Add(1)
+			// already expired, so the config token cache refreshes it on the next request
+			return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", n), Expiry: time.Now().Add(-time.Minute)}, nil
+		})
+		authenticator := &tokenAuthenticator{cfg: &config.Config{
+			Host:        "https://dev.cloud.databricks.com",
+			Credentials: config.NewTokenSourceStrategy("test", tokens),
+		}}
+
+		var headers []string
+		for range 2 {
+			req := httptest.NewRequest(http.MethodPost, "https://dev.cloud.databricks.com/sql/1.0/warehouses/1", nil)
+			require.NoError(t, authenticator.Authenticate(req))
+			headers = append(headers, req.Header.Get("Authorization"))
+		}
+		assert.NotEqual(t, headers[0], headers[1], "every request authenticates with the current token")
+		assert.Equal(t, fmt.Sprintf("Bearer token-%d", fetched.Load()), headers[1])
+	})
+
+	t.Run("token source error", func(t *testing.T) {
+		tokens := auth.TokenSourceFn(func(context.Context) (*oauth2.Token, error) {
+			return nil, errors.New("expired client secret")
+		})
+		authenticator := &tokenAuthenticator{cfg: &config.Config{
+			Host:        "https://dev.cloud.databricks.com",
+			Credentials: config.NewTokenSourceStrategy("test", tokens),
+		}}
+		req := httptest.NewRequest(http.MethodPost, "https://dev.cloud.databricks.com/sql/1.0/warehouses/1", nil)
+
+		assert.ErrorContains(t, authenticator.Authenticate(req), "expired client secret")
+		assert.Empty(t, req.Header.Get("Authorization"))
+	})
+}
@@ -0,0 +1,101 @@
+package account
+
+import (
+	"sort"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+)
+
+// warehouseSizes ranks the SQL warehouse cluster sizes from the smallest
+var warehouseSizes = map[string]int{
+	"2X-Small": 0,
+	"X-Small":  1,
+	"Small":    2,
+	"Medium":   3,
+	"Large":    4,
+	"X-Large":  5,
+	"2X-Large": 6,
+	"3X-Large": 7,
+	"4X-Large": 8,
+}
+
+// warehouseCandidates orders the warehouses queries can run on, the first one is used and the next ones
+// are failed over to. The configured warehouse comes first while it is running. A stopped configured warehouse
+// comes after the running ones so queries do not start it, and deleted warehouses are left out.
+func warehouseCandidates(warehouses []store.Warehouse, settings domain.SyncSettings) []store.Warehouse {
+	var configured *store.Warehouse
+	others := make([]store.Warehouse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		if isWarehouseDeleted(warehouse) {
+			continue
+		}
+		if settings.WarehouseID != "" && warehouse.ID == settings.WarehouseID {
+			configured = &warehouse
+			continue
+		}
+		others = append(others, warehouse)
+	}
+	sortWarehouses(others, settings.WarehousePolicy)
+
+	if configured == nil {
+		return others
+	}
+	if isWarehouseRunning(*configured) {
+		return append([]store.Warehouse{*configured}, others...)
+	}
+
+	candidates := make([]store.Warehouse, 0, len(others)+1)
+	for _, warehouse := range others {
+		if isWarehouseRunning(warehouse) {
+			candidates = append(candidates, warehouse)
+		}
+	}
+	candidates = append(candidates, *configured)
+	for _, warehouse := range others {
+		if !isWarehouseRunning(warehouse) {
+			candidates = append(candidates, warehouse)
+		}
+	}
+	return candidates
+}
+
+// sortWarehouses orders the warehouses by the policy, ties are broken by size and name
+func sortWarehouses(warehouses []store.Warehouse, policy domain.WarehousePolicy) {
+	sort.SliceStable(warehouses, func(i, j int) bool {
+		a, b := warehouses[i], warehouses[j]
+		switch policy {
+		case domain.WarehousePolicyPreferServerless:
+			if a.Serverless != b.Serverless {
+				return a.Serverless
+			}
+			if isWarehouseRunning(a) != isWarehouseRunning(b) {
+				return isWarehouseRunning(a)
+			}
+		case domain.WarehousePolicySmallest:
+		default:
+			if isWarehouseRunning(a) != isWarehouseRunning(b) {
+				return isWarehouseRunning(a)
+			}
+		}
+		if warehouseSizeRank(a) != warehouseSizeRank(b) {
+			return warehouseSizeRank(a) < warehouseSizeRank(b)
+		}
+		return a.Name < b.Name
+	})
+}
+
+func warehouseSizeRank(warehouse store.Warehouse) int {
+	if rank, ok := warehouseSizes[warehouse.Size]; ok {
+		return rank
+	}
+	return len(warehouseSizes)
+}
+
+func isWarehouseRunning(warehouse store.Warehouse) bool {
+	return warehouse.State == "RUNNING" || warehouse.State == "STARTING"
+}
+
+func isWarehouseDeleted(warehouse store.Warehouse) bool {
+	return warehouse.State == "DELETED" || warehouse.State == "DELETING"
+}
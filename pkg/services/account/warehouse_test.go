@@ -0,0 +1,75 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarehouseCandidates(t *testing.T) {
+	warehouses := []store.Warehouse{
+		{ID: "large-running", Name: "etl", Size: "Large", State: "RUNNING"},
+		{ID: "small-stopped", Name: "adhoc", Size: "Small", State: "STOPPED"},
+		{ID: "xsmall-serverless", Name: "bi", Size: "X-Small", State: "STOPPED", Serverless: true},
+		{ID: "medium-running", Name: "reporting", Size: "Medium", State: "RUNNING"},
+		{ID: "tiny-deleted", Name: "old", Size: "2X-Small", State: "DELETED"},
+	}
+
+	tests := []struct {
+		name     string
+		settings domain.SyncSettings
+		expected []string
+	}{
+		{
+			name:     "prefer running",
+			settings: domain.SyncSettings{WarehousePolicy: domain.WarehousePolicyPreferRunning},
+			expected: []string{"medium-running", "large-running", "xsmall-serverless", "small-stopped"},
+		},
+		{
+			name:     "prefer serverless",
+			settings: domain.SyncSettings{WarehousePolicy: domain.WarehousePolicyPreferServerless},
+			expected: []string{"xsmall-serverless", "medium-running", "large-running", "small-stopped"},
+		},
+		{
+			name:     "smallest",
+			settings: domain.SyncSettings{WarehousePolicy: domain.WarehousePolicySmallest},
+			expected: []string{"xsmall-serverless", "small-stopped", "medium-running", "large-running"},
+		},
+		{
+			name: "configured running warehouse first",
+			settings: domain.SyncSettings{
+				WarehouseID:     "large-running",
+				WarehousePolicy: domain.WarehousePolicySmallest,
+			},
+			expected: []string{"large-running", "xsmall-serverless", "small-stopped", "medium-running"},
+		},
+		{
+			name: "fail over from a stopped configured warehouse to running ones",
+			settings: domain.SyncSettings{
+				WarehouseID:     "small-stopped",
+				WarehousePolicy: domain.WarehousePolicyPreferRunning,
+			},
+			expected: []string{"medium-running", "large-running", "small-stopped", "xsmall-serverless"},
+		},
+		{
+			name: "fail over from a deleted configured warehouse",
+			settings: domain.SyncSettings{
+				WarehouseID:     "tiny-deleted",
+				WarehousePolicy: domain.WarehousePolicyPreferRunning,
+			},
+			expected: []string{"medium-running", "large-running", "xsmall-serverless", "small-stopped"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, warehouse := range warehouseCandidates(warehouses, tt.settings) {
+				ids = append(ids, warehouse.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
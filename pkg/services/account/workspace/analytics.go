@@ -0,0 +1,150 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+)
+
+// CostAnalyzer exposes aggregated views over the cached (DuckDB) usage records
+type CostAnalyzer interface {
+	GetDailyCost(
+		ctx context.Context,
+		dimension domain.CostDimension,
+		startTime, endTime time.Time,
+	) ([]domain.DailyCost, error)
+	CompareCost(
+		ctx context.Context,
+		dimension domain.CostDimension,
+		period, baseline domain.TimePeriod,
+		filters []domain.CostFilter,
+	) (domain.CostComparisonReport, error)
+	ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error)
+}
+
+// AnalyticsStore is the minimal interface required by CostAnalyzer for reading aggregated usage
+// Implemented by the DuckDB usage store
+type AnalyticsStore interface {
+	GetDailyCost(ctx context.Context, dimension string, startTime, endTime time.Time) ([]store.DailyUsageAggregate, error)
+	CompareCost(
+		ctx context.Context,
+		dimension string,
+		startTime, endTime time.Time,
+		baselineStart, baselineEnd time.Time,
+		filters []store.DimensionFilter,
+	) ([]store.CostComparison, error)
+	ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error)
+}
+
+type workspaceCostAnalyzer struct {
+	analyticsStore AnalyticsStore
+}
+
+func NewCostAnalyzer(analyticsStore AnalyticsStore) CostAnalyzer {
+	return &workspaceCostAnalyzer{
+		analyticsStore: analyticsStore,
+	}
+}
+
+func (w *workspaceCostAnalyzer) GetDailyCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	startTime, endTime time.Time,
+) ([]domain.DailyCost, error) {
+	if !startTime.Before(endTime) {
+		return nil, fmt.Errorf("invalid time range: start time (%s) must be before end time (%s)",
+			startTime.Format("2006-01-02"),
+			endTime.Format("2006-01-02"))
+	}
+
+	if !dimension.IsValid() {
+		return nil, fmt.Errorf("unsupported cost dimension: %s", dimension)
+	}
+
+	aggregates, err := w.analyticsStore.GetDailyCost(ctx, string(dimension), startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make([]domain.DailyCost, 0, len(aggregates))
+	for _, agg := range aggregates {
+		costs = append(costs, adapters.MapDailyUsageAggregateStoreToDomain(agg, dimension))
+	}
+
+	return costs, nil
+}
+
+// CompareCost compares cost grouped by the dimension between the period and the baseline period,
+// optionally restricted to the usage matching all filters
+func (w *workspaceCostAnalyzer) CompareCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	period, baseline domain.TimePeriod,
+	filters []domain.CostFilter,
+) (domain.CostComparisonReport, error) {
+	for _, p := range []domain.TimePeriod{period, baseline} {
+		if !p.Start.Before(p.End) {
+			return domain.CostComparisonReport{}, fmt.Errorf(
+				"invalid time range: start time (%s) must be before end time (%s)",
+				p.Start.Format("2006-01-02"),
+				p.End.Format("2006-01-02"))
+		}
+	}
+
+	if !dimension.IsValid() {
+		return domain.CostComparisonReport{}, fmt.Errorf("unsupported cost dimension: %s", dimension)
+	}
+
+	storeFilters := make([]store.DimensionFilter, 0, len(filters))
+	for _, f := range filters {
+		if !f.Dimension.IsValid() {
+			return domain.CostComparisonReport{}, fmt.Errorf("unsupported cost dimension: %s", f.Dimension)
+		}
+		storeFilters = append(storeFilters, adapters.MapCostFilterDomainToStore(f))
+	}
+
+	comparisons, err := w.analyticsStore.CompareCost(
+		ctx, string(dimension), period.Start, period.End, baseline.Start, baseline.End, storeFilters)
+	if err != nil {
+		return domain.CostComparisonReport{}, err
+	}
+
+	report := domain.CostComparisonReport{
+		GroupBy:        dimension,
+		Period:         period,
+		BaselinePeriod: baseline,
+		Items:          make([]domain.CostComparison, 0, len(comparisons)),
+		New:            []string{},
+		Disappeared:    []string{},
+	}
+	for _, c := range comparisons {
+		item := adapters.MapCostComparisonStoreToDomain(c)
+		report.Items = append(report.Items, item)
+		report.TotalCost += item.Cost
+		report.BaselineTotalCost += item.BaselineCost
+		if report.Currency == "" {
+			report.Currency = c.Currency
+		}
+
+		switch item.Status {
+		case domain.ComparisonStatusNew:
+			report.New = append(report.New, item.Key)
+		case domain.ComparisonStatusDisappeared:
+			report.Disappeared = append(report.Disappeared, item.Key)
+		case domain.ComparisonStatusChanged, domain.ComparisonStatusUnchanged:
+		}
+	}
+	report.Delta = report.TotalCost - report.BaselineTotalCost
+	report.DeltaPercent = domain.DeltaPercent(report.TotalCost, report.BaselineTotalCost)
+
+	return report, nil
+}
+
+// ListTagKeys returns the custom tag keys present on the usage in the period, the keys of the most costly usage first
+func (w *workspaceCostAnalyzer) ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	return w.analyticsStore.ListTagKeys(ctx, startTime, endTime)
+}
@@ -0,0 +1,117 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAnalyticsStore struct{ mock.Mock }
+
+func (m *mockAnalyticsStore) GetDailyCost(
+	ctx context.Context,
+	dimension string,
+	startTime, endTime time.Time,
+) ([]store.DailyUsageAggregate, error) {
+	args := m.Called(ctx, dimension, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.DailyUsageAggregate), args.Error(1)
+}
+
+func (m *mockAnalyticsStore) CompareCost(
+	ctx context.Context,
+	dimension string,
+	startTime, endTime time.Time,
+	baselineStart, baselineEnd time.Time,
+	filters []store.DimensionFilter,
+) ([]store.CostComparison, error) {
+	args := m.Called(ctx, dimension, startTime, endTime, baselineStart, baselineEnd, filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]store.CostComparison), args.Error(1)
+}
+
+func (m *mockAnalyticsStore) ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestCostAnalyzer_CompareCost(t *testing.T) {
+	ctx := context.Background()
+	baseline := domain.TimePeriod{
+		Start: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	period := domain.TimePeriod{
+		Start: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	s := new(mockAnalyticsStore)
+	s.On("CompareCost", mock.Anything, "resource_id", period.Start, period.End, baseline.Start, baseline.End,
+		[]store.DimensionFilter{{Dimension: "sku", Value: "PREMIUM_SQL"}}).
+		Return([]store.CostComparison{
+			{Resource: "wh-1", Cost: 150, BaselineCost: 100, Currency: "USD", InPeriod: true, InBaseline: true},
+			{Resource: "job-2", Cost: 40, Currency: "USD", InPeriod: true},
+			{Resource: "job-1", BaselineCost: 10, Currency: "USD", InBaseline: true},
+			{Resource: "app-1", Cost: 5, BaselineCost: 5, Currency: "USD", InPeriod: true, InBaseline: true},
+		}, nil)
+
+	filters := []domain.CostFilter{{Dimension: domain.CostDimensionSKU, Value: "PREMIUM_SQL"}}
+	report, err := NewCostAnalyzer(s).CompareCost(ctx, domain.CostDimensionResourceID, period, baseline, filters)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 195.0, report.TotalCost, 0.001)
+	assert.InDelta(t, 115.0, report.BaselineTotalCost, 0.001)
+	assert.InDelta(t, 80.0, report.Delta, 0.001)
+	require.NotNil(t, report.DeltaPercent)
+	assert.InDelta(t, 69.565, *report.DeltaPercent, 0.001)
+	assert.Equal(t, "USD", report.Currency)
+	assert.Equal(t, []string{"job-2"}, report.New)
+	assert.Equal(t, []string{"job-1"}, report.Disappeared)
+
+	require.Len(t, report.Items, 4)
+	assert.Equal(t, domain.ComparisonStatusChanged, report.Items[0].Status)
+	assert.InDelta(t, 50.0, *report.Items[0].DeltaPercent, 0.001)
+	assert.Equal(t, domain.ComparisonStatusNew, report.Items[1].Status)
+	assert.Nil(t, report.Items[1].DeltaPercent, "no percentage without baseline cost")
+	assert.Equal(t, domain.ComparisonStatusDisappeared, report.Items[2].Status)
+	assert.Equal(t, domain.ComparisonStatusUnchanged, report.Items[3].Status)
+	s.AssertExpectations(t)
+}
+
+func TestCostAnalyzer_CompareCost_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	valid := domain.TimePeriod{
+		Start: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	inverted := domain.TimePeriod{Start: valid.End, End: valid.Start}
+
+	s := new(mockAnalyticsStore)
+	analyzer := NewCostAnalyzer(s)
+
+	_, err := analyzer.CompareCost(ctx, domain.CostDimensionSKU, valid, inverted, nil)
+	assert.Error(t, err)
+
+	_, err = analyzer.CompareCost(ctx, "workspace", valid, valid, nil)
+	assert.Error(t, err)
+
+	_, err = analyzer.CompareCost(ctx, domain.CostDimensionSKU, valid, valid,
+		[]domain.CostFilter{{Dimension: domain.TagDimension(`a"b`), Value: "x"}})
+	assert.Error(t, err)
+
+	s.AssertNotCalled(t, "CompareCost")
+}
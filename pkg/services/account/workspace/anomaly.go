@@ -0,0 +1,371 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// AnomalyBaselineMethod selects how the expected daily cost of a series is computed
+type AnomalyBaselineMethod string
+
+const (
+	// AnomalyBaselineRolling uses the median/MAD of all days in the preceding baseline window
+	AnomalyBaselineRolling AnomalyBaselineMethod = "rolling"
+	// AnomalyBaselineWeekday uses the median/MAD of the same weekday in the preceding baseline window
+	AnomalyBaselineWeekday AnomalyBaselineMethod = "weekday"
+)
+
+const (
+	// madScaleFactor makes the MAD a consistent estimator of the standard deviation for normal data
+	madScaleFactor = 1.4826
+	// minRelativeScale keeps flat baselines (MAD == 0) from flagging every small wobble
+	minRelativeScale = 0.1
+)
+
+// AnomalyDetectionSettings contains configurable thresholds for cost anomaly detection
+type AnomalyDetectionSettings struct {
+	// Method is the baseline method (default: rolling)
+	Method AnomalyBaselineMethod `json:"method" yaml:"method"`
+	// BaselineDays is the number of days preceding each evaluated day used for the baseline (default: 28)
+	BaselineDays int `json:"baseline_days" yaml:"baseline_days"`
+	// MinBaselinePoints is the minimum number of baseline days required to evaluate a day (default: 4)
+	MinBaselinePoints int `json:"min_baseline_points" yaml:"min_baseline_points"`
+	// ZScoreThreshold is the robust z-score (deviation / scaled MAD) above which a day is flagged (default: 3.5)
+	ZScoreThreshold float64 `json:"z_score_threshold" yaml:"z_score_threshold"`
+	// MinImpact is the minimum cost above the baseline for a day to be flagged (default: 10)
+	MinImpact float64 `json:"min_impact" yaml:"min_impact"`
+	// MediumImpact is the cost above the baseline from which an anomaly is of medium severity (default: 100)
+	MediumImpact float64 `json:"medium_impact" yaml:"medium_impact"`
+	// HighImpact is the cost above the baseline from which an anomaly is of high severity (default: 1000)
+	HighImpact float64 `json:"high_impact" yaml:"high_impact"`
+}
+
+// DefaultAnomalyDetectionSettings returns the default configuration for cost anomaly detection
+func DefaultAnomalyDetectionSettings() AnomalyDetectionSettings {
+	return AnomalyDetectionSettings{
+		Method:            AnomalyBaselineRolling,
+		BaselineDays:      28,
+		MinBaselinePoints: 4,
+		ZScoreThreshold:   3.5,
+		MinImpact:         10,
+		MediumImpact:      100,
+		HighImpact:        1000,
+	}
+}
+
+// Validate checks that the settings can be used to detect anomalies
+func (s AnomalyDetectionSettings) Validate() error {
+	switch {
+	case s.Method != AnomalyBaselineRolling && s.Method != AnomalyBaselineWeekday:
+		return fmt.Errorf("invalid baseline method %q. Expected rolling or weekday", s.Method)
+	case s.BaselineDays <= 0:
+		return fmt.Errorf("baseline_days must be positive")
+	case s.MinBaselinePoints <= 0 || s.MinBaselinePoints > s.BaselineDays:
+		return fmt.Errorf("min_baseline_points must be between 1 and baseline_days")
+	case s.ZScoreThreshold <= 0:
+		return fmt.Errorf("z_score_threshold must be positive")
+	case s.MinImpact < 0 || s.MediumImpact < 0 || s.HighImpact < 0:
+		return fmt.Errorf("impact thresholds must not be negative")
+	case s.MediumImpact > s.HighImpact:
+		return fmt.Errorf("medium_impact must not exceed high_impact")
+	}
+	return nil
+}
+
+// dailyCostSeries is a dense daily cost series of a single dimension value
+type dailyCostSeries struct {
+	dimension    domain.CostDimension
+	key          string
+	resourceType string
+	currency     string
+	firstDay     time.Time
+	values       map[time.Time]float64
+}
+
+// GetCostAnomalies detects days where the cost of a resource or a SKU deviates significantly
+// from its own baseline and reports them as audit findings
+func GetCostAnomalies(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	analyzer CostAnalyzer,
+	settings AnomalyDetectionSettings,
+) (domain.AuditReport, error) {
+	report := domain.AuditReport{
+		Workspace:    ws.Name,
+		ResourceType: "cost_anomaly",
+		Period: domain.TimePeriod{
+			Start:    startTime,
+			End:      endTime,
+			Duration: int(endTime.Sub(startTime).Hours() / 24),
+		},
+		Summary:  map[string]any{},
+		Findings: []domain.AuditFinding{},
+	}
+
+	// Fetch enough history to build a baseline for the first evaluated day
+	lookbackStart := startTime.AddDate(0, 0, -settings.BaselineDays)
+
+	var seriesEvaluated, skuAnomalies int
+	var totalExcess, skuExcess float64
+	var currency string
+	for _, dimension := range []domain.CostDimension{domain.CostDimensionResourceID, domain.CostDimensionSKU} {
+		costs, err := analyzer.GetDailyCost(ctx, dimension, lookbackStart, endTime)
+		if err != nil {
+			return domain.AuditReport{}, err
+		}
+
+		for _, series := range buildDailyCostSeries(costs, dimension, endTime) {
+			seriesEvaluated++
+			if currency == "" {
+				currency = series.currency
+			}
+			findings := detectSeriesAnomalies(series, startTime, endTime, settings)
+			for _, f := range findings {
+				// SKU spikes are the resource spikes seen by SKU, they are totaled apart so a spike is counted once
+				if dimension == domain.CostDimensionSKU {
+					skuAnomalies++
+					skuExcess += f.excess
+				} else {
+					totalExcess += f.excess
+				}
+				report.Findings = append(report.Findings, f.finding)
+			}
+		}
+	}
+
+	report.Summary["baseline_method"] = string(settings.Method)
+	report.Summary["baseline_days"] = settings.BaselineDays
+	report.Summary["series_evaluated"] = seriesEvaluated
+	report.Summary["anomalies_detected"] = len(report.Findings)
+	report.Summary["total_excess_cost"] = totalExcess
+	report.Summary["sku_anomalies_detected"] = skuAnomalies
+	report.Summary["sku_excess_cost"] = skuExcess
+	if currency != "" {
+		report.Summary["currency"] = currency
+	}
+	if seriesEvaluated == 0 {
+		report.Summary["no_activity"] = "No usage records found in the selected period"
+	}
+	summarizeSpikeSavings(&report)
+
+	return report, nil
+}
+
+// buildDailyCostSeries groups daily costs by dimension value and fills days without usage with zero cost,
+// starting from the first day the value was observed
+func buildDailyCostSeries(
+	costs []domain.DailyCost,
+	dimension domain.CostDimension,
+	endTime time.Time,
+) []*dailyCostSeries {
+	seriesByKey := make(map[string]*dailyCostSeries)
+	for _, c := range costs {
+		s, ok := seriesByKey[c.Key]
+		if !ok {
+			s = &dailyCostSeries{
+				dimension:    dimension,
+				key:          c.Key,
+				resourceType: c.ResourceType,
+				currency:     c.Currency,
+				firstDay:     truncateToDay(c.Date),
+				values:       make(map[time.Time]float64),
+			}
+			seriesByKey[c.Key] = s
+		}
+		day := truncateToDay(c.Date)
+		if day.Before(s.firstDay) {
+			s.firstDay = day
+		}
+		s.values[day] += c.TotalCost
+	}
+
+	lastDay := truncateToDay(endTime)
+	series := make([]*dailyCostSeries, 0, len(seriesByKey))
+	for _, s := range seriesByKey {
+		for day := s.firstDay; day.Before(lastDay); day = day.AddDate(0, 0, 1) {
+			if _, ok := s.values[day]; !ok {
+				s.values[day] = 0
+			}
+		}
+		series = append(series, s)
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].key < series[j].key })
+	return series
+}
+
+type anomalyFinding struct {
+	finding domain.AuditFinding
+	excess  float64
+}
+
+// detectSeriesAnomalies evaluates every day of the series within [startTime, endTime) against its baseline
+func detectSeriesAnomalies(
+	series *dailyCostSeries,
+	startTime, endTime time.Time,
+	settings AnomalyDetectionSettings,
+) []anomalyFinding {
+	var findings []anomalyFinding
+
+	firstDay := truncateToDay(startTime)
+	if series.firstDay.After(firstDay) {
+		firstDay = series.firstDay
+	}
+
+	for day := firstDay; day.Before(endTime); day = day.AddDate(0, 0, 1) {
+		actual, ok := series.values[day]
+		if !ok {
+			continue
+		}
+
+		baseline := collectBaseline(series, day, settings)
+		if len(baseline) < settings.MinBaselinePoints || len(baseline) == 0 {
+			continue
+		}
+
+		expected := median(baseline)
+		deviations := make([]float64, 0, len(baseline))
+		for _, v := range baseline {
+			deviations = append(deviations, math.Abs(v-expected))
+		}
+		scale := math.Max(madScaleFactor*median(deviations), minRelativeScale*expected)
+
+		excess := actual - expected
+		if excess < settings.MinImpact {
+			continue
+		}
+
+		score := math.Inf(1)
+		if scale > 0 {
+			score = excess / scale
+		}
+		if score < settings.ZScoreThreshold {
+			continue
+		}
+
+		findings = append(findings, anomalyFinding{
+			finding: buildAnomalyFinding(series, day, actual, expected, excess, settings),
+			excess:  excess,
+		})
+	}
+
+	return findings
+}
+
+// collectBaseline returns the observed costs preceding the given day according to the baseline method
+func collectBaseline(series *dailyCostSeries, day time.Time, settings AnomalyDetectionSettings) []float64 {
+	var baseline []float64
+	for offset := 1; offset <= settings.BaselineDays; offset++ {
+		d := day.AddDate(0, 0, -offset)
+		if d.Before(series.firstDay) {
+			break
+		}
+		if settings.Method == AnomalyBaselineWeekday && d.Weekday() != day.Weekday() {
+			continue
+		}
+		if v, ok := series.values[d]; ok {
+			baseline = append(baseline, v)
+		}
+	}
+	return baseline
+}
+
+func buildAnomalyFinding(
+	series *dailyCostSeries,
+	day time.Time,
+	actual, expected, excess float64,
+	settings AnomalyDetectionSettings,
+) domain.AuditFinding {
+	date := day.Format("2006-01-02")
+
+	resource := domain.ResourceDef{
+		Platform: "Databricks",
+		Service:  series.resourceType,
+		Name:     series.key,
+		Metadata: map[string]string{"dimension": string(series.dimension), "date": date},
+	}
+	recommendation := fmt.Sprintf("Review recent changes to this %s (configuration, schedules, data volume) "+
+		"and confirm the increase is expected.", series.resourceType)
+	// a spike does not recur every month, avoiding it would have saved its excess once
+	savings := periodSavings(math.Round(excess*100)/100, series.currency, domain.SavingsConfidenceLow)
+	if series.dimension == domain.CostDimensionSKU {
+		resource.Service = "sku"
+		recommendation = "Break the increase down by resource for this SKU and confirm it is expected."
+		// the cost of a SKU is the cost of its resources, whose spikes already carry the savings
+		savings = noSavings(series.currency)
+	}
+
+	description := fmt.Sprintf("Daily cost on %s was %.2f %s", date, actual, series.currency)
+	if expected > 0 {
+		description += fmt.Sprintf(", %.1fx the baseline of %.2f %s", actual/expected, expected, series.currency)
+	} else {
+		description += " with no spend in the baseline window"
+	}
+	description += fmt.Sprintf(" (+%.2f %s).", excess, series.currency)
+
+	return domain.AuditFinding{
+		Id:                      fmt.Sprintf("%s_%s_cost_spike_%s", series.dimension, series.key, date),
+		Resource:                resource,
+		Issue:                   "cost_spike",
+		Description:             description,
+		Recommendation:          recommendation,
+		Severity:                anomalySeverity(excess, settings),
+		EstimatedMonthlySavings: savings,
+	}
+}
+
+// summarizeSpikeSavings adds the total addressable savings of the spikes to the summary. The excess of a spike
+// is spent once, so unlike recurring findings it is not scaled from the period to a month, and spikes of the
+// same resource on different days do not overlap, so they all count. SKU spikes carry no savings of their own.
+func summarizeSpikeSavings(report *domain.AuditReport) {
+	total := 0.0
+	var currency string
+	for _, finding := range report.Findings {
+		total += finding.EstimatedMonthlySavings.Amount
+		if currency == "" {
+			currency = finding.EstimatedMonthlySavings.Currency
+		}
+	}
+	report.Summary["total_addressable_savings"] = math.Round(total*100) / 100
+	if currency != "" {
+		report.Summary["savings_currency"] = currency
+	}
+}
+
+// anomalySeverity derives the finding severity from the cost above the baseline
+func anomalySeverity(excess float64, settings AnomalyDetectionSettings) domain.Severity {
+	switch {
+	case excess >= settings.HighImpact:
+		return domain.SeverityHigh
+	case excess >= settings.MediumImpact:
+		return domain.SeverityMedium
+	default:
+		return domain.SeverityLow
+	}
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// truncateToDay returns the start of the UTC day of t, the days of the daily cost being UTC days
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
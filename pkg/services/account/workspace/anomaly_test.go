@@ -0,0 +1,222 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockCostAnalyzer struct{ mock.Mock }
+
+func (m *mockCostAnalyzer) GetDailyCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	startTime, endTime time.Time,
+) ([]domain.DailyCost, error) {
+	args := m.Called(ctx, dimension, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DailyCost), args.Error(1)
+}
+
+func (m *mockCostAnalyzer) CompareCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	period, baseline domain.TimePeriod,
+	filters []domain.CostFilter,
+) (domain.CostComparisonReport, error) {
+	args := m.Called(ctx, dimension, period, baseline, filters)
+	return args.Get(0).(domain.CostComparisonReport), args.Error(1)
+}
+
+func (m *mockCostAnalyzer) ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// dailyCosts builds one DailyCost per value starting at the given day
+func dailyCosts(dimension domain.CostDimension, key string, from time.Time, values ...float64) []domain.DailyCost {
+	costs := make([]domain.DailyCost, 0, len(values))
+	for i, v := range values {
+		costs = append(costs, domain.DailyCost{
+			Date:         from.AddDate(0, 0, i),
+			Dimension:    dimension,
+			Key:          key,
+			ResourceType: "warehouse",
+			TotalCost:    v,
+			Currency:     "USD",
+		})
+	}
+	return costs
+}
+
+func TestGetCostAnomalies_DetectsSpike(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	start := time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	settings := DefaultAnomalyDetectionSettings()
+	settings.BaselineDays = 7
+	lookback := start.AddDate(0, 0, -settings.BaselineDays)
+
+	// 7 baseline days around 50 USD, a spike to 500 USD and a normal day
+	resourceCosts := dailyCosts(domain.CostDimensionResourceID, "wh-1", lookback,
+		48, 52, 50, 49, 51, 50, 50, 500, 51)
+	skuCosts := dailyCosts(domain.CostDimensionSKU, "PREMIUM_SQL", lookback,
+		48, 52, 50, 49, 51, 50, 50, 52, 51)
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceID, lookback, end).Return(resourceCosts, nil)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionSKU, lookback, end).Return(skuCosts, nil)
+
+	report, err := GetCostAnomalies(ctx, ws, start, end, analyzer, settings)
+	assert.NoError(t, err)
+	assert.Equal(t, "cost_anomaly", report.ResourceType)
+	assert.Len(t, report.Findings, 1)
+
+	finding := report.Findings[0]
+	assert.Equal(t, "cost_spike", finding.Issue)
+	assert.Equal(t, "resource_id_wh-1_cost_spike_2025-09-08", finding.Id)
+	assert.Equal(t, "wh-1", finding.Resource.Name)
+	assert.Equal(t, "warehouse", finding.Resource.Service)
+	assert.Equal(t, domain.SeverityMedium, finding.Severity)
+	assert.Contains(t, finding.Description, "500.00 USD")
+
+	assert.Equal(t, 2, report.Summary["series_evaluated"])
+	assert.Equal(t, 1, report.Summary["anomalies_detected"])
+	assert.InDelta(t, 450.0, report.Summary["total_excess_cost"], 0.001)
+
+	assert.Equal(t, 450.0, finding.EstimatedMonthlySavings.Amount, "the one-time excess is not scaled to a month")
+	assert.Equal(t, 450.0, report.Summary["total_addressable_savings"])
+	assert.Equal(t, "USD", report.Summary["savings_currency"])
+	analyzer.AssertExpectations(t)
+}
+
+func TestGetCostAnomalies_SKUSpikeCountedOnce(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	start := time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	settings := DefaultAnomalyDetectionSettings()
+	settings.BaselineDays = 7
+	lookback := start.AddDate(0, 0, -settings.BaselineDays)
+
+	// the warehouse is the only resource billed on its SKU, both series spike on the same day
+	resourceCosts := dailyCosts(domain.CostDimensionResourceID, "wh-1", lookback,
+		48, 52, 50, 49, 51, 50, 50, 500, 51)
+	skuCosts := dailyCosts(domain.CostDimensionSKU, "PREMIUM_SQL", lookback,
+		48, 52, 50, 49, 51, 50, 50, 500, 51)
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceID, lookback, end).Return(resourceCosts, nil)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionSKU, lookback, end).Return(skuCosts, nil)
+
+	report, err := GetCostAnomalies(ctx, ws, start, end, analyzer, settings)
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 2, "the spike is reported by resource and by SKU")
+
+	assert.Equal(t, 2, report.Summary["anomalies_detected"])
+	assert.Equal(t, 1, report.Summary["sku_anomalies_detected"])
+	assert.InDelta(t, 450.0, report.Summary["total_excess_cost"], 0.001, "the SKU spike is not added to the excess")
+	assert.InDelta(t, 450.0, report.Summary["sku_excess_cost"], 0.001)
+	assert.Equal(t, 450.0, report.Summary["total_addressable_savings"], "the SKU spike carries no savings")
+	for _, finding := range report.Findings {
+		if finding.Resource.Service == "sku" {
+			assert.Zero(t, finding.EstimatedMonthlySavings.Amount)
+		}
+	}
+}
+
+func TestGetCostAnomalies_WeekdayBaseline(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	// 2025-09-01 is a Monday
+	lookback := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	settings := DefaultAnomalyDetectionSettings()
+	settings.Method = AnomalyBaselineWeekday
+	settings.BaselineDays = 28
+	settings.MinBaselinePoints = 4
+	start := lookback.AddDate(0, 0, settings.BaselineDays)
+	end := start.AddDate(0, 0, 1)
+
+	// Mondays are always expensive (300 USD), other days cost 20 USD
+	var values []float64
+	for day := lookback; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Monday {
+			values = append(values, 300)
+		} else {
+			values = append(values, 20)
+		}
+	}
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceID, lookback, end).
+		Return(dailyCosts(domain.CostDimensionResourceID, "job-1", lookback, values...), nil)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionSKU, lookback, end).
+		Return([]domain.DailyCost{}, nil)
+
+	report, err := GetCostAnomalies(ctx, ws, start, end, analyzer, settings)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Findings, "a recurring weekday peak is not an anomaly")
+
+	settings.Method = AnomalyBaselineRolling
+	report, err = GetCostAnomalies(ctx, ws, start, end, analyzer, settings)
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 1, "the rolling baseline flags the weekday peak")
+}
+
+func TestGetCostAnomalies_NoActivity(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	start := time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, mock.Anything, mock.Anything, end).Return([]domain.DailyCost{}, nil)
+
+	report, err := GetCostAnomalies(ctx, ws, start, end, analyzer, DefaultAnomalyDetectionSettings())
+	assert.NoError(t, err)
+	assert.Empty(t, report.Findings)
+	assert.Contains(t, report.Summary, "no_activity")
+}
+
+func TestGetCostAnomalies_AnalyzerError(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	start := time.Date(2025, 9, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, mock.Anything, mock.Anything, end).Return(nil, assert.AnError)
+
+	_, err := GetCostAnomalies(ctx, ws, start, end, analyzer, DefaultAnomalyDetectionSettings())
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestAnomalySeverity(t *testing.T) {
+	settings := DefaultAnomalyDetectionSettings()
+
+	assert.Equal(t, domain.SeverityLow, anomalySeverity(50, settings))
+	assert.Equal(t, domain.SeverityMedium, anomalySeverity(100, settings))
+	assert.Equal(t, domain.SeverityHigh, anomalySeverity(2500, settings))
+}
+
+func TestTruncateToDay(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+
+	assert.Equal(t, time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC),
+		truncateToDay(time.Date(2025, 3, 10, 1, 30, 0, 0, tokyo)), "01:30 in Tokyo is still the previous UTC day")
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		truncateToDay(time.Date(2025, 3, 9, 22, 0, 0, 0, newYork)), "22:00 in New York is already the next UTC day")
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		truncateToDay(time.Date(2025, 3, 10, 23, 59, 59, 0, time.UTC)))
+}
@@ -0,0 +1,160 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/google/uuid"
+)
+
+// AuditHistoryStore is the minimal interface required to record audit runs
+type AuditHistoryStore interface {
+	AddRun(ctx context.Context, run store.AuditRun) error
+	GetLatestRun(ctx context.Context, workspace, scope string) (*store.AuditRun, error)
+	ListRuns(ctx context.Context, filter store.AuditRunFilter) ([]store.AuditRun, error)
+}
+
+// AuditHistory records audit reports and tracks their findings across runs
+type AuditHistory interface {
+	// Record stores the report and classifies its findings against the previous run of the same scope,
+	// the report is updated with the run id, the finding statuses and the resolved findings
+	Record(ctx context.Context, report *domain.AuditReport) (domain.AuditRun, error)
+	List(ctx context.Context, filter domain.AuditHistoryFilter) ([]domain.AuditRun, error)
+}
+
+type auditHistory struct {
+	store AuditHistoryStore
+	now   func() time.Time
+}
+
+func NewAuditHistory(store AuditHistoryStore) AuditHistory {
+	return &auditHistory{store: store, now: time.Now}
+}
+
+func (h *auditHistory) Record(ctx context.Context, report *domain.AuditReport) (domain.AuditRun, error) {
+	scope := auditScope(*report)
+	previous, err := h.store.GetLatestRun(ctx, report.Workspace, scope)
+	if err != nil {
+		return domain.AuditRun{}, fmt.Errorf("failed to load previous audit run: %w", err)
+	}
+
+	run := domain.AuditRun{
+		ID:        uuid.NewString(),
+		Scope:     scope,
+		CreatedAt: h.now().UTC(),
+	}
+	var previousFindings []domain.AuditFinding
+	if previous != nil {
+		run.PreviousRunID = previous.ID
+		previousReport := adapters.MapAuditRunStoreToDomain(*previous).Report
+		previousFindings = append(previousReport.Findings, previousReport.Suppressed...)
+	}
+
+	diffAuditFindings(report, previousFindings, report.Rules)
+	report.RunID = run.ID
+	run.Report = *report
+
+	if err := h.store.AddRun(ctx, adapters.MapAuditRunDomainToStore(run)); err != nil {
+		return domain.AuditRun{}, fmt.Errorf("failed to record audit run: %w", err)
+	}
+	return run, nil
+}
+
+func (h *auditHistory) List(ctx context.Context, filter domain.AuditHistoryFilter) ([]domain.AuditRun, error) {
+	runs, err := h.store.ListRuns(ctx, adapters.MapAuditHistoryFilterDomainToStore(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AuditRun, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, adapters.MapAuditRunStoreToDomain(run))
+	}
+	return result, nil
+}
+
+// diffAuditFindings marks the findings of the report as new or still open by their id,
+// findings of the previous run missing from the report are resolved, suppressed findings are not.
+// A rule that failed reported no findings, so its previous findings are carried forward with an unknown status
+// instead of being resolved.
+func diffAuditFindings(report *domain.AuditReport, previous []domain.AuditFinding, rules []domain.AuditRuleResult) {
+	open := make(map[string]bool, len(previous))
+	for _, finding := range previous {
+		open[finding.Id] = true
+	}
+
+	current := make(map[string]bool, len(report.Findings)+len(report.Suppressed))
+	for _, finding := range report.Suppressed {
+		current[finding.Id] = true
+	}
+	newFindings, openFindings := 0, 0
+	for i := range report.Findings {
+		current[report.Findings[i].Id] = true
+		if open[report.Findings[i].Id] {
+			report.Findings[i].Status = domain.FindingStatusOpen
+			openFindings++
+		} else {
+			report.Findings[i].Status = domain.FindingStatusNew
+			newFindings++
+		}
+	}
+
+	failedRules := make(map[string]bool)
+	failedResourceTypes := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Error != "" {
+			failedRules[rule.Rule] = true
+			failedResourceTypes[rule.ResourceType] = true
+		}
+	}
+
+	report.Resolved = nil
+	unknownFindings := 0
+	for _, finding := range previous {
+		if current[finding.Id] {
+			continue
+		}
+		// findings recorded before findings carried their rule are matched by resource type
+		failed := failedRules[finding.Rule] || (finding.Rule == "" && failedResourceTypes[finding.Resource.Service])
+		switch {
+		case failed && finding.Status == domain.FindingStatusSuppressed:
+			report.Suppressed = append(report.Suppressed, finding)
+		case failed:
+			finding.Status = domain.FindingStatusUnknown
+			report.Findings = append(report.Findings, finding)
+			unknownFindings++
+		default:
+			finding.Status = domain.FindingStatusResolved
+			report.Resolved = append(report.Resolved, finding)
+		}
+	}
+
+	if report.Summary == nil {
+		report.Summary = map[string]any{}
+	}
+	report.Summary["new_findings"] = newFindings
+	report.Summary["open_findings"] = openFindings
+	report.Summary["unknown_findings"] = unknownFindings
+	report.Summary["resolved_findings"] = len(report.Resolved)
+}
+
+// auditScope identifies the resource types and rules of the report,
+// so that a run is only compared against earlier runs checking the same things
+func auditScope(report domain.AuditReport) string {
+	if len(report.Rules) == 0 {
+		return report.ResourceType
+	}
+
+	rules := make([]string, 0, len(report.Rules))
+	for _, rule := range report.Rules {
+		rules = append(rules, rule.Rule)
+	}
+	slices.Sort(rules)
+	return report.ResourceType + ":" + strings.Join(rules, ",")
+}
@@ -0,0 +1,142 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuditHistoryStore keeps recorded runs in memory, latest last
+type memoryAuditHistoryStore struct {
+	runs []store.AuditRun
+}
+
+func (m *memoryAuditHistoryStore) AddRun(_ context.Context, run store.AuditRun) error {
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *memoryAuditHistoryStore) GetLatestRun(_ context.Context, workspace, scope string) (*store.AuditRun, error) {
+	for i := len(m.runs) - 1; i >= 0; i-- {
+		if m.runs[i].Workspace == workspace && m.runs[i].Scope == scope {
+			return &m.runs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryAuditHistoryStore) ListRuns(_ context.Context, _ store.AuditRunFilter) ([]store.AuditRun, error) {
+	return m.runs, nil
+}
+
+func historyReport(resourceType string, findingIDs ...string) *domain.AuditReport {
+	report := &domain.AuditReport{
+		Workspace:    "test-workspace",
+		ResourceType: resourceType,
+		Summary:      map[string]any{"settings": DefaultClusterAuditSettings()},
+	}
+	for _, id := range findingIDs {
+		report.Findings = append(report.Findings, domain.AuditFinding{Id: id, Issue: "long_runtime"})
+	}
+	return report
+}
+
+func TestAuditHistory_Record(t *testing.T) {
+	ctx := context.Background()
+	historyStore := &memoryAuditHistoryStore{}
+	history := NewAuditHistory(historyStore)
+
+	first, err := history.Record(ctx, historyReport("cluster", "c1_long_runtime", "c2_long_runtime"))
+	require.NoError(t, err)
+	assert.Empty(t, first.PreviousRunID)
+	assert.Equal(t, 2, first.Report.Summary["new_findings"])
+
+	report := historyReport("cluster", "c2_long_runtime", "c3_long_runtime")
+	second, err := history.Record(ctx, report)
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.PreviousRunID)
+	assert.Equal(t, second.ID, report.RunID)
+	assert.Equal(t, domain.FindingStatusOpen, report.Findings[0].Status)
+	assert.Equal(t, domain.FindingStatusNew, report.Findings[1].Status)
+	require.Len(t, report.Resolved, 1)
+	assert.Equal(t, "c1_long_runtime", report.Resolved[0].Id)
+	assert.Equal(t, 1, report.Summary["new_findings"])
+	assert.Equal(t, 1, report.Summary["open_findings"])
+	assert.Equal(t, 1, report.Summary["resolved_findings"])
+
+	stored := historyStore.runs[1]
+	assert.Len(t, stored.Findings, 3, "resolved findings are stored with the run")
+	assert.NotContains(t, stored.Summary, "settings")
+	assert.Equal(t, DefaultClusterAuditSettings(), stored.Settings)
+
+	// a different scope does not compare against the cluster runs
+	other, err := history.Record(ctx, historyReport("job", "c2_long_runtime"))
+	require.NoError(t, err)
+	assert.Empty(t, other.PreviousRunID)
+	assert.Equal(t, 1, other.Report.Summary["new_findings"])
+
+	runs, err := history.List(ctx, domain.AuditHistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+	assert.Len(t, runs[1].Report.Findings, 2)
+	assert.Len(t, runs[1].Report.Resolved, 1)
+	assert.Equal(t, DefaultClusterAuditSettings(), runs[1].Report.Summary["settings"])
+}
+
+func TestAuditHistory_RecordFailedRule(t *testing.T) {
+	ctx := context.Background()
+	history := NewAuditHistory(&memoryAuditHistoryStore{})
+	ruleReport := func(failed bool, findings ...domain.AuditFinding) *domain.AuditReport {
+		report := historyReport("cluster")
+		report.Findings = findings
+		report.Rules = []domain.AuditRuleResult{
+			{Rule: "cluster_runtime", ResourceType: "cluster"},
+			{Rule: "cluster_utilization", ResourceType: "cluster"},
+		}
+		if failed {
+			report.Rules[1].Error = "system tables unavailable"
+		}
+		return report
+	}
+	runtime := domain.AuditFinding{Id: "c1_long_runtime", Rule: "cluster_runtime"}
+	utilization := domain.AuditFinding{Id: "c2_low_cpu", Rule: "cluster_utilization"}
+
+	_, err := history.Record(ctx, ruleReport(false, runtime, utilization))
+	require.NoError(t, err)
+
+	report := ruleReport(true)
+	_, err = history.Record(ctx, report)
+	require.NoError(t, err)
+	require.Len(t, report.Resolved, 1, "only the findings of the rules that succeeded are resolved")
+	assert.Equal(t, "c1_long_runtime", report.Resolved[0].Id)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "c2_low_cpu", report.Findings[0].Id)
+	assert.Equal(t, domain.FindingStatusUnknown, report.Findings[0].Status,
+		"the findings of a failed rule are carried forward")
+	assert.Equal(t, 1, report.Summary["unknown_findings"])
+	assert.Equal(t, 1, report.Summary["resolved_findings"])
+
+	report = ruleReport(false, utilization)
+	_, err = history.Record(ctx, report)
+	require.NoError(t, err)
+	assert.Equal(t, domain.FindingStatusOpen, report.Findings[0].Status,
+		"a carried finding reported again once its rule succeeds is still open")
+	assert.Empty(t, report.Resolved)
+}
+
+func TestAuditScope(t *testing.T) {
+	assert.Equal(t, "cluster", auditScope(domain.AuditReport{ResourceType: "cluster"}))
+	assert.Equal(t, "cluster,job:cluster_runtime,job_wasted_runs", auditScope(domain.AuditReport{
+		ResourceType: "cluster,job",
+		Rules: []domain.AuditRuleResult{
+			{Rule: "job_wasted_runs", Duration: time.Second},
+			{Rule: "cluster_runtime"},
+		},
+	}))
+}
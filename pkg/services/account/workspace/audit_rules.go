@@ -0,0 +1,335 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+var errExplorerUnavailable = errors.New("workspace explorer not available")
+
+// AuditSettings groups the thresholds of all audit rules
+type AuditSettings struct {
+	Warehouse WarehouseAuditSettings    `json:"warehouse" yaml:"warehouse"`
+	Cluster   ClusterAuditSettings      `json:"cluster" yaml:"cluster"`
+	Endpoint  ModelServingAuditSettings `json:"endpoint" yaml:"endpoint"`
+	Job       JobAuditSettings          `json:"job" yaml:"job"`
+	DLT       DLTAuditSettings          `json:"dlt_pipeline" yaml:"dlt_pipeline"`
+}
+
+// DefaultAuditSettings returns the default configuration of all audit rules
+func DefaultAuditSettings() AuditSettings {
+	return AuditSettings{
+		Warehouse: DefaultWarehouseAuditSettings(),
+		Cluster:   DefaultClusterAuditSettings(),
+		Endpoint:  DefaultModelServingAuditSettings(),
+		Job:       DefaultJobAuditSettings(),
+		DLT:       DefaultDLTAuditSettings(),
+	}
+}
+
+// AuditInput is shared by the rules of an audit run,
+// data loaded by one rule is cached and reused by the following rules
+type AuditInput struct {
+	Workspace   domain.Workspace
+	StartTime   time.Time
+	EndTime     time.Time
+	CostManager CostManager
+	Explorer    Explorer // optional, rules depending on workspace metadata fail without it
+	Settings    AuditSettings
+
+	mu    sync.Mutex
+	cache map[string]cachedAuditData
+}
+
+type cachedAuditData struct {
+	value any
+	err   error
+}
+
+// loadAuditData returns the cached result of load for the key, calling load on first use
+func loadAuditData[T any](input *AuditInput, key string, load func() (T, error)) (T, error) {
+	input.mu.Lock()
+	defer input.mu.Unlock()
+
+	if input.cache == nil {
+		input.cache = make(map[string]cachedAuditData)
+	}
+	if cached, ok := input.cache[key]; ok {
+		value, _ := cached.value.(T)
+		return value, cached.err
+	}
+
+	value, err := load()
+	input.cache[key] = cachedAuditData{value: value, err: err}
+	return value, err
+}
+
+// resourceCost returns the usage records of the resource type in the audit period
+func (in *AuditInput) resourceCost(ctx context.Context, resourceType string) ([]domain.ResourceCost, error) {
+	return loadAuditData(in, "cost:"+resourceType, func() ([]domain.ResourceCost, error) {
+		resources := domain.WorkspaceResources{WorkspaceName: in.Workspace.Name, Resources: []string{resourceType}}
+		return in.CostManager.GetResourcesCost(ctx, resources, in.StartTime, in.EndTime)
+	})
+}
+
+func (in *AuditInput) period() domain.TimePeriod {
+	return domain.TimePeriod{
+		Start:    in.StartTime,
+		End:      in.EndTime,
+		Duration: int(in.EndTime.Sub(in.StartTime).Hours() / 24),
+	}
+}
+
+// AuditRule is a single check over the resources of one type
+type AuditRule interface {
+	Name() string
+	ResourceType() string
+	Description() string
+	Evaluate(ctx context.Context, input *AuditInput) ([]domain.AuditFinding, error)
+}
+
+type auditRule struct {
+	name         string
+	resourceType string
+	description  string
+	evaluate     func(ctx context.Context, input *AuditInput) ([]domain.AuditFinding, error)
+}
+
+// NewAuditRule creates an AuditRule from an evaluation function
+func NewAuditRule(
+	name, resourceType, description string,
+	evaluate func(ctx context.Context, input *AuditInput) ([]domain.AuditFinding, error),
+) AuditRule {
+	return &auditRule{name: name, resourceType: resourceType, description: description, evaluate: evaluate}
+}
+
+func (r *auditRule) Name() string         { return r.name }
+func (r *auditRule) ResourceType() string { return r.resourceType }
+func (r *auditRule) Description() string  { return r.description }
+
+func (r *auditRule) Evaluate(ctx context.Context, input *AuditInput) ([]domain.AuditFinding, error) {
+	return r.evaluate(ctx, input)
+}
+
+// AuditRegistry holds audit rules keyed by resource type, in registration order
+type AuditRegistry struct {
+	mu    sync.RWMutex
+	rules map[string][]AuditRule
+	names map[string]bool
+}
+
+func NewAuditRegistry() *AuditRegistry {
+	return &AuditRegistry{
+		rules: make(map[string][]AuditRule),
+		names: make(map[string]bool),
+	}
+}
+
+// Register adds the rule to the registry, rule names must be unique across resource types
+func (r *AuditRegistry) Register(rule AuditRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rule.Name() == "" || rule.ResourceType() == "" {
+		return fmt.Errorf("audit rule must have a name and a resource type")
+	}
+	if r.names[rule.Name()] {
+		return fmt.Errorf("audit rule %s is already registered", rule.Name())
+	}
+	r.names[rule.Name()] = true
+	r.rules[rule.ResourceType()] = append(r.rules[rule.ResourceType()], rule)
+	return nil
+}
+
+// ResourceTypes returns the resource types with registered rules, sorted by name
+func (r *AuditRegistry) ResourceTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.resourceTypesLocked()
+}
+
+// Rules returns the rules of the resource types matching the rule names,
+// empty resource types or names select everything
+func (r *AuditRegistry) Rules(resourceTypes, names []string) ([]AuditRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, resourceType := range resourceTypes {
+		if _, ok := r.rules[resourceType]; !ok {
+			return nil, fmt.Errorf("no audit rules for resource type %s", resourceType)
+		}
+	}
+	for _, name := range names {
+		if !r.names[name] {
+			return nil, fmt.Errorf("unknown audit rule %s", name)
+		}
+	}
+
+	var selected []AuditRule
+	for _, resourceType := range r.resourceTypesLocked() {
+		if len(resourceTypes) > 0 && !slices.Contains(resourceTypes, resourceType) {
+			continue
+		}
+		for _, rule := range r.rules[resourceType] {
+			if len(names) == 0 || slices.Contains(names, rule.Name()) {
+				selected = append(selected, rule)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no audit rules match resources %v and rules %v", resourceTypes, names)
+	}
+	return selected, nil
+}
+
+func (r *AuditRegistry) resourceTypesLocked() []string {
+	types := make([]string, 0, len(r.rules))
+	for resourceType := range r.rules {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+var auditRegistry = NewAuditRegistry()
+
+// RegisterAuditRule adds the rule to the default registry, it panics on invalid or duplicate rules
+// and is meant to be called from init functions
+func RegisterAuditRule(rule AuditRule) {
+	if err := auditRegistry.Register(rule); err != nil {
+		panic(err)
+	}
+}
+
+// DefaultAuditRegistry returns the registry the built-in audit rules register with
+func DefaultAuditRegistry() *AuditRegistry {
+	return auditRegistry
+}
+
+// RunAudit evaluates the rules in order and merges their findings into a single report,
+// a failing rule is recorded in the report and does not stop the remaining rules
+func RunAudit(ctx context.Context, input *AuditInput, rules []AuditRule) domain.AuditReport {
+	report := domain.AuditReport{
+		Workspace: input.Workspace.Name,
+		Period:    input.period(),
+		Summary:   map[string]any{"settings": input.Settings},
+		Findings:  []domain.AuditFinding{},
+		Rules:     make([]domain.AuditRuleResult, 0, len(rules)),
+	}
+
+	var resourceTypes []string
+	findingsByResourceType := map[string]int{}
+	failed := 0
+	for _, rule := range rules {
+		if !slices.Contains(resourceTypes, rule.ResourceType()) {
+			resourceTypes = append(resourceTypes, rule.ResourceType())
+		}
+
+		started := time.Now()
+		findings, err := rule.Evaluate(ctx, input)
+		result := domain.AuditRuleResult{
+			Rule:         rule.Name(),
+			ResourceType: rule.ResourceType(),
+			Findings:     len(findings),
+			Duration:     time.Since(started),
+		}
+		if err != nil {
+			failed++
+			result.Error = err.Error()
+		}
+		for i := range findings {
+			findings[i].Rule = rule.Name()
+		}
+		report.Rules = append(report.Rules, result)
+		report.Findings = append(report.Findings, findings...)
+		findingsByResourceType[rule.ResourceType()] += len(findings)
+	}
+	report.ResourceType = strings.Join(resourceTypes, ",")
+
+	severityCounts := map[domain.Severity]int{}
+	for _, finding := range report.Findings {
+		severityCounts[finding.Severity]++
+	}
+	report.Summary["rules_evaluated"] = len(rules)
+	report.Summary["rules_failed"] = failed
+	report.Summary["findings_by_resource_type"] = findingsByResourceType
+	report.Summary["high_severity_findings"] = severityCounts[domain.SeverityHigh]
+	report.Summary["medium_severity_findings"] = severityCounts[domain.SeverityMedium]
+	report.Summary["low_severity_findings"] = severityCounts[domain.SeverityLow]
+	report.Summary["total_findings"] = len(report.Findings)
+
+	switch {
+	case len(rules) > 0 && failed == len(rules):
+		report.Summary["audit_status"] = "Audit failed - no rule could be evaluated"
+	case len(report.Findings) == 0:
+		report.Summary["audit_status"] = "All resources passed audit checks - no issues detected"
+	default:
+		report.Summary["audit_status"] = fmt.Sprintf("Audit completed - found %d optimization opportunities", len(report.Findings))
+	}
+	if failed > 0 && failed < len(rules) {
+		report.Summary["audit_status"] = fmt.Sprintf("%s, %d of %d rules failed", report.Summary["audit_status"], failed, len(rules))
+	}
+	summarizeSavings(&report)
+
+	return report
+}
+
+// runResourceAudit evaluates the registered rules of the resource type for its own audit endpoint,
+// summarize adds the metrics of the resource type from the data the rules loaded into the input
+func runResourceAudit(
+	ctx context.Context,
+	input *AuditInput,
+	resourceType string,
+	summarize func(report *domain.AuditReport),
+) (domain.AuditReport, error) {
+	rules, err := DefaultAuditRegistry().Rules([]string{resourceType}, nil)
+	if err != nil {
+		return domain.AuditReport{}, err
+	}
+
+	report := RunAudit(ctx, input, rules)
+	status := report.Summary["audit_status"]
+	summarize(&report)
+	if failed, _ := report.Summary["rules_failed"].(int); failed > 0 {
+		// the status of the resource type does not mention the failed rules
+		report.Summary["audit_status"] = status
+	}
+	return report, nil
+}
+
+// noActivityReport is the report of a resource type without usage in the audit period,
+// its rules are not evaluated as there is nothing to audit
+func noActivityReport(
+	input *AuditInput,
+	resourceType string,
+	settings any,
+	summary, description, recommendation string,
+	severity domain.Severity,
+) domain.AuditReport {
+	report := domain.AuditReport{
+		Workspace:    input.Workspace.Name,
+		ResourceType: resourceType,
+		Period:       input.period(),
+		Summary:      map[string]any{"settings": settings, "no_activity": summary},
+		Findings: []domain.AuditFinding{{
+			Id:                      "no_activity",
+			Resource:                domain.ResourceDef{Platform: "Databricks", Service: resourceType, Name: "workspace"},
+			Issue:                   "no_activity",
+			Description:             description,
+			Recommendation:          recommendation,
+			Severity:                severity,
+			EstimatedMonthlySavings: noSavings(""),
+		}},
+	}
+	summarizeSavings(&report)
+	return report
+}
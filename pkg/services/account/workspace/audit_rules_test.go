@@ -0,0 +1,139 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func staticRule(name, resourceType string, findings []domain.AuditFinding, err error) AuditRule {
+	return NewAuditRule(name, resourceType, name,
+		func(context.Context, *AuditInput) ([]domain.AuditFinding, error) {
+			return findings, err
+		})
+}
+
+func TestAuditRegistry(t *testing.T) {
+	registry := NewAuditRegistry()
+	require.NoError(t, registry.Register(staticRule("wh_a", "warehouse", nil, nil)))
+	require.NoError(t, registry.Register(staticRule("wh_b", "warehouse", nil, nil)))
+	require.NoError(t, registry.Register(staticRule("cl_a", "cluster", nil, nil)))
+
+	assert.Error(t, registry.Register(staticRule("wh_a", "cluster", nil, nil)), "names are unique")
+	assert.Error(t, registry.Register(staticRule("", "cluster", nil, nil)))
+	assert.Equal(t, []string{"cluster", "warehouse"}, registry.ResourceTypes())
+
+	names := func(rules []AuditRule) []string {
+		var result []string
+		for _, rule := range rules {
+			result = append(result, rule.Name())
+		}
+		return result
+	}
+
+	rules, err := registry.Rules(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cl_a", "wh_a", "wh_b"}, names(rules))
+
+	rules, err = registry.Rules([]string{"warehouse"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"wh_a", "wh_b"}, names(rules))
+
+	rules, err = registry.Rules(nil, []string{"wh_b", "cl_a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cl_a", "wh_b"}, names(rules))
+
+	_, err = registry.Rules([]string{"endpoint"}, nil)
+	assert.Error(t, err)
+	_, err = registry.Rules(nil, []string{"missing"})
+	assert.Error(t, err)
+	_, err = registry.Rules([]string{"cluster"}, []string{"wh_a"})
+	assert.Error(t, err, "nothing matches both filters")
+}
+
+func TestDefaultAuditRegistry(t *testing.T) {
+	registry := DefaultAuditRegistry()
+	assert.Equal(t, []string{"cluster", "dlt_pipeline", "endpoint", "job", "warehouse"}, registry.ResourceTypes())
+
+	rules, err := registry.Rules([]string{"cluster"}, nil)
+	require.NoError(t, err)
+	assert.Len(t, rules, 4)
+}
+
+func TestRunAudit(t *testing.T) {
+	ctx := context.Background()
+	input := &AuditInput{
+		Workspace: domain.Workspace{Name: "ws1"},
+		StartTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		Settings:  DefaultAuditSettings(),
+	}
+	rules := []AuditRule{
+		staticRule("a", "warehouse", []domain.AuditFinding{
+			{Id: "wh_1_a", Severity: domain.SeverityHigh},
+			{Id: "wh_2_a", Severity: domain.SeverityLow},
+		}, nil),
+		staticRule("b", "cluster", nil, errors.New("boom")),
+		staticRule("c", "warehouse", []domain.AuditFinding{{Id: "wh_1_c", Severity: domain.SeverityMedium}}, nil),
+	}
+
+	report := RunAudit(ctx, input, rules)
+
+	assert.Equal(t, "ws1", report.Workspace)
+	assert.Equal(t, "warehouse,cluster", report.ResourceType)
+	assert.Equal(t, 7, report.Period.Duration)
+	assert.Len(t, report.Findings, 3)
+	require.Len(t, report.Rules, 3)
+	assert.Equal(t, "b", report.Rules[1].Rule)
+	assert.Equal(t, "boom", report.Rules[1].Error)
+	assert.Equal(t, 2, report.Rules[0].Findings)
+	assert.Empty(t, report.Rules[2].Error)
+
+	assert.Equal(t, 3, report.Summary["rules_evaluated"])
+	assert.Equal(t, 1, report.Summary["rules_failed"])
+	assert.Equal(t, map[string]int{"warehouse": 3, "cluster": 0}, report.Summary["findings_by_resource_type"])
+	assert.Equal(t, 1, report.Summary["high_severity_findings"])
+	assert.Equal(t, 3, report.Summary["total_findings"])
+	assert.Contains(t, report.Summary["audit_status"], "1 of 3 rules failed")
+}
+
+func TestRunAudit_SharesLoadedData(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	costManager := new(MockCostManager)
+	costManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), start, end).
+		Return([]domain.ResourceCost{clusterRecord("c1", start, 20, 50)}, nil).Once()
+
+	input := &AuditInput{
+		Workspace:   domain.Workspace{Name: "ws1"},
+		StartTime:   start,
+		EndTime:     end,
+		CostManager: costManager,
+		Settings:    DefaultAuditSettings(),
+	}
+	rules, err := DefaultAuditRegistry().Rules([]string{"cluster"}, nil)
+	require.NoError(t, err)
+
+	report := RunAudit(ctx, input, rules)
+
+	// usage is read once, rules needing workspace metadata fail without an explorer
+	costManager.AssertExpectations(t)
+	results := map[string]domain.AuditRuleResult{}
+	for _, result := range report.Rules {
+		results[result.Rule] = result
+	}
+	assert.Empty(t, results["cluster_runtime"].Error)
+	assert.Equal(t, 1, results["cluster_runtime"].Findings)
+	assert.Equal(t, errExplorerUnavailable.Error(), results["cluster_configuration"].Error)
+	assert.Equal(t, errExplorerUnavailable.Error(), results["cluster_utilization"].Error)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, "c1_long_runtime", report.Findings[0].Id)
+}
@@ -0,0 +1,163 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AuditSettingsProvider resolves the audit settings of a workspace,
+// global defaults are layered over the built-in defaults and workspace overrides over the global ones
+type AuditSettingsProvider struct {
+	defaults   AuditSettings
+	workspaces map[string]AuditSettings
+}
+
+// auditSettingsFile is the layout of the audit settings file, e.g.
+//
+//	defaults:
+//	  warehouse:
+//	    max_runtime_hours: 10
+//	workspaces:
+//	  production:
+//	    cluster:
+//	      low_cpu_percent: 10
+type auditSettingsFile struct {
+	Defaults   yaml.Node            `yaml:"defaults"`
+	Workspaces map[string]yaml.Node `yaml:"workspaces"`
+}
+
+// NewAuditSettingsProvider returns a provider serving the built-in defaults to every workspace
+func NewAuditSettingsProvider() *AuditSettingsProvider {
+	return &AuditSettingsProvider{
+		defaults:   DefaultAuditSettings(),
+		workspaces: make(map[string]AuditSettings),
+	}
+}
+
+// LoadAuditSettings reads the audit settings file at path, settings missing from the file keep their defaults
+func LoadAuditSettings(path string) (*AuditSettingsProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit settings: %w", err)
+	}
+	return ParseAuditSettings(data)
+}
+
+// ParseAuditSettings parses audit settings in the layout of the settings file
+func ParseAuditSettings(data []byte) (*AuditSettingsProvider, error) {
+	var file auditSettingsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid audit settings: %w", err)
+	}
+
+	provider := NewAuditSettingsProvider()
+	if err := decodeAuditSettings(&file.Defaults, &provider.defaults); err != nil {
+		return nil, fmt.Errorf("invalid audit settings defaults: %w", err)
+	}
+	for name, node := range file.Workspaces {
+		settings := provider.defaults
+		if err := decodeAuditSettings(&node, &settings); err != nil {
+			return nil, fmt.Errorf("invalid audit settings of workspace %s: %w", name, err)
+		}
+		provider.workspaces[name] = settings
+	}
+	return provider, nil
+}
+
+// decodeAuditSettings overwrites the settings present in the node, rejecting unknown and negative settings
+func decodeAuditSettings(node *yaml.Node, settings *AuditSettings) error {
+	if node.IsZero() {
+		return nil
+	}
+
+	// yaml.Node.Decode does not support strict decoding, so the node is re-encoded
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(settings); err != nil {
+		return err
+	}
+	return settings.Validate()
+}
+
+// ForWorkspace returns the settings of the workspace, the global defaults if it has no overrides
+func (p *AuditSettingsProvider) ForWorkspace(name string) AuditSettings {
+	if settings, ok := p.workspaces[name]; ok {
+		return settings
+	}
+	return p.defaults
+}
+
+// Validate checks that no threshold is negative
+func (s AuditSettings) Validate() error {
+	for key, field := range auditSettingFields(reflect.ValueOf(&s).Elem()) {
+		if (field.CanInt() && field.Int() < 0) || (field.CanFloat() && field.Float() < 0) {
+			return fmt.Errorf("audit setting %s must not be negative", key)
+		}
+	}
+	return nil
+}
+
+// Override sets the setting identified by a dotted key, e.g. cluster.low_cpu_percent
+func (s *AuditSettings) Override(key, value string) error {
+	field, ok := auditSettingFields(reflect.ValueOf(s).Elem())[key]
+	if !ok {
+		return fmt.Errorf("unknown audit setting %s", key)
+	}
+
+	switch field.Kind() {
+	case reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid audit setting %s. Expected a non-negative integer", key)
+		}
+		field.SetInt(int64(parsed))
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid audit setting %s. Expected a non-negative number", key)
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("audit setting %s cannot be overridden", key)
+	}
+	return nil
+}
+
+// IsAuditSettingKey reports whether the key has the form of a dotted audit setting key
+func IsAuditSettingKey(key string) bool {
+	section, _, found := strings.Cut(key, ".")
+	if !found {
+		return false
+	}
+	settingsType := reflect.TypeOf(AuditSettings{})
+	for i := 0; i < settingsType.NumField(); i++ {
+		if settingsType.Field(i).Tag.Get("json") == section {
+			return true
+		}
+	}
+	return false
+}
+
+// auditSettingFields maps the dotted keys of the settings to their fields
+func auditSettingFields(settings reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < settings.NumField(); i++ {
+		section := settings.Type().Field(i).Tag.Get("json")
+		group := settings.Field(i)
+		for j := 0; j < group.NumField(); j++ {
+			key := section + "." + group.Type().Field(j).Tag.Get("json")
+			fields[key] = group.Field(j)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,72 @@
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuditSettings(t *testing.T) {
+	t.Run("defaults and workspace overrides", func(t *testing.T) {
+		provider, err := ParseAuditSettings([]byte(`
+defaults:
+  warehouse:
+    max_runtime_hours: 10
+  job:
+    min_wasted_cost: 25
+workspaces:
+  production:
+    warehouse:
+      max_idle_hours: 1
+    cluster:
+      low_cpu_percent: 10
+`))
+		require.NoError(t, err)
+
+		other := provider.ForWorkspace("staging")
+		assert.Equal(t, 10.0, other.Warehouse.MaxRuntimeHours)
+		assert.Equal(t, 25.0, other.Job.MinWastedCost)
+		assert.Equal(t, DefaultClusterAuditSettings(), other.Cluster)
+
+		production := provider.ForWorkspace("production")
+		assert.Equal(t, 10.0, production.Warehouse.MaxRuntimeHours, "global defaults apply to overridden workspaces")
+		assert.Equal(t, 1.0, production.Warehouse.MaxIdleHours)
+		assert.Equal(t, 10.0, production.Cluster.LowCPUPercent)
+		assert.Equal(t, DefaultClusterAuditSettings().HighCPUPercent, production.Cluster.HighCPUPercent)
+		assert.Equal(t, 25.0, production.Job.MinWastedCost)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		provider, err := ParseAuditSettings(nil)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultAuditSettings(), provider.ForWorkspace("any"))
+	})
+
+	t.Run("unknown setting", func(t *testing.T) {
+		_, err := ParseAuditSettings([]byte("defaults:\n  cluster:\n    max_cpu: 10\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("negative setting", func(t *testing.T) {
+		_, err := ParseAuditSettings([]byte("workspaces:\n  production:\n    job:\n      recent_runs: -1\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestAuditSettingsOverride(t *testing.T) {
+	settings := DefaultAuditSettings()
+
+	require.NoError(t, settings.Override("endpoint.min_compute_hours", "12.5"))
+	require.NoError(t, settings.Override("warehouse.stale_resource_days", "7"))
+	assert.Equal(t, 12.5, settings.Endpoint.MinComputeHours)
+	assert.Equal(t, 7, settings.Warehouse.StaleResourceDays)
+
+	assert.Error(t, settings.Override("warehouse.stale_resource_days", "1.5"))
+	assert.Error(t, settings.Override("cluster.low_cpu_percent", "-1"))
+	assert.Error(t, settings.Override("cluster.unknown", "1"))
+
+	assert.True(t, IsAuditSettingKey("dlt_pipeline.min_maintenance_events"))
+	assert.False(t, IsAuditSettingKey("from"))
+	assert.False(t, IsAuditSettingKey("other.key"))
+}
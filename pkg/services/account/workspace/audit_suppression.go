@@ -0,0 +1,188 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/google/uuid"
+)
+
+var ErrSuppressionNotFound = errors.New("audit suppression not found")
+
+// AuditSuppressionStore is the minimal interface required to keep audit suppressions
+type AuditSuppressionStore interface {
+	AddSuppression(ctx context.Context, suppression store.AuditSuppression) error
+	ListSuppressions(ctx context.Context, workspace string) ([]store.AuditSuppression, error)
+	DeleteSuppression(ctx context.Context, id string) error
+}
+
+// AuditSuppressions manages acknowledged and suppressed findings
+type AuditSuppressions interface {
+	Create(ctx context.Context, suppression domain.AuditSuppression) (domain.AuditSuppression, error)
+	// List returns the suppressions applying to the workspace, all suppressions when workspace is empty
+	List(ctx context.Context, workspace string) ([]domain.AuditSuppression, error)
+	Delete(ctx context.Context, id string) error
+	// Apply marks acknowledged findings of the report and moves suppressed ones out of its findings
+	Apply(ctx context.Context, report *domain.AuditReport) error
+}
+
+type auditSuppressions struct {
+	store AuditSuppressionStore
+	now   func() time.Time
+}
+
+func NewAuditSuppressions(store AuditSuppressionStore) AuditSuppressions {
+	return &auditSuppressions{store: store, now: time.Now}
+}
+
+func (s *auditSuppressions) Create(
+	ctx context.Context,
+	suppression domain.AuditSuppression,
+) (domain.AuditSuppression, error) {
+	if suppression.Action == "" {
+		suppression.Action = domain.SuppressionActionSuppress
+	}
+	if err := ValidateAuditSuppression(suppression); err != nil {
+		return domain.AuditSuppression{}, err
+	}
+
+	suppression.ID = uuid.NewString()
+	suppression.CreatedAt = s.now().UTC()
+	if err := s.store.AddSuppression(ctx, adapters.MapAuditSuppressionDomainToStore(suppression)); err != nil {
+		return domain.AuditSuppression{}, err
+	}
+	return suppression, nil
+}
+
+func (s *auditSuppressions) List(ctx context.Context, workspace string) ([]domain.AuditSuppression, error) {
+	suppressions, err := s.store.ListSuppressions(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.AuditSuppression, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		result = append(result, adapters.MapAuditSuppressionStoreToDomain(suppression))
+	}
+	return result, nil
+}
+
+func (s *auditSuppressions) Delete(ctx context.Context, id string) error {
+	err := s.store.DeleteSuppression(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSuppressionNotFound
+	}
+	return err
+}
+
+func (s *auditSuppressions) Apply(ctx context.Context, report *domain.AuditReport) error {
+	suppressions, err := s.List(ctx, report.Workspace)
+	if err != nil {
+		return fmt.Errorf("failed to load audit suppressions: %w", err)
+	}
+	ApplyAuditSuppressions(report, suppressions, s.now())
+	return nil
+}
+
+// ValidateAuditSuppression checks that the suppression matches findings and is accounted for
+func ValidateAuditSuppression(suppression domain.AuditSuppression) error {
+	if suppression.FindingID == "" && suppression.Issue == "" {
+		return fmt.Errorf("suppression must match a finding id or an issue")
+	}
+	if suppression.FindingID != "" && (suppression.Issue != "" || suppression.ResourcePattern != "") {
+		return fmt.Errorf("suppression matches either a finding id or an issue and resource pattern")
+	}
+	if _, err := path.Match(suppression.ResourcePattern, ""); err != nil {
+		return fmt.Errorf("invalid resource pattern %s: %w", suppression.ResourcePattern, err)
+	}
+	if suppression.Action != domain.SuppressionActionAcknowledge && suppression.Action != domain.SuppressionActionSuppress {
+		return fmt.Errorf("invalid suppression action %s. Expected acknowledge or suppress", suppression.Action)
+	}
+	if suppression.Reason == "" || suppression.Owner == "" {
+		return fmt.Errorf("suppression must have a reason and an owner")
+	}
+	return nil
+}
+
+// ApplyAuditSuppressions applies the suppressions active at the time to the report,
+// the counts of acknowledged and suppressed findings are added to the summary
+func ApplyAuditSuppressions(report *domain.AuditReport, suppressions []domain.AuditSuppression, at time.Time) {
+	var active []domain.AuditSuppression
+	for _, suppression := range suppressions {
+		if suppression.Workspace != "" && suppression.Workspace != report.Workspace {
+			continue
+		}
+		if suppression.ExpiresAt != nil && !suppression.ExpiresAt.After(at) {
+			continue
+		}
+		active = append(active, suppression)
+	}
+
+	findings := make([]domain.AuditFinding, 0, len(report.Findings))
+	acknowledged := 0
+	for _, finding := range report.Findings {
+		suppression, ok := matchSuppression(finding, active)
+		if !ok {
+			findings = append(findings, finding)
+			continue
+		}
+
+		finding.SuppressionID = suppression.ID
+		if suppression.Action == domain.SuppressionActionAcknowledge {
+			acknowledged++
+			findings = append(findings, finding)
+		} else {
+			finding.Status = domain.FindingStatusSuppressed
+			report.Suppressed = append(report.Suppressed, finding)
+		}
+	}
+	report.Findings = findings
+
+	if report.Summary == nil {
+		report.Summary = map[string]any{}
+	}
+	report.Summary["acknowledged_findings"] = acknowledged
+	report.Summary["suppressed_findings"] = len(report.Suppressed)
+	if len(report.Suppressed) == 0 {
+		return
+	}
+	addSavingsSummary(report)
+
+	// findings counts reflect the findings left in the report
+	severityCounts := map[domain.Severity]int{}
+	for _, finding := range report.Findings {
+		severityCounts[finding.Severity]++
+	}
+	report.Summary["high_severity_findings"] = severityCounts[domain.SeverityHigh]
+	report.Summary["medium_severity_findings"] = severityCounts[domain.SeverityMedium]
+	report.Summary["low_severity_findings"] = severityCounts[domain.SeverityLow]
+	report.Summary["total_findings"] = len(report.Findings)
+}
+
+// matchSuppression returns the first suppression matching the finding, finding id matches take precedence
+func matchSuppression(finding domain.AuditFinding, suppressions []domain.AuditSuppression) (domain.AuditSuppression, bool) {
+	for _, suppression := range suppressions {
+		if suppression.FindingID != "" && suppression.FindingID == finding.Id {
+			return suppression, true
+		}
+	}
+	for _, suppression := range suppressions {
+		if suppression.Issue == "" || suppression.Issue != finding.Issue {
+			continue
+		}
+		if suppression.ResourcePattern == "" {
+			return suppression, true
+		}
+		if matched, _ := path.Match(suppression.ResourcePattern, finding.Resource.Name); matched {
+			return suppression, true
+		}
+	}
+	return domain.AuditSuppression{}, false
+}
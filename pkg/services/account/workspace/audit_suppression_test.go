@@ -0,0 +1,86 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyAuditSuppressions(t *testing.T) {
+	now := time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	finding := func(id, issue, resource string, severity domain.Severity) domain.AuditFinding {
+		return domain.AuditFinding{Id: id, Issue: issue, Severity: severity, Resource: domain.ResourceDef{Name: resource}}
+	}
+	report := &domain.AuditReport{
+		Workspace: "ws-1",
+		Findings: []domain.AuditFinding{
+			finding("wh-1_auto_stop_disabled", "auto_stop_disabled", "wh-1", domain.SeverityHigh),
+			finding("etl-1_long_runtime", "long_runtime", "etl-1", domain.SeverityMedium),
+			finding("adhoc_long_runtime", "long_runtime", "adhoc", domain.SeverityMedium),
+			finding("gpu-1_gpu_cost", "gpu_cost", "gpu-1", domain.SeverityLow),
+		},
+		Summary: map[string]any{"total_findings": 4, "high_severity_findings": 1},
+	}
+	report.Findings[1].EstimatedMonthlySavings = domain.SavingsEstimate{Amount: 40, Currency: "USD"}
+	report.Findings[2].EstimatedMonthlySavings = domain.SavingsEstimate{Amount: 10, Currency: "USD"}
+
+	ApplyAuditSuppressions(report, []domain.AuditSuppression{
+		{ID: "ack", FindingID: "wh-1_auto_stop_disabled", Action: domain.SuppressionActionAcknowledge},
+		{ID: "etl", Workspace: "ws-1", Issue: "long_runtime", ResourcePattern: "etl-*", Action: domain.SuppressionActionSuppress},
+		{ID: "other-ws", Workspace: "ws-2", Issue: "gpu_cost", Action: domain.SuppressionActionSuppress},
+		{ID: "expired", FindingID: "adhoc_long_runtime", Action: domain.SuppressionActionSuppress, ExpiresAt: &expired},
+	}, now)
+
+	require.Len(t, report.Findings, 3)
+	assert.Equal(t, "ack", report.Findings[0].SuppressionID)
+	assert.Empty(t, report.Findings[1].SuppressionID)
+	require.Len(t, report.Suppressed, 1)
+	assert.Equal(t, "etl-1_long_runtime", report.Suppressed[0].Id)
+	assert.Equal(t, "etl", report.Suppressed[0].SuppressionID)
+	assert.Equal(t, domain.FindingStatusSuppressed, report.Suppressed[0].Status)
+
+	assert.Equal(t, 1, report.Summary["acknowledged_findings"])
+	assert.Equal(t, 1, report.Summary["suppressed_findings"])
+	assert.Equal(t, 3, report.Summary["total_findings"])
+	assert.Equal(t, 1, report.Summary["medium_severity_findings"])
+	assert.Equal(t, 10.0, report.Summary["total_addressable_savings"])
+}
+
+func TestValidateAuditSuppression(t *testing.T) {
+	valid := domain.AuditSuppression{
+		Issue: "long_runtime", ResourcePattern: "etl-*", Action: domain.SuppressionActionSuppress,
+		Reason: "streaming", Owner: "data-eng",
+	}
+	assert.NoError(t, ValidateAuditSuppression(valid))
+
+	for name, update := range map[string]func(s *domain.AuditSuppression){
+		"no match":        func(s *domain.AuditSuppression) { s.Issue, s.ResourcePattern = "", "" },
+		"id and issue":    func(s *domain.AuditSuppression) { s.FindingID = "etl-1_long_runtime" },
+		"invalid pattern": func(s *domain.AuditSuppression) { s.ResourcePattern = "etl-[" },
+		"invalid action":  func(s *domain.AuditSuppression) { s.Action = "ignore" },
+		"no owner":        func(s *domain.AuditSuppression) { s.Owner = "" },
+		"no reason":       func(s *domain.AuditSuppression) { s.Reason = "" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			suppression := valid
+			update(&suppression)
+			assert.Error(t, ValidateAuditSuppression(suppression))
+		})
+	}
+}
+
+func TestDiffAuditFindings_Suppressed(t *testing.T) {
+	report := &domain.AuditReport{
+		Findings:   []domain.AuditFinding{{Id: "a"}},
+		Suppressed: []domain.AuditFinding{{Id: "b", Status: domain.FindingStatusSuppressed}},
+	}
+	diffAuditFindings(report, []domain.AuditFinding{{Id: "a"}, {Id: "b"}, {Id: "c"}}, nil)
+
+	assert.Equal(t, domain.FindingStatusOpen, report.Findings[0].Status)
+	require.Len(t, report.Resolved, 1, "suppressed findings are not resolved")
+	assert.Equal(t, "c", report.Resolved[0].Id)
+}
@@ -0,0 +1,516 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	clusterRule := func(
+		name, description string,
+		requires func(data clusterAuditData) error,
+		analyze func(stats []*ClusterStats, settings ClusterAuditSettings) []domain.AuditFinding,
+	) AuditRule {
+		return NewAuditRule(name, "cluster", description,
+			func(ctx context.Context, in *AuditInput) ([]domain.AuditFinding, error) {
+				data, err := clusterAuditDataFor(ctx, in)
+				if err != nil {
+					return nil, err
+				}
+				if err := requires(data); err != nil {
+					return nil, err
+				}
+				return analyze(data.stats, in.Settings.Cluster), nil
+			})
+	}
+	metadata := func(data clusterAuditData) error { return data.metadataErr }
+	utilization := func(data clusterAuditData) error { return data.utilizationErr }
+	usage := func(clusterAuditData) error { return nil }
+
+	RegisterAuditRule(clusterRule("cluster_configuration", "Auto-termination and autoscaling of all-purpose clusters",
+		metadata, analyzeClusterConfiguration))
+	RegisterAuditRule(clusterRule("cluster_utilization", "Top under- and overutilized clusters by CPU and memory",
+		utilization, analyzeClusterUtilization))
+	RegisterAuditRule(clusterRule("cluster_runtime", "Clusters running continuously for long periods",
+		usage, analyzeClusterRuntime))
+	RegisterAuditRule(clusterRule("cluster_gpu", "Cost of GPU clusters",
+		metadata, analyzeGPUClusters))
+}
+
+// ClusterAuditSettings contains configurable thresholds for cluster audit analysis
+type ClusterAuditSettings struct {
+	// MaxAutoterminationMinutes is the longest acceptable auto-termination timeout of all-purpose clusters (default: 120)
+	MaxAutoterminationMinutes int `json:"max_autotermination_minutes" yaml:"max_autotermination_minutes"`
+	// LowCPUPercent is the average CPU utilization below which a cluster is underutilized (default: 20)
+	LowCPUPercent float64 `json:"low_cpu_percent" yaml:"low_cpu_percent"`
+	// LowMemoryPercent is the average memory utilization below which a cluster is underutilized (default: 30)
+	LowMemoryPercent float64 `json:"low_memory_percent" yaml:"low_memory_percent"`
+	// HighCPUPercent is the average CPU utilization above which a cluster is overutilized (default: 90)
+	HighCPUPercent float64 `json:"high_cpu_percent" yaml:"high_cpu_percent"`
+	// HighMemoryPercent is the peak memory utilization above which a cluster is overutilized (default: 95)
+	HighMemoryPercent float64 `json:"high_memory_percent" yaml:"high_memory_percent"`
+	// MinNodeHours is the minimum node hours of telemetry required to judge utilization (default: 1)
+	MinNodeHours float64 `json:"min_node_hours" yaml:"min_node_hours"`
+	// TopUtilizationCount is the number of most under- and overutilized clusters to report (default: 5)
+	TopUtilizationCount int `json:"top_utilization_count" yaml:"top_utilization_count"`
+	// MaxRuntimeHours is the threshold for flagging clusters running continuously for too long (default: 12)
+	MaxRuntimeHours float64 `json:"max_runtime_hours" yaml:"max_runtime_hours"`
+}
+
+// DefaultClusterAuditSettings returns the default configuration for cluster audits
+func DefaultClusterAuditSettings() ClusterAuditSettings {
+	return ClusterAuditSettings{
+		MaxAutoterminationMinutes: 120,
+		LowCPUPercent:             20,
+		LowMemoryPercent:          30,
+		HighCPUPercent:            90,
+		HighMemoryPercent:         95,
+		MinNodeHours:              1,
+		TopUtilizationCount:       5,
+		MaxRuntimeHours:           12,
+	}
+}
+
+// ClusterStats holds billing, runtime and utilization data of a cluster
+type ClusterStats struct {
+	ClusterID       string
+	Metadata        *domain.ClusterMetadata    // nil for clusters that no longer exist
+	Utilization     *domain.ClusterUtilization // nil without node timeline data
+	TotalCost       float64
+	Currency        string
+	UsageHours      float64
+	LongestRunHours float64 // longest period of back-to-back usage records
+}
+
+// GetClusterAudit performs an audit of all-purpose and job clusters for the given workspace and time period,
+// analyzing billing, auto-termination and autoscaling settings, utilization, runtime and GPU usage
+func GetClusterAudit(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	explorer Explorer,
+	settings ClusterAuditSettings,
+) (domain.AuditReport, error) {
+	input := &AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Explorer:    explorer,
+		Settings:    AuditSettings{Cluster: settings},
+	}
+
+	data, err := clusterAuditDataFor(ctx, input)
+	if err != nil {
+		return domain.AuditReport{}, err
+	}
+	if len(data.stats) == 0 {
+		return noActivityReport(input, "cluster", settings,
+			"No cluster usage records found in the selected period",
+			"No cluster activity detected in the selected time window.",
+			"Verify cluster configurations and usage patterns.",
+			domain.SeverityLow), nil
+	}
+
+	return runResourceAudit(ctx, input, "cluster", func(report *domain.AuditReport) {
+		report.Summary["settings"] = settings
+		generateClusterSummaryMetrics(report, data.stats, data.utilizationErr == nil)
+	})
+}
+
+// clusterAuditDataFor returns the cached cluster stats of the audit run
+func clusterAuditDataFor(ctx context.Context, in *AuditInput) (clusterAuditData, error) {
+	return loadAuditData(in, "cluster", func() (clusterAuditData, error) {
+		return loadClusterAuditData(ctx, in.Workspace, in.StartTime, in.EndTime, in.CostManager, in.Explorer)
+	})
+}
+
+// clusterAuditData holds the cluster stats of an audit and the errors of its optional data sources
+type clusterAuditData struct {
+	stats          []*ClusterStats
+	metadataErr    error
+	utilizationErr error
+}
+
+// loadClusterAuditData reads cluster usage, metadata and utilization, only failing to read usage is an error
+func loadClusterAuditData(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	explorer Explorer,
+) (clusterAuditData, error) {
+	resources := domain.WorkspaceResources{
+		WorkspaceName: ws.Name,
+		Resources:     []string{"cluster"},
+	}
+	records, err := costManager.GetResourcesCost(ctx, resources, startTime, endTime)
+	if err != nil {
+		return clusterAuditData{}, err
+	}
+
+	data := clusterAuditData{metadataErr: errExplorerUnavailable, utilizationErr: errExplorerUnavailable}
+	var clusters []domain.ClusterMetadata
+	var utilization []domain.ClusterUtilization
+	if explorer != nil {
+		logger := zerolog.Ctx(ctx)
+		if clusters, data.metadataErr = explorer.ListClusters(ctx); data.metadataErr != nil {
+			logger.Warn().Err(data.metadataErr).Msg("cluster metadata unavailable, skipping configuration checks")
+		}
+		if utilization, data.utilizationErr = explorer.GetClusterUtilization(ctx, startTime, endTime); data.utilizationErr != nil {
+			logger.Warn().Err(data.utilizationErr).Msg("cluster utilization unavailable, skipping utilization checks")
+		}
+	}
+
+	data.stats = aggregateClusterStats(records, clusters, utilization)
+	return data, nil
+}
+
+// aggregateClusterStats joins usage records, cluster metadata and utilization by cluster ID,
+// the result is ordered by cluster ID
+func aggregateClusterStats(
+	records []domain.ResourceCost,
+	clusters []domain.ClusterMetadata,
+	utilization []domain.ClusterUtilization,
+) []*ClusterStats {
+	statsByID := make(map[string]*ClusterStats)
+	get := func(clusterID string) *ClusterStats {
+		if s, ok := statsByID[clusterID]; ok {
+			return s
+		}
+		s := &ClusterStats{ClusterID: clusterID}
+		statsByID[clusterID] = s
+		return s
+	}
+
+	recordsByID := make(map[string][]domain.ResourceCost)
+	for _, record := range records {
+		s := get(record.Resource.Name)
+		s.UsageHours += record.EndTime.Sub(record.StartTime).Hours()
+		for _, cost := range record.Costs {
+			s.TotalCost += cost.TotalAmount
+			if s.Currency == "" {
+				s.Currency = cost.Currency
+			}
+		}
+		recordsByID[record.Resource.Name] = append(recordsByID[record.Resource.Name], record)
+	}
+	for clusterID, clusterRecords := range recordsByID {
+		statsByID[clusterID].LongestRunHours = longestContinuousRun(clusterRecords).Hours()
+	}
+
+	for i := range clusters {
+		get(clusters[i].ID).Metadata = &clusters[i]
+	}
+	for i := range utilization {
+		// only report utilization for clusters billed in the period or still present in the workspace
+		if s, ok := statsByID[utilization[i].ClusterID]; ok {
+			s.Utilization = &utilization[i]
+		}
+	}
+
+	stats := make([]*ClusterStats, 0, len(statsByID))
+	for _, s := range statsByID {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ClusterID < stats[j].ClusterID })
+	return stats
+}
+
+// usageInterval is a period covered by one or more usage records
+type usageInterval struct {
+	start, end time.Time
+}
+
+// mergeUsageIntervals merges overlapping or adjacent usage records into intervals ordered by start time
+func mergeUsageIntervals(records []domain.ResourceCost) []usageInterval {
+	sorted := make([]domain.ResourceCost, len(records))
+	copy(sorted, records)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	var intervals []usageInterval
+	for _, record := range sorted {
+		last := len(intervals) - 1
+		if last < 0 || record.StartTime.After(intervals[last].end) {
+			intervals = append(intervals, usageInterval{start: record.StartTime, end: record.EndTime})
+		} else if record.EndTime.After(intervals[last].end) {
+			intervals[last].end = record.EndTime
+		}
+	}
+	return intervals
+}
+
+// longestContinuousRun returns the longest period of back-to-back usage records
+func longestContinuousRun(records []domain.ResourceCost) time.Duration {
+	var longest time.Duration
+	for _, interval := range mergeUsageIntervals(records) {
+		if d := interval.end.Sub(interval.start); d > longest {
+			longest = d
+		}
+	}
+	return longest
+}
+
+// isInteractiveCluster reports whether the cluster is an all-purpose cluster created from the UI or the API,
+// job and pipeline clusters are terminated with their run and are not subject to configuration checks
+func isInteractiveCluster(metadata *domain.ClusterMetadata) bool {
+	return metadata != nil && (metadata.Source == "UI" || metadata.Source == "API")
+}
+
+func clusterResource(s *ClusterStats) domain.ResourceDef {
+	resource := domain.ResourceDef{
+		Platform: "Databricks",
+		Service:  "cluster",
+		Name:     s.ClusterID,
+	}
+	if s.Metadata != nil && s.Metadata.Name != "" {
+		resource.Description = fmt.Sprintf("Databricks cluster %s", s.Metadata.Name)
+		resource.Metadata = map[string]string{"cluster_name": s.Metadata.Name}
+	}
+	return resource
+}
+
+// idleCost estimates the cost of a cluster idling, from its utilization when known
+func idleCost(s *ClusterStats) float64 {
+	if u := s.Utilization; u != nil && u.NodeHours > 0 {
+		return s.TotalCost * (1 - max(u.AvgCPUPercent, u.AvgMemoryPercent)/100)
+	}
+	return s.TotalCost * assumedIdleShare
+}
+
+// rightSizingSavings estimates the savings of shrinking the cluster to the target utilization
+func rightSizingSavings(s *ClusterStats) float64 {
+	u := s.Utilization
+	if u == nil || u.NodeHours == 0 {
+		return s.TotalCost * assumedIdleShare
+	}
+	used := max(u.AvgCPUPercent, u.AvgMemoryPercent)
+	if used >= targetUtilizationPercent {
+		return 0
+	}
+	return s.TotalCost * (1 - used/targetUtilizationPercent)
+}
+
+// analyzeClusterConfiguration checks auto-termination and autoscaling of all-purpose clusters
+func analyzeClusterConfiguration(stats []*ClusterStats, settings ClusterAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, s := range stats {
+		if !isInteractiveCluster(s.Metadata) {
+			continue
+		}
+		metadata := s.Metadata
+
+		switch {
+		case metadata.AutoterminationMinutes == 0:
+			findings = append(findings, domain.AuditFinding{
+				Id:                      fmt.Sprintf("%s_autotermination_disabled", s.ClusterID),
+				Resource:                clusterResource(s),
+				Issue:                   "autotermination_disabled",
+				Description:             "All-purpose cluster has auto-termination disabled and keeps running when idle.",
+				Recommendation:          fmt.Sprintf("Enable auto-termination with a timeout of at most %d minutes.", settings.MaxAutoterminationMinutes),
+				Severity:                domain.SeverityHigh,
+				EstimatedMonthlySavings: periodSavings(idleCost(s), s.Currency, domain.SavingsConfidenceLow),
+			})
+		case metadata.AutoterminationMinutes > settings.MaxAutoterminationMinutes:
+			findings = append(findings, domain.AuditFinding{
+				Id:             fmt.Sprintf("%s_long_autotermination", s.ClusterID),
+				Resource:       clusterResource(s),
+				Issue:          "long_autotermination",
+				Description:    fmt.Sprintf("Cluster auto-terminates after %d minutes of inactivity, exceeding threshold of %d minutes.", metadata.AutoterminationMinutes, settings.MaxAutoterminationMinutes),
+				Recommendation: "Reduce the auto-termination timeout to avoid paying for idle clusters.",
+				Severity:       domain.SeverityMedium,
+				// only the idle tail beyond the threshold is saved
+				EstimatedMonthlySavings: periodSavings(
+					idleCost(s)*(1-float64(settings.MaxAutoterminationMinutes)/float64(metadata.AutoterminationMinutes)),
+					s.Currency, domain.SavingsConfidenceLow),
+			})
+		}
+
+		if !metadata.AutoscalingEnabled && metadata.NumWorkers > 1 {
+			findings = append(findings, domain.AuditFinding{
+				Id:                      fmt.Sprintf("%s_autoscaling_disabled", s.ClusterID),
+				Resource:                clusterResource(s),
+				Issue:                   "autoscaling_disabled",
+				Description:             fmt.Sprintf("Cluster has a fixed size of %d workers.", metadata.NumWorkers),
+				Recommendation:          "Enable autoscaling so the cluster scales down when the workload is light.",
+				Severity:                domain.SeverityLow,
+				EstimatedMonthlySavings: periodSavings(rightSizingSavings(s), s.Currency, domain.SavingsConfidenceLow),
+			})
+		}
+	}
+
+	return findings
+}
+
+// analyzeClusterUtilization reports the top N underutilized clusters by cost and the top N overutilized clusters by CPU
+func analyzeClusterUtilization(stats []*ClusterStats, settings ClusterAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	var underutilized, overutilized []*ClusterStats
+	for _, s := range stats {
+		u := s.Utilization
+		if u == nil || u.NodeHours < settings.MinNodeHours {
+			continue
+		}
+		if u.AvgCPUPercent < settings.LowCPUPercent && u.AvgMemoryPercent < settings.LowMemoryPercent {
+			underutilized = append(underutilized, s)
+		}
+		if u.AvgCPUPercent >= settings.HighCPUPercent || u.PeakMemoryPercent >= settings.HighMemoryPercent {
+			overutilized = append(overutilized, s)
+		}
+	}
+
+	// The most expensive underutilized clusters waste the most
+	sort.SliceStable(underutilized, func(i, j int) bool { return underutilized[i].TotalCost > underutilized[j].TotalCost })
+	sort.SliceStable(overutilized, func(i, j int) bool {
+		return overutilized[i].Utilization.AvgCPUPercent > overutilized[j].Utilization.AvgCPUPercent
+	})
+
+	for rank, s := range underutilized {
+		if rank >= settings.TopUtilizationCount {
+			break
+		}
+		u := s.Utilization
+		severity := domain.SeverityLow
+		if u.AvgCPUPercent < settings.LowCPUPercent/2 {
+			severity = domain.SeverityMedium
+		}
+		findings = append(findings, domain.AuditFinding{
+			Id:       fmt.Sprintf("%s_underutilized", s.ClusterID),
+			Resource: clusterResource(s),
+			Issue:    "underutilized",
+			Description: fmt.Sprintf("Cluster ranks #%d among underutilized clusters: average CPU %.0f%% and memory %.0f%% over %.1f node hours (cost %.2f %s).",
+				rank+1, u.AvgCPUPercent, u.AvgMemoryPercent, u.NodeHours, s.TotalCost, s.Currency),
+			Recommendation:          "Use a smaller node type or fewer workers, enable autoscaling, or consolidate workloads onto shared clusters.",
+			Severity:                severity,
+			EstimatedMonthlySavings: periodSavings(rightSizingSavings(s), s.Currency, domain.SavingsConfidenceMedium),
+		})
+	}
+
+	for rank, s := range overutilized {
+		if rank >= settings.TopUtilizationCount {
+			break
+		}
+		u := s.Utilization
+		findings = append(findings, domain.AuditFinding{
+			Id:       fmt.Sprintf("%s_overutilized", s.ClusterID),
+			Resource: clusterResource(s),
+			Issue:    "overutilized",
+			Description: fmt.Sprintf("Cluster ranks #%d among overutilized clusters: average CPU %.0f%%, peak memory %.0f%%.",
+				rank+1, u.AvgCPUPercent, u.PeakMemoryPercent),
+			Recommendation: "Increase the maximum number of workers or use larger nodes to avoid slow runs and out-of-memory failures.",
+			Severity:       domain.SeverityMedium,
+			// scaling up increases the cost of the cluster
+			EstimatedMonthlySavings: noSavings(s.Currency),
+		})
+	}
+
+	return findings
+}
+
+// analyzeClusterRuntime flags clusters that ran continuously for longer than the threshold
+func analyzeClusterRuntime(stats []*ClusterStats, settings ClusterAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, s := range stats {
+		if s.LongestRunHours <= settings.MaxRuntimeHours {
+			continue
+		}
+		findings = append(findings, domain.AuditFinding{
+			Id:             fmt.Sprintf("%s_long_runtime", s.ClusterID),
+			Resource:       clusterResource(s),
+			Issue:          "long_runtime",
+			Description:    fmt.Sprintf("Cluster ran continuously for %.1f hours, exceeding threshold of %.1f hours.", s.LongestRunHours, settings.MaxRuntimeHours),
+			Recommendation: "Check for idle sessions keeping the cluster alive and move scheduled workloads to job clusters.",
+			Severity:       domain.SeverityMedium,
+			// runtime beyond the threshold is assumed to be avoidable
+			EstimatedMonthlySavings: periodSavings(
+				s.TotalCost*(s.LongestRunHours-settings.MaxRuntimeHours)/max(s.UsageHours, s.LongestRunHours),
+				s.Currency, domain.SavingsConfidenceLow),
+		})
+	}
+
+	return findings
+}
+
+// analyzeGPUClusters reports the cost of GPU clusters, GPU utilization is not part of the node timeline
+func analyzeGPUClusters(stats []*ClusterStats, settings ClusterAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, s := range stats {
+		if s.Metadata == nil || s.Metadata.NumGpus == 0 || s.TotalCost == 0 {
+			continue
+		}
+
+		description := fmt.Sprintf("GPU cluster (%s, %d GPUs per node) cost %.2f %s in the period.",
+			s.Metadata.NodeTypeID, s.Metadata.NumGpus, s.TotalCost, s.Currency)
+		severity := domain.SeverityLow
+		savings := noSavings(s.Currency)
+		if u := s.Utilization; u != nil && u.NodeHours >= settings.MinNodeHours && u.AvgCPUPercent < settings.LowCPUPercent {
+			description += fmt.Sprintf(" Average CPU utilization was only %.0f%%.", u.AvgCPUPercent)
+			severity = domain.SeverityMedium
+			savings = periodSavings(rightSizingSavings(s), s.Currency, domain.SavingsConfidenceLow)
+		}
+
+		findings = append(findings, domain.AuditFinding{
+			Id:                      fmt.Sprintf("%s_gpu_cost", s.ClusterID),
+			Resource:                clusterResource(s),
+			Issue:                   "gpu_cost",
+			Description:             description,
+			Recommendation:          "Confirm the workload needs GPUs, and terminate GPU clusters as soon as training or inference is done.",
+			Severity:                severity,
+			EstimatedMonthlySavings: savings,
+		})
+	}
+
+	return findings
+}
+
+// generateClusterSummaryMetrics creates summary metrics for the cluster audit report
+func generateClusterSummaryMetrics(report *domain.AuditReport, stats []*ClusterStats, utilizationAvailable bool) {
+	totalCost := 0.0
+	gpuCost := 0.0
+	var currency string
+	costByCluster := make(map[string]float64, len(stats))
+	for _, s := range stats {
+		totalCost += s.TotalCost
+		costByCluster[s.ClusterID] = s.TotalCost
+		if s.Metadata != nil && s.Metadata.NumGpus > 0 {
+			gpuCost += s.TotalCost
+		}
+		if currency == "" {
+			currency = s.Currency
+		}
+	}
+
+	report.Summary["clusters_evaluated"] = len(stats)
+	report.Summary["total_cost_analyzed"] = totalCost
+	report.Summary["gpu_cost"] = gpuCost
+	report.Summary["cost_by_cluster"] = costByCluster
+	report.Summary["utilization_available"] = utilizationAvailable
+	if currency != "" {
+		report.Summary["currency"] = currency
+	}
+
+	severityCounts := map[domain.Severity]int{}
+	for _, finding := range report.Findings {
+		severityCounts[finding.Severity]++
+	}
+	report.Summary["high_severity_findings"] = severityCounts[domain.SeverityHigh]
+	report.Summary["medium_severity_findings"] = severityCounts[domain.SeverityMedium]
+	report.Summary["low_severity_findings"] = severityCounts[domain.SeverityLow]
+	report.Summary["total_findings"] = len(report.Findings)
+
+	if len(report.Findings) == 0 {
+		report.Summary["audit_status"] = "All clusters passed audit checks - no issues detected"
+	} else {
+		report.Summary["audit_status"] = fmt.Sprintf("Audit completed - found %d optimization opportunities across %d clusters", len(report.Findings), len(stats))
+	}
+}
@@ -0,0 +1,168 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func clusterRecord(clusterID string, start time.Time, hours int, cost float64) domain.ResourceCost {
+	return domain.ResourceCost{
+		Resource:  domain.ResourceDef{Platform: "Databricks", Service: "cluster", Name: clusterID},
+		StartTime: start,
+		EndTime:   start.Add(time.Duration(hours) * time.Hour),
+		Costs:     []domain.CostComponent{{TotalAmount: cost, Currency: "USD"}},
+	}
+}
+
+func TestGetClusterAudit(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "test-workspace"}
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	settings := DefaultClusterAuditSettings()
+
+	t.Run("no cluster activity", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).
+			Return([]domain.ResourceCost{}, nil)
+		mockExplorer.On("ListClusters", ctx).Return([]domain.ClusterMetadata{}, nil)
+		mockExplorer.On("GetClusterUtilization", ctx, startTime, endTime).Return([]domain.ClusterUtilization{}, nil)
+
+		report, err := GetClusterAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, "cluster", report.ResourceType)
+		require.Len(t, report.Findings, 1)
+		assert.Equal(t, "no_activity", report.Findings[0].Issue)
+		assert.Contains(t, report.Summary, "no_activity")
+	})
+
+	t.Run("findings and summary", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+
+		records := []domain.ResourceCost{
+			// 8 + 8 back-to-back hours form a 16 hour run
+			clusterRecord("interactive", startTime, 8, 40),
+			clusterRecord("interactive", startTime.Add(8*time.Hour), 8, 40),
+			clusterRecord("job-cluster", startTime, 2, 10),
+			clusterRecord("gpu", startTime.Add(24*time.Hour), 4, 100),
+		}
+		mockCostManager.On("GetResourcesCost", ctx, domain.WorkspaceResources{
+			WorkspaceName: "test-workspace",
+			Resources:     []string{"cluster"},
+		}, startTime, endTime).Return(records, nil)
+
+		mockExplorer.On("ListClusters", ctx).Return([]domain.ClusterMetadata{
+			{ID: "interactive", Name: "shared", Source: "UI", NumWorkers: 4},
+			{ID: "job-cluster", Source: "JOB", NumWorkers: 4},
+			{ID: "gpu", Source: "API", NodeTypeID: "g5.xlarge", NumGpus: 1,
+				AutoscalingEnabled: true, MinWorkers: 1, MaxWorkers: 2, AutoterminationMinutes: 240},
+		}, nil)
+		mockExplorer.On("GetClusterUtilization", ctx, startTime, endTime).Return([]domain.ClusterUtilization{
+			{ClusterID: "interactive", NodeHours: 80, AvgCPUPercent: 5, AvgMemoryPercent: 10, PeakMemoryPercent: 20},
+			{ClusterID: "job-cluster", NodeHours: 10, AvgCPUPercent: 95, AvgMemoryPercent: 80, PeakMemoryPercent: 99},
+			{ClusterID: "gpu", NodeHours: 8, AvgCPUPercent: 12, AvgMemoryPercent: 60, PeakMemoryPercent: 70},
+		}, nil)
+
+		report, err := GetClusterAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+		require.NoError(t, err)
+
+		issues := map[string]domain.AuditFinding{}
+		for _, finding := range report.Findings {
+			issues[finding.Id] = finding
+		}
+		assert.Equal(t, domain.SeverityHigh, issues["interactive_autotermination_disabled"].Severity)
+		assert.Contains(t, issues, "interactive_autoscaling_disabled")
+		assert.Equal(t, domain.SeverityMedium, issues["interactive_underutilized"].Severity)
+		assert.Equal(t, "shared", issues["interactive_underutilized"].Resource.Metadata["cluster_name"])
+		assert.Contains(t, issues, "interactive_long_runtime")
+		assert.Contains(t, issues, "job-cluster_overutilized")
+		assert.Contains(t, issues, "gpu_long_autotermination")
+		assert.Equal(t, domain.SeverityMedium, issues["gpu_gpu_cost"].Severity)
+		// job clusters are terminated with their run
+		assert.NotContains(t, issues, "job-cluster_autotermination_disabled")
+		assert.NotContains(t, issues, "job-cluster_autoscaling_disabled")
+		assert.Len(t, report.Findings, 7)
+
+		assert.Equal(t, 3, report.Summary["clusters_evaluated"])
+		assert.InDelta(t, 190.0, report.Summary["total_cost_analyzed"], 0.001)
+		assert.InDelta(t, 100.0, report.Summary["gpu_cost"], 0.001)
+		assert.Equal(t, "USD", report.Summary["currency"])
+		assert.Equal(t, true, report.Summary["utilization_available"])
+		assert.Equal(t, 7, report.Summary["total_findings"])
+		assert.Equal(t, "cluster_gpu", issues["gpu_gpu_cost"].Rule)
+		mockExplorer.AssertNumberOfCalls(t, "ListClusters", 1)
+	})
+
+	t.Run("explorer errors are tolerated", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).
+			Return([]domain.ResourceCost{clusterRecord("c1", startTime, 1, 5)}, nil)
+		mockExplorer.On("ListClusters", ctx).Return(nil, errors.New("forbidden"))
+		mockExplorer.On("GetClusterUtilization", ctx, startTime, endTime).Return(nil, errors.New("no warehouse"))
+
+		report, err := GetClusterAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+		require.NoError(t, err)
+		assert.Empty(t, report.Findings)
+		assert.Equal(t, 1, report.Summary["clusters_evaluated"])
+		assert.Equal(t, false, report.Summary["utilization_available"])
+		assert.Equal(t, 3, report.Summary["rules_failed"])
+		assert.Contains(t, report.Summary["audit_status"], "3 of 4 rules failed")
+	})
+
+	t.Run("cost manager error", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).
+			Return([]domain.ResourceCost{}, errors.New("db down"))
+
+		_, err := GetClusterAudit(ctx, ws, startTime, endTime, mockCostManager, new(MockExplorer), settings)
+		assert.Error(t, err)
+	})
+}
+
+func TestAnalyzeClusterUtilization_TopN(t *testing.T) {
+	settings := DefaultClusterAuditSettings()
+	settings.TopUtilizationCount = 2
+
+	var stats []*ClusterStats
+	for i, cost := range []float64{10, 30, 20} {
+		stats = append(stats, &ClusterStats{
+			ClusterID:   string(rune('a' + i)),
+			TotalCost:   cost,
+			Utilization: &domain.ClusterUtilization{NodeHours: 10, AvgCPUPercent: 15, AvgMemoryPercent: 20},
+		})
+	}
+	// too little telemetry to judge
+	stats = append(stats, &ClusterStats{
+		ClusterID:   "d",
+		TotalCost:   100,
+		Utilization: &domain.ClusterUtilization{NodeHours: 0.5, AvgCPUPercent: 1, AvgMemoryPercent: 1},
+	})
+
+	findings := analyzeClusterUtilization(stats, settings)
+	require.Len(t, findings, 2)
+	assert.Equal(t, "b_underutilized", findings[0].Id)
+	assert.Equal(t, "c_underutilized", findings[1].Id)
+}
+
+func TestLongestContinuousRun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []domain.ResourceCost{
+		clusterRecord("c", start.Add(10*time.Hour), 1, 0),
+		clusterRecord("c", start, 2, 0),
+		clusterRecord("c", start.Add(time.Hour), 2, 0), // overlaps the first hours
+		clusterRecord("c", start.Add(11*time.Hour), 1, 0),
+	}
+	assert.Equal(t, 3*time.Hour, longestContinuousRun(records))
+	assert.Zero(t, longestContinuousRun(nil))
+}
@@ -8,16 +8,37 @@ import (
 	"github.com/de-tools/data-atlas/pkg/models/domain"
 )
 
+func init() {
+	RegisterAuditRule(NewAuditRule("dlt_pipeline_health", "dlt_pipeline",
+		"Maintenance overhead and long running updates of DLT pipelines",
+		func(ctx context.Context, in *AuditInput) ([]domain.AuditFinding, error) {
+			report, err := dltAuditFor(ctx, in)
+			if err != nil {
+				return nil, err
+			}
+			return report.Findings, nil
+		}))
+}
+
 // DLTAuditSettings holds configurable thresholds for DLT audit findings.
 type DLTAuditSettings struct {
 	// MaintenanceRatioThreshold is the minimum share of maintenance events (0..1) to flag overhead.
-	MaintenanceRatioThreshold float64
+	MaintenanceRatioThreshold float64 `json:"maintenance_ratio_threshold" yaml:"maintenance_ratio_threshold"`
 
 	// MinMaintenanceEvents is the minimum count of maintenance events to consider for overhead findings.
-	MinMaintenanceEvents int
+	MinMaintenanceEvents int `json:"min_maintenance_events" yaml:"min_maintenance_events"`
 
 	// LongRunAvgSecondsThreshold is the average update duration in seconds above which updates are flagged as long-running.
-	LongRunAvgSecondsThreshold float64
+	LongRunAvgSecondsThreshold float64 `json:"long_run_avg_seconds_threshold" yaml:"long_run_avg_seconds_threshold"`
+}
+
+// DefaultDLTAuditSettings returns the default configuration for DLT audits
+func DefaultDLTAuditSettings() DLTAuditSettings {
+	return DLTAuditSettings{
+		MaintenanceRatioThreshold:  0.3,
+		MinMaintenanceEvents:       3,
+		LongRunAvgSecondsThreshold: 2 * 3600,
+	}
 }
 
 // GetDLTAudit computes DLT pipelines audit for a workspace over the period.
@@ -27,6 +48,51 @@ func GetDLTAudit(
 	startTime, endTime time.Time,
 	costManager CostManager,
 	settings DLTAuditSettings,
+) (domain.AuditReport, error) {
+	input := &AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Settings:    AuditSettings{DLT: settings},
+	}
+
+	pipelines, err := dltAuditFor(ctx, input)
+	if err != nil {
+		return domain.AuditReport{}, err
+	}
+	if pipelines.Summary["pipelines_evaluated"] == 0 {
+		return noActivityReport(input, "dlt_pipeline", settings,
+			"No DLT usage records found in the selected period",
+			"No DLT pipeline activity detected in the selected time window.",
+			"Verify schedules/triggers and pipeline health. Consider reducing provisioned resources if unused.",
+			domain.SeverityMedium), nil
+	}
+
+	return runResourceAudit(ctx, input, "dlt_pipeline", func(report *domain.AuditReport) {
+		report.Summary["settings"] = settings
+		for _, key := range []string{
+			"pipelines_evaluated", "pipelines_with_high_maintenance_overhead", "pipelines_with_long_running_updates",
+		} {
+			report.Summary[key] = pipelines.Summary[key]
+		}
+	})
+}
+
+// dltAuditFor returns the cached DLT findings and pipeline counts of the audit run
+func dltAuditFor(ctx context.Context, in *AuditInput) (domain.AuditReport, error) {
+	return loadAuditData(in, "dlt_pipeline", func() (domain.AuditReport, error) {
+		return buildDLTAudit(ctx, in.Workspace, in.StartTime, in.EndTime, in.CostManager, in.Settings.DLT)
+	})
+}
+
+// buildDLTAudit computes the DLT findings and pipeline counts with savings estimated over the period
+func buildDLTAudit(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	settings DLTAuditSettings,
 ) (domain.AuditReport, error) {
 	// Collect DLT-related usage
 	resources := domain.WorkspaceResources{WorkspaceName: ws.Name, Resources: []string{"dlt_pipeline", "dlt_update", "dlt_maintenance"}}
@@ -38,6 +104,8 @@ func GetDLTAudit(
 	// Aggregate per pipeline
 	type agg struct {
 		totalCost        float64
+		maintenanceCost  float64
+		updatesCost      float64
 		totalUsage       float64
 		updatesCount     int
 		maintenanceCount int
@@ -53,13 +121,15 @@ func GetDLTAudit(
 			pipelines[id] = &agg{}
 		}
 		a := pipelines[id]
+		cost := 0.0
 		for _, c := range rec.Costs {
-			a.totalCost += c.TotalAmount
+			cost += c.TotalAmount
 			a.totalUsage += c.Value
 			if a.currency == "" {
 				a.currency = c.Currency
 			}
 		}
+		a.totalCost += cost
 		dur := rec.EndTime.Sub(rec.StartTime).Seconds()
 		a.totalDurationSec += dur
 		if dur > a.maxDurationSec {
@@ -68,9 +138,11 @@ func GetDLTAudit(
 		rt := rec.Resource.Service
 		if rt == "dlt_update" {
 			a.updatesCount++
+			a.updatesCost += cost
 		}
 		if rt == "dlt_maintenance" {
 			a.maintenanceCount++
+			a.maintenanceCost += cost
 		}
 	}
 
@@ -82,23 +154,10 @@ func GetDLTAudit(
 			End:      endTime,
 			Duration: int(endTime.Sub(startTime).Hours() / 24),
 		},
-		Summary:  map[string]any{},
+		Summary:  map[string]any{"settings": settings},
 		Findings: []domain.AuditFinding{},
 	}
 
-	if len(records) == 0 {
-		report.Summary["no_activity"] = "No DLT usage records found in the selected period"
-		report.Findings = append(report.Findings, domain.AuditFinding{
-			Id:             "no_activity",
-			Resource:       domain.ResourceDef{Platform: "Databricks", Service: "dlt_pipeline", Name: "workspace"},
-			Issue:          "no_activity",
-			Description:    "No DLT pipeline activity detected in the selected time window.",
-			Recommendation: "Verify schedules/triggers and pipeline health. Consider reducing provisioned resources if unused.",
-			Severity:       domain.SeverityMedium,
-		})
-		return report, nil
-	}
-
 	var totalPipelines, highMaintenance, longRuns int
 
 	for pid, a := range pipelines {
@@ -123,6 +182,9 @@ func GetDLTAudit(
 				Description:    fmt.Sprintf("Maintenance events constitute %.0f%% of pipeline activity.", maintenanceRatio*100),
 				Recommendation: "Reduce pipeline maintenance frequency, consolidate tasks, or review autoscaling/pool warmups.",
 				Severity:       domain.SeverityMedium,
+				// maintenance beyond the threshold share of the activity is saved
+				EstimatedMonthlySavings: periodSavings(a.maintenanceCost*(1-settings.MaintenanceRatioThreshold/maintenanceRatio),
+					a.currency, domain.SavingsConfidenceLow),
 			})
 		}
 
@@ -135,6 +197,9 @@ func GetDLTAudit(
 				Description:    fmt.Sprintf("Average update duration is %.1f hours (max %.1f h).", avgRunSec/3600, a.maxDurationSec/3600),
 				Recommendation: "Consider incremental model optimization, Z-ordering, optimized autoscaling, and proper cluster sizing.",
 				Severity:       domain.SeverityMedium,
+				// updates shortened to the threshold duration
+				EstimatedMonthlySavings: periodSavings(a.updatesCost*(1-settings.LongRunAvgSecondsThreshold/avgRunSec),
+					a.currency, domain.SavingsConfidenceLow),
 			})
 		}
 	}
@@ -0,0 +1,257 @@
+package workspace
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// CostExplanationSettings contains configurable parameters for cost change decomposition
+type CostExplanationSettings struct {
+	// Dimensions are the candidate dimensions to split by, in order of preference on ties
+	// (default: resource_type, sku, usage_type, resource_id)
+	Dimensions []domain.CostDimension `json:"dimensions" yaml:"dimensions"`
+	// IncludeTags adds a tag:<key> dimension for the custom tag keys found in either period (default: true)
+	IncludeTags bool `json:"include_tags" yaml:"include_tags"`
+	// MaxTagKeys is the number of tag keys, by cost of the tagged usage, evaluated as dimensions (default: 10)
+	MaxTagKeys int `json:"max_tag_keys" yaml:"max_tag_keys"`
+	// MaxDepth is the maximum depth of the contributor tree below the root (default: 3)
+	MaxDepth int `json:"max_depth" yaml:"max_depth"`
+	// MaxChildren is the maximum number of contributors listed under a node (default: 5)
+	MaxChildren int `json:"max_children" yaml:"max_children"`
+	// MinShare is the minimum fraction of the parent delta a contributor must explain to be listed (default: 0.1)
+	MinShare float64 `json:"min_share" yaml:"min_share"`
+}
+
+// DefaultCostExplanationSettings returns the default configuration for cost change decomposition
+func DefaultCostExplanationSettings() CostExplanationSettings {
+	return CostExplanationSettings{
+		Dimensions: []domain.CostDimension{
+			domain.CostDimensionResourceType,
+			domain.CostDimensionSKU,
+			domain.CostDimensionUsageType,
+			domain.CostDimensionResourceID,
+		},
+		IncludeTags: true,
+		MaxTagKeys:  10,
+		MaxDepth:    3,
+		MaxChildren: 5,
+		MinShare:    0.1,
+	}
+}
+
+// Validate checks that the settings can be used to explain cost changes
+func (s CostExplanationSettings) Validate() error {
+	for _, dimension := range s.Dimensions {
+		if !dimension.IsValid() {
+			return fmt.Errorf("unsupported cost dimension: %s", dimension)
+		}
+	}
+	switch {
+	case len(s.Dimensions) == 0 && !s.IncludeTags:
+		return fmt.Errorf("dimensions must not be empty when tags are not included")
+	case s.IncludeTags && s.MaxTagKeys <= 0:
+		return fmt.Errorf("max_tag_keys must be positive when tags are included")
+	case s.MaxDepth <= 0:
+		return fmt.Errorf("max_depth must be positive")
+	case s.MaxChildren <= 0:
+		return fmt.Errorf("max_children must be positive")
+	case s.MinShare < 0 || s.MinShare > 1:
+		return fmt.Errorf("min_share must be between 0 and 1")
+	}
+	return nil
+}
+
+// costSplit is the decomposition of a node's delta along one dimension
+type costSplit struct {
+	dimension    domain.CostDimension
+	contributors []domain.CostComparison
+	explained    float64 // fraction of the node delta explained by the contributors
+	values       int     // number of distinct values of the dimension within the node
+}
+
+// ExplainCostChange breaks the cost change between the baseline and the period down into a tree of contributors.
+// At every node each unused dimension is evaluated and the node is split by the one whose top contributors
+// explain the largest part of the node's delta, then the analysis recurses into those contributors.
+func ExplainCostChange(
+	ctx context.Context,
+	ws domain.Workspace,
+	period, baseline domain.TimePeriod,
+	analyzer CostAnalyzer,
+	settings CostExplanationSettings,
+) (domain.CostExplanation, error) {
+	if settings.MaxDepth < 1 || settings.MaxChildren < 1 {
+		return domain.CostExplanation{}, fmt.Errorf("max depth and max children must be positive")
+	}
+
+	dimensions := slices.Clone(settings.Dimensions)
+	if settings.IncludeTags {
+		keys, err := analyzer.ListTagKeys(ctx, minTime(period.Start, baseline.Start), maxTime(period.End, baseline.End))
+		if err != nil {
+			return domain.CostExplanation{}, err
+		}
+		// Every dimension costs a comparison query per node, so only the keys of the most costly usage are evaluated
+		tags := 0
+		for _, key := range keys {
+			if tags == settings.MaxTagKeys {
+				break
+			}
+			if d := domain.TagDimension(key); d.IsValid() {
+				dimensions = append(dimensions, d)
+				tags++
+			}
+		}
+	}
+	if len(dimensions) == 0 {
+		return domain.CostExplanation{}, fmt.Errorf("no dimensions to explain the cost change by")
+	}
+
+	// Totals do not depend on the dimension the comparison is grouped by
+	totals, err := analyzer.CompareCost(ctx, dimensions[0], period, baseline, nil)
+	if err != nil {
+		return domain.CostExplanation{}, err
+	}
+
+	explanation := domain.CostExplanation{
+		Workspace:      ws.Name,
+		Period:         period,
+		BaselinePeriod: baseline,
+		Currency:       totals.Currency,
+		Root: domain.CostContributor{
+			Cost:         totals.TotalCost,
+			BaselineCost: totals.BaselineTotalCost,
+			Delta:        totals.Delta,
+			DeltaPercent: totals.DeltaPercent,
+			Share:        1,
+			Children:     []domain.CostContributor{},
+		},
+	}
+
+	if err := explainNode(ctx, &explanation.Root, nil, dimensions, 0, period, baseline, analyzer, settings); err != nil {
+		return domain.CostExplanation{}, err
+	}
+
+	return explanation, nil
+}
+
+// explainNode splits the node by the most explanatory dimension and recurses into the selected contributors
+func explainNode(
+	ctx context.Context,
+	node *domain.CostContributor,
+	filters []domain.CostFilter,
+	dimensions []domain.CostDimension,
+	depth int,
+	period, baseline domain.TimePeriod,
+	analyzer CostAnalyzer,
+	settings CostExplanationSettings,
+) error {
+	if depth >= settings.MaxDepth || node.Delta == 0 || len(dimensions) == 0 {
+		return nil
+	}
+
+	var best, resourceSplit *costSplit
+	for _, dimension := range dimensions {
+		report, err := analyzer.CompareCost(ctx, dimension, period, baseline, filters)
+		if err != nil {
+			return err
+		}
+
+		split := splitNode(node.Delta, dimension, report.Items, settings)
+		if dimension == domain.CostDimensionResourceID {
+			resourceSplit = split
+		}
+		// A dimension with a single value does not decompose the delta any further
+		if split.values < 2 || len(split.contributors) == 0 {
+			continue
+		}
+		if best == nil || split.explained > best.explained {
+			best = split
+		}
+	}
+
+	// Without a useful split, still name the resource when the node boils down to a single one
+	if best == nil && resourceSplit != nil && resourceSplit.values == 1 && len(resourceSplit.contributors) == 1 {
+		best = resourceSplit
+	}
+	if best == nil {
+		return nil
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(dimensions), func(d domain.CostDimension) bool {
+		return d == best.dimension
+	})
+
+	node.SplitBy = best.dimension
+	for _, c := range best.contributors {
+		child := domain.CostContributor{
+			Dimension:    best.dimension,
+			Key:          c.Key,
+			Cost:         c.Cost,
+			BaselineCost: c.BaselineCost,
+			Delta:        c.Delta,
+			DeltaPercent: c.DeltaPercent,
+			Share:        c.Delta / node.Delta,
+			Children:     []domain.CostContributor{},
+		}
+		if best.dimension == domain.CostDimensionResourceID {
+			child.ResourceType = c.ResourceType
+		}
+
+		childFilters := append(slices.Clone(filters), domain.CostFilter{Dimension: best.dimension, Value: c.Key})
+		err := explainNode(ctx, &child, childFilters, remaining, depth+1, period, baseline, analyzer, settings)
+		if err != nil {
+			return err
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return nil
+}
+
+// splitNode selects the largest contributors moving in the same direction as the node delta
+func splitNode(
+	delta float64,
+	dimension domain.CostDimension,
+	items []domain.CostComparison,
+	settings CostExplanationSettings,
+) *costSplit {
+	split := &costSplit{dimension: dimension, values: len(items)}
+
+	contributors := make([]domain.CostComparison, 0, len(items))
+	for _, item := range items {
+		if share := item.Delta / delta; share >= settings.MinShare {
+			contributors = append(contributors, item)
+		}
+	}
+	slices.SortStableFunc(contributors, func(a, b domain.CostComparison) int {
+		return cmp.Compare(math.Abs(b.Delta), math.Abs(a.Delta))
+	})
+	if len(contributors) > settings.MaxChildren {
+		contributors = contributors[:settings.MaxChildren]
+	}
+
+	for _, c := range contributors {
+		split.explained += c.Delta / delta
+	}
+	split.contributors = contributors
+	return split
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
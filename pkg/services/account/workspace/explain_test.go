@@ -0,0 +1,203 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usageDelta is the cost of one combination of dimension values in both periods
+type usageDelta struct {
+	values       map[domain.CostDimension]string
+	cost         float64
+	baselineCost float64
+}
+
+// fakeCostAnalyzer compares cost over a fixed set of usage deltas
+type fakeCostAnalyzer struct {
+	CostAnalyzer
+	usage []usageDelta
+	tags  []string
+	calls int
+}
+
+func (f *fakeCostAnalyzer) CompareCost(
+	_ context.Context,
+	dimension domain.CostDimension,
+	_, _ domain.TimePeriod,
+	filters []domain.CostFilter,
+) (domain.CostComparisonReport, error) {
+	f.calls++
+	byKey := map[string]*domain.CostComparison{}
+	report := domain.CostComparisonReport{GroupBy: dimension, Currency: "USD"}
+outer:
+	for _, u := range f.usage {
+		for _, filter := range filters {
+			if u.values[filter.Dimension] != filter.Value {
+				continue outer
+			}
+		}
+		key := u.values[dimension]
+		item, ok := byKey[key]
+		if !ok {
+			item = &domain.CostComparison{Key: key, ResourceType: u.values[domain.CostDimensionResourceType]}
+			byKey[key] = item
+		}
+		item.Cost += u.cost
+		item.BaselineCost += u.baselineCost
+		item.Delta += u.cost - u.baselineCost
+		report.TotalCost += u.cost
+		report.BaselineTotalCost += u.baselineCost
+	}
+	for _, item := range byKey {
+		item.DeltaPercent = domain.DeltaPercent(item.Cost, item.BaselineCost)
+		report.Items = append(report.Items, *item)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		return math.Abs(report.Items[i].Delta) > math.Abs(report.Items[j].Delta)
+	})
+	report.Delta = report.TotalCost - report.BaselineTotalCost
+	report.DeltaPercent = domain.DeltaPercent(report.TotalCost, report.BaselineTotalCost)
+	return report, nil
+}
+
+func (f *fakeCostAnalyzer) ListTagKeys(context.Context, time.Time, time.Time) ([]string, error) {
+	return f.tags, nil
+}
+
+func explainTestUsage() []usageDelta {
+	job := func(id, team string, cost, baseline float64) usageDelta {
+		return usageDelta{
+			values: map[domain.CostDimension]string{
+				domain.CostDimensionResourceType: "job",
+				domain.CostDimensionResourceID:   id,
+				domain.CostDimensionSKU:          "JOBS",
+				domain.CostDimensionUsageType:    "COMPUTE_TIME",
+				domain.TagDimension("team"):      team,
+			},
+			cost:         cost,
+			baselineCost: baseline,
+		}
+	}
+	return []usageDelta{
+		{
+			values: map[domain.CostDimension]string{
+				domain.CostDimensionResourceType: "warehouse",
+				domain.CostDimensionResourceID:   "wh-1",
+				domain.CostDimensionSKU:          "SQL",
+				domain.CostDimensionUsageType:    "COMPUTE_TIME",
+			},
+			cost:         100,
+			baselineCost: 100,
+		},
+		job("job-1", "data", 400, 100),
+		job("job-2", "data", 60, 50),
+	}
+}
+
+func TestExplainCostChange(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	analyzer := &fakeCostAnalyzer{usage: explainTestUsage(), tags: []string{"team"}}
+
+	explanation, err := ExplainCostChange(ctx, ws, domain.TimePeriod{}, domain.TimePeriod{}, analyzer,
+		DefaultCostExplanationSettings())
+	require.NoError(t, err)
+
+	root := explanation.Root
+	assert.Equal(t, "ws1", explanation.Workspace)
+	assert.Equal(t, "USD", explanation.Currency)
+	assert.InDelta(t, 310.0, root.Delta, 0.001)
+
+	// resource_type and sku explain the whole delta, resource_type comes first
+	assert.Equal(t, domain.CostDimensionResourceType, root.SplitBy)
+	require.Len(t, root.Children, 1, "the warehouse did not change")
+	jobs := root.Children[0]
+	assert.Equal(t, "job", jobs.Key)
+	assert.InDelta(t, 1.0, jobs.Share, 0.001)
+
+	// within jobs only resource_id has more than one value, job-2 explains less than the minimum share
+	assert.Equal(t, domain.CostDimensionResourceID, jobs.SplitBy)
+	require.Len(t, jobs.Children, 1)
+	job := jobs.Children[0]
+	assert.Equal(t, "job-1", job.Key)
+	assert.Equal(t, "job", job.ResourceType)
+	assert.InDelta(t, 300.0/310.0, job.Share, 0.001)
+	require.NotNil(t, job.DeltaPercent)
+	assert.InDelta(t, 300.0, *job.DeltaPercent, 0.001)
+
+	// nothing left to decompose
+	assert.Empty(t, job.SplitBy)
+	assert.Empty(t, job.Children)
+}
+
+func TestExplainCostChange_NamesSingleResource(t *testing.T) {
+	ctx := context.Background()
+	usage := explainTestUsage()[:2]
+	analyzer := &fakeCostAnalyzer{usage: usage}
+	settings := DefaultCostExplanationSettings()
+	settings.IncludeTags = false
+
+	explanation, err := ExplainCostChange(ctx, domain.Workspace{}, domain.TimePeriod{}, domain.TimePeriod{}, analyzer,
+		settings)
+	require.NoError(t, err)
+
+	jobs := explanation.Root.Children[0]
+	require.Len(t, jobs.Children, 1)
+	assert.Equal(t, domain.CostDimensionResourceID, jobs.SplitBy)
+	assert.Equal(t, "job-1", jobs.Children[0].Key)
+}
+
+func TestExplainCostChange_Limits(t *testing.T) {
+	ctx := context.Background()
+	settings := DefaultCostExplanationSettings()
+	settings.MaxDepth = 1
+
+	analyzer := &fakeCostAnalyzer{usage: explainTestUsage()}
+	explanation, err := ExplainCostChange(ctx, domain.Workspace{}, domain.TimePeriod{}, domain.TimePeriod{}, analyzer,
+		settings)
+	require.NoError(t, err)
+	require.Len(t, explanation.Root.Children, 1)
+	assert.Empty(t, explanation.Root.Children[0].Children)
+
+	// without a change there is nothing to explain
+	flat := &fakeCostAnalyzer{usage: []usageDelta{explainTestUsage()[0]}}
+	explanation, err = ExplainCostChange(ctx, domain.Workspace{}, domain.TimePeriod{}, domain.TimePeriod{}, flat,
+		DefaultCostExplanationSettings())
+	require.NoError(t, err)
+	assert.Empty(t, explanation.Root.Children)
+	assert.Equal(t, 1, flat.calls, "only the totals are queried")
+
+	settings.MaxChildren = 0
+	_, err = ExplainCostChange(ctx, domain.Workspace{}, domain.TimePeriod{}, domain.TimePeriod{}, analyzer, settings)
+	assert.Error(t, err)
+}
+
+func TestExplainCostChange_MaxTagKeys(t *testing.T) {
+	ctx := context.Background()
+	tags := []string{"team"}
+	for i := range 50 {
+		tags = append(tags, fmt.Sprintf("key-%d", i))
+	}
+	analyzer := &fakeCostAnalyzer{usage: explainTestUsage(), tags: tags}
+	settings := DefaultCostExplanationSettings()
+	settings.MaxDepth = 1
+	settings.MaxTagKeys = 2
+
+	_, err := ExplainCostChange(ctx, domain.Workspace{}, domain.TimePeriod{}, domain.TimePeriod{}, analyzer, settings)
+	require.NoError(t, err)
+	assert.Equal(t, 1+len(settings.Dimensions)+2, analyzer.calls,
+		"only the tag keys of the most costly usage are compared besides the totals and the dimensions")
+
+	settings.MaxTagKeys = 0
+	assert.ErrorContains(t, settings.Validate(), "max_tag_keys must be positive")
+	settings.IncludeTags = false
+	assert.NoError(t, settings.Validate())
+}
@@ -3,38 +3,103 @@ package workspace
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"github.com/de-tools/data-atlas/pkg/adapters"
 
 	"github.com/databricks/databricks-sdk-go"
 	"github.com/databricks/databricks-sdk-go/config"
+	"github.com/databricks/databricks-sdk-go/service/billing"
+	"github.com/databricks/databricks-sdk-go/service/compute"
+	"github.com/databricks/databricks-sdk-go/service/serving"
 	"github.com/databricks/databricks-sdk-go/service/sql"
 
 	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
 )
 
 type Explorer interface {
 	ListSupportedResources(ctx context.Context) ([]domain.WorkspaceResource, error)
 	GetWarehouseMetadata(ctx context.Context, warehouseID string) (*domain.WarehouseMetadata, error)
 	ListWarehouses(ctx context.Context) ([]domain.WarehouseMetadata, error)
+	GetWarehouseQueryStats(ctx context.Context, startTime, endTime time.Time) ([]domain.WarehouseQueryStats, error)
+	GetWarehouseEvents(ctx context.Context, startTime, endTime time.Time) ([]domain.WarehouseEvent, error)
+	GetWarehouseQueryActivity(ctx context.Context, startTime, endTime time.Time) ([]domain.WarehouseQueryActivity, error)
+	ListClusters(ctx context.Context) ([]domain.ClusterMetadata, error)
+	GetClusterUtilization(ctx context.Context, startTime, endTime time.Time) ([]domain.ClusterUtilization, error)
+	ListServingEndpoints(ctx context.Context) ([]domain.ServingEndpointMetadata, error)
+	GetServingEndpointUsage(ctx context.Context, startTime, endTime time.Time) ([]domain.ServingEndpointUsage, error)
+	GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]domain.JobRun, error)
+	GetBudgetControls(ctx context.Context) (*domain.BudgetControls, error)
+}
+
+// ComputeStore is the minimal interface required by Explorer for reading compute telemetry
+// Implemented by the Databricks SQL compute store
+type ComputeStore interface {
+	GetClusterUtilization(ctx context.Context, startTime, endTime time.Time) ([]store.ClusterUtilization, error)
+	GetWarehouseEvents(ctx context.Context, startTime, endTime time.Time) ([]store.WarehouseEvent, error)
+}
+
+// ServingStore is the minimal interface required by Explorer for reading model serving request logs
+// Implemented by the Databricks SQL serving store
+type ServingStore interface {
+	GetEndpointUsage(ctx context.Context, startTime, endTime time.Time) ([]store.ServingEndpointUsage, error)
+}
+
+// QueryStore is the minimal interface required by Explorer for reading SQL warehouse query history
+// Implemented by the Databricks SQL query store
+type QueryStore interface {
+	GetWarehouseQueryStats(ctx context.Context, startTime, endTime time.Time) ([]store.WarehouseQueryStats, error)
+	GetWarehouseActiveMinutes(ctx context.Context, startTime, endTime time.Time) ([]store.WarehouseActiveMinute, error)
+}
+
+// JobStore is the minimal interface required by Explorer for reading job run history
+// Implemented by the Databricks SQL lakeflow store
+type JobStore interface {
+	GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]store.JobRun, error)
+}
+
+// BudgetStore is the minimal interface required by Explorer for reading account budgets and budget policies
+// Implemented by the Databricks SDK account client
+type BudgetStore interface {
+	ListBudgets(ctx context.Context) ([]billing.BudgetConfiguration, error)
+	ListBudgetPolicies(ctx context.Context) ([]billing.BudgetPolicy, error)
 }
 
 type workspaceExplorer struct {
-	ws     domain.Workspace
-	config *config.Config
-	client *databricks.WorkspaceClient
+	ws           domain.Workspace
+	config       *config.Config
+	client       *databricks.WorkspaceClient
+	computeStore ComputeStore
+	servingStore ServingStore
+	jobStore     JobStore
+	queryStore   QueryStore
+	budgetStore  BudgetStore
 }
 
-func NewExplorer(config *config.Config, ws domain.Workspace) Explorer {
-	// Create Databricks client - we'll handle errors in the methods that need it
-	client, _ := databricks.NewWorkspaceClient(&databricks.Config{
-		Host:  config.Host,
-		Token: config.Token,
-	})
+// NewExplorer creates a workspace Explorer, budgetStore is nil when no account profile is configured
+func NewExplorer(
+	config *config.Config,
+	ws domain.Workspace,
+	computeStore ComputeStore,
+	servingStore ServingStore,
+	jobStore JobStore,
+	queryStore QueryStore,
+	budgetStore BudgetStore,
+) Explorer {
+	// Create Databricks client - we'll handle errors in the methods that need it.
+	// The profile config is used as is, so OAuth profiles and discovered workspaces authenticate too
+	client, _ := databricks.NewWorkspaceClient((*databricks.Config)(config))
 
 	return &workspaceExplorer{
-		ws:     ws,
-		config: config,
-		client: client,
+		ws:           ws,
+		config:       config,
+		client:       client,
+		computeStore: computeStore,
+		servingStore: servingStore,
+		jobStore:     jobStore,
+		queryStore:   queryStore,
+		budgetStore:  budgetStore,
 	}
 }
 
@@ -89,6 +154,186 @@ func (w *workspaceExplorer) ListWarehouses(ctx context.Context) ([]domain.Wareho
 	return result, nil
 }
 
+// GetWarehouseQueryStats retrieves query counts, durations, queueing, scanned and spilled bytes per warehouse
+// from the query history
+func (w *workspaceExplorer) GetWarehouseQueryStats(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseQueryStats, error) {
+	if w.queryStore == nil {
+		return nil, fmt.Errorf("query store not initialized")
+	}
+
+	stats, err := w.queryStore.GetWarehouseQueryStats(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse query stats: %w", err)
+	}
+
+	result := make([]domain.WarehouseQueryStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, adapters.MapWarehouseQueryStatsStoreToDomain(s))
+	}
+
+	return result, nil
+}
+
+// GetWarehouseEvents retrieves the warehouse start, scale and stop events of the period,
+// preceded by the last event of each warehouse before it
+func (w *workspaceExplorer) GetWarehouseEvents(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseEvent, error) {
+	if w.computeStore == nil {
+		return nil, fmt.Errorf("compute store not initialized")
+	}
+
+	events, err := w.computeStore.GetWarehouseEvents(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse events: %w", err)
+	}
+
+	result := make([]domain.WarehouseEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, adapters.MapWarehouseEventStoreToDomain(e))
+	}
+
+	return result, nil
+}
+
+// GetWarehouseQueryActivity retrieves the minutes each warehouse was executing queries from the query history
+func (w *workspaceExplorer) GetWarehouseQueryActivity(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseQueryActivity, error) {
+	if w.queryStore == nil {
+		return nil, fmt.Errorf("query store not initialized")
+	}
+
+	minutes, err := w.queryStore.GetWarehouseActiveMinutes(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse query activity: %w", err)
+	}
+
+	return adapters.MapWarehouseActiveMinutesStoreToDomain(minutes), nil
+}
+
+// ListClusters retrieves metadata for all clusters in the workspace
+func (w *workspaceExplorer) ListClusters(ctx context.Context) ([]domain.ClusterMetadata, error) {
+	if w.client == nil {
+		return nil, fmt.Errorf("databricks client not initialized")
+	}
+
+	clusters, err := w.client.Clusters.ListAll(ctx, compute.ListClustersRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	// GPU counts are only available on node types
+	gpusPerNodeType := make(map[string]int)
+	if nodeTypes, err := w.client.Clusters.ListNodeTypes(ctx); err == nil {
+		for _, nodeType := range nodeTypes.NodeTypes {
+			gpusPerNodeType[nodeType.NodeTypeId] = nodeType.NumGpus
+		}
+	}
+
+	result := make([]domain.ClusterMetadata, 0, len(clusters))
+	for _, cluster := range clusters {
+		result = append(result, adapters.MapClusterDetailsToClusterMetadata(cluster, gpusPerNodeType))
+	}
+
+	return result, nil
+}
+
+// GetClusterUtilization retrieves CPU and memory utilization per cluster from the node timeline
+func (w *workspaceExplorer) GetClusterUtilization(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.ClusterUtilization, error) {
+	if w.computeStore == nil {
+		return nil, fmt.Errorf("compute store not initialized")
+	}
+
+	utilization, err := w.computeStore.GetClusterUtilization(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster utilization: %w", err)
+	}
+
+	result := make([]domain.ClusterUtilization, 0, len(utilization))
+	for _, u := range utilization {
+		result = append(result, adapters.MapClusterUtilizationStoreToDomain(u))
+	}
+
+	return result, nil
+}
+
+// ListServingEndpoints retrieves state and configuration of all model serving endpoints in the workspace
+func (w *workspaceExplorer) ListServingEndpoints(ctx context.Context) ([]domain.ServingEndpointMetadata, error) {
+	if w.client == nil {
+		return nil, fmt.Errorf("databricks client not initialized")
+	}
+
+	endpoints, err := w.client.ServingEndpoints.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serving endpoints: %w", err)
+	}
+
+	var result []domain.ServingEndpointMetadata
+	for _, endpoint := range endpoints {
+		// The list only summarizes served entities, scaling settings require the detailed config
+		detailed, err := w.client.ServingEndpoints.Get(ctx, serving.GetServingEndpointRequest{Name: endpoint.Name})
+		if err != nil {
+			continue // Skip endpoints we can't get details for
+		}
+		if detailed == nil {
+			continue
+		}
+		result = append(result, adapters.MapServingEndpointDetailedToMetadata(detailed))
+	}
+
+	return result, nil
+}
+
+// GetServingEndpointUsage retrieves request counts per serving endpoint from the endpoint usage logs
+func (w *workspaceExplorer) GetServingEndpointUsage(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.ServingEndpointUsage, error) {
+	if w.servingStore == nil {
+		return nil, fmt.Errorf("serving store not initialized")
+	}
+
+	usage, err := w.servingStore.GetEndpointUsage(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get serving endpoint usage: %w", err)
+	}
+
+	result := make([]domain.ServingEndpointUsage, 0, len(usage))
+	for _, u := range usage {
+		result = append(result, adapters.MapServingEndpointUsageStoreToDomain(u))
+	}
+
+	return result, nil
+}
+
+// GetJobRuns retrieves the runs started in the period from the job run timeline
+func (w *workspaceExplorer) GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]domain.JobRun, error) {
+	if w.jobStore == nil {
+		return nil, fmt.Errorf("job store not initialized")
+	}
+
+	runs, err := w.jobStore.GetJobRuns(ctx, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job runs: %w", err)
+	}
+
+	result := make([]domain.JobRun, 0, len(runs))
+	for _, r := range runs {
+		result = append(result, adapters.MapJobRunStoreToDomain(r))
+	}
+
+	return result, nil
+}
+
 func validResourceTypes(types []string) []string {
 	var supportedTypes []string
 	for _, rt := range types {
@@ -98,3 +343,42 @@ func validResourceTypes(types []string) []string {
 	}
 	return supportedTypes
 }
+
+// GetBudgetControls retrieves the account budgets and budget policies applying to the workspace
+func (w *workspaceExplorer) GetBudgetControls(ctx context.Context) (*domain.BudgetControls, error) {
+	if w.budgetStore == nil {
+		return nil, fmt.Errorf("budget store not initialized")
+	}
+	if w.client == nil {
+		return nil, fmt.Errorf("databricks client not initialized")
+	}
+
+	workspaceID, err := w.client.CurrentWorkspaceID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace id: %w", err)
+	}
+
+	budgets, err := w.budgetStore.ListBudgets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+
+	policies, err := w.budgetStore.ListBudgetPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budget policies: %w", err)
+	}
+
+	controls := &domain.BudgetControls{WorkspaceID: workspaceID}
+	for _, b := range budgets {
+		if budget := adapters.MapBudgetConfigurationToBudget(b); budget.AppliesTo(workspaceID) {
+			controls.Budgets = append(controls.Budgets, budget)
+		}
+	}
+	for _, p := range policies {
+		if policy := adapters.MapBudgetPolicyToDomain(p); policy.AppliesTo(workspaceID) {
+			controls.Policies = append(controls.Policies, policy)
+		}
+	}
+
+	return controls, nil
+}
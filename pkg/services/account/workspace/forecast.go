@@ -0,0 +1,357 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// forecastTotalKey is the key of the forecast series when cost is not grouped
+const forecastTotalKey = "total"
+
+// ForecastSettings contains configurable parameters for spend forecasting
+type ForecastSettings struct {
+	// HistoryDays is the number of days of history used to fit the model (default: 90)
+	HistoryDays int `json:"history_days" yaml:"history_days"`
+	// MinHistoryDays is the minimum number of observed days required to fit a series (default: 14)
+	MinHistoryDays int `json:"min_history_days" yaml:"min_history_days"`
+	// ConfidenceLevel is the two-sided coverage of the prediction intervals (default: 0.9)
+	ConfidenceLevel float64 `json:"confidence_level" yaml:"confidence_level"`
+	// MaxHorizonDays is the longest forecast horizon accepted (default: 366)
+	MaxHorizonDays int `json:"max_horizon_days" yaml:"max_horizon_days"`
+}
+
+// DefaultForecastSettings returns the default configuration for spend forecasting
+func DefaultForecastSettings() ForecastSettings {
+	return ForecastSettings{
+		HistoryDays:     90,
+		MinHistoryDays:  14,
+		ConfidenceLevel: 0.9,
+		MaxHorizonDays:  366,
+	}
+}
+
+// Validate checks that the settings can be used to fit forecasts
+func (s ForecastSettings) Validate() error {
+	switch {
+	case s.HistoryDays <= 0:
+		return fmt.Errorf("history_days must be positive")
+	case s.MinHistoryDays <= 0 || s.MinHistoryDays > s.HistoryDays:
+		return fmt.Errorf("min_history_days must be between 1 and history_days")
+	case s.ConfidenceLevel <= 0 || s.ConfidenceLevel >= 1:
+		return fmt.Errorf("confidence_level must be between 0 and 1")
+	case s.MaxHorizonDays <= 0:
+		return fmt.Errorf("max_horizon_days must be positive")
+	}
+	return nil
+}
+
+// GetCostForecast fits a linear trend with weekly seasonality to the daily cost of every value
+// of the groupBy dimension (or the workspace total when groupBy is empty) and forecasts
+// the next horizonDays days starting at asOf. The month and quarter end estimates are forecasted
+// to the end of the period whatever the horizon.
+func GetCostForecast(
+	ctx context.Context,
+	ws domain.Workspace,
+	asOf time.Time,
+	horizonDays int,
+	groupBy domain.CostDimension,
+	analyzer CostAnalyzer,
+	settings ForecastSettings,
+) (domain.CostForecastReport, error) {
+	if horizonDays <= 0 || horizonDays > settings.MaxHorizonDays {
+		return domain.CostForecastReport{}, fmt.Errorf("forecast horizon must be between 1 and %d days",
+			settings.MaxHorizonDays)
+	}
+	if groupBy != "" && !groupBy.IsValid() {
+		return domain.CostForecastReport{}, fmt.Errorf("unsupported cost dimension: %s", groupBy)
+	}
+	if settings.ConfidenceLevel <= 0 || settings.ConfidenceLevel >= 1 {
+		return domain.CostForecastReport{}, fmt.Errorf("confidence level must be between 0 and 1")
+	}
+
+	start := truncateToDay(asOf)
+	historyStart := start.AddDate(0, 0, -settings.HistoryDays)
+
+	// Month and quarter estimates need the actual spend since the beginning of the period
+	fetchStart := historyStart
+	if qs := quarterStart(start); qs.Before(fetchStart) {
+		fetchStart = qs
+	}
+
+	dimension := groupBy
+	if dimension == "" {
+		dimension = domain.CostDimensionResourceType
+	}
+	costs, err := analyzer.GetDailyCost(ctx, dimension, fetchStart, start)
+	if err != nil {
+		return domain.CostForecastReport{}, err
+	}
+	if groupBy == "" {
+		for i := range costs {
+			costs[i].Key = forecastTotalKey
+			costs[i].ResourceType = ""
+		}
+	}
+
+	report := domain.CostForecastReport{
+		Workspace:       ws.Name,
+		Start:           start,
+		HorizonDays:     horizonDays,
+		HistoryDays:     settings.HistoryDays,
+		ConfidenceLevel: settings.ConfidenceLevel,
+		Forecasts:       []domain.CostForecast{},
+	}
+
+	z := math.Sqrt2 * math.Erfinv(settings.ConfidenceLevel)
+	for _, series := range buildDailyCostSeries(costs, groupBy, start) {
+		forecast, err := forecastSeries(series, start, historyStart, horizonDays, z, settings)
+		if err != nil {
+			report.SkippedSeries++
+			continue
+		}
+		report.Forecasts = append(report.Forecasts, forecast)
+	}
+
+	sort.SliceStable(report.Forecasts, func(i, j int) bool {
+		return report.Forecasts[i].Total.Value > report.Forecasts[j].Total.Value
+	})
+
+	return report, nil
+}
+
+// forecastSeries fits the model to the series history and projects it over the horizon,
+// or to the end of the quarter when it ends later
+func forecastSeries(
+	series *dailyCostSeries,
+	start, historyStart time.Time,
+	horizonDays int,
+	z float64,
+	settings ForecastSettings,
+) (domain.CostForecast, error) {
+	fitStart := historyStart
+	if series.firstDay.After(fitStart) {
+		fitStart = series.firstDay
+	}
+
+	var xs [][]float64
+	var ys []float64
+	for day := fitStart; day.Before(start); day = day.AddDate(0, 0, 1) {
+		xs = append(xs, forecastFeatures(day, fitStart))
+		ys = append(ys, series.values[day])
+	}
+	if len(ys) < settings.MinHistoryDays {
+		return domain.CostForecast{}, fmt.Errorf("not enough history: %d days", len(ys))
+	}
+
+	model, err := fitLinearModel(xs, ys)
+	if err != nil {
+		return domain.CostForecast{}, err
+	}
+
+	horizonEnd := start.AddDate(0, 0, horizonDays)
+	monthStart := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	qs := quarterStart(start)
+	quarterEnd := qs.AddDate(0, 3, 0)
+	// the quarter end is never before the month end
+	forecastEnd := horizonEnd
+	if quarterEnd.After(forecastEnd) {
+		forecastEnd = quarterEnd
+	}
+
+	var points []domain.ForecastPoint
+	var variances []float64
+	for day := start; day.Before(forecastEnd); day = day.AddDate(0, 0, 1) {
+		value, variance := model.predict(forecastFeatures(day, fitStart))
+		margin := z * math.Sqrt(variance)
+		points = append(points, domain.ForecastPoint{
+			Date:  day,
+			Value: math.Max(0, value),
+			Lower: math.Max(0, value-margin),
+			Upper: math.Max(0, value+margin),
+		})
+		variances = append(variances, variance)
+	}
+
+	return domain.CostForecast{
+		Dimension:  series.dimension,
+		Key:        series.key,
+		Currency:   series.currency,
+		Points:     points[:horizonDays],
+		Total:      estimatePeriod(series, points, variances, start, start, horizonEnd, z),
+		MonthEnd:   estimatePeriod(series, points, variances, start, monthStart, monthEnd, z),
+		QuarterEnd: estimatePeriod(series, points, variances, start, qs, quarterEnd, z),
+	}, nil
+}
+
+// estimatePeriod sums the observed spend in [periodStart, start) and the forecasted spend in [start, periodEnd).
+// Daily forecast errors are treated as independent, so the interval is an approximation.
+func estimatePeriod(
+	series *dailyCostSeries,
+	points []domain.ForecastPoint,
+	variances []float64,
+	start, periodStart, periodEnd time.Time,
+	z float64,
+) domain.ForecastEstimate {
+	estimate := domain.ForecastEstimate{End: periodEnd}
+	for day := periodStart; day.Before(start); day = day.AddDate(0, 0, 1) {
+		estimate.Actual += series.values[day]
+	}
+
+	var forecasted, variance float64
+	for i, p := range points {
+		if !p.Date.Before(periodEnd) {
+			break
+		}
+		forecasted += p.Value
+		variance += variances[i]
+	}
+
+	margin := z * math.Sqrt(variance)
+	estimate.Value = estimate.Actual + forecasted
+	estimate.Lower = estimate.Actual + math.Max(0, forecasted-margin)
+	estimate.Upper = estimate.Actual + forecasted + margin
+	return estimate
+}
+
+// forecastFeatures returns the regressors of a day: intercept, linear trend and weekday dummies (Monday is the base)
+func forecastFeatures(day, origin time.Time) []float64 {
+	features := make([]float64, 8)
+	features[0] = 1
+	features[1] = day.Sub(origin).Hours() / 24
+	if wd := day.Weekday(); wd != time.Monday {
+		// Tuesday..Saturday -> 2..6, Sunday -> 7
+		idx := int(wd)
+		if wd == time.Sunday {
+			idx = 7
+		}
+		features[idx] = 1
+	}
+	return features
+}
+
+// linearModel is an ordinary least squares fit
+type linearModel struct {
+	coefficients []float64
+	xtxInverse   [][]float64
+	residualVar  float64
+}
+
+// predict returns the point prediction and the prediction variance for the given regressors
+func (m *linearModel) predict(x []float64) (float64, float64) {
+	var value, leverage float64
+	for i := range x {
+		value += m.coefficients[i] * x[i]
+		for j := range x {
+			leverage += x[i] * m.xtxInverse[i][j] * x[j]
+		}
+	}
+	return value, m.residualVar * (1 + leverage)
+}
+
+func fitLinearModel(xs [][]float64, ys []float64) (*linearModel, error) {
+	n := len(ys)
+	if n == 0 {
+		return nil, errors.New("no observations")
+	}
+	p := len(xs[0])
+	if n <= p {
+		return nil, fmt.Errorf("not enough observations to fit %d parameters: %d", p, n)
+	}
+
+	xtx := make([][]float64, p)
+	xty := make([]float64, p)
+	for i := range p {
+		xtx[i] = make([]float64, p)
+	}
+	for row, x := range xs {
+		for i := range p {
+			xty[i] += x[i] * ys[row]
+			for j := range p {
+				xtx[i][j] += x[i] * x[j]
+			}
+		}
+	}
+
+	inverse, err := invertMatrix(xtx)
+	if err != nil {
+		return nil, err
+	}
+
+	coefficients := make([]float64, p)
+	for i := range p {
+		for j := range p {
+			coefficients[i] += inverse[i][j] * xty[j]
+		}
+	}
+
+	var ssr float64
+	for row, x := range xs {
+		var fitted float64
+		for i := range p {
+			fitted += coefficients[i] * x[i]
+		}
+		ssr += (ys[row] - fitted) * (ys[row] - fitted)
+	}
+
+	return &linearModel{
+		coefficients: coefficients,
+		xtxInverse:   inverse,
+		residualVar:  ssr / float64(n-p),
+	}, nil
+}
+
+// invertMatrix inverts a square matrix using Gauss-Jordan elimination with partial pivoting
+func invertMatrix(m [][]float64) ([][]float64, error) {
+	n := len(m)
+	aug := make([][]float64, n)
+	for i := range n {
+		aug[i] = make([]float64, 2*n)
+		copy(aug[i], m[i])
+		aug[i][n+i] = 1
+	}
+
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(aug[row][col]) > math.Abs(aug[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(aug[pivot][col]) < 1e-12 {
+			return nil, errors.New("matrix is singular")
+		}
+		aug[col], aug[pivot] = aug[pivot], aug[col]
+
+		scale := aug[col][col]
+		for j := range 2 * n {
+			aug[col][j] /= scale
+		}
+		for row := range n {
+			if row == col {
+				continue
+			}
+			factor := aug[row][col]
+			for j := range 2 * n {
+				aug[row][j] -= factor * aug[col][j]
+			}
+		}
+	}
+
+	inverse := make([][]float64, n)
+	for i := range n {
+		inverse[i] = aug[i][n:]
+	}
+	return inverse, nil
+}
+
+func quarterStart(t time.Time) time.Time {
+	month := time.Month((int(t.Month())-1)/3*3 + 1)
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,164 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// trendWithWeekends returns 100 + 2*t per day with a 50 surcharge on weekends
+func trendWithWeekends(from time.Time, days int) []float64 {
+	values := make([]float64, 0, days)
+	for i := range days {
+		day := from.AddDate(0, 0, i)
+		v := 100 + 2*float64(i)
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			v += 50
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestGetCostForecast_TrendAndSeasonality(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	asOf := time.Date(2025, 9, 15, 13, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)
+	settings := DefaultForecastSettings()
+	settings.HistoryDays = 60
+	historyStart := start.AddDate(0, 0, -settings.HistoryDays)
+	fetchStart := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) // quarter start precedes history start
+
+	values := trendWithWeekends(historyStart, settings.HistoryDays)
+	costs := dailyCosts(domain.CostDimensionResourceType, "warehouse", historyStart, values...)
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceType, fetchStart, start).Return(costs, nil)
+
+	report, err := GetCostForecast(ctx, ws, asOf, 30, domain.CostDimensionResourceType, analyzer, settings)
+	require.NoError(t, err)
+	require.Len(t, report.Forecasts, 1)
+	assert.Equal(t, start, report.Start)
+	assert.Equal(t, 0, report.SkippedSeries)
+
+	forecast := report.Forecasts[0]
+	assert.Equal(t, "warehouse", forecast.Key)
+	require.Len(t, forecast.Points, 30)
+
+	expected := trendWithWeekends(historyStart, settings.HistoryDays+30)[settings.HistoryDays:]
+	for i, p := range forecast.Points {
+		assert.InDelta(t, expected[i], p.Value, 0.001, "day %s", p.Date)
+		assert.LessOrEqual(t, p.Lower, p.Value)
+		assert.GreaterOrEqual(t, p.Upper, p.Value)
+	}
+
+	// September ends within the horizon, the quarter (end of September) as well
+	var septemberActual, septemberForecast float64
+	for i, v := range values {
+		if day := historyStart.AddDate(0, 0, i); day.Month() == time.September {
+			septemberActual += v
+		}
+	}
+	for i := range 16 { // September 15th to 30th
+		septemberForecast += expected[i]
+	}
+	assert.InDelta(t, septemberActual, forecast.MonthEnd.Actual, 0.001)
+	assert.InDelta(t, septemberActual+septemberForecast, forecast.MonthEnd.Value, 0.01)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), forecast.MonthEnd.End)
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), forecast.QuarterEnd.End)
+
+	analyzer.AssertExpectations(t)
+}
+
+func TestGetCostForecast_TotalAndSkippedSeries(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	start := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	settings := DefaultForecastSettings()
+	settings.HistoryDays = 28
+	historyStart := start.AddDate(0, 0, -settings.HistoryDays)
+
+	costs := append(
+		dailyCosts(domain.CostDimensionResourceType, "warehouse", historyStart, trendWithWeekends(historyStart, 28)...),
+		dailyCosts(domain.CostDimensionResourceType, "job", historyStart, trendWithWeekends(historyStart, 28)...)...,
+	)
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceType, mock.Anything, start).Return(costs, nil)
+
+	report, err := GetCostForecast(ctx, ws, start, 7, "", analyzer, settings)
+	require.NoError(t, err)
+	require.Len(t, report.Forecasts, 1)
+	assert.Equal(t, forecastTotalKey, report.Forecasts[0].Key)
+	require.Len(t, report.Forecasts[0].Points, 7)
+
+	// the month and quarter end are beyond a 7 day horizon and still estimated,
+	// the total is the sum of both series, observed from July 4th
+	values := trendWithWeekends(historyStart, 28+61)
+	var julyActual, augustForecast, septemberForecast float64
+	for i, v := range values {
+		switch {
+		case i < 28:
+			julyActual += 2 * v
+		case i < 28+31:
+			augustForecast += 2 * v
+		default:
+			septemberForecast += 2 * v
+		}
+	}
+	monthEnd := report.Forecasts[0].MonthEnd
+	assert.Equal(t, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), monthEnd.End)
+	assert.Zero(t, monthEnd.Actual)
+	assert.InDelta(t, augustForecast, monthEnd.Value, 0.01)
+	quarterEnd := report.Forecasts[0].QuarterEnd
+	assert.Equal(t, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), quarterEnd.End)
+	assert.InDelta(t, julyActual, quarterEnd.Actual, 0.001)
+	assert.InDelta(t, julyActual+augustForecast+septemberForecast, quarterEnd.Value, 0.01)
+
+	// a series observed for only 5 days cannot be fitted
+	shortCosts := dailyCosts(domain.CostDimensionSKU, "NEW_SKU", start.AddDate(0, 0, -5), 1, 2, 3, 4, 5)
+	analyzer = new(mockCostAnalyzer)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionSKU, mock.Anything, start).Return(shortCosts, nil)
+
+	report, err = GetCostForecast(ctx, ws, start, 7, domain.CostDimensionSKU, analyzer, settings)
+	require.NoError(t, err)
+	assert.Empty(t, report.Forecasts)
+	assert.Equal(t, 1, report.SkippedSeries)
+}
+
+func TestGetCostForecast_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	analyzer := new(mockCostAnalyzer)
+	settings := DefaultForecastSettings()
+
+	_, err := GetCostForecast(ctx, ws, time.Now(), 0, "", analyzer, settings)
+	assert.Error(t, err)
+
+	_, err = GetCostForecast(ctx, ws, time.Now(), 30, "warehouse_name", analyzer, settings)
+	assert.Error(t, err)
+
+	analyzer.AssertNotCalled(t, "GetDailyCost")
+}
+
+func TestFitLinearModel(t *testing.T) {
+	xs := [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}}
+	ys := []float64{1, 3, 5, 7}
+
+	model, err := fitLinearModel(xs, ys)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, model.coefficients[0], 1e-9)
+	assert.InDelta(t, 2.0, model.coefficients[1], 1e-9)
+
+	value, variance := model.predict([]float64{1, 10})
+	assert.InDelta(t, 21.0, value, 1e-9)
+	assert.InDelta(t, 0.0, variance, 1e-9)
+
+	_, err = fitLinearModel([][]float64{{1, 1}, {1, 1}, {1, 1}}, []float64{1, 2, 3})
+	assert.Error(t, err, "collinear regressors")
+}
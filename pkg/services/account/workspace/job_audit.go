@@ -0,0 +1,508 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	jobRule := func(
+		name, description string,
+		requiresRuns bool,
+		analyze func(jobs []*JobStats, settings JobAuditSettings) []domain.AuditFinding,
+	) AuditRule {
+		return NewAuditRule(name, "job", description,
+			func(ctx context.Context, in *AuditInput) ([]domain.AuditFinding, error) {
+				data, err := jobAuditDataFor(ctx, in)
+				if err != nil {
+					return nil, err
+				}
+				if requiresRuns && data.runsErr != nil {
+					return nil, data.runsErr
+				}
+				return analyze(data.jobs, in.Settings.Job), nil
+			})
+	}
+
+	RegisterAuditRule(jobRule("job_wasted_runs", "Spend on failed and retried job runs",
+		true, analyzeWastedRuns))
+	RegisterAuditRule(jobRule("job_all_purpose_compute", "Jobs billed at all-purpose compute rates",
+		false, func(jobs []*JobStats, _ JobAuditSettings) []domain.AuditFinding {
+			return analyzeAllPurposeCompute(jobs)
+		}))
+	RegisterAuditRule(jobRule("job_run_regressions", "Job runs slower or more expensive than their history",
+		true, analyzeRunRegressions))
+}
+
+// JobAuditSettings contains configurable thresholds for job audit analysis
+type JobAuditSettings struct {
+	// MinWastedCost is the spend on failed or retried runs of a job above which it is reported (default: 10)
+	MinWastedCost float64 `json:"min_wasted_cost" yaml:"min_wasted_cost"`
+	// HighWastedCost is the spend on failed or retried runs of a job above which the severity is raised (default: 100)
+	HighWastedCost float64 `json:"high_wasted_cost" yaml:"high_wasted_cost"`
+	// RegressionThreshold is the relative increase of recent runs over the historical median to flag a regression (default: 0.5)
+	RegressionThreshold float64 `json:"regression_threshold" yaml:"regression_threshold"`
+	// MinHistoryRuns is the minimum number of earlier successful runs to compare recent runs against (default: 5)
+	MinHistoryRuns int `json:"min_history_runs" yaml:"min_history_runs"`
+	// RecentRuns is the number of latest successful runs compared against the history (default: 3)
+	RecentRuns int `json:"recent_runs" yaml:"recent_runs"`
+	// TopRunsCount is the number of most expensive runs listed in the summary (default: 10)
+	TopRunsCount int `json:"top_runs_count" yaml:"top_runs_count"`
+}
+
+// DefaultJobAuditSettings returns the default configuration for job audits
+func DefaultJobAuditSettings() JobAuditSettings {
+	return JobAuditSettings{
+		MinWastedCost:       10,
+		HighWastedCost:      100,
+		RegressionThreshold: 0.5,
+		MinHistoryRuns:      5,
+		RecentRuns:          3,
+		TopRunsCount:        10,
+	}
+}
+
+// RunStats holds the cost and the outcome of a job run
+type RunStats struct {
+	RunID string
+	JobID string
+	Run   *domain.JobRun // nil for runs outside of the job run timeline
+	Cost  float64
+}
+
+// JobStats holds billing and run history of a job
+type JobStats struct {
+	JobID          string
+	Name           string
+	TotalCost      float64
+	AllPurposeCost float64 // cost billed at all-purpose compute rates
+	Currency       string
+	Runs           []*RunStats // ordered by start time, runs without timeline data last
+}
+
+// GetJobAudit performs an audit of jobs and job runs for the given workspace and time period,
+// combining billed usage with run outcomes from the job run timeline
+func GetJobAudit(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	explorer Explorer,
+	settings JobAuditSettings,
+) (domain.AuditReport, error) {
+	input := &AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Explorer:    explorer,
+		Settings:    AuditSettings{Job: settings},
+	}
+
+	data, err := jobAuditDataFor(ctx, input)
+	if err != nil {
+		return domain.AuditReport{}, err
+	}
+	if len(data.jobs) == 0 {
+		return noActivityReport(input, "job", settings,
+			"No job usage records found in the selected period",
+			"No job activity detected in the selected time window.",
+			"Verify job schedules and triggers.",
+			domain.SeverityLow), nil
+	}
+
+	return runResourceAudit(ctx, input, "job", func(report *domain.AuditReport) {
+		report.Summary["settings"] = settings
+		generateJobSummaryMetrics(report, data.jobs, data.runsErr == nil, settings)
+	})
+}
+
+// jobAuditDataFor returns the cached job stats of the audit run
+func jobAuditDataFor(ctx context.Context, in *AuditInput) (jobAuditData, error) {
+	return loadAuditData(in, "job", func() (jobAuditData, error) {
+		return loadJobAuditData(ctx, in.StartTime, in.EndTime, in.CostManager, in.Explorer)
+	})
+}
+
+// jobAuditData holds the job stats of an audit and the error of the job run timeline
+type jobAuditData struct {
+	jobs    []*JobStats
+	runsErr error
+}
+
+// loadJobAuditData reads job usage and job runs, only failing to read usage is an error
+func loadJobAuditData(
+	ctx context.Context,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	explorer Explorer,
+) (jobAuditData, error) {
+	// Job usage can be attributed to the job, the run or the cluster, so all usage is read
+	// and job records are selected by the job and run ids in their metadata
+	records, err := costManager.GetUsage(ctx, startTime, endTime)
+	if err != nil {
+		return jobAuditData{}, err
+	}
+
+	data := jobAuditData{runsErr: errExplorerUnavailable}
+	var runs []domain.JobRun
+	if explorer != nil {
+		if runs, data.runsErr = explorer.GetJobRuns(ctx, startTime, endTime); data.runsErr != nil {
+			zerolog.Ctx(ctx).Warn().Err(data.runsErr).Msg("job run timeline unavailable, skipping run outcome checks")
+		}
+	}
+
+	data.jobs = aggregateJobStats(records, runs)
+	return data, nil
+}
+
+// jobRunKeys returns the job and run ids a usage record is billed to
+func jobRunKeys(record domain.ResourceCost) (jobID, runID string) {
+	jobID = record.Resource.Metadata["job_id"]
+	runID = record.Resource.Metadata["job_run_id"]
+	switch record.Resource.Service {
+	case "job":
+		if jobID == "" {
+			jobID = record.Resource.Name
+		}
+	case "job_run":
+		if runID == "" {
+			runID = record.Resource.Name
+		}
+	}
+	return jobID, runID
+}
+
+// isAllPurposeSKU reports whether usage is billed at all-purpose (interactive) compute rates
+func isAllPurposeSKU(sku string) bool {
+	return strings.Contains(strings.ToUpper(sku), "ALL_PURPOSE")
+}
+
+// aggregateJobStats joins job usage records with job runs, the result is ordered by job ID
+func aggregateJobStats(records []domain.ResourceCost, runs []domain.JobRun) []*JobStats {
+	jobsByID := make(map[string]*JobStats)
+	getJob := func(jobID string) *JobStats {
+		if j, ok := jobsByID[jobID]; ok {
+			return j
+		}
+		j := &JobStats{JobID: jobID}
+		jobsByID[jobID] = j
+		return j
+	}
+	runsByID := make(map[string]*RunStats)
+	getRun := func(jobID, runID string) *RunStats {
+		if r, ok := runsByID[runID]; ok {
+			return r
+		}
+		r := &RunStats{RunID: runID, JobID: jobID}
+		runsByID[runID] = r
+		job := getJob(jobID)
+		job.Runs = append(job.Runs, r)
+		return r
+	}
+
+	for i := range runs {
+		getRun(runs[i].JobID, runs[i].RunID).Run = &runs[i]
+	}
+
+	for _, record := range records {
+		jobID, runID := jobRunKeys(record)
+		if jobID == "" && runID != "" {
+			if r, ok := runsByID[runID]; ok {
+				jobID = r.JobID
+			}
+		}
+		if jobID == "" {
+			continue
+		}
+
+		job := getJob(jobID)
+		var run *RunStats
+		if runID != "" {
+			run = getRun(jobID, runID)
+		}
+		for _, cost := range record.Costs {
+			job.TotalCost += cost.TotalAmount
+			if isAllPurposeSKU(cost.SKU) {
+				job.AllPurposeCost += cost.TotalAmount
+			}
+			if job.Currency == "" {
+				job.Currency = cost.Currency
+			}
+			if run != nil {
+				run.Cost += cost.TotalAmount
+			}
+		}
+	}
+
+	jobs := make([]*JobStats, 0, len(jobsByID))
+	for _, job := range jobsByID {
+		sort.SliceStable(job.Runs, func(i, j int) bool {
+			a, b := job.Runs[i].Run, job.Runs[j].Run
+			if a == nil || b == nil {
+				return b == nil && a != nil
+			}
+			return a.StartTime.Before(b.StartTime)
+		})
+		for i := len(job.Runs) - 1; i >= 0; i-- {
+			if r := job.Runs[i].Run; r != nil && r.RunName != "" {
+				job.Name = r.RunName
+				break
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].JobID < jobs[j].JobID })
+	return jobs
+}
+
+func jobResource(job *JobStats) domain.ResourceDef {
+	resource := domain.ResourceDef{
+		Platform: "Databricks",
+		Service:  "job",
+		Name:     job.JobID,
+	}
+	if job.Name != "" {
+		resource.Description = fmt.Sprintf("Databricks job %s", job.Name)
+		resource.Metadata = map[string]string{"job_name": job.Name}
+	}
+	return resource
+}
+
+func wastedSpendSeverity(cost float64, settings JobAuditSettings) domain.Severity {
+	if cost >= settings.HighWastedCost {
+		return domain.SeverityHigh
+	}
+	return domain.SeverityMedium
+}
+
+// analyzeWastedRuns reports the spend on failed runs and on successful runs that needed task retries
+func analyzeWastedRuns(jobs []*JobStats, settings JobAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, job := range jobs {
+		var failedRuns, retriedRuns, retries int
+		var failedCost, retriedCost float64
+		for _, r := range job.Runs {
+			switch {
+			case r.Run == nil:
+				continue
+			case r.Run.Failed():
+				failedRuns++
+				failedCost += r.Cost
+			case r.Run.Retries > 0:
+				retriedRuns++
+				retries += r.Run.Retries
+				retriedCost += r.Cost
+			}
+		}
+
+		if failedCost > 0 && failedCost >= settings.MinWastedCost {
+			findings = append(findings, domain.AuditFinding{
+				Id:       fmt.Sprintf("%s_failed_runs", job.JobID),
+				Resource: jobResource(job),
+				Issue:    "failed_runs",
+				Description: fmt.Sprintf("%d of %d runs failed, spending %.2f %s (%.0f%% of the job cost) without producing results.",
+					failedRuns, len(job.Runs), failedCost, job.Currency, 100*failedCost/job.TotalCost),
+				Recommendation:          "Fix the root cause of the failures, add validation before expensive tasks, and set timeouts to stop hanging runs early.",
+				Severity:                wastedSpendSeverity(failedCost, settings),
+				EstimatedMonthlySavings: periodSavings(failedCost, job.Currency, domain.SavingsConfidenceHigh),
+			})
+		}
+
+		if retriedCost > 0 && retriedCost >= settings.MinWastedCost {
+			// retried runs still produced results, only the failed attempts are wasted
+			severity := domain.SeverityLow
+			if retriedCost >= settings.HighWastedCost {
+				severity = domain.SeverityMedium
+			}
+			findings = append(findings, domain.AuditFinding{
+				Id:       fmt.Sprintf("%s_retried_runs", job.JobID),
+				Resource: jobResource(job),
+				Issue:    "retried_runs",
+				Description: fmt.Sprintf("%d runs succeeded only after %d task retries, these runs cost %.2f %s.",
+					retriedRuns, retries, retriedCost, job.Currency),
+				Recommendation: "Investigate flaky tasks; retries repeat the work of the failed attempts.",
+				Severity:       severity,
+				// every retry is assumed to repeat the work of one attempt of the run
+				EstimatedMonthlySavings: periodSavings(retriedCost*float64(retries)/float64(retries+retriedRuns),
+					job.Currency, domain.SavingsConfidenceLow),
+			})
+		}
+	}
+
+	return findings
+}
+
+// jobsComputeDiscount is the share of the all-purpose compute price saved by running on jobs compute,
+// based on the list prices of 0.55 and 0.15 per DBU of the premium tier
+const jobsComputeDiscount = 0.7
+
+// analyzeAllPurposeCompute flags jobs billed at all-purpose compute rates instead of job compute rates
+func analyzeAllPurposeCompute(jobs []*JobStats) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, job := range jobs {
+		if job.AllPurposeCost == 0 {
+			continue
+		}
+		findings = append(findings, domain.AuditFinding{
+			Id:       fmt.Sprintf("%s_all_purpose_compute", job.JobID),
+			Resource: jobResource(job),
+			Issue:    "all_purpose_compute",
+			Description: fmt.Sprintf("Job runs on all-purpose compute, costing %.2f %s (%.0f%% of the job cost).",
+				job.AllPurposeCost, job.Currency, 100*job.AllPurposeCost/job.TotalCost),
+			Recommendation:          "Run the job on a job cluster or serverless jobs compute, which are billed at lower rates than all-purpose clusters.",
+			Severity:                domain.SeverityMedium,
+			EstimatedMonthlySavings: periodSavings(job.AllPurposeCost*jobsComputeDiscount, job.Currency, domain.SavingsConfidenceMedium),
+		})
+	}
+
+	return findings
+}
+
+// analyzeRunRegressions compares the latest successful runs of each job against the median of its earlier runs
+func analyzeRunRegressions(jobs []*JobStats, settings JobAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, job := range jobs {
+		var durations, costs []float64
+		for _, r := range job.Runs {
+			if r.Run == nil || r.Run.ResultState != "SUCCEEDED" {
+				continue
+			}
+			durations = append(durations, r.Run.Duration().Minutes())
+			if r.Cost > 0 {
+				costs = append(costs, r.Cost)
+			}
+		}
+
+		if recent, baseline, ok := detectRegression(durations, settings); ok {
+			// assuming the cost of a run grows with its duration, every run of the period pays for the regression
+			excess := job.TotalCost / float64(len(job.Runs)) * (1 - baseline/recent) * float64(len(durations))
+			findings = append(findings, domain.AuditFinding{
+				Id:       fmt.Sprintf("%s_duration_regression", job.JobID),
+				Resource: jobResource(job),
+				Issue:    "duration_regression",
+				Description: fmt.Sprintf("The last %d successful runs took %.1f minutes on average, %.0f%% longer than the median of %.1f minutes of earlier runs.",
+					settings.RecentRuns, recent, 100*(recent/baseline-1), baseline),
+				Recommendation:          "Check for data volume growth, skew or changes in the job code and cluster configuration.",
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: periodSavings(excess, job.Currency, domain.SavingsConfidenceLow),
+			})
+		}
+
+		if recent, baseline, ok := detectRegression(costs, settings); ok {
+			findings = append(findings, domain.AuditFinding{
+				Id:       fmt.Sprintf("%s_cost_regression", job.JobID),
+				Resource: jobResource(job),
+				Issue:    "cost_regression",
+				Description: fmt.Sprintf("The last %d successful runs cost %.2f %s on average, %.0f%% more than the median of %.2f %s of earlier runs.",
+					settings.RecentRuns, recent, job.Currency, 100*(recent/baseline-1), baseline, job.Currency),
+				Recommendation: "Review recent changes to the job, its cluster size and the data it processes.",
+				Severity:       domain.SeverityMedium,
+				// every run of the period at the regressed cost
+				EstimatedMonthlySavings: periodSavings((recent-baseline)*float64(len(costs)), job.Currency, domain.SavingsConfidenceMedium),
+			})
+		}
+	}
+
+	return findings
+}
+
+// detectRegression compares the mean of the latest values against the median of the earlier ones
+func detectRegression(values []float64, settings JobAuditSettings) (recent, baseline float64, regressed bool) {
+	if settings.RecentRuns <= 0 || len(values) < settings.MinHistoryRuns+settings.RecentRuns {
+		return 0, 0, false
+	}
+	split := len(values) - settings.RecentRuns
+	baseline = median(values[:split])
+	for _, v := range values[split:] {
+		recent += v
+	}
+	recent /= float64(settings.RecentRuns)
+	return recent, baseline, baseline > 0 && recent > baseline*(1+settings.RegressionThreshold)
+}
+
+// generateJobSummaryMetrics creates summary metrics for the job audit report
+func generateJobSummaryMetrics(report *domain.AuditReport, jobs []*JobStats, runsAvailable bool, settings JobAuditSettings) {
+	totalCost := 0.0
+	allPurposeCost := 0.0
+	failedRunCost := 0.0
+	var currency string
+	costByJob := make(map[string]float64, len(jobs))
+	outcomes := map[string]int{}
+	var runs []*RunStats
+	for _, job := range jobs {
+		totalCost += job.TotalCost
+		allPurposeCost += job.AllPurposeCost
+		costByJob[job.JobID] = job.TotalCost
+		if currency == "" {
+			currency = job.Currency
+		}
+		for _, r := range job.Runs {
+			runs = append(runs, r)
+			if r.Run == nil {
+				continue
+			}
+			state := r.Run.ResultState
+			if state == "" {
+				state = "RUNNING"
+			}
+			outcomes[state]++
+			if r.Run.Failed() {
+				failedRunCost += r.Cost
+			}
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Cost > runs[j].Cost })
+	var topRuns []map[string]any
+	for _, r := range runs {
+		if len(topRuns) >= settings.TopRunsCount || r.Cost == 0 {
+			break
+		}
+		run := map[string]any{"job_id": r.JobID, "run_id": r.RunID, "cost": r.Cost}
+		if r.Run != nil {
+			run["result_state"] = r.Run.ResultState
+			run["duration_minutes"] = r.Run.Duration().Minutes()
+		}
+		topRuns = append(topRuns, run)
+	}
+
+	report.Summary["jobs_evaluated"] = len(jobs)
+	report.Summary["runs_evaluated"] = len(runs)
+	report.Summary["total_cost_analyzed"] = totalCost
+	report.Summary["all_purpose_cost"] = allPurposeCost
+	report.Summary["cost_by_job"] = costByJob
+	report.Summary["most_expensive_runs"] = topRuns
+	report.Summary["runs_available"] = runsAvailable
+	if runsAvailable {
+		report.Summary["failed_run_cost"] = failedRunCost
+		report.Summary["run_outcomes"] = outcomes
+	}
+	if currency != "" {
+		report.Summary["currency"] = currency
+	}
+
+	severityCounts := map[domain.Severity]int{}
+	for _, finding := range report.Findings {
+		severityCounts[finding.Severity]++
+	}
+	report.Summary["high_severity_findings"] = severityCounts[domain.SeverityHigh]
+	report.Summary["medium_severity_findings"] = severityCounts[domain.SeverityMedium]
+	report.Summary["low_severity_findings"] = severityCounts[domain.SeverityLow]
+	report.Summary["total_findings"] = len(report.Findings)
+
+	if len(report.Findings) == 0 {
+		report.Summary["audit_status"] = "All jobs passed audit checks - no issues detected"
+	} else {
+		report.Summary["audit_status"] = fmt.Sprintf("Audit completed - found %d optimization opportunities across %d jobs", len(report.Findings), len(jobs))
+	}
+}
@@ -0,0 +1,158 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func jobRecord(service, name, jobID, runID, sku string, cost float64) domain.ResourceCost {
+	metadata := map[string]string{"resource_type": service}
+	if jobID != "" {
+		metadata["job_id"] = jobID
+	}
+	if runID != "" {
+		metadata["job_run_id"] = runID
+	}
+	return domain.ResourceCost{
+		Resource: domain.ResourceDef{Platform: "Databricks", Service: service, Name: name, Metadata: metadata},
+		Costs:    []domain.CostComponent{{TotalAmount: cost, Currency: "USD", SKU: sku}},
+	}
+}
+
+func TestGetJobAudit(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "test-workspace"}
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	settings := DefaultJobAuditSettings()
+
+	t.Run("no job activity", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+		mockCostManager.On("GetUsage", ctx, startTime, endTime).Return([]domain.ResourceCost{
+			jobRecord("warehouse", "wh-1", "", "", "SQL", 5),
+		}, nil)
+		mockExplorer.On("GetJobRuns", ctx, startTime, endTime).Return([]domain.JobRun{}, nil)
+
+		report, err := GetJobAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, "job", report.ResourceType)
+		require.Len(t, report.Findings, 1)
+		assert.Equal(t, "no_activity", report.Findings[0].Issue)
+	})
+
+	t.Run("findings and summary", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+
+		var runs []domain.JobRun
+		var records []domain.ResourceCost
+		// job-1: eight daily runs, the last three take twice as long and cost twice as much
+		for day := 0; day < 8; day++ {
+			minutes, cost := 30, 5.0
+			if day >= 5 {
+				minutes, cost = 60, 10.0
+			}
+			runID := string(rune('a' + day))
+			start := startTime.AddDate(0, 0, day)
+			runs = append(runs, domain.JobRun{
+				JobID: "job-1", RunID: runID, RunName: "nightly etl", ResultState: "SUCCEEDED",
+				StartTime: start, EndTime: start.Add(time.Duration(minutes) * time.Minute),
+			})
+			// usage attributed to the cluster still carries the job and run ids
+			records = append(records, jobRecord("cluster", "job-cluster", "job-1", runID, "PREMIUM_JOBS_COMPUTE", cost))
+		}
+		// job-2: an expensive failure and a retried run on all-purpose compute
+		runs = append(runs,
+			domain.JobRun{JobID: "job-2", RunID: "f1", ResultState: "FAILED",
+				StartTime: startTime, EndTime: startTime.Add(time.Hour)},
+			domain.JobRun{JobID: "job-2", RunID: "r1", ResultState: "SUCCEEDED", Retries: 2,
+				StartTime: startTime.Add(2 * time.Hour), EndTime: startTime.Add(3 * time.Hour)},
+		)
+		records = append(records,
+			jobRecord("job_run", "f1", "", "", "PREMIUM_ALL_PURPOSE_COMPUTE", 150),
+			jobRecord("job", "job-2", "", "r1", "PREMIUM_ALL_PURPOSE_COMPUTE", 20),
+		)
+		mockCostManager.On("GetUsage", ctx, startTime, endTime).Return(records, nil)
+		mockExplorer.On("GetJobRuns", ctx, startTime, endTime).Return(runs, nil)
+
+		report, err := GetJobAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+		require.NoError(t, err)
+
+		issues := map[string]domain.AuditFinding{}
+		for _, finding := range report.Findings {
+			issues[finding.Id] = finding
+		}
+		assert.Equal(t, domain.SeverityHigh, issues["job-2_failed_runs"].Severity)
+		assert.Equal(t, domain.SeverityLow, issues["job-2_retried_runs"].Severity)
+		assert.Contains(t, issues, "job-2_all_purpose_compute")
+		assert.Contains(t, issues, "job-1_duration_regression")
+		assert.Contains(t, issues, "job-1_cost_regression")
+		assert.Equal(t, "nightly etl", issues["job-1_cost_regression"].Resource.Metadata["job_name"])
+		assert.Len(t, report.Findings, 5)
+
+		// the period is 30 days, so the estimates are not scaled
+		assert.Equal(t, domain.SavingsEstimate{Amount: 150, Currency: "USD", Confidence: domain.SavingsConfidenceHigh},
+			issues["job-2_failed_runs"].EstimatedMonthlySavings)
+		assert.InDelta(t, 119.0, issues["job-2_all_purpose_compute"].EstimatedMonthlySavings.Amount, 0.001)
+		assert.InDelta(t, 40.0, issues["job-1_cost_regression"].EstimatedMonthlySavings.Amount, 0.001)
+		assert.InDelta(t, 27.5, issues["job-1_duration_regression"].EstimatedMonthlySavings.Amount, 0.001)
+		// only the largest estimate of each job counts
+		assert.InDelta(t, 190.0, report.Summary["total_addressable_savings"], 0.001)
+
+		assert.Equal(t, 2, report.Summary["jobs_evaluated"])
+		assert.Equal(t, 10, report.Summary["runs_evaluated"])
+		assert.InDelta(t, 225.0, report.Summary["total_cost_analyzed"], 0.001)
+		assert.InDelta(t, 170.0, report.Summary["all_purpose_cost"], 0.001)
+		assert.InDelta(t, 150.0, report.Summary["failed_run_cost"], 0.001)
+		assert.Equal(t, map[string]int{"SUCCEEDED": 9, "FAILED": 1}, report.Summary["run_outcomes"])
+		topRuns := report.Summary["most_expensive_runs"].([]map[string]any)
+		require.NotEmpty(t, topRuns)
+		assert.Equal(t, "f1", topRuns[0]["run_id"])
+	})
+
+	t.Run("run timeline errors are tolerated", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+		mockCostManager.On("GetUsage", ctx, startTime, endTime).Return([]domain.ResourceCost{
+			jobRecord("job", "job-1", "", "", "PREMIUM_JOBS_COMPUTE", 5),
+		}, nil)
+		mockExplorer.On("GetJobRuns", ctx, startTime, endTime).Return(nil, errors.New("no warehouse"))
+
+		report, err := GetJobAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+		require.NoError(t, err)
+		assert.Empty(t, report.Findings)
+		assert.Equal(t, false, report.Summary["runs_available"])
+		assert.NotContains(t, report.Summary, "run_outcomes")
+	})
+
+	t.Run("cost manager error", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockCostManager.On("GetUsage", ctx, startTime, endTime).Return([]domain.ResourceCost{}, errors.New("db down"))
+
+		_, err := GetJobAudit(ctx, ws, startTime, endTime, mockCostManager, new(MockExplorer), settings)
+		assert.Error(t, err)
+	})
+}
+
+func TestDetectRegression(t *testing.T) {
+	settings := DefaultJobAuditSettings()
+
+	_, _, regressed := detectRegression([]float64{10, 10, 10, 10, 10, 20, 20}, settings)
+	assert.False(t, regressed, "not enough history")
+
+	recent, baseline, regressed := detectRegression([]float64{10, 12, 8, 10, 10, 16, 16, 16}, settings)
+	assert.True(t, regressed)
+	assert.InDelta(t, 16.0, recent, 0.001)
+	assert.InDelta(t, 10.0, baseline, 0.001)
+
+	_, _, regressed = detectRegression([]float64{10, 12, 8, 10, 10, 14, 14, 14}, settings)
+	assert.False(t, regressed, "within threshold")
+}
@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"math"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// daysPerMonth is the length of the month savings estimates are normalized to
+const daysPerMonth = 30.0
+
+const (
+	// assumedIdleShare is the share of the cost spent idling by resources that never stop,
+	// in line with the idle time heuristic of calculateIdleTime
+	assumedIdleShare = 0.3
+	// targetUtilizationPercent is the average utilization a right-sized cluster is expected to reach
+	targetUtilizationPercent = 50.0
+)
+
+// periodSavings estimates the savings of a finding over the audited period,
+// summarizeSavings normalizes the amounts to a month once the report is complete
+func periodSavings(amount float64, currency string, confidence domain.SavingsConfidence) domain.SavingsEstimate {
+	return domain.SavingsEstimate{Amount: max(amount, 0), Currency: currency, Confidence: confidence}
+}
+
+// noSavings is the estimate of findings without direct cost impact, e.g. failed deployments or missing governance
+func noSavings(currency string) domain.SavingsEstimate {
+	return domain.SavingsEstimate{Currency: currency, Confidence: domain.SavingsConfidenceHigh}
+}
+
+// summarizeSavings scales the savings estimates of the findings from the report period to a month
+// and adds the total addressable savings to the summary
+func summarizeSavings(report *domain.AuditReport) {
+	scale := 1.0
+	if days := report.Period.End.Sub(report.Period.Start).Hours() / 24; days > 0 {
+		scale = daysPerMonth / days
+	}
+	for i := range report.Findings {
+		savings := &report.Findings[i].EstimatedMonthlySavings
+		savings.Amount = math.Round(savings.Amount*scale*100) / 100
+	}
+	addSavingsSummary(report)
+}
+
+// addSavingsSummary sets the total addressable savings of the report findings in the summary,
+// fixes of the same resource overlap, so only the largest estimate of each resource is counted
+func addSavingsSummary(report *domain.AuditReport) {
+	if report.Summary == nil {
+		report.Summary = map[string]any{}
+	}
+
+	byResource := make(map[string]float64)
+	var currency string
+	for _, finding := range report.Findings {
+		savings := finding.EstimatedMonthlySavings
+		key := finding.Resource.Service + "/" + finding.Resource.Name
+		byResource[key] = max(byResource[key], savings.Amount)
+		if currency == "" {
+			currency = savings.Currency
+		}
+	}
+
+	total := 0.0
+	for _, amount := range byResource {
+		total += amount
+	}
+	report.Summary["total_addressable_savings"] = math.Round(total*100) / 100
+	if currency != "" {
+		report.Summary["savings_currency"] = currency
+	}
+}
@@ -0,0 +1,39 @@
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeSavings(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	finding := func(resource string, amount float64) domain.AuditFinding {
+		return domain.AuditFinding{
+			Resource:                domain.ResourceDef{Service: "warehouse", Name: resource},
+			EstimatedMonthlySavings: periodSavings(amount, "USD", domain.SavingsConfidenceLow),
+		}
+	}
+	report := &domain.AuditReport{
+		Period: domain.TimePeriod{Start: start, End: start.AddDate(0, 0, 15)},
+		Findings: []domain.AuditFinding{
+			finding("wh-1", 10),
+			finding("wh-1", 20.004),
+			finding("wh-2", 5),
+			finding("wh-3", -1),
+		},
+		Summary: map[string]any{},
+	}
+
+	summarizeSavings(report)
+
+	// savings over 15 days double over a month
+	assert.Equal(t, 20.0, report.Findings[0].EstimatedMonthlySavings.Amount)
+	assert.Equal(t, 40.01, report.Findings[1].EstimatedMonthlySavings.Amount)
+	assert.Equal(t, 10.0, report.Findings[2].EstimatedMonthlySavings.Amount)
+	assert.Equal(t, 0.0, report.Findings[3].EstimatedMonthlySavings.Amount)
+	assert.Equal(t, 50.01, report.Summary["total_addressable_savings"])
+	assert.Equal(t, "USD", report.Summary["savings_currency"])
+}
@@ -0,0 +1,449 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	endpointRule := func(
+		name, description string,
+		requires func(data endpointAuditData) error,
+		analyze func(stats []*EndpointStats, settings ModelServingAuditSettings) []domain.AuditFinding,
+	) AuditRule {
+		return NewAuditRule(name, "endpoint", description,
+			func(ctx context.Context, in *AuditInput) ([]domain.AuditFinding, error) {
+				data, err := endpointAuditDataFor(ctx, in)
+				if err != nil {
+					return nil, err
+				}
+				if err := requires(data); err != nil {
+					return nil, err
+				}
+				return analyze(data.stats, in.Settings.Endpoint), nil
+			})
+	}
+
+	RegisterAuditRule(endpointRule("endpoint_health", "Failed and not ready serving endpoints",
+		func(data endpointAuditData) error { return data.metadataErr },
+		func(stats []*EndpointStats, _ ModelServingAuditSettings) []domain.AuditFinding {
+			return analyzeEndpointHealth(stats)
+		}))
+	RegisterAuditRule(endpointRule("endpoint_always_on", "Always-on serving endpoints with low traffic",
+		func(data endpointAuditData) error { return errors.Join(data.metadataErr, data.usageErr) },
+		analyzeAlwaysOnEndpoints))
+	RegisterAuditRule(endpointRule("endpoint_active_compute", "Billed compute time far above the hours with requests",
+		func(data endpointAuditData) error { return data.usageErr },
+		analyzeEndpointComputeTime))
+}
+
+// ModelServingAuditSettings contains configurable thresholds for model serving endpoint audit analysis
+type ModelServingAuditSettings struct {
+	// MinRequestsPerComputeHour is the traffic below which an always-on endpoint is considered idle (default: 10)
+	MinRequestsPerComputeHour float64 `json:"min_requests_per_compute_hour" yaml:"min_requests_per_compute_hour"`
+	// MaxComputeToActiveRatio is the ratio of billed compute hours to hours with requests above which
+	// the endpoint stays provisioned for too long after traffic stops (default: 4)
+	MaxComputeToActiveRatio float64 `json:"max_compute_to_active_ratio" yaml:"max_compute_to_active_ratio"`
+	// MinComputeHours is the minimum billed compute hours to judge compute usage against traffic (default: 24)
+	MinComputeHours float64 `json:"min_compute_hours" yaml:"min_compute_hours"`
+}
+
+// DefaultModelServingAuditSettings returns the default configuration for model serving endpoint audits
+func DefaultModelServingAuditSettings() ModelServingAuditSettings {
+	return ModelServingAuditSettings{
+		MinRequestsPerComputeHour: 10,
+		MaxComputeToActiveRatio:   4,
+		MinComputeHours:           24,
+	}
+}
+
+// EndpointStats holds billing, configuration and traffic data of a serving endpoint
+type EndpointStats struct {
+	EndpointID   string
+	Metadata     *domain.ServingEndpointMetadata // nil for endpoints that no longer exist
+	Usage        *domain.ServingEndpointUsage    // nil without request logs for the endpoint
+	TotalCost    float64
+	Currency     string
+	ComputeHours float64 // hours covered by billing records
+}
+
+// Name returns the endpoint name when known, the endpoint ID otherwise
+func (s *EndpointStats) Name() string {
+	if s.Metadata != nil && s.Metadata.Name != "" {
+		return s.Metadata.Name
+	}
+	if s.Usage != nil && s.Usage.EndpointName != "" {
+		return s.Usage.EndpointName
+	}
+	return s.EndpointID
+}
+
+// GetModelServingAudit performs an audit of model serving endpoints for the given workspace and time period,
+// combining billed compute with endpoint configuration and request logs
+func GetModelServingAudit(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	explorer Explorer,
+	settings ModelServingAuditSettings,
+) (domain.AuditReport, error) {
+	input := &AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Explorer:    explorer,
+		Settings:    AuditSettings{Endpoint: settings},
+	}
+
+	data, err := endpointAuditDataFor(ctx, input)
+	if err != nil {
+		return domain.AuditReport{}, err
+	}
+	if len(data.stats) == 0 {
+		return noActivityReport(input, "endpoint", settings,
+			"No serving endpoint usage records found in the selected period",
+			"No model serving activity detected in the selected time window.",
+			"Verify serving endpoint configurations and usage patterns.",
+			domain.SeverityLow), nil
+	}
+
+	return runResourceAudit(ctx, input, "endpoint", func(report *domain.AuditReport) {
+		report.Summary["settings"] = settings
+		generateServingSummaryMetrics(report, data.stats, data.usageErr == nil)
+	})
+}
+
+// endpointAuditDataFor returns the cached endpoint stats of the audit run
+func endpointAuditDataFor(ctx context.Context, in *AuditInput) (endpointAuditData, error) {
+	return loadAuditData(in, "endpoint", func() (endpointAuditData, error) {
+		return loadEndpointAuditData(ctx, in.Workspace, in.StartTime, in.EndTime, in.CostManager, in.Explorer)
+	})
+}
+
+// endpointAuditData holds the endpoint stats of an audit and the errors of its optional data sources
+type endpointAuditData struct {
+	stats       []*EndpointStats
+	metadataErr error
+	usageErr    error
+}
+
+// loadEndpointAuditData reads serving usage, endpoint metadata and request logs,
+// only failing to read usage is an error
+func loadEndpointAuditData(
+	ctx context.Context,
+	ws domain.Workspace,
+	startTime, endTime time.Time,
+	costManager CostManager,
+	explorer Explorer,
+) (endpointAuditData, error) {
+	resources := domain.WorkspaceResources{
+		WorkspaceName: ws.Name,
+		Resources:     []string{"endpoint"},
+	}
+	records, err := costManager.GetResourcesCost(ctx, resources, startTime, endTime)
+	if err != nil {
+		return endpointAuditData{}, err
+	}
+
+	data := endpointAuditData{metadataErr: errExplorerUnavailable, usageErr: errExplorerUnavailable}
+	var endpoints []domain.ServingEndpointMetadata
+	var usage []domain.ServingEndpointUsage
+	if explorer != nil {
+		logger := zerolog.Ctx(ctx)
+		if endpoints, data.metadataErr = explorer.ListServingEndpoints(ctx); data.metadataErr != nil {
+			logger.Warn().Err(data.metadataErr).Msg("serving endpoint metadata unavailable, skipping configuration checks")
+		}
+		if usage, data.usageErr = explorer.GetServingEndpointUsage(ctx, startTime, endTime); data.usageErr != nil {
+			logger.Warn().Err(data.usageErr).Msg("serving endpoint usage unavailable, skipping traffic checks")
+		}
+	}
+
+	data.stats = aggregateEndpointStats(records, endpoints, usage)
+	return data, nil
+}
+
+// aggregateEndpointStats joins usage records, endpoint metadata and request logs by endpoint ID,
+// the result is ordered by endpoint ID
+func aggregateEndpointStats(
+	records []domain.ResourceCost,
+	endpoints []domain.ServingEndpointMetadata,
+	usage []domain.ServingEndpointUsage,
+) []*EndpointStats {
+	statsByID := make(map[string]*EndpointStats)
+	get := func(endpointID string) *EndpointStats {
+		if s, ok := statsByID[endpointID]; ok {
+			return s
+		}
+		s := &EndpointStats{EndpointID: endpointID}
+		statsByID[endpointID] = s
+		return s
+	}
+
+	recordsByID := make(map[string][]domain.ResourceCost)
+	for _, record := range records {
+		s := get(record.Resource.Name)
+		for _, cost := range record.Costs {
+			s.TotalCost += cost.TotalAmount
+			if s.Currency == "" {
+				s.Currency = cost.Currency
+			}
+		}
+		recordsByID[record.Resource.Name] = append(recordsByID[record.Resource.Name], record)
+	}
+	for endpointID, endpointRecords := range recordsByID {
+		for _, interval := range mergeUsageIntervals(endpointRecords) {
+			statsByID[endpointID].ComputeHours += interval.end.Sub(interval.start).Hours()
+		}
+	}
+
+	for i := range endpoints {
+		get(endpoints[i].ID).Metadata = &endpoints[i]
+	}
+	for i := range usage {
+		if s, ok := statsByID[usage[i].EndpointID]; ok {
+			s.Usage = &usage[i]
+		}
+	}
+
+	stats := make([]*EndpointStats, 0, len(statsByID))
+	for _, s := range statsByID {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].EndpointID < stats[j].EndpointID })
+	return stats
+}
+
+func endpointResource(s *EndpointStats) domain.ResourceDef {
+	resource := domain.ResourceDef{
+		Platform: "Databricks",
+		Service:  "endpoint",
+		Name:     s.EndpointID,
+	}
+	if name := s.Name(); name != s.EndpointID {
+		resource.Description = fmt.Sprintf("Model serving endpoint %s", name)
+		resource.Metadata = map[string]string{"endpoint_name": name}
+	}
+	return resource
+}
+
+// describeServedEntities summarizes workload size and scaling of the served entities with provisioned compute
+func describeServedEntities(entities []domain.ServedEntityMetadata) string {
+	var parts []string
+	for _, e := range entities {
+		if e.External {
+			continue
+		}
+		part := fmt.Sprintf("%s (%s", e.Name, e.WorkloadSize)
+		if e.WorkloadType != "" {
+			part += " " + e.WorkloadType
+		}
+		if e.MaxProvisionedConcurrency > 0 {
+			part += fmt.Sprintf(", concurrency %d-%d", e.MinProvisionedConcurrency, e.MaxProvisionedConcurrency)
+		}
+		if e.MaxProvisionedThroughput > 0 {
+			part += fmt.Sprintf(", throughput %d-%d", e.MinProvisionedThroughput, e.MaxProvisionedThroughput)
+		}
+		if e.ScaleToZeroEnabled {
+			part += ", scale-to-zero"
+		}
+		parts = append(parts, part+")")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// idleComputeCost estimates the cost of compute billed in hours without requests,
+// which scale-to-zero would not have been billed for
+func idleComputeCost(s *EndpointStats) float64 {
+	if s.Usage == nil || s.ComputeHours == 0 {
+		return 0
+	}
+	// hours with traffic are rounded up to whole hours, so one active hour is the lower bound
+	activeHours := min(max(s.Usage.ActiveHours, 1), s.ComputeHours)
+	return s.TotalCost * (1 - activeHours/s.ComputeHours)
+}
+
+// analyzeEndpointHealth flags endpoints with failed deployments or config updates and endpoints that are not ready
+func analyzeEndpointHealth(stats []*EndpointStats) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, s := range stats {
+		if s.Metadata == nil {
+			continue
+		}
+
+		var failed []string
+		for _, e := range s.Metadata.ServedEntities {
+			if e.DeploymentState == "DEPLOYMENT_FAILED" || e.DeploymentState == "DEPLOYMENT_ABORTED" {
+				failed = append(failed, fmt.Sprintf("%s: %s", e.Name, e.DeploymentStateMessage))
+			}
+		}
+		if s.Metadata.ConfigUpdate == "UPDATE_FAILED" {
+			failed = append(failed, "latest config update failed")
+		}
+
+		switch {
+		case len(failed) > 0:
+			findings = append(findings, domain.AuditFinding{
+				Id:                      fmt.Sprintf("%s_deployment_failed", s.EndpointID),
+				Resource:                endpointResource(s),
+				Issue:                   "deployment_failed",
+				Description:             fmt.Sprintf("Endpoint deployment failed (%s).", strings.Join(failed, "; ")),
+				Recommendation:          "Check the build and server logs of the endpoint, fix the model or config, or delete the endpoint if it is no longer needed.",
+				Severity:                domain.SeverityHigh,
+				EstimatedMonthlySavings: periodSavings(s.TotalCost, s.Currency, domain.SavingsConfidenceHigh),
+			})
+		case s.Metadata.Ready == "NOT_READY":
+			severity := domain.SeverityLow
+			if s.TotalCost > 0 {
+				severity = domain.SeverityMedium
+			}
+			findings = append(findings, domain.AuditFinding{
+				Id:                      fmt.Sprintf("%s_endpoint_not_ready", s.EndpointID),
+				Resource:                endpointResource(s),
+				Issue:                   "endpoint_not_ready",
+				Description:             fmt.Sprintf("Endpoint is not ready to serve requests (cost %.2f %s in the period).", s.TotalCost, s.Currency),
+				Recommendation:          "Investigate why the endpoint is stuck provisioning, or delete it if it is no longer needed.",
+				Severity:                severity,
+				EstimatedMonthlySavings: periodSavings(s.TotalCost, s.Currency, domain.SavingsConfidenceHigh),
+			})
+		}
+	}
+
+	return findings
+}
+
+// analyzeAlwaysOnEndpoints flags endpoints keeping compute provisioned without enough traffic to justify it
+func analyzeAlwaysOnEndpoints(stats []*EndpointStats, settings ModelServingAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, s := range stats {
+		// Request logs only exist for endpoints with usage tracking, without them traffic is unknown
+		if s.Metadata == nil || s.Usage == nil || s.ComputeHours == 0 {
+			continue
+		}
+		var alwaysOn []domain.ServedEntityMetadata
+		for _, e := range s.Metadata.ServedEntities {
+			if e.AlwaysOn() {
+				alwaysOn = append(alwaysOn, e)
+			}
+		}
+		if len(alwaysOn) == 0 {
+			continue
+		}
+
+		requests := s.Usage.Requests
+		requestsPerHour := float64(requests) / s.ComputeHours
+		if requestsPerHour >= settings.MinRequestsPerComputeHour {
+			continue
+		}
+
+		severity := domain.SeverityMedium
+		if requestsPerHour < 1 {
+			severity = domain.SeverityHigh
+		}
+		findings = append(findings, domain.AuditFinding{
+			Id:       fmt.Sprintf("%s_always_on_low_traffic", s.EndpointID),
+			Resource: endpointResource(s),
+			Issue:    "always_on_low_traffic",
+			Description: fmt.Sprintf("Endpoint keeps compute provisioned without scale-to-zero [%s] but served only %d requests over %.1f compute hours (%.1f per hour, threshold %.1f); cost %.2f %s.",
+				describeServedEntities(alwaysOn), requests, s.ComputeHours, requestsPerHour, settings.MinRequestsPerComputeHour, s.TotalCost, s.Currency),
+			Recommendation:          "Enable scale-to-zero and set minimum provisioned concurrency to zero, or delete the endpoint if it is unused.",
+			Severity:                severity,
+			EstimatedMonthlySavings: periodSavings(idleComputeCost(s), s.Currency, domain.SavingsConfidenceMedium),
+		})
+	}
+
+	return findings
+}
+
+// analyzeEndpointComputeTime flags endpoints whose billed compute time far exceeds the hours with requests
+func analyzeEndpointComputeTime(stats []*EndpointStats, settings ModelServingAuditSettings) []domain.AuditFinding {
+	var findings []domain.AuditFinding
+
+	for _, s := range stats {
+		if s.Usage == nil || s.ComputeHours < settings.MinComputeHours {
+			continue
+		}
+		// hours with traffic are rounded up to whole hours, so one active hour is the lower bound
+		activeHours := max(s.Usage.ActiveHours, 1)
+		ratio := s.ComputeHours / activeHours
+		if ratio <= settings.MaxComputeToActiveRatio {
+			continue
+		}
+
+		description := fmt.Sprintf("Endpoint was billed for %.1f compute hours but received requests in only %.0f hours (%.1fx, threshold %.1fx).",
+			s.ComputeHours, s.Usage.ActiveHours, ratio, settings.MaxComputeToActiveRatio)
+		if s.Metadata != nil {
+			if entities := describeServedEntities(s.Metadata.ServedEntities); entities != "" {
+				description += fmt.Sprintf(" Served entities: %s.", entities)
+			}
+		}
+		findings = append(findings, domain.AuditFinding{
+			Id:                      fmt.Sprintf("%s_high_active_compute", s.EndpointID),
+			Resource:                endpointResource(s),
+			Issue:                   "high_active_compute",
+			Description:             description,
+			Recommendation:          "Reduce the workload size or maximum provisioned concurrency, and enable scale-to-zero for bursty traffic.",
+			Severity:                domain.SeverityMedium,
+			EstimatedMonthlySavings: periodSavings(idleComputeCost(s), s.Currency, domain.SavingsConfidenceMedium),
+		})
+	}
+
+	return findings
+}
+
+// generateServingSummaryMetrics creates summary metrics for the model serving audit report
+func generateServingSummaryMetrics(report *domain.AuditReport, stats []*EndpointStats, usageAvailable bool) {
+	totalCost := 0.0
+	computeHours := 0.0
+	var totalRequests int64
+	var currency string
+	costByEndpoint := make(map[string]float64, len(stats))
+	for _, s := range stats {
+		totalCost += s.TotalCost
+		computeHours += s.ComputeHours
+		costByEndpoint[s.Name()] += s.TotalCost
+		if s.Usage != nil {
+			totalRequests += s.Usage.Requests
+		}
+		if currency == "" {
+			currency = s.Currency
+		}
+	}
+
+	report.Summary["endpoints_evaluated"] = len(stats)
+	report.Summary["total_cost_analyzed"] = totalCost
+	report.Summary["compute_hours"] = computeHours
+	report.Summary["cost_by_endpoint"] = costByEndpoint
+	report.Summary["usage_available"] = usageAvailable
+	if usageAvailable {
+		report.Summary["total_requests"] = totalRequests
+	}
+	if currency != "" {
+		report.Summary["currency"] = currency
+	}
+
+	severityCounts := map[domain.Severity]int{}
+	for _, finding := range report.Findings {
+		severityCounts[finding.Severity]++
+	}
+	report.Summary["high_severity_findings"] = severityCounts[domain.SeverityHigh]
+	report.Summary["medium_severity_findings"] = severityCounts[domain.SeverityMedium]
+	report.Summary["low_severity_findings"] = severityCounts[domain.SeverityLow]
+	report.Summary["total_findings"] = len(report.Findings)
+
+	if len(report.Findings) == 0 {
+		report.Summary["audit_status"] = "All serving endpoints passed audit checks - no issues detected"
+	} else {
+		report.Summary["audit_status"] = fmt.Sprintf("Audit completed - found %d optimization opportunities across %d endpoints", len(report.Findings), len(stats))
+	}
+}
@@ -0,0 +1,135 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func endpointRecords(endpointID string, start time.Time, hours int, costPerHour float64) []domain.ResourceCost {
+	var records []domain.ResourceCost
+	for h := 0; h < hours; h++ {
+		records = append(records, domain.ResourceCost{
+			Resource:  domain.ResourceDef{Platform: "Databricks", Service: "endpoint", Name: endpointID},
+			StartTime: start.Add(time.Duration(h) * time.Hour),
+			EndTime:   start.Add(time.Duration(h+1) * time.Hour),
+			Costs:     []domain.CostComponent{{TotalAmount: costPerHour, Currency: "USD"}},
+		})
+	}
+	return records
+}
+
+func TestGetModelServingAudit(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "test-workspace"}
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	settings := DefaultModelServingAuditSettings()
+
+	t.Run("no serving activity", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).
+			Return([]domain.ResourceCost{}, nil)
+		mockExplorer.On("ListServingEndpoints", ctx).Return([]domain.ServingEndpointMetadata{}, nil)
+		mockExplorer.On("GetServingEndpointUsage", ctx, startTime, endTime).Return([]domain.ServingEndpointUsage{}, nil)
+
+		report, err := GetModelServingAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+
+		require.NoError(t, err)
+		assert.Equal(t, "endpoint", report.ResourceType)
+		require.Len(t, report.Findings, 1)
+		assert.Equal(t, "no_activity", report.Findings[0].Issue)
+	})
+
+	t.Run("findings and summary", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+
+		var records []domain.ResourceCost
+		records = append(records, endpointRecords("ep-idle", startTime, 48, 1)...)
+		records = append(records, endpointRecords("ep-busy", startTime, 48, 2)...)
+		records = append(records, endpointRecords("ep-failed", startTime, 2, 1)...)
+		mockCostManager.On("GetResourcesCost", ctx, domain.WorkspaceResources{
+			WorkspaceName: "test-workspace",
+			Resources:     []string{"endpoint"},
+		}, startTime, endTime).Return(records, nil)
+
+		mockExplorer.On("ListServingEndpoints", ctx).Return([]domain.ServingEndpointMetadata{
+			{ID: "ep-idle", Name: "recommender", Ready: "READY", ServedEntities: []domain.ServedEntityMetadata{
+				{Name: "model-1", WorkloadSize: "Small", MinProvisionedConcurrency: 4, MaxProvisionedConcurrency: 4},
+			}},
+			{ID: "ep-busy", Name: "classifier", Ready: "READY", ServedEntities: []domain.ServedEntityMetadata{
+				{Name: "model-2", WorkloadSize: "Large", ScaleToZeroEnabled: true, MaxProvisionedConcurrency: 64},
+			}},
+			{ID: "ep-failed", Name: "broken", Ready: "NOT_READY", ServedEntities: []domain.ServedEntityMetadata{
+				{Name: "model-3", DeploymentState: "DEPLOYMENT_FAILED", DeploymentStateMessage: "container crashed"},
+			}},
+			{ID: "ep-pending", Name: "pending", Ready: "NOT_READY"},
+			{ID: "ep-external", Name: "gpt", Ready: "READY", ServedEntities: []domain.ServedEntityMetadata{
+				{Name: "openai", External: true},
+			}},
+		}, nil)
+		mockExplorer.On("GetServingEndpointUsage", ctx, startTime, endTime).Return([]domain.ServingEndpointUsage{
+			{EndpointID: "ep-idle", Requests: 20, ActiveHours: 2},
+			// plenty of traffic, but all within 6 of the 48 billed hours
+			{EndpointID: "ep-busy", Requests: 50000, ActiveHours: 6},
+		}, nil)
+
+		report, err := GetModelServingAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+		require.NoError(t, err)
+
+		issues := map[string]domain.AuditFinding{}
+		for _, finding := range report.Findings {
+			issues[finding.Id] = finding
+		}
+		assert.Equal(t, domain.SeverityHigh, issues["ep-failed_deployment_failed"].Severity)
+		assert.Contains(t, issues["ep-failed_deployment_failed"].Description, "container crashed")
+		assert.Equal(t, domain.SeverityLow, issues["ep-pending_endpoint_not_ready"].Severity)
+		assert.Equal(t, domain.SeverityHigh, issues["ep-idle_always_on_low_traffic"].Severity)
+		assert.Equal(t, "recommender", issues["ep-idle_always_on_low_traffic"].Resource.Metadata["endpoint_name"])
+		assert.Contains(t, issues["ep-idle_always_on_low_traffic"].Description, "concurrency 4-4")
+		assert.Contains(t, issues, "ep-idle_high_active_compute")
+		assert.Contains(t, issues, "ep-busy_high_active_compute")
+		assert.NotContains(t, issues, "ep-busy_always_on_low_traffic")
+		assert.Len(t, report.Findings, 5)
+
+		assert.Equal(t, 5, report.Summary["endpoints_evaluated"])
+		assert.InDelta(t, 146.0, report.Summary["total_cost_analyzed"], 0.001)
+		assert.InDelta(t, 98.0, report.Summary["compute_hours"], 0.001)
+		assert.Equal(t, int64(50020), report.Summary["total_requests"])
+		assert.Equal(t, true, report.Summary["usage_available"])
+	})
+
+	t.Run("traffic checks need request logs", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockExplorer := new(MockExplorer)
+		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).
+			Return(endpointRecords("ep-idle", startTime, 48, 1), nil)
+		mockExplorer.On("ListServingEndpoints", ctx).Return([]domain.ServingEndpointMetadata{
+			{ID: "ep-idle", Ready: "READY", ServedEntities: []domain.ServedEntityMetadata{{Name: "model-1"}}},
+		}, nil)
+		mockExplorer.On("GetServingEndpointUsage", ctx, startTime, endTime).Return(nil, errors.New("no warehouse"))
+
+		report, err := GetModelServingAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
+		require.NoError(t, err)
+		assert.Empty(t, report.Findings)
+		assert.Equal(t, false, report.Summary["usage_available"])
+		assert.NotContains(t, report.Summary, "total_requests")
+	})
+
+	t.Run("cost manager error", func(t *testing.T) {
+		mockCostManager := new(MockCostManager)
+		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).
+			Return([]domain.ResourceCost{}, errors.New("db down"))
+
+		_, err := GetModelServingAudit(ctx, ws, startTime, endTime, mockCostManager, new(MockExplorer), settings)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// GetTopCostDrivers ranks the values of the dimension by cost in the period and returns the n most expensive ones
+// with their share of the total cost, their daily cost and the change from the preceding period of the same length
+func GetTopCostDrivers(
+	ctx context.Context,
+	ws domain.Workspace,
+	period domain.TimePeriod,
+	n int,
+	by domain.CostDimension,
+	analyzer CostAnalyzer,
+) (domain.CostDriversReport, error) {
+	if n <= 0 {
+		return domain.CostDriversReport{}, fmt.Errorf("number of cost drivers must be positive")
+	}
+
+	previous := domain.TimePeriod{
+		Start:    period.Start.Add(-period.End.Sub(period.Start)),
+		End:      period.Start,
+		Duration: period.Duration,
+	}
+	comparison, err := analyzer.CompareCost(ctx, by, period, previous, nil)
+	if err != nil {
+		return domain.CostDriversReport{}, err
+	}
+
+	report := domain.CostDriversReport{
+		Workspace:      ws.Name,
+		By:             by,
+		Period:         period,
+		PreviousPeriod: previous,
+		TotalCost:      comparison.TotalCost,
+		Currency:       comparison.Currency,
+		Days:           []time.Time{},
+		Drivers:        []domain.CostDriver{},
+	}
+
+	items := make([]domain.CostComparison, 0, len(comparison.Items))
+	for _, item := range comparison.Items {
+		if item.Cost > 0 {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Cost > items[j].Cost })
+	if len(items) > n {
+		items = items[:n]
+	}
+
+	dayIndex := make(map[time.Time]int)
+	for day := truncateToDay(period.Start); day.Before(period.End); day = day.AddDate(0, 0, 1) {
+		dayIndex[day] = len(report.Days)
+		report.Days = append(report.Days, day)
+	}
+
+	driverIndex := make(map[string]int, len(items))
+	topCost := 0.0
+	for _, item := range items {
+		driver := domain.CostDriver{
+			Key:           item.Key,
+			ResourceType:  item.ResourceType,
+			Cost:          item.Cost,
+			PreviousCost:  item.BaselineCost,
+			Change:        item.Delta,
+			ChangePercent: item.DeltaPercent,
+			Daily:         make([]float64, len(report.Days)),
+		}
+		if report.TotalCost > 0 {
+			driver.Share = item.Cost / report.TotalCost
+		}
+		driverIndex[item.Key] = len(report.Drivers)
+		report.Drivers = append(report.Drivers, driver)
+		topCost += item.Cost
+	}
+	report.OtherCost = report.TotalCost - topCost
+
+	if len(report.Drivers) == 0 {
+		return report, nil
+	}
+
+	daily, err := analyzer.GetDailyCost(ctx, by, period.Start, period.End)
+	if err != nil {
+		return domain.CostDriversReport{}, err
+	}
+	for _, c := range daily {
+		i, ok := driverIndex[c.Key]
+		if !ok {
+			continue
+		}
+		if d, ok := dayIndex[truncateToDay(c.Date)]; ok {
+			report.Drivers[i].Daily[d] += c.TotalCost
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,84 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTopCostDrivers(t *testing.T) {
+	ctx := context.Background()
+	ws := domain.Workspace{Name: "ws1"}
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 3)
+	period := domain.TimePeriod{Start: start, End: end, Duration: 3}
+	previous := domain.TimePeriod{Start: start.AddDate(0, 0, -3), End: start, Duration: 3}
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("CompareCost", mock.Anything, domain.CostDimensionResourceID, period, previous, []domain.CostFilter(nil)).
+		Return(domain.CostComparisonReport{
+			TotalCost: 200,
+			Currency:  "USD",
+			// ordered by absolute delta, not by cost
+			Items: []domain.CostComparison{
+				{Key: "job-gone", BaselineCost: 500, Delta: -500},
+				{Key: "wh-1", ResourceType: "warehouse", Cost: 120, BaselineCost: 60, Delta: 60,
+					DeltaPercent: domain.DeltaPercent(120, 60)},
+				{Key: "job-new", ResourceType: "job", Cost: 50, Delta: 50},
+				{Key: "app-1", ResourceType: "app", Cost: 30, BaselineCost: 30},
+			},
+		}, nil)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceID, start, end).
+		Return(append(
+			dailyCosts(domain.CostDimensionResourceID, "wh-1", start, 20, 40, 60),
+			dailyCosts(domain.CostDimensionResourceID, "app-1", start, 10, 10, 10)...,
+		), nil)
+
+	report, err := GetTopCostDrivers(ctx, ws, period, 2, domain.CostDimensionResourceID, analyzer)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ws1", report.Workspace)
+	assert.Equal(t, previous, report.PreviousPeriod)
+	assert.Len(t, report.Days, 3)
+	require.Len(t, report.Drivers, 2)
+
+	wh := report.Drivers[0]
+	assert.Equal(t, "wh-1", wh.Key)
+	assert.Equal(t, "warehouse", wh.ResourceType)
+	assert.InDelta(t, 0.6, wh.Share, 0.001)
+	assert.InDelta(t, 60.0, wh.Change, 0.001)
+	require.NotNil(t, wh.ChangePercent)
+	assert.InDelta(t, 100.0, *wh.ChangePercent, 0.001)
+	assert.Equal(t, []float64{20, 40, 60}, wh.Daily)
+
+	job := report.Drivers[1]
+	assert.Equal(t, "job-new", job.Key)
+	assert.Nil(t, job.ChangePercent)
+	assert.Equal(t, []float64{0, 0, 0}, job.Daily)
+
+	assert.InDelta(t, 30.0, report.OtherCost, 0.001)
+	analyzer.AssertExpectations(t)
+}
+
+func TestGetTopCostDrivers_NoUsage(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	period := domain.TimePeriod{Start: start, End: start.AddDate(0, 0, 7)}
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("CompareCost", mock.Anything, domain.CostDimensionSKU, period, mock.Anything, mock.Anything).
+		Return(domain.CostComparisonReport{}, nil)
+
+	report, err := GetTopCostDrivers(ctx, domain.Workspace{}, period, 10, domain.CostDimensionSKU, analyzer)
+	require.NoError(t, err)
+	assert.Empty(t, report.Drivers)
+	analyzer.AssertNotCalled(t, "GetDailyCost")
+
+	_, err = GetTopCostDrivers(ctx, domain.Workspace{}, period, 0, domain.CostDimensionSKU, analyzer)
+	assert.Error(t, err)
+}
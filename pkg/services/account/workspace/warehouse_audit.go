@@ -6,22 +6,140 @@ import (
 	"time"
 
 	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/rs/zerolog"
 )
 
+func init() {
+	warehouseRule := func(
+		name, description string,
+		analyze func(ctx context.Context, in *AuditInput, records []domain.ResourceCost, metadata map[string]domain.WarehouseMetadata) []domain.AuditFinding,
+	) AuditRule {
+		return NewAuditRule(name, "warehouse", description,
+			func(ctx context.Context, in *AuditInput) ([]domain.AuditFinding, error) {
+				records, err := in.resourceCost(ctx, "warehouse")
+				if err != nil {
+					return nil, err
+				}
+				metadata, err := warehouseMetadataFor(ctx, in, records)
+				if err != nil {
+					return nil, err
+				}
+				return analyze(ctx, in, records, metadata), nil
+			})
+	}
+
+	RegisterAuditRule(NewAuditRule("warehouse_runtime", "warehouse",
+		"Warehouses running for long periods or idling",
+		func(ctx context.Context, in *AuditInput) ([]domain.AuditFinding, error) {
+			records, err := in.resourceCost(ctx, "warehouse")
+			if err != nil {
+				return nil, err
+			}
+			return analyzeRuntimeDuration(records, warehouseUptimeFor(ctx, in), in.Settings.Warehouse), nil
+		}))
+	RegisterAuditRule(warehouseRule("warehouse_sizing", "Oversized warehouses and cluster counts",
+		func(ctx context.Context, in *AuditInput, records []domain.ResourceCost, metadata map[string]domain.WarehouseMetadata) []domain.AuditFinding {
+			return analyzeWarehouseSizes(records, metadata, in.Settings.Warehouse)
+		}))
+	RegisterAuditRule(warehouseRule("warehouse_best_practices", "Auto-stop, serverless and channel configuration",
+		func(ctx context.Context, in *AuditInput, records []domain.ResourceCost, metadata map[string]domain.WarehouseMetadata) []domain.AuditFinding {
+			return analyzeBestPracticesCompliance(records, metadata, warehouseBudgetControlsFor(ctx, in), in.Settings.Warehouse)
+		}))
+	RegisterAuditRule(warehouseRule("warehouse_stale_resources", "Warehouses without recent activity",
+		func(ctx context.Context, in *AuditInput, records []domain.ResourceCost, metadata map[string]domain.WarehouseMetadata) []domain.AuditFinding {
+			return analyzeStaleResources(records, metadata, warehouseQueryStatsFor(ctx, in), in.Settings.Warehouse, in.StartTime, in.EndTime)
+		}))
+	RegisterAuditRule(warehouseRule("warehouse_provisioning", "Warehouse size mismatched with the workload",
+		func(ctx context.Context, in *AuditInput, records []domain.ResourceCost, metadata map[string]domain.WarehouseMetadata) []domain.AuditFinding {
+			return analyzeProvisioningPatterns(records, metadata, warehouseQueryStatsFor(ctx, in), in.Settings.Warehouse)
+		}))
+}
+
+// warehouseMetadataFor returns the cached warehouse metadata of the audit run
+func warehouseMetadataFor(
+	ctx context.Context,
+	in *AuditInput,
+	records []domain.ResourceCost,
+) (map[string]domain.WarehouseMetadata, error) {
+	return loadAuditData(in, "warehouse_metadata", func() (map[string]domain.WarehouseMetadata, error) {
+		if in.Explorer == nil {
+			return nil, errExplorerUnavailable
+		}
+		return fetchWarehouseMetadata(ctx, records, in.Explorer), nil
+	})
+}
+
+// warehouseQueryStatsFor returns the cached query history of the audit run keyed by warehouse ID,
+// nil when the history cannot be read and the checks fall back to estimates from usage records
+func warehouseQueryStatsFor(ctx context.Context, in *AuditInput) map[string]domain.WarehouseQueryStats {
+	stats, _ := loadAuditData(in, "warehouse_query_stats", func() (map[string]domain.WarehouseQueryStats, error) {
+		if in.Explorer == nil {
+			return nil, errExplorerUnavailable
+		}
+		return fetchWarehouseQueryStats(ctx, in.Explorer, in.StartTime, in.EndTime), nil
+	})
+	return stats
+}
+
+// fetchWarehouseQueryStats reads the query history of all warehouses, it returns nil when the history is unavailable
+func fetchWarehouseQueryStats(
+	ctx context.Context,
+	explorer Explorer,
+	startTime, endTime time.Time,
+) map[string]domain.WarehouseQueryStats {
+	stats, err := explorer.GetWarehouseQueryStats(ctx, startTime, endTime)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("warehouse query history unavailable, estimating workload from usage records")
+		return nil
+	}
+
+	statsByID := make(map[string]domain.WarehouseQueryStats, len(stats))
+	for _, s := range stats {
+		statsByID[s.WarehouseID] = s
+	}
+	return statsByID
+}
+
+// warehouseBudgetControlsFor returns the cached budgets and budget policies of the workspace,
+// nil when they cannot be read and the budget checks are skipped
+func warehouseBudgetControlsFor(ctx context.Context, in *AuditInput) *domain.BudgetControls {
+	controls, _ := loadAuditData(in, "budget_controls", func() (*domain.BudgetControls, error) {
+		if in.Explorer == nil {
+			return nil, errExplorerUnavailable
+		}
+		return fetchBudgetControls(ctx, in.Explorer), nil
+	})
+	return controls
+}
+
+// fetchBudgetControls reads the budgets and budget policies of the workspace, it returns nil when they are unavailable
+func fetchBudgetControls(ctx context.Context, explorer Explorer) *domain.BudgetControls {
+	controls, err := explorer.GetBudgetControls(ctx)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("budgets unavailable, skipping budget alert and spending limit checks")
+		return nil
+	}
+	return controls
+}
+
 // WarehouseAuditSettings contains configurable thresholds for warehouse audit analysis
 type WarehouseAuditSettings struct {
 	// MaxRuntimeHours is the threshold for flagging warehouses with excessive runtime (default: 8.0)
-	MaxRuntimeHours float64
+	MaxRuntimeHours float64 `json:"max_runtime_hours" yaml:"max_runtime_hours"`
 	// MaxIdleHours is the threshold for flagging warehouses with excessive idle time (default: 2.0)
-	MaxIdleHours float64
+	MaxIdleHours float64 `json:"max_idle_hours" yaml:"max_idle_hours"`
 	// IdleTimeThreshold is the percentage threshold for idle time vs active time (default: 0.5 = 50%)
-	IdleTimeThreshold float64
+	IdleTimeThreshold float64 `json:"idle_time_threshold" yaml:"idle_time_threshold"`
 	// StaleResourceDays is the number of days to consider a warehouse stale (default: 30)
-	StaleResourceDays int
+	StaleResourceDays int `json:"stale_resource_days" yaml:"stale_resource_days"`
 	// TopLargestCount is the number of largest warehouses to identify (default: 5)
-	TopLargestCount int
+	TopLargestCount int `json:"top_largest_count" yaml:"top_largest_count"`
 	// MinQueryCountThreshold is the minimum query count for provisioning analysis (default: 10)
-	MinQueryCountThreshold int
+	MinQueryCountThreshold int `json:"min_query_count_threshold" yaml:"min_query_count_threshold"`
+	// MaxAvgQueuedSeconds is the average queueing time of queries above which a warehouse is under-provisioned (default: 10)
+	MaxAvgQueuedSeconds float64 `json:"max_avg_queued_seconds" yaml:"max_avg_queued_seconds"`
+	// MaxSpillRatio is the share of spilled to scanned bytes above which a warehouse is under-provisioned (default: 0.05)
+	MaxSpillRatio float64 `json:"max_spill_ratio" yaml:"max_spill_ratio"`
 }
 
 // DefaultWarehouseAuditSettings returns the default configuration for warehouse audits
@@ -33,6 +151,8 @@ func DefaultWarehouseAuditSettings() WarehouseAuditSettings {
 		StaleResourceDays:      30,
 		TopLargestCount:        5,
 		MinQueryCountThreshold: 10,
+		MaxAvgQueuedSeconds:    10,
+		MaxSpillRatio:          0.05,
 	}
 }
 
@@ -70,7 +190,12 @@ type WarehouseRuntimeStats struct {
 	TotalIdleHours    float64
 	IdleTimePercent   float64
 	RecordCount       int
+	TotalCost         float64
 	Currency          string
+	UptimeMeasured    bool // runtime reconstructed from warehouse events
+	IdleMeasured      bool // idle time measured against the query history
+	ClusterHours      float64
+	PeakClusters      int
 }
 
 // GetWarehouseAudit performs a comprehensive audit of SQL warehouses for the given workspace
@@ -83,63 +208,42 @@ func GetWarehouseAudit(
 	explorer Explorer,
 	settings WarehouseAuditSettings,
 ) (domain.AuditReport, error) {
-	// Initialize audit report
-	report := domain.AuditReport{
-		Workspace:    ws.Name,
-		ResourceType: "warehouse",
-		Period: domain.TimePeriod{
-			Start:    startTime,
-			End:      endTime,
-			Duration: int(endTime.Sub(startTime).Hours() / 24),
-		},
-		Summary:  map[string]any{},
-		Findings: []domain.AuditFinding{},
-	}
-
-	// Retrieve warehouse usage data from cost manager
-	resources := domain.WorkspaceResources{
-		WorkspaceName: ws.Name,
-		Resources:     []string{"warehouse"},
-	}
-	records, err := costManager.GetResourcesCost(ctx, resources, startTime, endTime)
+	input := &AuditInput{
+		Workspace:   ws,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		CostManager: costManager,
+		Explorer:    explorer,
+		Settings:    AuditSettings{Warehouse: settings},
+	}
+
+	records, err := input.resourceCost(ctx, "warehouse")
 	if err != nil {
 		return domain.AuditReport{}, err
 	}
-
-	// Handle no data scenario
 	if len(records) == 0 {
-		report.Summary["no_activity"] = "No warehouse usage records found in the selected period"
-		report.Findings = append(report.Findings, domain.AuditFinding{
-			Id:             "no_activity",
-			Resource:       domain.ResourceDef{Platform: "Databricks", Service: "warehouse", Name: "workspace"},
-			Issue:          "no_activity",
-			Description:    "No warehouse activity detected in the selected time window.",
-			Recommendation: "Verify warehouse configurations and usage patterns. Consider reducing provisioned resources if unused.",
-			Severity:       domain.SeverityMedium,
-		})
-		return report, nil
-	}
-
-	// Fetch warehouse metadata once for reuse across all analyses
-	warehouseMetadata := make(map[string]domain.WarehouseMetadata)
-	if explorer != nil {
-		warehouseMetadata = fetchWarehouseMetadata(ctx, records, explorer)
-	}
-
-	// Run all analyses and collect findings
-	report.Findings = append(report.Findings, analyzeRuntimeDuration(records, settings)...)
-
-	if explorer != nil {
-		report.Findings = append(report.Findings, analyzeWarehouseSizes(records, warehouseMetadata, settings)...)
-		report.Findings = append(report.Findings, analyzeBestPracticesCompliance(records, warehouseMetadata, settings)...)
-		report.Findings = append(report.Findings, analyzeStaleResources(records, warehouseMetadata, settings, startTime, endTime)...)
-		report.Findings = append(report.Findings, analyzeProvisioningPatterns(records, warehouseMetadata, settings)...)
-	}
-
-	// Generate summary metrics
-	generateSummaryMetrics(&report, records, warehouseMetadata)
-
-	return report, nil
+		return noActivityReport(input, "warehouse", settings,
+			"No warehouse usage records found in the selected period",
+			"No warehouse activity detected in the selected time window.",
+			"Verify warehouse configurations and usage patterns. Consider reducing provisioned resources if unused.",
+			domain.SeverityMedium), nil
+	}
+
+	return runResourceAudit(ctx, input, "warehouse", func(report *domain.AuditReport) {
+		report.Summary["settings"] = settings
+		// the rules loaded the data into the input, the metadata is missing without an explorer
+		warehouseMetadata, _ := warehouseMetadataFor(ctx, input, records)
+		queryStats := warehouseQueryStatsFor(ctx, input)
+		generateSummaryMetrics(report, records, warehouseMetadata)
+		var queriesAnalyzed int64
+		for _, s := range queryStats {
+			queriesAnalyzed += s.QueryCount
+		}
+		report.Summary["query_history_available"] = queryStats != nil
+		report.Summary["queries_analyzed"] = queriesAnalyzed
+		report.Summary["warehouse_events_available"] = warehouseUptimeFor(ctx, input) != nil
+		report.Summary["budgets_available"] = warehouseBudgetControlsFor(ctx, input) != nil
+	})
 }
 
 // generateSummaryMetrics creates comprehensive summary metrics for the audit report
@@ -197,14 +301,16 @@ func generateSummaryMetrics(report *domain.AuditReport, records []domain.Resourc
 	}
 }
 
-// analyzeRuntimeDuration analyzes warehouse runtime patterns and returns audit findings
-func analyzeRuntimeDuration(records []domain.ResourceCost, settings WarehouseAuditSettings) []domain.AuditFinding {
-	runtimeStats := aggregateWarehouseRuntimeStats(records)
+// analyzeRuntimeDuration analyzes warehouse runtime patterns and returns audit findings,
+// runtime and idle time come from the warehouse uptime when available
+func analyzeRuntimeDuration(records []domain.ResourceCost, uptime map[string]domain.WarehouseUptime, settings WarehouseAuditSettings) []domain.AuditFinding {
+	runtimeStats := aggregateWarehouseRuntimeStats(records, uptime)
 	return generateRuntimeFindings(runtimeStats, settings)
 }
 
-// aggregateWarehouseRuntimeStats aggregates warehouse usage records and calculates runtime statistics
-func aggregateWarehouseRuntimeStats(records []domain.ResourceCost) map[string]*WarehouseRuntimeStats {
+// aggregateWarehouseRuntimeStats aggregates warehouse usage records and calculates runtime statistics,
+// warehouses with reconstructed uptime take their runtime and idle time from it instead of the record spans
+func aggregateWarehouseRuntimeStats(records []domain.ResourceCost, uptime map[string]domain.WarehouseUptime) map[string]*WarehouseRuntimeStats {
 	warehouseStats := make(map[string]*WarehouseRuntimeStats)
 
 	for _, record := range records {
@@ -228,6 +334,9 @@ func aggregateWarehouseRuntimeStats(records []domain.ResourceCost) map[string]*W
 		if stats.Currency == "" && len(record.Costs) > 0 {
 			stats.Currency = record.Costs[0].Currency
 		}
+		for _, cost := range record.Costs {
+			stats.TotalCost += cost.TotalAmount
+		}
 
 		// Calculate idle time based on usage patterns
 		// For warehouses, we consider periods with minimal compute usage as idle
@@ -237,7 +346,17 @@ func aggregateWarehouseRuntimeStats(records []domain.ResourceCost) map[string]*W
 	}
 
 	// Calculate idle time percentages
-	for _, stats := range warehouseStats {
+	for warehouseID, stats := range warehouseStats {
+		if u, ok := uptime[warehouseID]; ok {
+			stats.UptimeMeasured = true
+			stats.TotalRuntimeHours = u.Hours()
+			stats.ClusterHours = u.ClusterHours()
+			stats.PeakClusters = u.PeakClusters()
+			if u.ActivityMeasured {
+				stats.IdleMeasured = true
+				stats.TotalIdleHours = u.IdleHours
+			}
+		}
 		if stats.TotalRuntimeHours > 0 {
 			stats.IdleTimePercent = stats.TotalIdleHours / stats.TotalRuntimeHours
 		}
@@ -278,6 +397,11 @@ func generateRuntimeFindings(warehouseStats map[string]*WarehouseRuntimeStats, s
 	for warehouseID, stats := range warehouseStats {
 		// Check for excessive runtime
 		if stats.TotalRuntimeHours > settings.MaxRuntimeHours {
+			description := fmt.Sprintf("Warehouse ran for %.1f hours, exceeding threshold of %.1f hours.", stats.TotalRuntimeHours, settings.MaxRuntimeHours)
+			if stats.UptimeMeasured {
+				description = fmt.Sprintf("Warehouse was up for %.1f hours (%.1f cluster hours, up to %d clusters), exceeding threshold of %.1f hours.",
+					stats.TotalRuntimeHours, stats.ClusterHours, stats.PeakClusters, settings.MaxRuntimeHours)
+			}
 			findings = append(findings, domain.AuditFinding{
 				Id: fmt.Sprintf("%s_excessive_runtime", warehouseID),
 				Resource: domain.ResourceDef{
@@ -286,14 +410,23 @@ func generateRuntimeFindings(warehouseStats map[string]*WarehouseRuntimeStats, s
 					Name:     warehouseID,
 				},
 				Issue:          "excessive_runtime",
-				Description:    fmt.Sprintf("Warehouse ran for %.1f hours, exceeding threshold of %.1f hours.", stats.TotalRuntimeHours, settings.MaxRuntimeHours),
+				Description:    description,
 				Recommendation: "Consider implementing auto-stop policies, reviewing query patterns, or splitting workloads across multiple warehouses.",
 				Severity:       domain.SeverityMedium,
+				// runtime beyond the threshold is assumed to be avoidable
+				EstimatedMonthlySavings: periodSavings(stats.TotalCost*(1-settings.MaxRuntimeHours/stats.TotalRuntimeHours), stats.Currency, domain.SavingsConfidenceLow),
 			})
 		}
 
 		// Check for excessive idle time
 		if stats.TotalIdleHours > settings.MaxIdleHours {
+			description := fmt.Sprintf("Warehouse was idle for %.1f hours (%.0f%% of runtime), exceeding threshold of %.1f hours.", stats.TotalIdleHours, stats.IdleTimePercent*100, settings.MaxIdleHours)
+			confidence := domain.SavingsConfidenceLow
+			if stats.IdleMeasured {
+				description = fmt.Sprintf("Warehouse was running without executing queries for %.1f hours (%.0f%% of uptime), exceeding threshold of %.1f hours.",
+					stats.TotalIdleHours, stats.IdleTimePercent*100, settings.MaxIdleHours)
+				confidence = domain.SavingsConfidenceMedium
+			}
 			findings = append(findings, domain.AuditFinding{
 				Id: fmt.Sprintf("%s_high_idle_time", warehouseID),
 				Resource: domain.ResourceDef{
@@ -301,10 +434,11 @@ func generateRuntimeFindings(warehouseStats map[string]*WarehouseRuntimeStats, s
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "high_idle_time",
-				Description:    fmt.Sprintf("Warehouse was idle for %.1f hours (%.0f%% of runtime), exceeding threshold of %.1f hours.", stats.TotalIdleHours, stats.IdleTimePercent*100, settings.MaxIdleHours),
-				Recommendation: "Reduce auto-stop timeout, optimize query scheduling, or consider serverless warehouses for intermittent workloads.",
-				Severity:       domain.SeverityMedium,
+				Issue:                   "high_idle_time",
+				Description:             description,
+				Recommendation:          "Reduce auto-stop timeout, optimize query scheduling, or consider serverless warehouses for intermittent workloads.",
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: periodSavings(stats.TotalCost*stats.IdleTimePercent, stats.Currency, confidence),
 			})
 		}
 
@@ -317,10 +451,11 @@ func generateRuntimeFindings(warehouseStats map[string]*WarehouseRuntimeStats, s
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "idle_workload",
-				Description:    fmt.Sprintf("Warehouse idle time is %.0f%% of total runtime, exceeding threshold of %.0f%%.", stats.IdleTimePercent*100, settings.IdleTimeThreshold*100),
-				Recommendation: "Review warehouse utilization patterns, implement more aggressive auto-stop policies, or consolidate workloads.",
-				Severity:       domain.SeverityMedium,
+				Issue:                   "idle_workload",
+				Description:             fmt.Sprintf("Warehouse idle time is %.0f%% of total runtime, exceeding threshold of %.0f%%.", stats.IdleTimePercent*100, settings.IdleTimeThreshold*100),
+				Recommendation:          "Review warehouse utilization patterns, implement more aggressive auto-stop policies, or consolidate workloads.",
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: periodSavings(stats.TotalCost*stats.IdleTimePercent, stats.Currency, domain.SavingsConfidenceLow),
 			})
 		}
 	}
@@ -535,6 +670,8 @@ func generateSizeFindings(warehouseSizes map[string]*WarehouseSizeInfo, settings
 				Description:    description,
 				Recommendation: "Review if warehouse size is appropriate for workload complexity. Consider right-sizing based on query patterns and performance requirements.",
 				Severity:       domain.SeverityMedium,
+				// each size down halves the cost of the warehouse
+				EstimatedMonthlySavings: periodSavings(sizeInfo.TotalCost/2, sizeInfo.Currency, domain.SavingsConfidenceLow),
 			})
 		}
 	}
@@ -606,16 +743,21 @@ type WarehouseBestPracticesInfo struct {
 	TotalCost        float64
 	UsageHours       float64
 	Currency         string
+	Tags             map[string]string
+	BudgetsChecked   bool     // budgets and budget policies could be read
+	BudgetAlerts     []string // names of the budgets with alerts covering the warehouse
+	BudgetPolicies   []string // names of the budget policies governing the warehouse
 }
 
-// analyzeBestPracticesCompliance analyzes best practices compliance and returns audit findings
-func analyzeBestPracticesCompliance(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, settings WarehouseAuditSettings) []domain.AuditFinding {
-	bestPracticesInfo := extractBestPracticesInfo(records, warehouseMetadata)
+// analyzeBestPracticesCompliance analyzes best practices compliance and returns audit findings,
+// budget alerts and spending limits are only checked when the budgets are available
+func analyzeBestPracticesCompliance(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, budgets *domain.BudgetControls, settings WarehouseAuditSettings) []domain.AuditFinding {
+	bestPracticesInfo := extractBestPracticesInfo(records, warehouseMetadata, budgets)
 	return generateBestPracticesFindings(bestPracticesInfo)
 }
 
 // extractBestPracticesInfo evaluates warehouse configurations against best practices
-func extractBestPracticesInfo(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata) map[string]*WarehouseBestPracticesInfo {
+func extractBestPracticesInfo(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, budgets *domain.BudgetControls) map[string]*WarehouseBestPracticesInfo {
 	bestPracticesInfo := make(map[string]*WarehouseBestPracticesInfo)
 
 	// Create best practices info for warehouses with available metadata
@@ -628,6 +770,17 @@ func extractBestPracticesInfo(records []domain.ResourceCost, warehouseMetadata m
 			EnableServerless: metadata.EnableServerless,
 			WarehouseType:    metadata.Size, // Using Size as warehouse type for now
 			MissingPractices: []string{},
+			Tags:             metadata.Tags,
+		}
+
+		if budgets != nil {
+			info.BudgetsChecked = true
+			for _, budget := range budgets.AlertingBudgets(metadata.Tags) {
+				info.BudgetAlerts = append(info.BudgetAlerts, budget.Name)
+			}
+			for _, policy := range budgets.GoverningPolicies(metadata.Tags) {
+				info.BudgetPolicies = append(info.BudgetPolicies, policy.Name)
+			}
 		}
 
 		// Evaluate best practices compliance
@@ -688,17 +841,24 @@ func evaluateBestPractices(info *WarehouseBestPracticesInfo) {
 		}
 	}
 
-	// Note: Budget alerts and spending limits are not available in the current warehouse metadata
-	// These would require additional API calls to workspace settings or billing APIs
-	// For now, we'll add placeholders that can be implemented when those APIs are available
-
-	// Placeholder for budget alerts (would require additional API integration)
-	totalPractices++
-	info.MissingPractices = append(info.MissingPractices, "budget_alerts_unknown")
+	// Budget checks are left out of the score when the account budgets could not be read
+	if info.BudgetsChecked {
+		// Check for a budget alerting on the warehouse spending
+		totalPractices++
+		if len(info.BudgetAlerts) > 0 {
+			compliantPractices++
+		} else {
+			info.MissingPractices = append(info.MissingPractices, "budget_alert_missing")
+		}
 
-	// Placeholder for spending limits (would require additional API integration)
-	totalPractices++
-	info.MissingPractices = append(info.MissingPractices, "spending_limits_unknown")
+		// Check for a budget policy the warehouse spending is attributed to
+		totalPractices++
+		if len(info.BudgetPolicies) > 0 {
+			compliantPractices++
+		} else {
+			info.MissingPractices = append(info.MissingPractices, "spending_limit_missing")
+		}
+	}
 
 	// Calculate compliance score
 	if totalPractices > 0 {
@@ -724,10 +884,11 @@ func generateBestPracticesFindings(bestPracticesInfo map[string]*WarehouseBestPr
 						Service:  "warehouse",
 						Name:     warehouseID,
 					},
-					Issue:          "auto_stop_disabled",
-					Description:    "Warehouse does not have auto-stop configured, which may lead to unnecessary costs from idle resources.",
-					Recommendation: "Enable auto-stop with an appropriate timeout (recommended: 10-30 minutes for interactive workloads, 60-120 minutes for batch workloads).",
-					Severity:       domain.SeverityHigh,
+					Issue:                   "auto_stop_disabled",
+					Description:             "Warehouse does not have auto-stop configured, which may lead to unnecessary costs from idle resources.",
+					Recommendation:          "Enable auto-stop with an appropriate timeout (recommended: 10-30 minutes for interactive workloads, 60-120 minutes for batch workloads).",
+					Severity:                domain.SeverityHigh,
+					EstimatedMonthlySavings: periodSavings(info.TotalCost*assumedIdleShare, info.Currency, domain.SavingsConfidenceLow),
 				}
 
 			case "auto_stop_timeout_too_high":
@@ -742,6 +903,8 @@ func generateBestPracticesFindings(bestPracticesInfo map[string]*WarehouseBestPr
 					Description:    fmt.Sprintf("Warehouse auto-stop timeout is set to %d minutes, which may be too high for cost optimization.", info.AutoStopMins),
 					Recommendation: "Consider reducing auto-stop timeout to 10-30 minutes for interactive workloads or 60-120 minutes for batch workloads.",
 					Severity:       domain.SeverityMedium,
+					// only the idle tail beyond the recommended timeout is saved
+					EstimatedMonthlySavings: periodSavings(info.TotalCost*assumedIdleShare*(1-30/float64(info.AutoStopMins)), info.Currency, domain.SavingsConfidenceLow),
 				}
 
 			case "serverless_not_enabled":
@@ -756,34 +919,42 @@ func generateBestPracticesFindings(bestPracticesInfo map[string]*WarehouseBestPr
 					Description:    "Warehouse is not configured for serverless compute, which could provide better cost efficiency for variable workloads.",
 					Recommendation: "Consider enabling serverless compute for better cost optimization, especially for intermittent or unpredictable workloads.",
 					Severity:       domain.SeverityLow,
+					// serverless is billed at a higher rate, savings depend on the idle time it removes
+					EstimatedMonthlySavings: periodSavings(0, info.Currency, domain.SavingsConfidenceLow),
 				}
 
-			case "budget_alerts_unknown":
+			case "budget_alert_missing":
+				description := "No budget with an alert covers this warehouse, cost overruns go unnoticed until the bill arrives."
+				if len(info.Tags) == 0 {
+					description += " The warehouse has no custom tags, so only workspace or account wide budgets can cover it."
+				}
 				finding = domain.AuditFinding{
-					Id: fmt.Sprintf("%s_budget_alerts_unknown", warehouseID),
+					Id: fmt.Sprintf("%s_budget_alert_missing", warehouseID),
 					Resource: domain.ResourceDef{
 						Platform: "Databricks",
 						Service:  "warehouse",
 						Name:     warehouseID,
 					},
-					Issue:          "budget_alerts_unknown",
-					Description:    "Unable to verify if budget alerts are configured for this warehouse.",
-					Recommendation: "Ensure budget alerts are configured to monitor warehouse spending and prevent cost overruns.",
-					Severity:       domain.SeverityLow,
+					Issue:                   "budget_alert_missing",
+					Description:             description,
+					Recommendation:          "Create a budget scoped to this workspace or to the warehouse custom tags, with an alert notifying the warehouse owners.",
+					Severity:                domain.SeverityLow,
+					EstimatedMonthlySavings: noSavings(info.Currency),
 				}
 
-			case "spending_limits_unknown":
+			case "spending_limit_missing":
 				finding = domain.AuditFinding{
-					Id: fmt.Sprintf("%s_spending_limits_unknown", warehouseID),
+					Id: fmt.Sprintf("%s_spending_limit_missing", warehouseID),
 					Resource: domain.ResourceDef{
 						Platform: "Databricks",
 						Service:  "warehouse",
 						Name:     warehouseID,
 					},
-					Issue:          "spending_limits_unknown",
-					Description:    "Unable to verify if spending limits are configured for this warehouse.",
-					Recommendation: "Consider setting spending limits to prevent unexpected cost increases and maintain budget control.",
-					Severity:       domain.SeverityLow,
+					Issue:                   "spending_limit_missing",
+					Description:             "Warehouse spending is not attributed to any budget policy bound to this workspace.",
+					Recommendation:          "Tag the warehouse with the custom tags of a budget policy so its spending is tracked and limited together with the team owning it.",
+					Severity:                domain.SeverityLow,
+					EstimatedMonthlySavings: noSavings(info.Currency),
 				}
 			}
 
@@ -801,10 +972,11 @@ func generateBestPracticesFindings(bestPracticesInfo map[string]*WarehouseBestPr
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "low_compliance_score",
-				Description:    fmt.Sprintf("Warehouse has a low best practices compliance score of %.0f%%. Missing practices: %v", info.ComplianceScore*100, info.MissingPractices),
-				Recommendation: "Review and implement missing best practices to improve cost efficiency and governance.",
-				Severity:       domain.SeverityMedium,
+				Issue:                   "low_compliance_score",
+				Description:             fmt.Sprintf("Warehouse has a low best practices compliance score of %.0f%%. Missing practices: %v", info.ComplianceScore*100, info.MissingPractices),
+				Recommendation:          "Review and implement missing best practices to improve cost efficiency and governance.",
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: noSavings(info.Currency),
 			})
 		}
 	}
@@ -818,6 +990,7 @@ func updateBestPracticesSummary(report *domain.AuditReport, bestPracticesInfo ma
 	totalComplianceScore := 0.0
 	warehousesWithAutoStop := 0
 	warehousesWithServerless := 0
+	warehousesWithBudgetAlerts := 0
 
 	for _, info := range bestPracticesInfo {
 		// Count warehouses with best practice issues
@@ -834,11 +1007,15 @@ func updateBestPracticesSummary(report *domain.AuditReport, bestPracticesInfo ma
 		if info.EnableServerless {
 			warehousesWithServerless++
 		}
+		if len(info.BudgetAlerts) > 0 {
+			warehousesWithBudgetAlerts++
+		}
 	}
 
 	report.Summary["warehouses_with_best_practice_issues"] = warehousesWithBestPracticeIssues
 	report.Summary["warehouses_with_auto_stop"] = warehousesWithAutoStop
 	report.Summary["warehouses_with_serverless"] = warehousesWithServerless
+	report.Summary["warehouses_with_budget_alerts"] = warehousesWithBudgetAlerts
 
 	// Calculate average compliance score
 	if len(bestPracticesInfo) > 0 {
@@ -865,14 +1042,15 @@ type WarehouseStaleResourceInfo struct {
 	Currency          string
 }
 
-// analyzeStaleResources analyzes stale resources and returns audit findings
-func analyzeStaleResources(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, settings WarehouseAuditSettings, startTime, endTime time.Time) []domain.AuditFinding {
-	staleResourcesInfo := extractStaleResourcesInfo(records, warehouseMetadata, settings, startTime, endTime)
+// analyzeStaleResources analyzes stale resources and returns audit findings,
+// query counts come from the query history when available
+func analyzeStaleResources(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, queryStats map[string]domain.WarehouseQueryStats, settings WarehouseAuditSettings, startTime, endTime time.Time) []domain.AuditFinding {
+	staleResourcesInfo := extractStaleResourcesInfo(records, warehouseMetadata, queryStats, settings, startTime, endTime)
 	return generateStaleResourcesFindings(staleResourcesInfo, settings)
 }
 
 // extractStaleResourcesInfo identifies warehouses with no recent activity or that are orphaned
-func extractStaleResourcesInfo(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, settings WarehouseAuditSettings, startTime, endTime time.Time) map[string]*WarehouseStaleResourceInfo {
+func extractStaleResourcesInfo(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, queryStats map[string]domain.WarehouseQueryStats, settings WarehouseAuditSettings, startTime, endTime time.Time) map[string]*WarehouseStaleResourceInfo {
 	staleResourcesInfo := make(map[string]*WarehouseStaleResourceInfo)
 
 	// Get unique warehouse IDs from usage records
@@ -906,7 +1084,9 @@ func extractStaleResourcesInfo(records []domain.ResourceCost, warehouseMetadata
 		warehouseID := record.Resource.Name
 		if info, exists := staleResourcesInfo[warehouseID]; exists {
 			info.HasActivity = true
-			info.QueryCount++ // Each record represents some query activity
+			if queryStats == nil {
+				info.QueryCount++ // Without query history each record is taken as some query activity
+			}
 
 			// Track the most recent activity time
 			if info.LastActivityTime == nil || record.EndTime.After(*info.LastActivityTime) {
@@ -929,7 +1109,11 @@ func extractStaleResourcesInfo(records []domain.ResourceCost, warehouseMetadata
 
 	// Calculate stale resource metrics
 	now := time.Now()
-	for _, info := range staleResourcesInfo {
+	for warehouseID, info := range staleResourcesInfo {
+		if queryStats != nil {
+			info.QueryCount = int(queryStats[warehouseID].QueryCount)
+		}
+
 		// Calculate days since last activity
 		if info.LastActivityTime != nil {
 			info.DaysSinceActivity = int(now.Sub(*info.LastActivityTime).Hours() / 24)
@@ -973,10 +1157,11 @@ func generateStaleResourcesFindings(staleResourcesInfo map[string]*WarehouseStal
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "stale_warehouse",
-				Description:    description,
-				Recommendation: "Review if this warehouse is still needed. Consider deleting unused warehouses to reduce management overhead and potential costs.",
-				Severity:       domain.SeverityMedium,
+				Issue:                   "stale_warehouse",
+				Description:             description,
+				Recommendation:          "Review if this warehouse is still needed. Consider deleting unused warehouses to reduce management overhead and potential costs.",
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: periodSavings(info.TotalCost, info.Currency, domain.SavingsConfidenceHigh),
 			})
 		}
 
@@ -994,10 +1179,11 @@ func generateStaleResourcesFindings(staleResourcesInfo map[string]*WarehouseStal
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "orphaned_warehouse",
-				Description:    description,
-				Recommendation: "Investigate if this warehouse was created for a specific purpose that hasn't been implemented yet, or if it can be safely deleted.",
-				Severity:       domain.SeverityHigh,
+				Issue:                   "orphaned_warehouse",
+				Description:             description,
+				Recommendation:          "Investigate if this warehouse was created for a specific purpose that hasn't been implemented yet, or if it can be safely deleted.",
+				Severity:                domain.SeverityHigh,
+				EstimatedMonthlySavings: noSavings(info.Currency),
 			})
 		}
 
@@ -1015,10 +1201,11 @@ func generateStaleResourcesFindings(staleResourcesInfo map[string]*WarehouseStal
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "zero_query_activity",
-				Description:    description,
-				Recommendation: "Review warehouse configuration and usage patterns. Warehouses running without executing queries may indicate misconfiguration or inefficient usage.",
-				Severity:       domain.SeverityMedium,
+				Issue:                   "zero_query_activity",
+				Description:             description,
+				Recommendation:          "Review warehouse configuration and usage patterns. Warehouses running without executing queries may indicate misconfiguration or inefficient usage.",
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: periodSavings(info.TotalCost, info.Currency, domain.SavingsConfidenceHigh),
 			})
 		}
 
@@ -1033,10 +1220,11 @@ func generateStaleResourcesFindings(staleResourcesInfo map[string]*WarehouseStal
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "never_started",
-				Description:    description,
-				Recommendation: "Consider deleting this warehouse if it's not needed, or investigate why it was created but never used.",
-				Severity:       domain.SeverityHigh,
+				Issue:                   "never_started",
+				Description:             description,
+				Recommendation:          "Consider deleting this warehouse if it's not needed, or investigate why it was created but never used.",
+				Severity:                domain.SeverityHigh,
+				EstimatedMonthlySavings: noSavings(info.Currency),
 			})
 		}
 	}
@@ -1062,16 +1250,18 @@ type WarehouseProvisioningInfo struct {
 	IsUnderProvisioned     bool
 	RecommendedSize        string
 	PotentialSavings       float64
+	QueryStats             *domain.WarehouseQueryStats // measured workload, nil when estimated from usage records
 }
 
-// analyzeProvisioningPatterns analyzes provisioning patterns and returns audit findings
-func analyzeProvisioningPatterns(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, settings WarehouseAuditSettings) []domain.AuditFinding {
-	provisioningInfo := extractProvisioningInfo(records, warehouseMetadata, settings)
+// analyzeProvisioningPatterns analyzes provisioning patterns and returns audit findings,
+// the workload is measured from the query history when available and estimated from usage records otherwise
+func analyzeProvisioningPatterns(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, queryStats map[string]domain.WarehouseQueryStats, settings WarehouseAuditSettings) []domain.AuditFinding {
+	provisioningInfo := extractProvisioningInfo(records, warehouseMetadata, queryStats, settings)
 	return generateProvisioningFindings(provisioningInfo, settings)
 }
 
 // extractProvisioningInfo analyzes correlation between query complexity and warehouse size
-func extractProvisioningInfo(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, settings WarehouseAuditSettings) map[string]*WarehouseProvisioningInfo {
+func extractProvisioningInfo(records []domain.ResourceCost, warehouseMetadata map[string]domain.WarehouseMetadata, queryStats map[string]domain.WarehouseQueryStats, settings WarehouseAuditSettings) map[string]*WarehouseProvisioningInfo {
 	provisioningInfo := make(map[string]*WarehouseProvisioningInfo)
 
 	// Create provisioning info for warehouses with available metadata
@@ -1083,6 +1273,10 @@ func extractProvisioningInfo(records []domain.ResourceCost, warehouseMetadata ma
 			MaxClusters: metadata.MaxNumClusters,
 			NodeCount:   calculateNodeCount(metadata.Size, metadata.MaxNumClusters),
 		}
+		if queryStats != nil {
+			stats := queryStats[warehouseID]
+			info.QueryStats = &stats
+		}
 
 		provisioningInfo[warehouseID] = info
 	}
@@ -1091,7 +1285,9 @@ func extractProvisioningInfo(records []domain.ResourceCost, warehouseMetadata ma
 	for _, record := range records {
 		warehouseID := record.Resource.Name
 		if info, exists := provisioningInfo[warehouseID]; exists {
-			info.QueryCount++
+			if info.QueryStats == nil {
+				info.QueryCount++
+			}
 
 			// Accumulate cost and usage data
 			for _, cost := range record.Costs {
@@ -1105,14 +1301,27 @@ func extractProvisioningInfo(records []domain.ResourceCost, warehouseMetadata ma
 			usageHours := record.EndTime.Sub(record.StartTime).Hours()
 			info.UsageHours += usageHours
 
-			// Estimate query complexity based on cost patterns and duration
-			queryComplexity := estimateQueryComplexity(record, usageHours)
-			info.QueryComplexityScore += queryComplexity
+			if info.QueryStats == nil {
+				// Estimate query complexity based on cost patterns and duration
+				info.QueryComplexityScore += estimateQueryComplexity(record, usageHours)
+			}
 		}
 	}
 
 	// Calculate provisioning metrics for each warehouse
 	for _, info := range provisioningInfo {
+		if info.QueryStats != nil {
+			info.QueryCount = int(info.QueryStats.QueryCount)
+			if info.QueryCount > 0 {
+				info.QueryComplexityScore = measureQueryComplexity(*info.QueryStats)
+				info.AvgResourceUtilization = measureResourceUtilization(info)
+				info.ProvisioningScore = calculateProvisioningScore(info)
+				analyzeMeasuredProvisioning(info, settings)
+				info.PotentialSavings = estimatePotentialSavings(info)
+			}
+			continue
+		}
+
 		if info.QueryCount > 0 {
 			// Average query complexity
 			info.QueryComplexityScore = info.QueryComplexityScore / float64(info.QueryCount)
@@ -1163,6 +1372,58 @@ func estimateQueryComplexity(record domain.ResourceCost, usageHours float64) flo
 	return complexityScore
 }
 
+// measureQueryComplexity scores the measured workload from 0 to 100, a query taking 10 minutes
+// or scanning 10 GiB at the 95th percentile scores 100
+func measureQueryComplexity(stats domain.WarehouseQueryStats) float64 {
+	durationScore := stats.P95DurationSeconds / 6
+	bytesScore := stats.ReadBytesPerQuery() / (1 << 30) * 10
+	return min(max(durationScore, bytesScore), 100)
+}
+
+// measureResourceUtilization returns the share of the billed warehouse hours spent executing queries
+func measureResourceUtilization(info *WarehouseProvisioningInfo) float64 {
+	if info.UsageHours == 0 {
+		return 0
+	}
+	// concurrent queries can add up to more execution hours than the warehouse ran
+	return min(info.QueryStats.ExecutionHours/info.UsageHours, 1.0)
+}
+
+// analyzeMeasuredProvisioning flags a warehouse from its query history, queries waiting for capacity
+// or spilling to disk mean it is too small, a mostly idle warehouse running light queries is too large
+func analyzeMeasuredProvisioning(info *WarehouseProvisioningInfo, settings WarehouseAuditSettings) {
+	if info.QueryCount < settings.MinQueryCountThreshold {
+		info.RecommendedSize = info.Size
+		return
+	}
+
+	stats := info.QueryStats
+	if stats.AvgQueuedSeconds > settings.MaxAvgQueuedSeconds || stats.SpillRatio() > settings.MaxSpillRatio {
+		info.IsUnderProvisioned = true
+		info.RecommendedSize = recommendWarehouseSize(info)
+		return
+	}
+
+	if info.AvgResourceUtilization < 0.3 {
+		info.IsOverProvisioned = true
+		info.RecommendedSize = recommendWarehouseSize(info)
+		if getWarehouseSizeScore(info.RecommendedSize) >= getWarehouseSizeScore(info.Size) {
+			// the queries need the current size even though the warehouse is mostly idle
+			info.IsOverProvisioned = false
+			info.RecommendedSize = info.Size
+		}
+		return
+	}
+
+	info.RecommendedSize = info.Size
+}
+
+// workloadDescription describes the measured workload of a warehouse
+func workloadDescription(stats *domain.WarehouseQueryStats) string {
+	return fmt.Sprintf("p95 query duration %.1fs, average queueing %.1fs, %.1f MiB scanned per query, %.0f%% of scanned bytes spilled",
+		stats.P95DurationSeconds, stats.AvgQueuedSeconds, stats.ReadBytesPerQuery()/(1<<20), stats.SpillRatio()*100)
+}
+
 // calculateResourceUtilization calculates average resource utilization based on cost efficiency
 func calculateResourceUtilization(info *WarehouseProvisioningInfo) float64 {
 	if info.UsageHours == 0 || info.TotalCost == 0 {
@@ -1369,6 +1630,9 @@ func generateProvisioningFindings(provisioningInfo map[string]*WarehouseProvisio
 			if info.QueryCount > 0 {
 				description += fmt.Sprintf(" (%d queries analyzed)", info.QueryCount)
 			}
+			if info.QueryStats != nil {
+				description += "; " + workloadDescription(info.QueryStats)
+			}
 
 			recommendation := fmt.Sprintf("Consider downsizing to %s to optimize costs", info.RecommendedSize)
 			if info.PotentialSavings > 0 {
@@ -1382,10 +1646,11 @@ func generateProvisioningFindings(provisioningInfo map[string]*WarehouseProvisio
 					Service:  "warehouse",
 					Name:     warehouseID,
 				},
-				Issue:          "over_provisioned",
-				Description:    description,
-				Recommendation: recommendation,
-				Severity:       domain.SeverityMedium,
+				Issue:                   "over_provisioned",
+				Description:             description,
+				Recommendation:          recommendation,
+				Severity:                domain.SeverityMedium,
+				EstimatedMonthlySavings: periodSavings(info.PotentialSavings, info.Currency, domain.SavingsConfidenceMedium),
 			})
 		}
 
@@ -1397,6 +1662,9 @@ func generateProvisioningFindings(provisioningInfo map[string]*WarehouseProvisio
 			if info.QueryCount > 0 {
 				description += fmt.Sprintf(" (%d queries analyzed)", info.QueryCount)
 			}
+			if info.QueryStats != nil {
+				description += "; " + workloadDescription(info.QueryStats)
+			}
 
 			recommendation := fmt.Sprintf("Consider upgrading to %s to improve performance for complex workloads", info.RecommendedSize)
 
@@ -1411,17 +1679,22 @@ func generateProvisioningFindings(provisioningInfo map[string]*WarehouseProvisio
 				Description:    description,
 				Recommendation: recommendation,
 				Severity:       domain.SeverityMedium,
+				// upsizing increases the cost per hour
+				EstimatedMonthlySavings: noSavings(info.Currency),
 			})
 		}
 
 		// Generate finding for warehouses running simple queries on large clusters
-		if info.QueryComplexityScore < 20 && getWarehouseSizeScore(info.Size) >= 16 { // Large or bigger
+		if info.QueryComplexityScore < 20 && !info.IsUnderProvisioned && getWarehouseSizeScore(info.Size) >= 16 { // Large or bigger
 			description := fmt.Sprintf("Large warehouse (%s) running simple queries (complexity score: %.1f)",
 				info.Size, info.QueryComplexityScore)
 
 			if info.AvgResourceUtilization < 0.5 {
 				description += fmt.Sprintf(" with low utilization (%.0f%%)", info.AvgResourceUtilization*100)
 			}
+			if info.QueryStats != nil {
+				description += "; " + workloadDescription(info.QueryStats)
+			}
 
 			findings = append(findings, domain.AuditFinding{
 				Id: fmt.Sprintf("%s_simple_queries_large_cluster", warehouseID),
@@ -1434,6 +1707,8 @@ func generateProvisioningFindings(provisioningInfo map[string]*WarehouseProvisio
 				Description:    description,
 				Recommendation: "Consider using a smaller warehouse for simple queries, or consolidate complex workloads to justify the large cluster size.",
 				Severity:       domain.SeverityMedium,
+				// savings of moving the queries to a Medium warehouse
+				EstimatedMonthlySavings: periodSavings(info.TotalCost*(1-getWarehouseSizeScore("Medium")/getWarehouseSizeScore(info.Size)), info.Currency, domain.SavingsConfidenceLow),
 			})
 		}
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/de-tools/data-atlas/pkg/models/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockCostManager for testing
@@ -50,6 +51,93 @@ func (m *MockExplorer) ListWarehouses(ctx context.Context) ([]domain.WarehouseMe
 	return args.Get(0).([]domain.WarehouseMetadata), args.Error(1)
 }
 
+func (m *MockExplorer) GetWarehouseQueryStats(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseQueryStats, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WarehouseQueryStats), args.Error(1)
+}
+
+func (m *MockExplorer) GetWarehouseEvents(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseEvent, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WarehouseEvent), args.Error(1)
+}
+
+func (m *MockExplorer) GetWarehouseQueryActivity(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.WarehouseQueryActivity, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WarehouseQueryActivity), args.Error(1)
+}
+
+func (m *MockExplorer) GetBudgetControls(ctx context.Context) (*domain.BudgetControls, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetControls), args.Error(1)
+}
+
+func (m *MockExplorer) ListClusters(ctx context.Context) ([]domain.ClusterMetadata, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ClusterMetadata), args.Error(1)
+}
+
+func (m *MockExplorer) GetClusterUtilization(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.ClusterUtilization, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ClusterUtilization), args.Error(1)
+}
+
+func (m *MockExplorer) ListServingEndpoints(ctx context.Context) ([]domain.ServingEndpointMetadata, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServingEndpointMetadata), args.Error(1)
+}
+
+func (m *MockExplorer) GetServingEndpointUsage(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]domain.ServingEndpointUsage, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServingEndpointUsage), args.Error(1)
+}
+
+func (m *MockExplorer) GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]domain.JobRun, error) {
+	args := m.Called(ctx, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.JobRun), args.Error(1)
+}
+
 // Test the main GetWarehouseAudit function
 func TestGetWarehouseAudit(t *testing.T) {
 	ctx := context.Background()
@@ -106,6 +194,9 @@ func TestGetWarehouseAudit(t *testing.T) {
 		mockCostManager.On("GetResourcesCost", ctx, mock.AnythingOfType("domain.WorkspaceResources"), startTime, endTime).Return(records, nil)
 		mockExplorer.On("ListWarehouses", ctx).Return([]domain.WarehouseMetadata{*warehouseMetadata}, nil)
 		mockExplorer.On("GetWarehouseMetadata", ctx, "warehouse-1").Return(warehouseMetadata, nil)
+		mockExplorer.On("GetWarehouseQueryStats", ctx, startTime, endTime).Return(nil, assert.AnError)
+		mockExplorer.On("GetWarehouseEvents", ctx, startTime, endTime).Return(nil, assert.AnError)
+		mockExplorer.On("GetBudgetControls", ctx).Return(&domain.BudgetControls{WorkspaceID: 42}, nil)
 
 		report, err := GetWarehouseAudit(ctx, ws, startTime, endTime, mockCostManager, mockExplorer, settings)
 
@@ -119,6 +210,7 @@ func TestGetWarehouseAudit(t *testing.T) {
 		assert.Contains(t, report.Summary, "total_cost_analyzed")
 		assert.Contains(t, report.Summary, "total_findings")
 		assert.Contains(t, report.Summary, "audit_status")
+		assert.Equal(t, false, report.Summary["query_history_available"])
 
 		// Should have findings for excessive runtime and missing auto-stop
 		hasRuntimeFinding := false
@@ -133,6 +225,18 @@ func TestGetWarehouseAudit(t *testing.T) {
 		}
 		assert.True(t, hasRuntimeFinding, "Should have excessive runtime finding")
 		assert.True(t, hasAutoStopFinding, "Should have auto-stop finding")
+		assert.Equal(t, true, report.Summary["budgets_available"])
+
+		// the registered rules are evaluated and share the data they load
+		var rules []string
+		for _, rule := range report.Rules {
+			rules = append(rules, rule.Rule)
+		}
+		assert.Equal(t, []string{"warehouse_runtime", "warehouse_sizing", "warehouse_best_practices",
+			"warehouse_stale_resources", "warehouse_provisioning"}, rules)
+		mockCostManager.AssertNumberOfCalls(t, "GetResourcesCost", 1)
+		mockExplorer.AssertNumberOfCalls(t, "GetBudgetControls", 1)
+		mockExplorer.AssertNumberOfCalls(t, "GetWarehouseQueryStats", 1)
 
 		mockCostManager.AssertExpectations(t)
 		mockExplorer.AssertExpectations(t)
@@ -167,7 +271,7 @@ func TestAnalyzeRuntimeDuration(t *testing.T) {
 			},
 		}
 
-		findings := analyzeRuntimeDuration(records, settings)
+		findings := analyzeRuntimeDuration(records, nil, settings)
 
 		assert.Len(t, findings, 1)
 		assert.Equal(t, "excessive_runtime", findings[0].Issue)
@@ -187,7 +291,7 @@ func TestAnalyzeRuntimeDuration(t *testing.T) {
 			},
 		}
 
-		findings := analyzeRuntimeDuration(records, settings)
+		findings := analyzeRuntimeDuration(records, nil, settings)
 
 		assert.Len(t, findings, 0)
 	})
@@ -268,7 +372,7 @@ func TestAnalyzeBestPracticesCompliance(t *testing.T) {
 			},
 		}
 
-		findings := analyzeBestPracticesCompliance(records, warehouseMetadata, settings)
+		findings := analyzeBestPracticesCompliance(records, warehouseMetadata, nil, settings)
 
 		assert.Greater(t, len(findings), 0)
 
@@ -280,6 +384,55 @@ func TestAnalyzeBestPracticesCompliance(t *testing.T) {
 			}
 		}
 		assert.True(t, hasAutoStopFinding, "Should identify missing auto-stop")
+		for _, finding := range findings {
+			assert.NotContains(t, finding.Issue, "budget", "budget checks need the account budgets")
+		}
+	})
+
+	t.Run("checks budget alerts and budget policies", func(t *testing.T) {
+		records := []domain.ResourceCost{
+			{
+				Resource: domain.ResourceDef{Name: "covered"},
+				Costs:    []domain.CostComponent{{TotalAmount: 50.0, Currency: "USD"}},
+			},
+		}
+		warehouseMetadata := map[string]domain.WarehouseMetadata{
+			"covered": {
+				ID: "covered", Name: "Covered", Size: "X-Small", AutoStopMins: 10,
+				Tags: map[string]string{"team": "bi", "cost_center": "42"},
+			},
+			"uncovered": {
+				ID: "uncovered", Name: "Uncovered", Size: "X-Small", AutoStopMins: 10,
+				Tags: map[string]string{"team": "ml"},
+			},
+		}
+		budgets := &domain.BudgetControls{
+			WorkspaceID: 7,
+			Budgets: []domain.Budget{
+				{Name: "bi", WorkspaceIDs: []int64{7}, Tags: map[string][]string{"team": {"bi", "analytics"}}, AlertRecipients: 1},
+				{Name: "ml without alerts", Tags: map[string][]string{"team": {"ml"}}},
+				{Name: "other workspace", WorkspaceIDs: []int64{8}, AlertRecipients: 2},
+			},
+			Policies: []domain.BudgetPolicy{
+				{Name: "cost center 42", Tags: map[string]string{"cost_center": "42"}},
+				{Name: "open without tags"},
+			},
+		}
+
+		findings := analyzeBestPracticesCompliance(records, warehouseMetadata, budgets, settings)
+
+		issues := map[string][]string{}
+		for _, finding := range findings {
+			issues[finding.Resource.Name] = append(issues[finding.Resource.Name], finding.Issue)
+		}
+		assert.Empty(t, issues["covered"])
+		assert.ElementsMatch(t, []string{"budget_alert_missing", "spending_limit_missing"}, issues["uncovered"])
+
+		info := extractBestPracticesInfo(records, warehouseMetadata, budgets)
+		assert.Equal(t, 1.0, info["covered"].ComplianceScore)
+		assert.Equal(t, []string{"bi"}, info["covered"].BudgetAlerts)
+		assert.Equal(t, []string{"cost center 42"}, info["covered"].BudgetPolicies)
+		assert.Equal(t, 0.5, info["uncovered"].ComplianceScore)
 	})
 }
 
@@ -311,7 +464,7 @@ func TestAnalyzeStaleResources(t *testing.T) {
 			},
 		}
 
-		findings := analyzeStaleResources(records, warehouseMetadata, settings, startTime, endTime)
+		findings := analyzeStaleResources(records, warehouseMetadata, nil, settings, startTime, endTime)
 
 		assert.Greater(t, len(findings), 0)
 
@@ -326,6 +479,133 @@ func TestAnalyzeStaleResources(t *testing.T) {
 	})
 }
 
+// Test stale resources measured from the query history
+func TestAnalyzeStaleResourcesWithQueryHistory(t *testing.T) {
+	settings := DefaultWarehouseAuditSettings()
+	startTime := time.Now().Add(-7 * 24 * time.Hour)
+	endTime := time.Now()
+
+	records := []domain.ResourceCost{
+		{
+			Resource:  domain.ResourceDef{Name: "idle-warehouse"},
+			StartTime: startTime,
+			EndTime:   startTime.Add(5 * time.Hour),
+			Costs:     []domain.CostComponent{{TotalAmount: 40.0, Currency: "USD"}},
+		},
+		{
+			Resource:  domain.ResourceDef{Name: "busy-warehouse"},
+			StartTime: startTime,
+			EndTime:   startTime.Add(5 * time.Hour),
+			Costs:     []domain.CostComponent{{TotalAmount: 40.0, Currency: "USD"}},
+		},
+	}
+	warehouseMetadata := map[string]domain.WarehouseMetadata{
+		"idle-warehouse": {ID: "idle-warehouse", Name: "Idle", Size: "Small"},
+		"busy-warehouse": {ID: "busy-warehouse", Name: "Busy", Size: "Small"},
+	}
+	queryStats := map[string]domain.WarehouseQueryStats{
+		"busy-warehouse": {WarehouseID: "busy-warehouse", QueryCount: 120},
+	}
+
+	findings := analyzeStaleResources(records, warehouseMetadata, queryStats, settings, startTime, endTime)
+
+	require.Len(t, findings, 1)
+	assert.Equal(t, "zero_query_activity", findings[0].Issue)
+	assert.Equal(t, "idle-warehouse", findings[0].Resource.Name)
+	assert.Equal(t, 40.0, findings[0].EstimatedMonthlySavings.Amount)
+}
+
+// Test provisioning analysis measured from the query history
+func TestAnalyzeProvisioningPatternsWithQueryHistory(t *testing.T) {
+	settings := DefaultWarehouseAuditSettings()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []domain.ResourceCost{
+		{
+			Resource:  domain.ResourceDef{Name: "warehouse-1"},
+			StartTime: start,
+			EndTime:   start.Add(10 * time.Hour),
+			Costs:     []domain.CostComponent{{TotalAmount: 100.0, Currency: "USD"}},
+		},
+	}
+	metadata := map[string]domain.WarehouseMetadata{
+		"warehouse-1": {ID: "warehouse-1", Name: "BI", Size: "Large", MaxNumClusters: 1},
+	}
+	issues := func(findings []domain.AuditFinding) []string {
+		var result []string
+		for _, f := range findings {
+			result = append(result, f.Issue)
+		}
+		return result
+	}
+
+	t.Run("light and mostly idle workload is over-provisioned", func(t *testing.T) {
+		queryStats := map[string]domain.WarehouseQueryStats{
+			"warehouse-1": {
+				WarehouseID:        "warehouse-1",
+				QueryCount:         500,
+				P95DurationSeconds: 12,
+				ExecutionHours:     1,
+				ReadBytes:          500 * (100 << 20),
+			},
+		}
+
+		findings := analyzeProvisioningPatterns(records, metadata, queryStats, settings)
+
+		assert.ElementsMatch(t, []string{"over_provisioned", "simple_queries_large_cluster"}, issues(findings))
+		for _, f := range findings {
+			assert.Contains(t, f.Description, "p95 query duration 12.0s")
+			if f.Issue == "over_provisioned" {
+				assert.Contains(t, f.Description, "500 queries analyzed")
+				assert.Contains(t, f.Recommendation, "2X-Small")
+				assert.InDelta(t, 93.75, f.EstimatedMonthlySavings.Amount, 0.001)
+			}
+		}
+	})
+
+	t.Run("queueing workload is under-provisioned", func(t *testing.T) {
+		queryStats := map[string]domain.WarehouseQueryStats{
+			"warehouse-1": {
+				WarehouseID:        "warehouse-1",
+				QueryCount:         500,
+				P95DurationSeconds: 12,
+				AvgQueuedSeconds:   45,
+				ExecutionHours:     9,
+				ReadBytes:          500 * (100 << 20),
+			},
+		}
+
+		findings := analyzeProvisioningPatterns(records, metadata, queryStats, settings)
+
+		require.Len(t, findings, 1)
+		assert.Equal(t, "under_provisioned", findings[0].Issue)
+		assert.Contains(t, findings[0].Recommendation, "X-Large")
+		assert.Contains(t, findings[0].Description, "average queueing 45.0s")
+	})
+
+	t.Run("spilling workload is under-provisioned", func(t *testing.T) {
+		queryStats := map[string]domain.WarehouseQueryStats{
+			"warehouse-1": {
+				WarehouseID:        "warehouse-1",
+				QueryCount:         50,
+				P95DurationSeconds: 300,
+				ExecutionHours:     8,
+				ReadBytes:          100 << 30,
+				SpilledBytes:       20 << 30,
+			},
+		}
+
+		findings := analyzeProvisioningPatterns(records, metadata, queryStats, settings)
+
+		assert.Equal(t, []string{"under_provisioned"}, issues(findings))
+	})
+
+	t.Run("warehouses without queries in the history are skipped", func(t *testing.T) {
+		findings := analyzeProvisioningPatterns(records, metadata, map[string]domain.WarehouseQueryStats{}, settings)
+
+		assert.Empty(t, findings)
+	})
+}
+
 // Test default settings
 func TestDefaultWarehouseAuditSettings(t *testing.T) {
 	settings := DefaultWarehouseAuditSettings()
@@ -336,4 +616,6 @@ func TestDefaultWarehouseAuditSettings(t *testing.T) {
 	assert.Equal(t, 30, settings.StaleResourceDays)
 	assert.Equal(t, 5, settings.TopLargestCount)
 	assert.Equal(t, 10, settings.MinQueryCountThreshold)
+	assert.Equal(t, 10.0, settings.MaxAvgQueuedSeconds)
+	assert.Equal(t, 0.05, settings.MaxSpillRatio)
 }
@@ -0,0 +1,139 @@
+package workspace
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/rs/zerolog"
+)
+
+// warehouseUptimeFor returns the cached warehouse uptime of the audit run keyed by warehouse ID,
+// nil when the warehouse events cannot be read and runtime is estimated from usage records
+func warehouseUptimeFor(ctx context.Context, in *AuditInput) map[string]domain.WarehouseUptime {
+	uptime, _ := loadAuditData(in, "warehouse_uptime", func() (map[string]domain.WarehouseUptime, error) {
+		if in.Explorer == nil {
+			return nil, errExplorerUnavailable
+		}
+		return fetchWarehouseUptime(ctx, in.Explorer, in.StartTime, in.EndTime), nil
+	})
+	return uptime
+}
+
+// fetchWarehouseUptime reconstructs the uptime of all warehouses from their events and overlays it with
+// the query history, it returns nil when the events are unavailable
+func fetchWarehouseUptime(
+	ctx context.Context,
+	explorer Explorer,
+	startTime, endTime time.Time,
+) map[string]domain.WarehouseUptime {
+	logger := zerolog.Ctx(ctx)
+
+	events, err := explorer.GetWarehouseEvents(ctx, startTime, endTime)
+	if err != nil {
+		logger.Warn().Err(err).Msg("warehouse events unavailable, estimating runtime from usage records")
+		return nil
+	}
+	uptime := reconstructWarehouseUptime(events, startTime, endTime)
+
+	activity, err := explorer.GetWarehouseQueryActivity(ctx, startTime, endTime)
+	if err != nil {
+		logger.Warn().Err(err).Msg("warehouse query activity unavailable, estimating idle time from usage records")
+		return uptime
+	}
+	overlayQueryActivity(uptime, activity)
+
+	return uptime
+}
+
+// reconstructWarehouseUptime replays the events of every warehouse into running intervals clipped to the period.
+// Start and scale events open or resize an interval, stop events close it. An event before the period sets
+// the state the warehouse starts the period in.
+func reconstructWarehouseUptime(
+	events []domain.WarehouseEvent,
+	startTime, endTime time.Time,
+) map[string]domain.WarehouseUptime {
+	eventsByID := make(map[string][]domain.WarehouseEvent)
+	for _, event := range events {
+		eventsByID[event.WarehouseID] = append(eventsByID[event.WarehouseID], event)
+	}
+
+	uptime := make(map[string]domain.WarehouseUptime, len(eventsByID))
+	for warehouseID, warehouseEvents := range eventsByID {
+		sort.SliceStable(warehouseEvents, func(i, j int) bool {
+			return warehouseEvents[i].EventTime.Before(warehouseEvents[j].EventTime)
+		})
+
+		u := domain.WarehouseUptime{WarehouseID: warehouseID}
+		running := false
+		clusters := 0
+		var openedAt time.Time
+		closeInterval := func(at time.Time) {
+			if running && at.After(openedAt) {
+				u.Intervals = append(u.Intervals, domain.WarehouseUptimeInterval{Start: openedAt, End: at, ClusterCount: clusters})
+			}
+		}
+
+		for i, event := range warehouseEvents {
+			at := event.EventTime
+			if at.Before(startTime) {
+				at = startTime
+			}
+			if at.After(endTime) {
+				break
+			}
+
+			switch event.EventType {
+			case domain.WarehouseEventStarting, domain.WarehouseEventRunning,
+				domain.WarehouseEventScaledUp, domain.WarehouseEventScaledDown:
+				count := max(event.ClusterCount, 1)
+				if running && count == clusters {
+					continue
+				}
+				closeInterval(at)
+				running, clusters, openedAt = true, count, at
+			case domain.WarehouseEventStopping, domain.WarehouseEventStopped:
+				if i == 0 && event.EventTime.After(startTime) {
+					// the warehouse was started before the retained events
+					running, clusters, openedAt = true, 1, startTime
+				}
+				closeInterval(at)
+				running = false
+			}
+		}
+		closeInterval(endTime)
+
+		uptime[warehouseID] = u
+	}
+
+	return uptime
+}
+
+// overlayQueryActivity sets the idle hours of every warehouse to its running time without any query executing
+func overlayQueryActivity(uptime map[string]domain.WarehouseUptime, activity []domain.WarehouseQueryActivity) {
+	minutesByID := make(map[string][]time.Time, len(activity))
+	for _, a := range activity {
+		minutesByID[a.WarehouseID] = a.ActiveMinutes
+	}
+
+	for warehouseID, u := range uptime {
+		busy := 0.0
+		i := 0
+		for _, minute := range minutesByID[warehouseID] {
+			minuteEnd := minute.Add(time.Minute)
+			for i < len(u.Intervals) && !u.Intervals[i].End.After(minute) {
+				i++
+			}
+			for j := i; j < len(u.Intervals) && u.Intervals[j].Start.Before(minuteEnd); j++ {
+				start := maxTime(u.Intervals[j].Start, minute)
+				end := minTime(u.Intervals[j].End, minuteEnd)
+				busy += end.Sub(start).Hours()
+			}
+		}
+
+		u.IdleHours = max(u.Hours()-busy, 0)
+		u.ActivityMeasured = true
+		uptime[warehouseID] = u
+	}
+}
@@ -0,0 +1,162 @@
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconstructWarehouseUptime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	at := func(hours float64) time.Time { return start.Add(time.Duration(hours * float64(time.Hour))) }
+
+	t.Run("start, scale and stop events", func(t *testing.T) {
+		events := []domain.WarehouseEvent{
+			{WarehouseID: "wh", EventType: domain.WarehouseEventStopping, ClusterCount: 0, EventTime: at(8)},
+			{WarehouseID: "wh", EventType: domain.WarehouseEventStarting, ClusterCount: 1, EventTime: at(2)},
+			{WarehouseID: "wh", EventType: domain.WarehouseEventRunning, ClusterCount: 1, EventTime: at(2.1)},
+			{WarehouseID: "wh", EventType: domain.WarehouseEventScaledUp, ClusterCount: 3, EventTime: at(4)},
+			{WarehouseID: "wh", EventType: domain.WarehouseEventScaledDown, ClusterCount: 1, EventTime: at(6)},
+			{WarehouseID: "wh", EventType: domain.WarehouseEventStopped, ClusterCount: 0, EventTime: at(8.1)},
+		}
+
+		uptime := reconstructWarehouseUptime(events, start, end)
+
+		u := uptime["wh"]
+		require.Len(t, u.Intervals, 3)
+		assert.Equal(t, domain.WarehouseUptimeInterval{Start: at(2), End: at(4), ClusterCount: 1}, u.Intervals[0])
+		assert.Equal(t, domain.WarehouseUptimeInterval{Start: at(4), End: at(6), ClusterCount: 3}, u.Intervals[1])
+		assert.Equal(t, domain.WarehouseUptimeInterval{Start: at(6), End: at(8), ClusterCount: 1}, u.Intervals[2])
+		assert.InDelta(t, 6.0, u.Hours(), 1e-9)
+		assert.InDelta(t, 10.0, u.ClusterHours(), 1e-9)
+		assert.Equal(t, 3, u.PeakClusters())
+	})
+
+	t.Run("running before the period and still running at its end", func(t *testing.T) {
+		events := []domain.WarehouseEvent{
+			{WarehouseID: "wh", EventType: domain.WarehouseEventRunning, ClusterCount: 2, EventTime: start.Add(-48 * time.Hour)},
+		}
+
+		u := reconstructWarehouseUptime(events, start, end)["wh"]
+
+		require.Len(t, u.Intervals, 1)
+		assert.Equal(t, domain.WarehouseUptimeInterval{Start: start, End: end, ClusterCount: 2}, u.Intervals[0])
+	})
+
+	t.Run("stopped before the period", func(t *testing.T) {
+		events := []domain.WarehouseEvent{
+			{WarehouseID: "wh", EventType: domain.WarehouseEventStopped, EventTime: start.Add(-time.Hour)},
+		}
+
+		u := reconstructWarehouseUptime(events, start, end)["wh"]
+
+		assert.Empty(t, u.Intervals)
+		assert.Zero(t, u.Hours())
+	})
+
+	t.Run("stopping without earlier events ran from the period start", func(t *testing.T) {
+		events := []domain.WarehouseEvent{
+			{WarehouseID: "wh", EventType: domain.WarehouseEventStopping, EventTime: at(3)},
+		}
+
+		u := reconstructWarehouseUptime(events, start, end)["wh"]
+
+		assert.InDelta(t, 3.0, u.Hours(), 1e-9)
+	})
+}
+
+func TestOverlayQueryActivity(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	uptime := map[string]domain.WarehouseUptime{
+		"wh": {WarehouseID: "wh", Intervals: []domain.WarehouseUptimeInterval{
+			{Start: start, End: start.Add(time.Hour), ClusterCount: 1},
+			{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour), ClusterCount: 2},
+		}},
+		"idle": {WarehouseID: "idle", Intervals: []domain.WarehouseUptimeInterval{
+			{Start: start, End: start.Add(time.Hour), ClusterCount: 1},
+		}},
+	}
+	var minutes []time.Time
+	// 30 busy minutes across the resize, and one minute before the warehouse started
+	minutes = append(minutes, start.Add(-time.Minute))
+	for m := 45; m < 75; m++ {
+		minutes = append(minutes, start.Add(time.Duration(m)*time.Minute))
+	}
+
+	overlayQueryActivity(uptime, []domain.WarehouseQueryActivity{{WarehouseID: "wh", ActiveMinutes: minutes}})
+
+	assert.True(t, uptime["wh"].ActivityMeasured)
+	assert.InDelta(t, 1.5, uptime["wh"].IdleHours, 1e-9)
+	assert.True(t, uptime["idle"].ActivityMeasured)
+	assert.InDelta(t, 1.0, uptime["idle"].IdleHours, 1e-9)
+}
+
+func TestAnalyzeRuntimeDurationWithUptime(t *testing.T) {
+	settings := DefaultWarehouseAuditSettings()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// billing spans suggest 4 hours without idle time
+	records := []domain.ResourceCost{
+		{
+			Resource:  domain.ResourceDef{Name: "wh"},
+			StartTime: start,
+			EndTime:   start.Add(4 * time.Hour),
+			Costs:     []domain.CostComponent{{TotalAmount: 120.0, Currency: "USD"}},
+		},
+	}
+	uptime := map[string]domain.WarehouseUptime{
+		"wh": {
+			WarehouseID: "wh",
+			Intervals: []domain.WarehouseUptimeInterval{
+				{Start: start, End: start.Add(12 * time.Hour), ClusterCount: 2},
+			},
+			IdleHours:        9,
+			ActivityMeasured: true,
+		},
+	}
+
+	findings := analyzeRuntimeDuration(records, uptime, settings)
+
+	byIssue := map[string]domain.AuditFinding{}
+	for _, f := range findings {
+		byIssue[f.Issue] = f
+	}
+	require.Contains(t, byIssue, "excessive_runtime")
+	assert.Contains(t, byIssue["excessive_runtime"].Description, "12.0 hours (24.0 cluster hours, up to 2 clusters)")
+	require.Contains(t, byIssue, "high_idle_time")
+	assert.Contains(t, byIssue["high_idle_time"].Description, "without executing queries for 9.0 hours (75% of uptime)")
+	assert.Equal(t, domain.SavingsConfidenceMedium, byIssue["high_idle_time"].EstimatedMonthlySavings.Confidence)
+	assert.InDelta(t, 90.0, byIssue["high_idle_time"].EstimatedMonthlySavings.Amount, 1e-9)
+	assert.Contains(t, byIssue, "idle_workload")
+}
+
+func TestFetchWarehouseUptime(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	t.Run("events unavailable", func(t *testing.T) {
+		explorer := new(MockExplorer)
+		explorer.On("GetWarehouseEvents", ctx, start, end).Return(nil, assert.AnError)
+
+		assert.Nil(t, fetchWarehouseUptime(ctx, explorer, start, end))
+	})
+
+	t.Run("query activity unavailable", func(t *testing.T) {
+		explorer := new(MockExplorer)
+		explorer.On("GetWarehouseEvents", ctx, start, end).Return([]domain.WarehouseEvent{
+			{WarehouseID: "wh", EventType: domain.WarehouseEventRunning, ClusterCount: 1, EventTime: start},
+		}, nil)
+		explorer.On("GetWarehouseQueryActivity", ctx, start, end).Return(nil, assert.AnError)
+
+		uptime := fetchWarehouseUptime(ctx, explorer, start, end)
+
+		require.Contains(t, uptime, "wh")
+		assert.InDelta(t, 24.0, uptime["wh"].Hours(), 1e-9)
+		assert.False(t, uptime["wh"].ActivityMeasured)
+	})
+}
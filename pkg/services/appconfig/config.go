@@ -0,0 +1,325 @@
+package appconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/de-tools/data-atlas/pkg/services/workflow"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/pricing"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the application config, e.g.
+//
+//	server:
+//	  host: 0.0.0.0
+//	  port: 8080
+//	  shutdown_timeout: 30s
+//	storage:
+//	  duckdb_path: /var/lib/data-atlas/data-atlas.db
+//	sync:
+//	  enabled: true
+//	  mode: workspace
+//	  poll_interval: 1m
+//	  workspaces:
+//	    production:
+//	      poll_interval: 5m
+//	audit:
+//	  defaults:
+//	    warehouse:
+//	      max_runtime_hours: 10
+//	anomalies:
+//	  method: weekday
+//	forecast:
+//	  history_days: 120
+//	explain:
+//	  max_depth: 2
+//	pricing:
+//	  currency: USD
+//	  default_price: 0.22
+//	  skus:
+//	    PREMIUM_JOBS_*: 0.15
+type Config struct {
+	Server  ServerConfig  `yaml:"server"`
+	Storage StorageConfig `yaml:"storage"`
+	Sync    SyncConfig    `yaml:"sync"`
+	// Audit is in the layout of the audit settings file
+	Audit     yaml.Node                          `yaml:"audit"`
+	Anomalies workspace.AnomalyDetectionSettings `yaml:"anomalies"`
+	Forecast  workspace.ForecastSettings         `yaml:"forecast"`
+	Explain   workspace.CostExplanationSettings  `yaml:"explain"`
+	Pricing   PricingConfig                      `yaml:"pricing"`
+
+	auditSettings *workspace.AuditSettingsProvider
+}
+
+type ServerConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// ShutdownTimeout is how long in-flight requests are waited for on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type StorageConfig struct {
+	DuckDBPath string `yaml:"duckdb_path"`
+}
+
+type SyncConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Mode    string `yaml:"mode"`
+	// PollInterval is how long a sync sleeps once it caught up with the usage
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// BatchInterval is the span of usage read at once
+	BatchInterval time.Duration `yaml:"batch_interval"`
+	// Workspaces are the schedules of workspaces by name, unset intervals are inherited
+	Workspaces map[string]ScheduleConfig `yaml:"workspaces"`
+}
+
+type ScheduleConfig struct {
+	PollInterval  time.Duration `yaml:"poll_interval"`
+	BatchInterval time.Duration `yaml:"batch_interval"`
+}
+
+type PricingConfig struct {
+	Currency     string  `yaml:"currency"`
+	DefaultPrice float64 `yaml:"default_price"`
+	// Skus are the prices by SKU name, a name ending with "*" matches every SKU with that prefix
+	Skus map[string]float64 `yaml:"skus"`
+}
+
+// Default returns the config used when no config file is given
+func Default() *Config {
+	runner := workflow.DefaultRunnerConfig()
+	prices := pricing.DefaultSettings()
+	return &Config{
+		Server: ServerConfig{
+			Host:            "localhost",
+			Port:            8080,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Storage: StorageConfig{
+			DuckDBPath: "data-atlas.db",
+		},
+		Sync: SyncConfig{
+			Mode:          string(workflow.SyncModeWorkspace),
+			PollInterval:  runner.SleepInterval,
+			BatchInterval: runner.BatchInterval,
+		},
+		Anomalies: workspace.DefaultAnomalyDetectionSettings(),
+		Forecast:  workspace.DefaultForecastSettings(),
+		Explain:   workspace.DefaultCostExplanationSettings(),
+		Pricing: PricingConfig{
+			Currency:     prices.CurrencyCode,
+			DefaultPrice: prices.DefaultPrice,
+		},
+		auditSettings: workspace.NewAuditSettingsProvider(),
+	}
+}
+
+// Load reads the config file at path, the defaults when path is empty, and applies the
+// DATA_ATLAS_* environment variables over it. Settings missing from the file keep their defaults.
+func Load(path string) (*Config, error) {
+	var data []byte
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+	}
+	return load(data, os.LookupEnv)
+}
+
+func load(data []byte, lookupEnv func(string) (string, bool)) (*Config, error) {
+	config := Default()
+	if len(bytes.TrimSpace(data)) > 0 {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
+	var errs []error
+	for _, override := range envOverrides {
+		value, ok := lookupEnv(override.name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := override.apply(config, value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q: %w", override.name, value, err))
+		}
+	}
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
+	return config, nil
+}
+
+// envOverrides are applied in order, so the legacy variables come before the ones replacing them
+var envOverrides = []struct {
+	name  string
+	apply func(config *Config, value string) error
+}{
+	{"SERVER_HOST", func(c *Config, v string) error { c.Server.Host = v; return nil }},
+	{"SERVER_PORT", func(c *Config, v string) (err error) { c.Server.Port, err = strconv.Atoi(v); return err }},
+	{"DATA_ATLAS_SERVER_HOST", func(c *Config, v string) error { c.Server.Host = v; return nil }},
+	{"DATA_ATLAS_SERVER_PORT", func(c *Config, v string) (err error) { c.Server.Port, err = strconv.Atoi(v); return err }},
+	{"DATA_ATLAS_SERVER_SHUTDOWN_TIMEOUT", func(c *Config, v string) (err error) {
+		c.Server.ShutdownTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"DATA_ATLAS_STORAGE_DUCKDB_PATH", func(c *Config, v string) error { c.Storage.DuckDBPath = v; return nil }},
+	{"DATA_ATLAS_SYNC_ENABLED", func(c *Config, v string) (err error) { c.Sync.Enabled, err = strconv.ParseBool(v); return err }},
+	{"DATA_ATLAS_SYNC_MODE", func(c *Config, v string) error { c.Sync.Mode = v; return nil }},
+	{"DATA_ATLAS_SYNC_POLL_INTERVAL", func(c *Config, v string) (err error) {
+		c.Sync.PollInterval, err = time.ParseDuration(v)
+		return err
+	}},
+	{"DATA_ATLAS_SYNC_BATCH_INTERVAL", func(c *Config, v string) (err error) {
+		c.Sync.BatchInterval, err = time.ParseDuration(v)
+		return err
+	}},
+	{"DATA_ATLAS_PRICING_CURRENCY", func(c *Config, v string) error { c.Pricing.Currency = v; return nil }},
+	{"DATA_ATLAS_PRICING_DEFAULT_PRICE", func(c *Config, v string) (err error) {
+		c.Pricing.DefaultPrice, err = strconv.ParseFloat(v, 64)
+		return err
+	}},
+}
+
+// validate returns an error per invalid setting, prefixed with the path of the setting
+func (c *Config) validate() []error {
+	var errs []error
+	invalid := func(path, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server.port", "must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.ShutdownTimeout < 0 {
+		invalid("server.shutdown_timeout", "must not be negative, got %s", c.Server.ShutdownTimeout)
+	}
+
+	if c.Storage.DuckDBPath == "" {
+		invalid("storage.duckdb_path", "must not be empty")
+	}
+
+	if _, err := workflow.ParseSyncMode(c.Sync.Mode); err != nil {
+		invalid("sync.mode", "%v", err)
+	}
+	if c.Sync.PollInterval <= 0 {
+		invalid("sync.poll_interval", "must be positive, got %s", c.Sync.PollInterval)
+	}
+	if c.Sync.BatchInterval <= 0 {
+		invalid("sync.batch_interval", "must be positive, got %s", c.Sync.BatchInterval)
+	}
+	for name, schedule := range c.Sync.Workspaces {
+		if schedule.PollInterval < 0 {
+			invalid("sync.workspaces."+name+".poll_interval", "must not be negative, got %s", schedule.PollInterval)
+		}
+		if schedule.BatchInterval < 0 {
+			invalid("sync.workspaces."+name+".batch_interval", "must not be negative, got %s", schedule.BatchInterval)
+		}
+	}
+
+	if err := c.parseAudit(); err != nil {
+		invalid("audit", "%v", err)
+	}
+	if err := c.Anomalies.Validate(); err != nil {
+		invalid("anomalies", "%v", err)
+	}
+	if err := c.Forecast.Validate(); err != nil {
+		invalid("forecast", "%v", err)
+	}
+	if err := c.Explain.Validate(); err != nil {
+		invalid("explain", "%v", err)
+	}
+
+	if c.Pricing.Currency == "" {
+		invalid("pricing.currency", "must not be empty")
+	}
+	if c.Pricing.DefaultPrice < 0 {
+		invalid("pricing.default_price", "must not be negative, got %g", c.Pricing.DefaultPrice)
+	}
+	for sku, price := range c.Pricing.Skus {
+		if price < 0 {
+			invalid("pricing.skus."+sku, "must not be negative, got %g", price)
+		}
+		if strings.Contains(strings.TrimSuffix(sku, "*"), "*") {
+			invalid("pricing.skus."+sku, "a wildcard is only supported at the end of the SKU name")
+		}
+	}
+	return errs
+}
+
+func (c *Config) parseAudit() error {
+	if c.Audit.IsZero() {
+		c.auditSettings = workspace.NewAuditSettingsProvider()
+		return nil
+	}
+	data, err := yaml.Marshal(&c.Audit)
+	if err != nil {
+		return err
+	}
+	c.auditSettings, err = workspace.ParseAuditSettings(data)
+	return err
+}
+
+// SetAuditSettings replaces the audit section, e.g. with a separate audit settings file
+func (c *Config) SetAuditSettings(settings *workspace.AuditSettingsProvider) {
+	c.auditSettings = settings
+}
+
+// Addr returns the address the HTTP server listens on
+func (c *Config) Addr() string {
+	return net.JoinHostPort(c.Server.Host, strconv.Itoa(c.Server.Port))
+}
+
+// AuditSettings returns the audit settings of the audit section
+func (c *Config) AuditSettings() *workspace.AuditSettingsProvider {
+	return c.auditSettings
+}
+
+// SyncSettings returns the sync settings, the workspace schedules inherit the intervals they do not set
+func (c *Config) SyncSettings() workflow.Settings {
+	// the mode is validated on load
+	mode, _ := workflow.ParseSyncMode(c.Sync.Mode)
+	settings := workflow.Settings{
+		Mode: mode,
+		Runner: workflow.RunnerConfig{
+			BatchInterval: c.Sync.BatchInterval,
+			SleepInterval: c.Sync.PollInterval,
+		},
+		Workspaces: make(map[string]workflow.RunnerConfig, len(c.Sync.Workspaces)),
+	}
+	for name, schedule := range c.Sync.Workspaces {
+		runner := settings.Runner
+		if schedule.BatchInterval > 0 {
+			runner.BatchInterval = schedule.BatchInterval
+		}
+		if schedule.PollInterval > 0 {
+			runner.SleepInterval = schedule.PollInterval
+		}
+		settings.Workspaces[name] = runner
+	}
+	return settings
+}
+
+// PricingSettings returns the price overlays of the usage read from the system tables
+func (c *Config) PricingSettings() pricing.Settings {
+	return pricing.Settings{
+		CurrencyCode: c.Pricing.Currency,
+		DefaultPrice: c.Pricing.DefaultPrice,
+		Skus:         c.Pricing.Skus,
+	}
+}
@@ -0,0 +1,202 @@
+package appconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/de-tools/data-atlas/pkg/services/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noEnv(string) (string, bool) { return "", false }
+
+func envOf(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("no config file", func(t *testing.T) {
+		config, err := Load("")
+		require.NoError(t, err)
+		assert.Equal(t, "data-atlas.db", config.Storage.DuckDBPath)
+		assert.Equal(t, workflow.DefaultSettings().Runner, config.SyncSettings().Runner)
+		assert.NotNil(t, config.AuditSettings())
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "data-atlas.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  host: 0.0.0.0
+  port: 9090
+  shutdown_timeout: 5s
+storage:
+  duckdb_path: /tmp/atlas.db
+sync:
+  enabled: true
+  mode: account
+  poll_interval: 2m
+  workspaces:
+    production:
+      poll_interval: 10m
+      batch_interval: 720h
+    staging:
+      batch_interval: 24h
+audit:
+  defaults:
+    warehouse:
+      max_runtime_hours: 10
+  workspaces:
+    production:
+      cluster:
+        low_cpu_percent: 5
+anomalies:
+  method: weekday
+  z_score_threshold: 2.5
+forecast:
+  history_days: 120
+explain:
+  dimensions: [sku, tag:team]
+  max_depth: 2
+pricing:
+  currency: EUR
+  skus:
+    PREMIUM_JOBS_*: 0.15
+`), 0o600))
+
+		config, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "0.0.0.0:9090", config.Addr())
+		assert.Equal(t, 5*time.Second, config.Server.ShutdownTimeout)
+		assert.Equal(t, "/tmp/atlas.db", config.Storage.DuckDBPath)
+		assert.True(t, config.Sync.Enabled)
+
+		sync := config.SyncSettings()
+		assert.Equal(t, workflow.SyncModeAccount, sync.Mode)
+		assert.Equal(t, 2*time.Minute, sync.Runner.SleepInterval)
+		assert.Equal(t, workflow.DefaultRunnerConfig().BatchInterval, sync.Runner.BatchInterval)
+		assert.Equal(t, workflow.RunnerConfig{BatchInterval: 720 * time.Hour, SleepInterval: 10 * time.Minute},
+			sync.Workspaces["production"])
+		assert.Equal(t, workflow.RunnerConfig{BatchInterval: 24 * time.Hour, SleepInterval: 2 * time.Minute},
+			sync.Workspaces["staging"], "unset intervals are inherited")
+
+		assert.Equal(t, 10.0, config.AuditSettings().ForWorkspace("staging").Warehouse.MaxRuntimeHours)
+		assert.Equal(t, 5.0, config.AuditSettings().ForWorkspace("production").Cluster.LowCPUPercent)
+
+		assert.Equal(t, workspace.AnomalyBaselineWeekday, config.Anomalies.Method)
+		assert.Equal(t, 2.5, config.Anomalies.ZScoreThreshold)
+		assert.Equal(t, workspace.DefaultAnomalyDetectionSettings().BaselineDays, config.Anomalies.BaselineDays)
+		assert.Equal(t, 120, config.Forecast.HistoryDays)
+		assert.Equal(t, workspace.DefaultForecastSettings().ConfidenceLevel, config.Forecast.ConfidenceLevel)
+		assert.Equal(t, []domain.CostDimension{domain.CostDimensionSKU, "tag:team"}, config.Explain.Dimensions)
+		assert.Equal(t, 2, config.Explain.MaxDepth)
+
+		prices := config.PricingSettings()
+		assert.Equal(t, "EUR", prices.CurrencyCode)
+		assert.Equal(t, 0.22, prices.DefaultPrice, "unset settings keep their defaults")
+		assert.Equal(t, map[string]float64{"PREMIUM_JOBS_*": 0.15}, prices.Skus)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorContains(t, err, "failed to read config")
+	})
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	config, err := load([]byte(`
+server:
+  port: 9090
+`), envOf(map[string]string{
+		"SERVER_HOST":                    "legacy",
+		"SERVER_PORT":                    "7070",
+		"DATA_ATLAS_SERVER_PORT":         "6060",
+		"DATA_ATLAS_STORAGE_DUCKDB_PATH": "/data/atlas.db",
+		"DATA_ATLAS_SYNC_ENABLED":        "true",
+		"DATA_ATLAS_SYNC_POLL_INTERVAL":  "30s",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "legacy:6060", config.Addr(), "the DATA_ATLAS_ variables take precedence over the legacy ones")
+	assert.Equal(t, "/data/atlas.db", config.Storage.DuckDBPath)
+	assert.True(t, config.Sync.Enabled)
+	assert.Equal(t, 30*time.Second, config.SyncSettings().Runner.SleepInterval)
+}
+
+func TestLoadValidation(t *testing.T) {
+	t.Run("every invalid setting is reported", func(t *testing.T) {
+		_, err := load([]byte(`
+server:
+  port: 70000
+storage:
+  duckdb_path: ""
+sync:
+  mode: cluster
+  poll_interval: 0s
+  workspaces:
+    production:
+      batch_interval: -1h
+pricing:
+  default_price: -1
+  skus:
+    PREMIUM_*_COMPUTE: 0.5
+`), noEnv)
+		require.Error(t, err)
+		for _, message := range []string{
+			"server.port: must be between 1 and 65535, got 70000",
+			"storage.duckdb_path: must not be empty",
+			`sync.mode: invalid sync mode "cluster"`,
+			"sync.poll_interval: must be positive, got 0s",
+			"sync.workspaces.production.batch_interval: must not be negative, got -1h0m0s",
+			"pricing.default_price: must not be negative, got -1",
+			"pricing.skus.PREMIUM_*_COMPUTE: a wildcard is only supported at the end of the SKU name",
+		} {
+			assert.ErrorContains(t, err, message)
+		}
+	})
+
+	t.Run("unknown setting", func(t *testing.T) {
+		_, err := load([]byte(`
+server:
+  listen: 0.0.0.0
+`), noEnv)
+		assert.ErrorContains(t, err, "field listen not found")
+	})
+
+	t.Run("invalid audit settings", func(t *testing.T) {
+		_, err := load([]byte(`
+audit:
+  defaults:
+    warehouse:
+      max_runtime_hours: -1
+`), noEnv)
+		assert.ErrorContains(t, err, "audit: invalid audit settings defaults")
+	})
+
+	t.Run("invalid analysis settings", func(t *testing.T) {
+		_, err := load([]byte(`
+anomalies:
+  method: median
+forecast:
+  confidence_level: 95
+explain:
+  dimensions: [color]
+`), noEnv)
+		require.Error(t, err)
+		for _, message := range []string{"anomalies: ", "forecast: ", "explain: "} {
+			assert.ErrorContains(t, err, message)
+		}
+	})
+
+	t.Run("invalid environment variable", func(t *testing.T) {
+		_, err := load(nil, envOf(map[string]string{"DATA_ATLAS_SERVER_SHUTDOWN_TIMEOUT": "soon"}))
+		assert.ErrorContains(t, err, `invalid DATA_ATLAS_SERVER_SHUTDOWN_TIMEOUT "soon"`)
+	})
+}
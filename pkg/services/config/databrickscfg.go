@@ -3,9 +3,15 @@ package config
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	databricksconfig "github.com/databricks/databricks-sdk-go/config"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
 	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/store/databrickssdk/client"
 	"github.com/rs/zerolog"
 	"gopkg.in/ini.v1"
 )
@@ -13,61 +19,373 @@ import (
 type Registry interface {
 	GetProfiles(ctx context.Context) ([]domain.ConfigProfile, error)
 	GetConfig(ctx context.Context, profile domain.ConfigProfile) (*databricksconfig.Config, error)
+	// GetSyncSettings returns the sync settings of the profile, read from the sync_* keys of its section
+	GetSyncSettings(ctx context.Context, profile domain.ConfigProfile) (domain.SyncSettings, error)
+}
+
+// StatusProvider reports the outcome of the loads of the config file
+type StatusProvider interface {
+	Status() domain.ConfigReloadStatus
+}
+
+// ChangeListener is notified of the profiles added, removed or changed by a reload of the config file
+type ChangeListener func(ctx context.Context, changes domain.ProfileChanges)
+
+const (
+	syncWarehouseIDKey     = "sync_warehouse_id"
+	syncWarehousePolicyKey = "sync_warehouse_policy"
+)
+
+// workspaceDiscoverer lists the workspaces of an account profile and builds their configs
+type workspaceDiscoverer interface {
+	CanMintWorkspaceTokens() bool
+	ListWorkspaces(ctx context.Context) ([]provisioning.Workspace, error)
+	WorkspaceHost(workspace provisioning.Workspace) string
+	WorkspaceConfig(workspace provisioning.Workspace) *databricksconfig.Config
 }
 
 type CfgRegistry struct {
-	cfg        *ini.File
-	path       string
-	profileMap map[domain.ConfigProfile]*databricksconfig.Config
+	path string
+	// newDiscoverer creates the account client discovering the workspaces of an account profile
+	newDiscoverer func(cfg *databricksconfig.Config) (workspaceDiscoverer, error)
+
+	mu        sync.RWMutex
+	profiles  *profileSet
+	status    domain.ConfigReloadStatus
+	listeners []ChangeListener
+}
+
+// profileSet is the profiles of a load of the config file, replaced as a whole on reload
+type profileSet struct {
+	profileMap   map[domain.ConfigProfile]*databricksconfig.Config
+	syncSettings map[string]domain.SyncSettings // by profile name
+	fingerprints map[string]string              // by profile name, changes when the profile values change
+}
+
+func newProfileSet() *profileSet {
+	return &profileSet{
+		profileMap:   make(map[domain.ConfigProfile]*databricksconfig.Config),
+		syncSettings: make(map[string]domain.SyncSettings),
+		fingerprints: make(map[string]string),
+	}
 }
 
 func NewRegistry(path string) (*CfgRegistry, error) {
-	cfg, err := ini.Load(path)
-	if err != nil {
+	if _, err := ini.Load(path); err != nil {
 		return nil, err
 	}
 
 	return &CfgRegistry{
-		cfg:        cfg,
-		path:       path,
-		profileMap: make(map[domain.ConfigProfile]*databricksconfig.Config),
+		path: path,
+		newDiscoverer: func(cfg *databricksconfig.Config) (workspaceDiscoverer, error) {
+			return client.NewAccountClient(cfg)
+		},
+		profiles: newProfileSet(),
+		status:   domain.ConfigReloadStatus{Path: path},
 	}, nil
 }
 
 func (cr *CfgRegistry) Init(ctx context.Context) error {
+	profiles, err := cr.load(ctx, newProfileSet())
+	if err != nil {
+		return err
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.profiles = profiles
+	cr.status.LoadedAt = time.Now().UTC()
+	cr.status.Profiles = profiles.list()
+	return nil
+}
+
+// OnChange registers a listener notified after every reload that added, removed or changed profiles
+func (cr *CfgRegistry) OnChange(listener ChangeListener) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.listeners = append(cr.listeners, listener)
+}
+
+// Reload loads the config file again and replaces the profiles. The profiles are kept as they were
+// when the file cannot be loaded, e.g. while it is being written.
+func (cr *CfgRegistry) Reload(ctx context.Context) (domain.ProfileChanges, error) {
+	logger := zerolog.Ctx(ctx)
+
+	cr.mu.RLock()
+	previous := cr.profiles
+	cr.mu.RUnlock()
+
+	profiles, err := cr.load(ctx, previous)
+	if err != nil {
+		cr.mu.Lock()
+		now := time.Now().UTC()
+		cr.status.LastError = err.Error()
+		cr.status.LastErrorAt = &now
+		cr.mu.Unlock()
+
+		logger.Error().Err(err).Msgf("failed to reload `%s`, keeping the loaded profiles.", cr.path)
+		return domain.ProfileChanges{}, err
+	}
+
+	cr.mu.Lock()
+	profiles.keepUnchanged(cr.profiles)
+	changes := diffProfiles(cr.profiles, profiles)
+	cr.profiles = profiles
+	cr.status.LoadedAt = time.Now().UTC()
+	cr.status.Reloads++
+	cr.status.Profiles = profiles.list()
+	cr.status.LastChanges = changes
+	cr.status.LastError = ""
+	cr.status.LastErrorAt = nil
+	listeners := append([]ChangeListener(nil), cr.listeners...)
+	cr.mu.Unlock()
+
+	logger.Info().
+		Strs("added", profileNames(changes.Added)).
+		Strs("removed", profileNames(changes.Removed)).
+		Strs("changed", profileNames(changes.Changed)).
+		Msgf("Configuration at `%s` reloaded.", cr.path)
+
+	if !changes.IsEmpty() {
+		for _, listener := range listeners {
+			listener(ctx, changes)
+		}
+	}
+	return changes, nil
+}
+
+func (cr *CfgRegistry) Status() domain.ConfigReloadStatus {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	status := cr.status
+	status.Profiles = append([]domain.ConfigProfile(nil), cr.status.Profiles...)
+	return status
+}
+
+// load reads the profiles of the config file and discovers the workspaces of its account profiles.
+// The workspaces discovered in the previous load are kept for the accounts whose discovery fails.
+func (cr *CfgRegistry) load(ctx context.Context, previous *profileSet) (*profileSet, error) {
 	logger := zerolog.Ctx(ctx)
-	for _, section := range cr.cfg.Sections() {
+
+	file, err := ini.Load(cr.path)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := newProfileSet()
+	for _, section := range file.Sections() {
 		if len(section.Keys()) > 0 {
 			profileName := section.Name()
 
-			cfg, err := cr.loadConfig(ctx, profileName)
+			cfg, err := cr.loadConfig(ctx, file, profileName)
 			if err != nil {
-				return err
+				return nil, err
 			}
+			syncSettings, err := cr.loadSyncSettings(file, profileName)
+			if err != nil {
+				return nil, err
+			}
+			profiles.syncSettings[profileName] = syncSettings
+			profiles.fingerprints[profileName] = sectionFingerprint(section)
 
+			profile := domain.ConfigProfile{Name: profileName, Type: domain.ProfileTypeWorkspace}
 			if cfg.IsAccountClient() {
 				logger.Info().
-					Msgf("profile %s is an account level profile, Skipped.", profileName)
+					Msgf("profile %s is an account level profile, used for account APIs.", profileName)
+				profile.Type = domain.ProfileTypeAccount
+			}
+			profiles.profileMap[profile] = cfg
+		}
+	}
+
+	cr.discoverWorkspaces(ctx, profiles, previous)
+	return profiles, nil
+}
+
+// discoverWorkspaces registers the running workspaces of every account profile as workspace profiles.
+// Workspaces with a profile of the same name or host in the config file keep that profile.
+// Discovery failures are logged, the profiles of the config file are still usable and the workspaces
+// of the account discovered in the previous load are kept, so a transient failure does not remove them.
+func (cr *CfgRegistry) discoverWorkspaces(ctx context.Context, profiles, previous *profileSet) {
+	logger := zerolog.Ctx(ctx)
+
+	hosts := make(map[string]bool)
+	var accounts []domain.ConfigProfile
+	for profile, cfg := range profiles.profileMap {
+		switch profile.Type {
+		case domain.ProfileTypeWorkspace:
+			hosts[normalizeHost(cfg.Host)] = true
+		case domain.ProfileTypeAccount:
+			accounts = append(accounts, profile)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+
+	for _, account := range accounts {
+		accountClient, err := cr.newDiscoverer(profiles.profileMap[account])
+		if err != nil {
+			logger.Warn().Err(err).Msgf("failed to create account client for profile %s", account.Name)
+			profiles.keepDiscovered(previous, account.Name, hosts)
+			continue
+		}
+		if !accountClient.CanMintWorkspaceTokens() {
+			logger.Info().Msgf("profile %s has no OAuth client credentials, its workspaces are not discovered.",
+				account.Name)
+			continue
+		}
+
+		workspaces, err := accountClient.ListWorkspaces(ctx)
+		if err != nil {
+			logger.Warn().Err(err).Msgf("failed to discover workspaces of profile %s", account.Name)
+			profiles.keepDiscovered(previous, account.Name, hosts)
+			continue
+		}
+		for _, ws := range workspaces {
+			if ws.WorkspaceStatus != provisioning.WorkspaceStatusRunning {
+				continue
+			}
+			host := accountClient.WorkspaceHost(ws)
+			if hosts[normalizeHost(host)] || profiles.has(ws.WorkspaceName, domain.ProfileTypeWorkspace) {
+				logger.Debug().Msgf("workspace %s of profile %s is already configured.", ws.WorkspaceName, account.Name)
 				continue
 			}
 
-			profile := domain.ConfigProfile{Name: profileName, Type: domain.ProfileTypeWorkspace}
-			cr.profileMap[profile] = cfg
+			profile := domain.ConfigProfile{Name: ws.WorkspaceName, Type: domain.ProfileTypeWorkspace, Account: account.Name}
+			profiles.profileMap[profile] = accountClient.WorkspaceConfig(ws)
+			// discovered workspaces pick their warehouse with the policy of the account profile
+			profiles.syncSettings[profile.Name] = domain.SyncSettings{
+				WarehousePolicy: profiles.syncSettings[account.Name].WarehousePolicy,
+			}
+			profiles.fingerprints[profile.Name] = profiles.fingerprints[account.Name] + "\n" + host
+			hosts[normalizeHost(host)] = true
+			logger.Info().Msgf("workspace %s (%s) discovered through profile %s.", ws.WorkspaceName, host, account.Name)
+		}
+	}
+}
+
+// keepDiscovered copies the workspaces discovered through the account profile in the previous load,
+// with their configs and fingerprints, unless a profile of the same name or host is configured
+func (p *profileSet) keepDiscovered(previous *profileSet, account string, hosts map[string]bool) {
+	for profile, cfg := range previous.profileMap {
+		if profile.Account != account || profile.Type != domain.ProfileTypeWorkspace {
+			continue
+		}
+		if hosts[normalizeHost(cfg.Host)] || p.has(profile.Name, domain.ProfileTypeWorkspace) {
+			continue
+		}
+		p.profileMap[profile] = cfg
+		p.syncSettings[profile.Name] = previous.syncSettings[profile.Name]
+		p.fingerprints[profile.Name] = previous.fingerprints[profile.Name]
+		hosts[normalizeHost(cfg.Host)] = true
+	}
+}
+
+func (p *profileSet) has(name string, profileType domain.ProfileType) bool {
+	_, ok := p.find(name, profileType)
+	return ok
+}
+
+func (p *profileSet) find(name string, profileType domain.ProfileType) (domain.ConfigProfile, bool) {
+	for profile := range p.profileMap {
+		if profile.Name == name && profile.Type == profileType {
+			return profile, true
+		}
+	}
+	return domain.ConfigProfile{}, false
+}
+
+// list returns the profiles sorted by type and name
+func (p *profileSet) list() []domain.ConfigProfile {
+	profiles := make([]domain.ConfigProfile, 0, len(p.profileMap))
+	for profile := range p.profileMap {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Type != profiles[j].Type {
+			return profiles[i].Type < profiles[j].Type
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// keepUnchanged reuses the configs of the previous load for the profiles whose values did not change,
+// so the clients and connection pools created for a config outlive reloads that do not touch its profile
+func (p *profileSet) keepUnchanged(previous *profileSet) {
+	for profile := range p.profileMap {
+		cfg, ok := previous.profileMap[profile]
+		if ok && previous.fingerprints[profile.Name] == p.fingerprints[profile.Name] {
+			p.profileMap[profile] = cfg
 		}
 	}
-	return nil
+}
+
+// diffProfiles returns the profiles added, removed or with changed values between two loads
+func diffProfiles(previous, current *profileSet) domain.ProfileChanges {
+	var changes domain.ProfileChanges
+	for _, profile := range current.list() {
+		if _, ok := previous.find(profile.Name, profile.Type); !ok {
+			changes.Added = append(changes.Added, profile)
+			continue
+		}
+		if previous.fingerprints[profile.Name] != current.fingerprints[profile.Name] {
+			changes.Changed = append(changes.Changed, profile)
+		}
+	}
+	for _, profile := range previous.list() {
+		if !current.has(profile.Name, profile.Type) {
+			changes.Removed = append(changes.Removed, profile)
+		}
+	}
+	return changes
+}
+
+// sectionFingerprint returns the sorted key values of the section
+func sectionFingerprint(section *ini.Section) string {
+	values := section.KeysHash()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", key, values[key])
+	}
+	return b.String()
+}
+
+func profileNames(profiles []domain.ConfigProfile) []string {
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(host), "https://"), "http://")
+	return strings.TrimSuffix(host, "/")
 }
 
 func (cr *CfgRegistry) GetProfiles(_ context.Context) ([]domain.ConfigProfile, error) {
-	profiles := make([]domain.ConfigProfile, 0, len(cr.profileMap))
-	for profile := range cr.profileMap {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	profiles := make([]domain.ConfigProfile, 0, len(cr.profiles.profileMap))
+	for profile := range cr.profiles.profileMap {
 		profiles = append(profiles, profile)
 	}
 	return profiles, nil
 }
 
 func (cr *CfgRegistry) GetConfig(_ context.Context, profile domain.ConfigProfile) (*databricksconfig.Config, error) {
-	for key, value := range cr.profileMap {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	for key, value := range cr.profiles.profileMap {
 		if key.Name == profile.Name && key.Type == profile.Type {
 			return value, nil
 		}
@@ -76,8 +394,39 @@ func (cr *CfgRegistry) GetConfig(_ context.Context, profile domain.ConfigProfile
 	return nil, fmt.Errorf("profile %s not found in %s", profile, cr.path)
 }
 
-func (cr *CfgRegistry) loadConfig(_ context.Context, profile string) (*databricksconfig.Config, error) {
-	profileValues := cr.cfg.Section(profile)
+func (cr *CfgRegistry) GetSyncSettings(_ context.Context, profile domain.ConfigProfile) (domain.SyncSettings, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	if !cr.profiles.has(profile.Name, profile.Type) {
+		return domain.SyncSettings{}, fmt.Errorf("profile %s not found in %s", profile, cr.path)
+	}
+	settings, ok := cr.profiles.syncSettings[profile.Name]
+	if !ok {
+		return domain.SyncSettings{WarehousePolicy: domain.WarehousePolicyPreferRunning}, nil
+	}
+	return settings, nil
+}
+
+// loadSyncSettings reads the sync_* keys of the profile, which the Databricks config ignores
+func (cr *CfgRegistry) loadSyncSettings(file *ini.File, profile string) (domain.SyncSettings, error) {
+	section := file.Section(profile)
+	policy, err := domain.ParseWarehousePolicy(section.Key(syncWarehousePolicyKey).String())
+	if err != nil {
+		return domain.SyncSettings{}, fmt.Errorf("%s %s profile: %w", cr.path, profile, err)
+	}
+	return domain.SyncSettings{
+		WarehouseID:     section.Key(syncWarehouseIDKey).String(),
+		WarehousePolicy: policy,
+	}, nil
+}
+
+func (cr *CfgRegistry) loadConfig(
+	_ context.Context,
+	file *ini.File,
+	profile string,
+) (*databricksconfig.Config, error) {
+	profileValues := file.Section(profile)
 	if len(profileValues.Keys()) == 0 {
 		return nil, fmt.Errorf("%s has no %s profile configured", cr.path, profile)
 	}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	databricksconfig "github.com/databricks/databricks-sdk-go/config"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+[dev]
+host  = https://dev.cloud.databricks.com
+token = dapi-dev
+
+[prod]
+host  = https://prod.cloud.databricks.com
+token = dapi-prod
+sync_warehouse_policy = smallest
+`
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func newTestRegistry(t *testing.T, content string) *CfgRegistry {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".databrickscfg")
+	writeConfig(t, path, content)
+
+	registry, err := NewRegistry(path)
+	require.NoError(t, err)
+	require.NoError(t, registry.Init(context.Background()))
+	return registry
+}
+
+func TestCfgRegistry_ReloadKeepsUnchangedConfigs(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(t, testConfig)
+	dev := domain.ConfigProfile{Name: "dev", Type: domain.ProfileTypeWorkspace}
+	prod := domain.ConfigProfile{Name: "prod", Type: domain.ProfileTypeWorkspace}
+	devCfg, err := registry.GetConfig(ctx, dev)
+	require.NoError(t, err)
+	prodCfg, err := registry.GetConfig(ctx, prod)
+	require.NoError(t, err)
+
+	writeConfig(t, registry.path, testConfig+`
+[staging]
+host  = https://staging.cloud.databricks.com
+token = dapi-staging
+`)
+	_, err = registry.Reload(ctx)
+	require.NoError(t, err)
+
+	reloaded, err := registry.GetConfig(ctx, dev)
+	require.NoError(t, err)
+	assert.Same(t, devCfg, reloaded, "unchanged profiles keep their config")
+	reloaded, err = registry.GetConfig(ctx, prod)
+	require.NoError(t, err)
+	assert.Same(t, prodCfg, reloaded)
+
+	writeConfig(t, registry.path, strings.Replace(testConfig, "dapi-dev", "dapi-rotated", 1))
+	_, err = registry.Reload(ctx)
+	require.NoError(t, err)
+
+	reloaded, err = registry.GetConfig(ctx, dev)
+	require.NoError(t, err)
+	assert.NotSame(t, devCfg, reloaded, "changed profiles get a new config")
+	assert.Equal(t, "dapi-rotated", reloaded.Token)
+}
+
+func TestCfgRegistry_Reload(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(t, testConfig)
+
+	var notified []domain.ProfileChanges
+	registry.OnChange(func(_ context.Context, changes domain.ProfileChanges) {
+		notified = append(notified, changes)
+	})
+
+	writeConfig(t, registry.path, `
+[prod]
+host  = https://prod.cloud.databricks.com
+token = dapi-rotated
+sync_warehouse_policy = smallest
+
+[staging]
+host  = https://staging.cloud.databricks.com
+token = dapi-staging
+`)
+	changes, err := registry.Reload(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.ConfigProfile{{Name: "staging", Type: domain.ProfileTypeWorkspace}}, changes.Added)
+	assert.Equal(t, []domain.ConfigProfile{{Name: "dev", Type: domain.ProfileTypeWorkspace}}, changes.Removed)
+	assert.Equal(t, []domain.ConfigProfile{{Name: "prod", Type: domain.ProfileTypeWorkspace}}, changes.Changed)
+	assert.Equal(t, []domain.ProfileChanges{changes}, notified)
+
+	_, err = registry.GetConfig(ctx, domain.ConfigProfile{Name: "dev", Type: domain.ProfileTypeWorkspace})
+	assert.Error(t, err)
+	cfg, err := registry.GetConfig(ctx, domain.ConfigProfile{Name: "prod", Type: domain.ProfileTypeWorkspace})
+	require.NoError(t, err)
+	assert.Equal(t, "dapi-rotated", cfg.Token)
+
+	status := registry.Status()
+	assert.Equal(t, 1, status.Reloads)
+	assert.Empty(t, status.LastError)
+	assert.Len(t, status.Profiles, 2)
+}
+
+func TestCfgRegistry_ReloadKeepsProfilesOnError(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(t, testConfig)
+
+	writeConfig(t, registry.path, `
+[prod]
+host  = https://prod.cloud.databricks.com
+sync_warehouse_policy = biggest
+`)
+	_, err := registry.Reload(ctx)
+	require.Error(t, err)
+
+	profiles, err := registry.GetProfiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, profiles, 2)
+
+	settings, err := registry.GetSyncSettings(ctx, domain.ConfigProfile{Name: "prod", Type: domain.ProfileTypeWorkspace})
+	require.NoError(t, err)
+	assert.Equal(t, domain.WarehousePolicySmallest, settings.WarehousePolicy)
+
+	status := registry.Status()
+	assert.Contains(t, status.LastError, "invalid warehouse policy")
+	assert.NotNil(t, status.LastErrorAt)
+}
+
+// fakeDiscoverer lists the workspaces of an account, or fails with err
+type fakeDiscoverer struct {
+	workspaces []provisioning.Workspace
+	err        error
+}
+
+func (d *fakeDiscoverer) CanMintWorkspaceTokens() bool { return true }
+
+func (d *fakeDiscoverer) ListWorkspaces(context.Context) ([]provisioning.Workspace, error) {
+	return d.workspaces, d.err
+}
+
+func (d *fakeDiscoverer) WorkspaceHost(ws provisioning.Workspace) string {
+	return "https://" + ws.DeploymentName + ".cloud.databricks.com"
+}
+
+func (d *fakeDiscoverer) WorkspaceConfig(ws provisioning.Workspace) *databricksconfig.Config {
+	return &databricksconfig.Config{Host: d.WorkspaceHost(ws)}
+}
+
+func TestCfgRegistry_ReloadKeepsDiscoveredWorkspacesOnDiscoveryError(t *testing.T) {
+	ctx := context.Background()
+	accountConfig := testConfig + `
+[account]
+host          = https://accounts.cloud.databricks.com
+account_id    = 00000000-0000-0000-0000-000000000000
+client_id     = client
+client_secret = secret
+`
+	path := filepath.Join(t.TempDir(), ".databrickscfg")
+	writeConfig(t, path, accountConfig)
+	registry, err := NewRegistry(path)
+	require.NoError(t, err)
+	discoverer := &fakeDiscoverer{workspaces: []provisioning.Workspace{
+		{WorkspaceName: "analytics", DeploymentName: "analytics", WorkspaceStatus: provisioning.WorkspaceStatusRunning},
+	}}
+	registry.newDiscoverer = func(*databricksconfig.Config) (workspaceDiscoverer, error) { return discoverer, nil }
+	require.NoError(t, registry.Init(ctx))
+
+	analytics := domain.ConfigProfile{Name: "analytics", Type: domain.ProfileTypeWorkspace, Account: "account"}
+	discovered, err := registry.GetConfig(ctx, analytics)
+	require.NoError(t, err)
+	assert.Equal(t, "https://analytics.cloud.databricks.com", discovered.Host)
+
+	discoverer.err = fmt.Errorf("503 Service Unavailable")
+	writeConfig(t, path, strings.Replace(accountConfig, "dapi-dev", "dapi-rotated", 1))
+	changes, err := registry.Reload(ctx)
+	require.NoError(t, err)
+
+	assert.Empty(t, changes.Removed, "a discovery failure does not remove the discovered workspaces")
+	assert.Equal(t, []domain.ConfigProfile{{Name: "dev", Type: domain.ProfileTypeWorkspace}}, changes.Changed)
+	kept, err := registry.GetConfig(ctx, analytics)
+	require.NoError(t, err)
+	assert.Same(t, discovered, kept, "the discovered workspaces keep their config")
+
+	discoverer.err = nil
+	discoverer.workspaces = nil
+	writeConfig(t, path, accountConfig)
+	changes, err = registry.Reload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ConfigProfile{analytics}, changes.Removed,
+		"workspaces no longer listed by a successful discovery are removed")
+}
+
+func TestCfgRegistry_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := newTestRegistry(t, testConfig)
+
+	changed := make(chan domain.ProfileChanges, 1)
+	registry.OnChange(func(_ context.Context, changes domain.ProfileChanges) {
+		changed <- changes
+	})
+	require.NoError(t, registry.Watch(ctx))
+	assert.True(t, registry.Status().Watching)
+
+	writeConfig(t, registry.path, testConfig+`
+[staging]
+host  = https://staging.cloud.databricks.com
+token = dapi-staging
+`)
+
+	select {
+	case changes := <-changed:
+		assert.Equal(t, []domain.ConfigProfile{{Name: "staging", Type: domain.ProfileTypeWorkspace}}, changes.Added)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not reloaded")
+	}
+}
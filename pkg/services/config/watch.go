@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+)
+
+// reloadDelay is how long the watcher waits for writes to the config file to settle before reloading it
+const reloadDelay = 500 * time.Millisecond
+
+// Watch reloads the registry whenever the config file changes until the context is cancelled.
+// The directory of the file is watched, so files replaced by editors with a rename are picked up too.
+func (cr *CfgRegistry) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	path := filepath.Clean(cr.path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", cr.path, err)
+	}
+
+	cr.mu.Lock()
+	cr.status.Watching = true
+	cr.mu.Unlock()
+
+	go func() {
+		logger := zerolog.Ctx(ctx)
+		defer func() {
+			watcher.Close()
+			cr.mu.Lock()
+			cr.status.Watching = false
+			cr.mu.Unlock()
+		}()
+
+		// nil until a change is seen, a nil channel never fires
+		var reload <-chan time.Time
+		var timer *time.Timer
+		for {
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || event.Op == fsnotify.Chmod {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(reloadDelay)
+				reload = timer.C
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warn().Err(err).Msgf("config watcher of `%s` failed", cr.path)
+			case <-reload:
+				reload = nil
+				// failures are logged and recorded in the status by Reload
+				_, _ = cr.Reload(ctx)
+			}
+		}
+	}()
+	return nil
+}
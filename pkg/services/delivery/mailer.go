@@ -0,0 +1,263 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TLSMode is how the connection to the SMTP server is secured
+type TLSMode string
+
+const (
+	// TLSModeNone sends in plain text, only meant for local SMTP catchers
+	TLSModeNone TLSMode = "none"
+	// TLSModeStartTLS upgrades the plain connection with STARTTLS, usually on port 587
+	TLSModeStartTLS TLSMode = "starttls"
+	// TLSModeImplicit connects over TLS, usually on port 465
+	TLSModeImplicit TLSMode = "tls"
+)
+
+const defaultSMTPTimeout = 30 * time.Second
+
+// SMTPSettings configures the SMTP server reports are sent through
+type SMTPSettings struct {
+	Host     string
+	Port     int
+	Username string // authenticates with PLAIN when set
+	Password string
+	From     string
+	TLS      TLSMode
+	// InsecureSkipVerify accepts any server certificate, e.g. self-signed certificates of test servers
+	InsecureSkipVerify bool
+	Timeout            time.Duration
+}
+
+// SMTPSettingsFromEnv reads the SMTP settings from the SMTP_* environment variables,
+// ok is false when SMTP_HOST is not set and email delivery is disabled
+func SMTPSettingsFromEnv() (settings SMTPSettings, ok bool, err error) {
+	settings = SMTPSettings{
+		Host:     os.Getenv("SMTP_HOST"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+		TLS:      TLSMode(strings.ToLower(os.Getenv("SMTP_TLS"))),
+		Timeout:  defaultSMTPTimeout,
+	}
+	if settings.Host == "" {
+		return settings, false, nil
+	}
+	if settings.TLS == "" {
+		settings.TLS = TLSModeStartTLS
+	}
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		settings.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return settings, false, fmt.Errorf("invalid SMTP_PORT %q", port)
+		}
+	}
+	if skip := os.Getenv("SMTP_INSECURE_SKIP_VERIFY"); skip != "" {
+		settings.InsecureSkipVerify, err = strconv.ParseBool(skip)
+		if err != nil {
+			return settings, false, fmt.Errorf("invalid SMTP_INSECURE_SKIP_VERIFY %q", skip)
+		}
+	}
+	if settings.Port == 0 {
+		settings.Port = defaultSMTPPort(settings.TLS)
+	}
+	return settings, true, settings.Validate()
+}
+
+func defaultSMTPPort(mode TLSMode) int {
+	switch mode {
+	case TLSModeImplicit:
+		return 465
+	case TLSModeNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+// Validate checks the settings are complete
+func (s SMTPSettings) Validate() error {
+	if s.Host == "" {
+		return fmt.Errorf("SMTP host is required")
+	}
+	if s.Port <= 0 || s.Port > 65535 {
+		return fmt.Errorf("invalid SMTP port %d", s.Port)
+	}
+	if _, err := mail.ParseAddress(s.From); err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", s.From, err)
+	}
+	switch s.TLS {
+	case TLSModeNone, TLSModeStartTLS, TLSModeImplicit:
+	default:
+		return fmt.Errorf("invalid SMTP TLS mode %q. Expected none, starttls or tls", s.TLS)
+	}
+	return nil
+}
+
+// Message is an email with a single body part
+type Message struct {
+	To          []string
+	Subject     string
+	ContentType string // e.g. text/html; charset=utf-8
+	Body        []byte
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type smtpMailer struct {
+	settings SMTPSettings
+	now      func() time.Time
+}
+
+func NewSMTPMailer(settings SMTPSettings) Mailer {
+	if settings.Timeout == 0 {
+		settings.Timeout = defaultSMTPTimeout
+	}
+	return &smtpMailer{settings: settings, now: time.Now}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("message has no recipients")
+	}
+	data, err := m.buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.settings.TLS == TLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server %s does not support STARTTLS", m.settings.Host)
+		}
+		if err := client.StartTLS(m.tlsConfig()); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if m.settings.Username != "" {
+		auth := smtp.PlainAuth("", m.settings.Username, m.settings.Password, m.settings.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate with the SMTP server: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(m.settings.From)
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start message data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
+
+func (m *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.settings.Host, strconv.Itoa(m.settings.Port))
+	dialer := &net.Dialer{Timeout: m.settings.Timeout}
+
+	var conn net.Conn
+	var err error
+	if m.settings.TLS == TLSModeImplicit {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: m.tlsConfig()}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+
+	deadline := time.Now().Add(m.settings.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	client, err := smtp.NewClient(conn, m.settings.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to greet SMTP server %s: %w", addr, err)
+	}
+	return client, nil
+}
+
+func (m *smtpMailer) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName:         m.settings.Host,
+		InsecureSkipVerify: m.settings.InsecureSkipVerify, //nolint:gosec // opt-in for test servers
+		MinVersion:         tls.VersionTLS12,
+	}
+}
+
+// buildMessage formats the message with its headers, the body is quoted-printable encoded
+func (m *smtpMailer) buildMessage(msg Message) ([]byte, error) {
+	from, err := mail.ParseAddress(m.settings.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", strings.Join(msg.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", m.now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", uuid.NewString(), domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", msg.ContentType},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write(msg.Body); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
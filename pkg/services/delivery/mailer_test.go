@@ -0,0 +1,168 @@
+package delivery
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// caughtMail is a message received by the SMTP catcher
+type caughtMail struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPCatcher runs a minimal plain text SMTP server accepting a single message
+func startSMTPCatcher(t *testing.T, extensions ...string) (int, <-chan caughtMail) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	caught := make(chan caughtMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP catcher")
+
+		var mail caughtMail
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO":
+				reply("250-localhost")
+				for _, extension := range extensions {
+					reply("250-" + extension)
+				}
+				reply("250 8BITMIME")
+			case "AUTH":
+				mail.auth = line
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL":
+				mail.from = line
+				reply("250 OK")
+			case "RCPT":
+				mail.to = append(mail.to, line)
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				mail.data = data.String()
+				reply("250 OK queued")
+			case "QUIT":
+				reply("221 Bye")
+				caught <- mail
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, caught
+}
+
+func TestSMTPMailer(t *testing.T) {
+	port, caught := startSMTPCatcher(t, "AUTH PLAIN")
+	mailer := NewSMTPMailer(SMTPSettings{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Username: "atlas",
+		Password: "secret",
+		From:     "Data Atlas <atlas@example.com>",
+		TLS:      TLSModeNone,
+	})
+
+	err := mailer.Send(context.Background(), Message{
+		To:          []string{"finance@example.com"},
+		Subject:     "Cost report: prod",
+		ContentType: "text/html; charset=utf-8",
+		Body:        []byte("<h1>Cost report: prod</h1><p>Total spend 1234.50 USD</p>"),
+	})
+	require.NoError(t, err)
+
+	select {
+	case mail := <-caught:
+		assert.True(t, strings.HasPrefix(mail.auth, "AUTH PLAIN "))
+		assert.Equal(t, "MAIL FROM:<atlas@example.com> BODY=8BITMIME", mail.from)
+		assert.Equal(t, []string{"RCPT TO:<finance@example.com>"}, mail.to)
+
+		headers, body, ok := strings.Cut(mail.data, "\r\n\r\n")
+		require.True(t, ok)
+		assert.Contains(t, headers, "From: \"Data Atlas\" <atlas@example.com>\r\n")
+		assert.Contains(t, headers, "To: finance@example.com\r\n")
+		assert.Contains(t, headers, "Subject: Cost report: prod\r\n")
+		assert.Contains(t, headers, "Content-Type: text/html; charset=utf-8\r\n")
+		assert.Contains(t, headers, "Message-ID: <")
+
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+		require.NoError(t, err)
+		assert.Contains(t, string(decoded), "<h1>Cost report: prod</h1>")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message caught")
+	}
+}
+
+func TestSMTPMailerRequiresStartTLS(t *testing.T) {
+	port, _ := startSMTPCatcher(t)
+	mailer := NewSMTPMailer(SMTPSettings{
+		Host: "127.0.0.1",
+		Port: port,
+		From: "atlas@example.com",
+		TLS:  TLSModeStartTLS,
+	})
+
+	err := mailer.Send(context.Background(), Message{To: []string{"finance@example.com"}, Subject: "report"})
+	assert.ErrorContains(t, err, "does not support STARTTLS")
+}
+
+func TestSMTPSettingsFromEnv(t *testing.T) {
+	t.Setenv("SMTP_HOST", "")
+	_, ok, err := SMTPSettingsFromEnv()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_FROM", "atlas@example.com")
+	t.Setenv("SMTP_TLS", "tls")
+	settings, ok, err := SMTPSettingsFromEnv()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, TLSModeImplicit, settings.TLS)
+	assert.Equal(t, 465, settings.Port)
+
+	t.Setenv("SMTP_TLS", "ssl")
+	_, _, err = SMTPSettingsFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/services/reporting"
+)
+
+// cadencePeriod returns the report period sent with the cadence
+func cadencePeriod(cadence domain.DeliveryCadence) (string, error) {
+	switch cadence {
+	case domain.DeliveryCadenceWeekly:
+		return reporting.PeriodWeek, nil
+	case domain.DeliveryCadenceMonthly:
+		return reporting.PeriodMonth, nil
+	case domain.DeliveryCadenceQuarterly:
+		return reporting.PeriodQuarter, nil
+	default:
+		return "", fmt.Errorf("invalid cadence %q. Expected weekly, monthly or quarterly", cadence)
+	}
+}
+
+// lastScheduledAt returns the latest time at or before now the cadence sends a report at: Mondays,
+// the first day of the month or the first day of the quarter, at sendHour UTC
+func lastScheduledAt(cadence domain.DeliveryCadence, now time.Time, sendHour int) (time.Time, error) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), sendHour, 0, 0, 0, time.UTC)
+
+	var scheduled time.Time
+	var previous func(time.Time) time.Time
+	switch cadence {
+	case domain.DeliveryCadenceWeekly:
+		scheduled = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		previous = func(t time.Time) time.Time { return t.AddDate(0, 0, -7) }
+	case domain.DeliveryCadenceMonthly:
+		scheduled = today.AddDate(0, 0, 1-today.Day())
+		previous = func(t time.Time) time.Time { return t.AddDate(0, -1, 0) }
+	case domain.DeliveryCadenceQuarterly:
+		scheduled = time.Date(today.Year(), ((today.Month()-1)/3)*3+1, 1, sendHour, 0, 0, 0, time.UTC)
+		previous = func(t time.Time) time.Time { return t.AddDate(0, -3, 0) }
+	default:
+		return time.Time{}, fmt.Errorf("invalid cadence %q", cadence)
+	}
+
+	if scheduled.After(now) {
+		scheduled = previous(scheduled)
+	}
+	return scheduled, nil
+}
+
+// isDue reports whether the subscription has not been sent since the scheduled time,
+// new subscriptions wait for the first scheduled time after they were created
+func isDue(subscription domain.ReportSubscription, scheduledAt time.Time) bool {
+	since := subscription.CreatedAt
+	if subscription.LastSentAt != nil {
+		since = *subscription.LastSentAt
+	}
+	return since.Before(scheduledAt)
+}
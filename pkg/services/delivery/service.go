@@ -0,0 +1,243 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/de-tools/data-atlas/pkg/services/render"
+	"github.com/de-tools/data-atlas/pkg/services/reporting"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+var (
+	ErrSubscriptionNotFound  = errors.New("report subscription not found")
+	ErrDeliveryNotConfigured = errors.New("email delivery is not configured")
+)
+
+// SubscriptionStore is the minimal interface required to keep report subscriptions
+type SubscriptionStore interface {
+	AddSubscription(ctx context.Context, subscription store.ReportSubscription) error
+	GetSubscription(ctx context.Context, id string) (*store.ReportSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]store.ReportSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+}
+
+// Settings contains configurable parameters of scheduled delivery
+type Settings struct {
+	// SendHour is the hour of the day in UTC scheduled reports are sent at (default: 7)
+	SendHour int
+}
+
+// DefaultSettings returns the default configuration of scheduled delivery
+func DefaultSettings() Settings {
+	return Settings{SendHour: 7}
+}
+
+// Service manages report subscriptions and sends their reports by email
+type Service interface {
+	Create(ctx context.Context, subscription domain.ReportSubscription) (domain.ReportSubscription, error)
+	List(ctx context.Context) ([]domain.ReportSubscription, error)
+	Delete(ctx context.Context, id string) error
+	// Send sends the report of the subscription now, regardless of its schedule
+	Send(ctx context.Context, id string) error
+	// SendDue sends the reports of every subscription whose scheduled time has passed since it was last sent
+	SendDue(ctx context.Context) error
+}
+
+type service struct {
+	store    SubscriptionStore
+	builder  reporting.Builder
+	mailer   Mailer
+	settings Settings
+	now      func() time.Time
+}
+
+// NewService creates the delivery service, reports cannot be sent when mailer is nil
+func NewService(store SubscriptionStore, builder reporting.Builder, mailer Mailer, settings Settings) Service {
+	return &service{
+		store:    store,
+		builder:  builder,
+		mailer:   mailer,
+		settings: settings,
+		now:      time.Now,
+	}
+}
+
+// ValidateSubscription checks the recipient, cadence and format of the subscription
+func ValidateSubscription(subscription domain.ReportSubscription) error {
+	if _, err := mail.ParseAddress(subscription.Recipient); err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", subscription.Recipient, err)
+	}
+	if _, err := cadencePeriod(subscription.Cadence); err != nil {
+		return err
+	}
+	format, err := render.ParseFormat(subscription.Format)
+	if err != nil {
+		return err
+	}
+	if format != render.FormatHTML && format != render.FormatMarkdown {
+		return fmt.Errorf("invalid format %q. Expected html or markdown", subscription.Format)
+	}
+	return nil
+}
+
+func (s *service) Create(
+	ctx context.Context,
+	subscription domain.ReportSubscription,
+) (domain.ReportSubscription, error) {
+	if subscription.Format == "" {
+		subscription.Format = string(render.FormatHTML)
+	}
+	if err := ValidateSubscription(subscription); err != nil {
+		return domain.ReportSubscription{}, err
+	}
+
+	subscription.ID = uuid.NewString()
+	subscription.CreatedAt = s.now().UTC()
+	subscription.LastSentAt = nil
+	if err := s.store.AddSubscription(ctx, adapters.MapReportSubscriptionDomainToStore(subscription)); err != nil {
+		return domain.ReportSubscription{}, err
+	}
+	return subscription, nil
+}
+
+func (s *service) List(ctx context.Context) ([]domain.ReportSubscription, error) {
+	subscriptions, err := s.store.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ReportSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		result = append(result, adapters.MapReportSubscriptionStoreToDomain(subscription))
+	}
+	return result, nil
+}
+
+func (s *service) Delete(ctx context.Context, id string) error {
+	err := s.store.DeleteSubscription(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSubscriptionNotFound
+	}
+	return err
+}
+
+func (s *service) Send(ctx context.Context, id string) error {
+	if s.mailer == nil {
+		return ErrDeliveryNotConfigured
+	}
+	subscription, err := s.store.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if subscription == nil {
+		return ErrSubscriptionNotFound
+	}
+	return s.send(ctx, adapters.MapReportSubscriptionStoreToDomain(*subscription), s.now())
+}
+
+func (s *service) SendDue(ctx context.Context) error {
+	if s.mailer == nil {
+		return ErrDeliveryNotConfigured
+	}
+	subscriptions, err := s.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list report subscriptions: %w", err)
+	}
+
+	now := s.now()
+	var errs []error
+	for _, subscription := range subscriptions {
+		scheduledAt, err := lastScheduledAt(subscription.Cadence, now, s.settings.SendHour)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", subscription.ID, err))
+			continue
+		}
+		if !isDue(subscription, scheduledAt) {
+			continue
+		}
+		if err := s.send(ctx, subscription, now); err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", subscription.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send builds the report of the period preceding now for the subscription, emails it and records the delivery
+func (s *service) send(ctx context.Context, subscription domain.ReportSubscription, now time.Time) error {
+	periodName, err := cadencePeriod(subscription.Cadence)
+	if err != nil {
+		return err
+	}
+	period, err := reporting.ParsePeriod(periodName, now)
+	if err != nil {
+		return err
+	}
+	format, err := render.ParseFormat(subscription.Format)
+	if err != nil {
+		return err
+	}
+
+	workspaces := make([]domain.Workspace, 0, len(subscription.Workspaces))
+	for _, name := range subscription.Workspaces {
+		workspaces = append(workspaces, domain.Workspace{Name: name})
+	}
+	report, err := s.builder.Build(ctx, period, workspaces)
+	if err != nil {
+		return fmt.Errorf("failed to build report: %w", err)
+	}
+
+	var body bytes.Buffer
+	if err := render.Report(&body, report, format); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	contentType := format.ContentType()
+	if format == render.FormatMarkdown {
+		// mail clients show text/markdown as an attachment
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	err = s.mailer.Send(ctx, Message{
+		To: []string{subscription.Recipient},
+		Subject: fmt.Sprintf("%s, %s to %s", report.Title, period.Start.Format("2006-01-02"),
+			period.End.AddDate(0, 0, -1).Format("2006-01-02")),
+		ContentType: contentType,
+		Body:        body.Bytes(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	zerolog.Ctx(ctx).Info().Str("subscription", subscription.ID).Str("recipient", subscription.Recipient).
+		Msg("report sent")
+
+	return s.store.MarkSent(ctx, subscription.ID, now.UTC())
+}
+
+// RunScheduler sends the due reports every interval until the context is cancelled
+func RunScheduler(ctx context.Context, service Service, interval time.Duration) {
+	logger := zerolog.Ctx(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := service.SendDue(ctx); err != nil {
+			logger.Error().Err(err).Msg("failed to send scheduled reports")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
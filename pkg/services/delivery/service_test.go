@@ -0,0 +1,242 @@
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSubscriptionStore struct {
+	mock.Mock
+}
+
+func (m *mockSubscriptionStore) AddSubscription(ctx context.Context, subscription store.ReportSubscription) error {
+	return m.Called(ctx, subscription).Error(0)
+}
+
+func (m *mockSubscriptionStore) GetSubscription(ctx context.Context, id string) (*store.ReportSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*store.ReportSubscription), args.Error(1)
+}
+
+func (m *mockSubscriptionStore) ListSubscriptions(ctx context.Context) ([]store.ReportSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]store.ReportSubscription), args.Error(1)
+}
+
+func (m *mockSubscriptionStore) DeleteSubscription(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockSubscriptionStore) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	return m.Called(ctx, id, sentAt).Error(0)
+}
+
+type mockReportBuilder struct {
+	mock.Mock
+}
+
+func (m *mockReportBuilder) Build(
+	ctx context.Context,
+	period domain.TimePeriod,
+	workspaces []domain.Workspace,
+) (domain.Report, error) {
+	args := m.Called(ctx, period, workspaces)
+	return args.Get(0).(domain.Report), args.Error(1)
+}
+
+type recordingMailer struct {
+	sent []Message
+	err  error
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestValidateSubscription(t *testing.T) {
+	valid := domain.ReportSubscription{
+		Recipient: "Finance <finance@example.com>",
+		Cadence:   domain.DeliveryCadenceWeekly,
+		Format:    "markdown",
+	}
+	assert.NoError(t, ValidateSubscription(valid))
+
+	for name, modify := range map[string]func(*domain.ReportSubscription){
+		"recipient": func(s *domain.ReportSubscription) { s.Recipient = "finance" },
+		"cadence":   func(s *domain.ReportSubscription) { s.Cadence = "hourly" },
+		"format":    func(s *domain.ReportSubscription) { s.Format = "csv" },
+	} {
+		invalid := valid
+		modify(&invalid)
+		assert.Error(t, ValidateSubscription(invalid), name)
+	}
+}
+
+func TestSendDue(t *testing.T) {
+	// Monday 2025-08-04 08:00 UTC, after the 07:00 send time
+	now := time.Date(2025, 8, 4, 8, 0, 0, 0, time.UTC)
+	lastWeek := domain.TimePeriod{
+		Start:    time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+		Duration: 7,
+	}
+	lastMonth := domain.TimePeriod{
+		Start:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		Duration: 31,
+	}
+	sentThisMonth := time.Date(2025, 8, 1, 7, 0, 5, 0, time.UTC)
+
+	subscriptionStore := new(mockSubscriptionStore)
+	subscriptionStore.On("ListSubscriptions", mock.Anything).Return([]store.ReportSubscription{
+		{
+			ID: "weekly-bi", Recipient: "bi-lead@example.com", Workspaces: []string{"bi"}, Cadence: "weekly",
+			Format: "markdown", CreatedAt: now.AddDate(0, -1, 0),
+		},
+		// already sent on the first of the month
+		{
+			ID: "monthly-finance", Recipient: "finance@example.com", Cadence: "monthly", Format: "html",
+			CreatedAt: now.AddDate(0, -2, 0), LastSentAt: &sentThisMonth,
+		},
+		// created after this week's send time, waits for next Monday
+		{
+			ID: "weekly-new", Recipient: "new@example.com", Cadence: "weekly", Format: "html",
+			CreatedAt: now.Add(-30 * time.Minute),
+		},
+		// created before the send time of this month
+		{
+			ID: "monthly-cfo", Recipient: "cfo@example.com", Cadence: "monthly", Format: "html",
+			CreatedAt: lastMonth.Start,
+		},
+	}, nil)
+	subscriptionStore.On("MarkSent", mock.Anything, "weekly-bi", now).Return(nil)
+	subscriptionStore.On("MarkSent", mock.Anything, "monthly-cfo", now).Return(nil)
+
+	builder := new(mockReportBuilder)
+	builder.On("Build", mock.Anything, lastWeek, []domain.Workspace{{Name: "bi"}}).
+		Return(domain.Report{Title: "Cost report: bi", Period: lastWeek, Currency: "USD"}, nil)
+	builder.On("Build", mock.Anything, lastMonth, []domain.Workspace{}).
+		Return(domain.Report{Title: "Cost report: all workspaces", Period: lastMonth, Currency: "USD"}, nil)
+
+	mailer := &recordingMailer{}
+	svc := NewService(subscriptionStore, builder, mailer, DefaultSettings()).(*service)
+	svc.now = func() time.Time { return now }
+
+	require.NoError(t, svc.SendDue(context.Background()))
+
+	require.Len(t, mailer.sent, 2)
+	assert.Equal(t, []string{"bi-lead@example.com"}, mailer.sent[0].To)
+	assert.Equal(t, "Cost report: bi, 2025-07-28 to 2025-08-03", mailer.sent[0].Subject)
+	assert.Equal(t, "text/plain; charset=utf-8", mailer.sent[0].ContentType)
+	assert.Contains(t, string(mailer.sent[0].Body), "# Cost report: bi")
+	assert.Equal(t, []string{"cfo@example.com"}, mailer.sent[1].To)
+	assert.Equal(t, "text/html; charset=utf-8", mailer.sent[1].ContentType)
+
+	subscriptionStore.AssertExpectations(t)
+	builder.AssertExpectations(t)
+}
+
+func TestSendDueReportsFailures(t *testing.T) {
+	now := time.Date(2025, 8, 4, 8, 0, 0, 0, time.UTC)
+
+	subscriptionStore := new(mockSubscriptionStore)
+	subscriptionStore.On("ListSubscriptions", mock.Anything).Return([]store.ReportSubscription{
+		{ID: "s1", Recipient: "a@example.com", Cadence: "weekly", Format: "html", CreatedAt: now.AddDate(0, 0, -30)},
+	}, nil)
+	builder := new(mockReportBuilder)
+	builder.On("Build", mock.Anything, mock.Anything, mock.Anything).Return(domain.Report{}, nil)
+
+	svc := NewService(subscriptionStore, builder, &recordingMailer{err: fmt.Errorf("connection refused")},
+		DefaultSettings()).(*service)
+	svc.now = func() time.Time { return now }
+
+	err := svc.SendDue(context.Background())
+	assert.ErrorContains(t, err, "subscription s1: failed to send report: connection refused")
+	subscriptionStore.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSend(t *testing.T) {
+	subscriptionStore := new(mockSubscriptionStore)
+	subscriptionStore.On("GetSubscription", mock.Anything, "missing").Return(nil, nil)
+	subscriptionStore.On("DeleteSubscription", mock.Anything, "missing").
+		Return(fmt.Errorf("report subscription missing: %w", sql.ErrNoRows))
+
+	svc := NewService(subscriptionStore, new(mockReportBuilder), &recordingMailer{}, DefaultSettings())
+	assert.ErrorIs(t, svc.Send(context.Background(), "missing"), ErrSubscriptionNotFound)
+	assert.ErrorIs(t, svc.Delete(context.Background(), "missing"), ErrSubscriptionNotFound)
+
+	unconfigured := NewService(subscriptionStore, new(mockReportBuilder), nil, DefaultSettings())
+	assert.ErrorIs(t, unconfigured.Send(context.Background(), "missing"), ErrDeliveryNotConfigured)
+}
+
+func TestLastScheduledAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		cadence  domain.DeliveryCadence
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "weekly on Monday after the send time",
+			cadence:  domain.DeliveryCadenceWeekly,
+			now:      time.Date(2025, 8, 4, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 8, 4, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly on Monday before the send time",
+			cadence:  domain.DeliveryCadenceWeekly,
+			now:      time.Date(2025, 8, 4, 6, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 7, 28, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly on Sunday",
+			cadence:  domain.DeliveryCadenceWeekly,
+			now:      time.Date(2025, 8, 10, 23, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 8, 4, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly",
+			cadence:  domain.DeliveryCadenceMonthly,
+			now:      time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 8, 1, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "monthly before the send time",
+			cadence:  domain.DeliveryCadenceMonthly,
+			now:      time.Date(2025, 8, 1, 5, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 7, 1, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "quarterly",
+			cadence:  domain.DeliveryCadenceQuarterly,
+			now:      time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 7, 1, 7, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduled, err := lastScheduledAt(tt.cadence, tt.now, 7)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, scheduled)
+		})
+	}
+
+	_, err := lastScheduledAt("daily", time.Now(), 7)
+	assert.Error(t, err)
+}
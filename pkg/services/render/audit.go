@@ -0,0 +1,237 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// AuditReport writes the audit report in the format. JSON matches the API response, HTML and Markdown
+// group the findings by severity and resource, CSV has a row per finding.
+func AuditReport(w io.Writer, report domain.AuditReport, format Format) error {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(adapters.MapAuditReportDomainToApi(report))
+	case FormatHTML:
+		return htmlTemplates.ExecuteTemplate(w, "audit.html.tmpl", newAuditView(report))
+	case FormatMarkdown:
+		return markdownTemplates.ExecuteTemplate(w, "audit.md.tmpl", newAuditView(report))
+	case FormatCSV:
+		return auditCSV(w, report)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type auditView struct {
+	Workspace    string
+	ResourceType string
+	Start        string
+	End          string
+	Days         int
+	RunID        string
+	Summary      []summaryRow
+	Savings      string
+	Total        int
+	Severities   []severityGroup
+	Rules        []domain.AuditRuleResult
+	Resolved     int
+	Suppressed   int
+}
+
+type summaryRow struct {
+	Name  string
+	Value string
+}
+
+type severityGroup struct {
+	Severity  string
+	Count     int
+	Resources []resourceGroup
+}
+
+type resourceGroup struct {
+	Resource string
+	Findings []findingView
+}
+
+type findingView struct {
+	ID             string
+	Issue          string
+	Description    string
+	Recommendation string
+	Status         string
+	Savings        string
+}
+
+// summaryKeysOmitted are summary entries too large or too technical for a shared report
+var summaryKeysOmitted = []string{"settings", "total_addressable_savings", "savings_currency"}
+
+func newAuditView(report domain.AuditReport) auditView {
+	view := auditView{
+		Workspace:    report.Workspace,
+		ResourceType: report.ResourceType,
+		Start:        report.Period.Start.Format("2006-01-02"),
+		End:          report.Period.End.Format("2006-01-02"),
+		Days:         report.Period.Duration,
+		RunID:        report.RunID,
+		Total:        len(report.Findings),
+		Rules:        report.Rules,
+		Resolved:     len(report.Resolved),
+		Suppressed:   len(report.Suppressed),
+	}
+
+	keys := make([]string, 0, len(report.Summary))
+	for key := range report.Summary {
+		if !slices.Contains(summaryKeysOmitted, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		view.Summary = append(view.Summary, summaryRow{Name: humanize(key), Value: formatValue(report.Summary[key])})
+	}
+	if total, ok := report.Summary["total_addressable_savings"].(float64); ok {
+		currency, _ := report.Summary["savings_currency"].(string)
+		view.Savings = strings.TrimSpace(fmt.Sprintf("%.2f %s", total, currency))
+	}
+
+	for _, severity := range []domain.Severity{domain.SeverityHigh, domain.SeverityMedium, domain.SeverityLow} {
+		group := severityGroup{Severity: string(adapters.MapSeverityDomainToApi(severity))}
+		indexByResource := map[string]int{}
+		for _, finding := range sortedFindings(report.Findings) {
+			if finding.Severity != severity {
+				continue
+			}
+			resource := resourceName(finding.Resource)
+			i, ok := indexByResource[resource]
+			if !ok {
+				i = len(group.Resources)
+				indexByResource[resource] = i
+				group.Resources = append(group.Resources, resourceGroup{Resource: resource})
+			}
+			group.Resources[i].Findings = append(group.Resources[i].Findings, newFindingView(finding))
+			group.Count++
+		}
+		if group.Count > 0 {
+			view.Severities = append(view.Severities, group)
+		}
+	}
+
+	return view
+}
+
+func newFindingView(finding domain.AuditFinding) findingView {
+	view := findingView{
+		ID:             finding.Id,
+		Issue:          finding.Issue,
+		Description:    finding.Description,
+		Recommendation: finding.Recommendation,
+		Status:         string(finding.Status),
+	}
+	if savings := finding.EstimatedMonthlySavings; savings.Amount > 0 {
+		view.Savings = strings.TrimSpace(fmt.Sprintf("%.2f %s", savings.Amount, savings.Currency))
+		if savings.Confidence != "" {
+			view.Savings += fmt.Sprintf(" (%s confidence)", savings.Confidence)
+		}
+	}
+	return view
+}
+
+// sortedFindings orders findings by severity, resource and issue, highest severity first
+func sortedFindings(findings []domain.AuditFinding) []domain.AuditFinding {
+	sorted := slices.Clone(findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Severity != sorted[j].Severity {
+			return sorted[i].Severity > sorted[j].Severity
+		}
+		if ri, rj := resourceName(sorted[i].Resource), resourceName(sorted[j].Resource); ri != rj {
+			return ri < rj
+		}
+		return sorted[i].Issue < sorted[j].Issue
+	})
+	return sorted
+}
+
+func resourceName(resource domain.ResourceDef) string {
+	if resource.Service == "" {
+		return resource.Name
+	}
+	return resource.Service + " " + resource.Name
+}
+
+// humanize turns a summary key like total_findings into Total findings
+func humanize(key string) string {
+	text := strings.ReplaceAll(key, "_", " ")
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', 2, 32)
+	case time.Time:
+		return v.Format("2006-01-02")
+	case []string:
+		return strings.Join(v, ", ")
+	case map[string]int:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprintf("%s: %d", key, v[key]))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var auditCSVHeader = []string{
+	"severity", "service", "resource", "issue", "status", "description", "recommendation",
+	"estimated_monthly_savings", "currency", "confidence", "finding_id",
+}
+
+func auditCSV(w io.Writer, report domain.AuditReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	for _, finding := range sortedFindings(report.Findings) {
+		savings := finding.EstimatedMonthlySavings
+		if err := writer.Write([]string{
+			string(adapters.MapSeverityDomainToApi(finding.Severity)),
+			finding.Resource.Service,
+			finding.Resource.Name,
+			finding.Issue,
+			string(finding.Status),
+			finding.Description,
+			finding.Recommendation,
+			strconv.FormatFloat(savings.Amount, 'f', 2, 64),
+			savings.Currency,
+			string(savings.Confidence),
+			finding.Id,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuditReport() domain.AuditReport {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	return domain.AuditReport{
+		Workspace:    "production",
+		ResourceType: "warehouse",
+		Period:       domain.TimePeriod{Start: start, End: start.AddDate(0, 0, 30), Duration: 30},
+		Summary: map[string]any{
+			"total_findings":            2,
+			"total_addressable_savings": 120.5,
+			"savings_currency":          "USD",
+			"settings":                  map[string]any{"max_runtime_hours": 10},
+		},
+		Findings: []domain.AuditFinding{
+			{
+				Id:             "f-low",
+				Resource:       domain.ResourceDef{Service: "warehouse", Name: "bi"},
+				Issue:          "missing_tags",
+				Description:    "Warehouse has no tags",
+				Recommendation: "Tag the warehouse | owner",
+				Severity:       domain.SeverityLow,
+			},
+			{
+				Id:             "f-high",
+				Resource:       domain.ResourceDef{Service: "warehouse", Name: "etl"},
+				Issue:          "auto_stop_disabled",
+				Description:    "Warehouse <etl> never stops",
+				Recommendation: "Enable auto stop",
+				Severity:       domain.SeverityHigh,
+				Status:         domain.FindingStatusNew,
+				EstimatedMonthlySavings: domain.SavingsEstimate{
+					Amount: 120.5, Currency: "USD", Confidence: domain.SavingsConfidenceLow,
+				},
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{
+		"":         FormatJSON,
+		"json":     FormatJSON,
+		"HTML":     FormatHTML,
+		"markdown": FormatMarkdown,
+		"md":       FormatMarkdown,
+		"csv":      FormatCSV,
+	} {
+		format, err := ParseFormat(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, format, name)
+	}
+
+	_, err := ParseFormat("pdf")
+	assert.Error(t, err)
+}
+
+func TestAuditReport(t *testing.T) {
+	report := testAuditReport()
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, AuditReport(&buf, report, FormatHTML))
+		out := buf.String()
+
+		assert.Contains(t, out, "<h1>warehouse audit: production</h1>")
+		assert.Contains(t, out, "Warehouse &lt;etl&gt; never stops")
+		assert.Contains(t, out, "120.50 USD (low confidence)")
+		assert.Contains(t, out, "Enable auto stop")
+		assert.NotContains(t, out, "max_runtime_hours")
+		assert.Less(t, strings.Index(out, "high severity"), strings.Index(out, "low severity"))
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, AuditReport(&buf, report, FormatMarkdown))
+		out := buf.String()
+
+		assert.Contains(t, out, "| Estimated monthly savings | 120.50 USD |")
+		assert.Contains(t, out, "| Total findings | 2 |")
+		assert.Contains(t, out, "#### warehouse etl")
+		assert.Contains(t, out, "  - Recommendation: Tag the warehouse | owner")
+		assert.Less(t, strings.Index(out, "### high severity"), strings.Index(out, "### low severity"))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, AuditReport(&buf, report, FormatCSV))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, auditCSVHeader, rows[0])
+		assert.Equal(t, []string{
+			"high", "warehouse", "etl", "auto_stop_disabled", "new", "Warehouse <etl> never stops",
+			"Enable auto stop", "120.50", "USD", "low", "f-high",
+		}, rows[1])
+		assert.Equal(t, "f-low", rows[2][10])
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		report := testAuditReport()
+		report.Findings = nil
+
+		var buf bytes.Buffer
+		require.NoError(t, AuditReport(&buf, report, FormatMarkdown))
+		assert.Contains(t, buf.String(), "No findings.")
+	})
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format is an output format of rendered reports
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	FormatCSV      Format = "csv"
+)
+
+// ParseFormat returns the format named by s, an empty name selects JSON
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatHTML, FormatMarkdown, FormatCSV:
+		return f, nil
+	case "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q. Expected json, html, markdown or csv", s)
+	}
+}
+
+// ContentType returns the HTTP content type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
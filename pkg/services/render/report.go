@@ -0,0 +1,94 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// Report writes the report in the format, sections are rendered in order with their summary, details and notes.
+// CSV is not supported as sections have different columns.
+func Report(w io.Writer, report domain.Report, format Format) error {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(adapters.MapReportDomainToApi(report))
+	case FormatHTML:
+		return htmlTemplates.ExecuteTemplate(w, "report.html.tmpl", newReportView(report))
+	case FormatMarkdown:
+		return markdownTemplates.ExecuteTemplate(w, "report.md.tmpl", newReportView(report))
+	default:
+		return fmt.Errorf("unsupported report format %q. Expected json, html or markdown", format)
+	}
+}
+
+type reportView struct {
+	Title    string
+	Start    string
+	End      string
+	Days     int
+	Total    string
+	Sections []sectionView
+}
+
+type sectionView struct {
+	Title   string
+	Summary []summaryRow
+	Details []detailView
+	Notes   []summaryRow
+}
+
+type detailView struct {
+	Name        string
+	Value       string
+	Description string
+}
+
+func newReportView(report domain.Report) reportView {
+	view := reportView{
+		Title: report.Title,
+		Start: report.Period.Start.Format("2006-01-02"),
+		// periods end exclusively, the report shows the last day included
+		End:   report.Period.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days:  report.Period.Duration,
+		Total: withUnit(formatValue(report.TotalAmount), report.Currency),
+	}
+	for _, section := range report.Sections {
+		s := sectionView{
+			Title:   section.Title,
+			Summary: summaryRows(section.Summary),
+			Notes:   summaryRows(section.Metadata),
+		}
+		for _, detail := range section.Details {
+			s.Details = append(s.Details, detailView{
+				Name:        detail.Name,
+				Value:       withUnit(formatValue(detail.Value), detail.Unit),
+				Description: detail.Description,
+			})
+		}
+		view.Sections = append(view.Sections, s)
+	}
+	return view
+}
+
+func summaryRows(values map[string]interface{}) []summaryRow {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([]summaryRow, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, summaryRow{Name: humanize(key), Value: formatValue(values[key])})
+	}
+	return rows
+}
+
+func withUnit(value, unit string) string {
+	return strings.TrimSpace(value + " " + unit)
+}
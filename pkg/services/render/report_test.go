@@ -0,0 +1,77 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() domain.Report {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	return domain.Report{
+		Title:       "Cost report: production",
+		Period:      domain.TimePeriod{Start: start, End: start.AddDate(0, 1, 0), Duration: 31},
+		TotalAmount: 1234.5,
+		Currency:    "USD",
+		Sections: []domain.ReportSection{
+			{
+				Title:   "Spend by resource type",
+				Summary: map[string]interface{}{"resource_types": 1},
+				Details: []domain.ReportDetail{
+					{Name: "warehouse", Value: 1234.5, Unit: "USD", Description: "100.0% of total | all"},
+				},
+			},
+			{
+				Title:    "Forecast",
+				Summary:  map[string]interface{}{"month_end": 1500.0},
+				Metadata: map[string]interface{}{"as_of": start.AddDate(0, 1, 0)},
+			},
+		},
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := testReport()
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Report(&buf, report, FormatMarkdown))
+		out := buf.String()
+
+		assert.Contains(t, out, "# Cost report: production")
+		assert.Contains(t, out, "Period 2025-07-01 to 2025-07-31 (31 days), total spend **1234.50 USD**")
+		assert.Contains(t, out, "| warehouse | 1234.50 USD | 100.0% of total \\| all |")
+		assert.Contains(t, out, "| Month end | 1500.00 |")
+		assert.Contains(t, out, "_As of: 2025-08-01_")
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Report(&buf, report, FormatHTML))
+		out := buf.String()
+
+		assert.Contains(t, out, "<h2>Spend by resource type</h2>")
+		assert.Contains(t, out, `<td class="value">1234.50 USD</td>`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, Report(&buf, report, FormatJSON))
+
+		var decoded api.Report
+		require.NoError(t, json.NewDecoder(&buf).Decode(&decoded))
+		assert.Equal(t, 1234.5, decoded.TotalAmount)
+		require.Len(t, decoded.Sections, 2)
+		assert.Equal(t, "warehouse", decoded.Sections[0].Details[0].Name)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		assert.Error(t, Report(&bytes.Buffer{}, report, FormatCSV))
+	})
+}
@@ -0,0 +1,25 @@
+package render
+
+import (
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.New("").ParseFS(templateFS, "templates/*.html.tmpl"))
+
+	markdownTemplates = texttemplate.Must(texttemplate.New("").Funcs(texttemplate.FuncMap{
+		"cell": markdownCell,
+	}).ParseFS(templateFS, "templates/*.md.tmpl"))
+)
+
+// markdownCell keeps a value on one line of a Markdown table
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
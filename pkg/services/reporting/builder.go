@@ -0,0 +1,578 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/adapters"
+	"github.com/de-tools/data-atlas/pkg/models/api"
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/services/account"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/rs/zerolog"
+)
+
+// Titles of the report sections
+const (
+	SectionSpend         = "Total spend"
+	SectionResourceTypes = "Spend by resource type"
+	SectionTopMovers     = "Top movers"
+	SectionFindings      = "Open audit findings"
+	SectionBudgets       = "Budget status"
+	SectionForecast      = "Forecast"
+)
+
+// Budget statuses of the budget section
+const (
+	BudgetStatusOnTrack     = "on_track"
+	BudgetStatusAtRisk      = "at_risk"  // the month is projected to end above the limit
+	BudgetStatusExceeded    = "exceeded" // the spend of the month is already above the limit
+	BudgetStatusNoLimit     = "no_limit"
+	BudgetStatusNotMeasured = "not_measured" // tag scoped budgets or workspaces without a forecast
+)
+
+// Settings contains configurable parameters of the cost report
+type Settings struct {
+	// TopMovers is the number of resources with the largest cost change listed (default: 10)
+	TopMovers int
+	// TopFindings is the number of open audit findings listed, largest savings first (default: 10)
+	TopFindings int
+	// Forecast configures the month and quarter end forecasts
+	Forecast workspace.ForecastSettings
+}
+
+// DefaultSettings returns the default configuration of the cost report
+func DefaultSettings() Settings {
+	return Settings{
+		TopMovers:   10,
+		TopFindings: 10,
+		Forecast:    workspace.DefaultForecastSettings(),
+	}
+}
+
+// Builder assembles periodic cost reports of one workspace or across workspaces
+type Builder interface {
+	// Build reports on the period for the workspaces, every workspace of the account when none are given
+	Build(ctx context.Context, period domain.TimePeriod, workspaces []domain.Workspace) (domain.Report, error)
+}
+
+type builder struct {
+	explorer     account.Explorer
+	auditHistory workspace.AuditHistory
+	settings     Settings
+}
+
+// NewBuilder creates a report builder, open audit findings are left out when auditHistory is nil
+func NewBuilder(explorer account.Explorer, auditHistory workspace.AuditHistory, settings Settings) Builder {
+	return &builder{
+		explorer:     explorer,
+		auditHistory: auditHistory,
+		settings:     settings,
+	}
+}
+
+// workspaceFigures holds what the report needs of a single workspace, optional sources are nil when unavailable
+type workspaceFigures struct {
+	workspace  domain.Workspace
+	byType     domain.CostComparisonReport
+	byResource domain.CostComparisonReport
+	forecast   *domain.CostForecast
+	budgets    *domain.BudgetControls
+	findings   []domain.AuditFinding
+	audited    bool
+}
+
+func (b *builder) Build(
+	ctx context.Context,
+	period domain.TimePeriod,
+	workspaces []domain.Workspace,
+) (domain.Report, error) {
+	if !period.Start.Before(period.End) {
+		return domain.Report{}, fmt.Errorf("invalid report period: start (%s) must be before end (%s)",
+			period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"))
+	}
+
+	allWorkspaces := len(workspaces) == 0
+	if allWorkspaces {
+		var err error
+		workspaces, err = b.explorer.ListWorkspaces(ctx)
+		if err != nil {
+			return domain.Report{}, fmt.Errorf("failed to list workspaces: %w", err)
+		}
+		if len(workspaces) == 0 {
+			return domain.Report{}, fmt.Errorf("no workspaces to report on")
+		}
+	}
+
+	previous := domain.TimePeriod{
+		Start:    period.Start.Add(-period.End.Sub(period.Start)),
+		End:      period.Start,
+		Duration: period.Duration,
+	}
+
+	figures := make([]workspaceFigures, 0, len(workspaces))
+	for _, ws := range workspaces {
+		f, err := b.collect(ctx, ws, period, previous)
+		if err != nil {
+			return domain.Report{}, fmt.Errorf("failed to collect cost of workspace %s: %w", ws.Name, err)
+		}
+		figures = append(figures, f)
+	}
+
+	report := domain.Report{
+		Title:  reportTitle(workspaces, allWorkspaces),
+		Period: period,
+	}
+	for _, f := range figures {
+		report.TotalAmount += f.byType.TotalCost
+		if report.Currency == "" {
+			report.Currency = f.byType.Currency
+		}
+	}
+
+	report.Sections = []domain.ReportSection{
+		spendSection(figures, previous, report.Currency),
+		resourceTypeSection(figures, report.TotalAmount, report.Currency),
+		topMoversSection(figures, b.settings.TopMovers, report.Currency),
+	}
+	if b.auditHistory != nil {
+		report.Sections = append(report.Sections, findingsSection(figures, b.settings.TopFindings))
+	}
+	report.Sections = append(report.Sections,
+		budgetSection(figures, report.Currency),
+		forecastSection(figures, period.End, b.settings.Forecast, report.Currency),
+	)
+
+	return report, nil
+}
+
+// collect reads the cost of the workspace in the period and the previous one, the forecast as of the period end,
+// the budgets and the findings open at the period end
+func (b *builder) collect(
+	ctx context.Context,
+	ws domain.Workspace,
+	period, previous domain.TimePeriod,
+) (workspaceFigures, error) {
+	logger := zerolog.Ctx(ctx).With().Str("workspace", ws.Name).Logger()
+	f := workspaceFigures{workspace: ws}
+
+	analyzer, err := b.explorer.GetWorkspaceCostAnalyzer(ctx, ws)
+	if err != nil {
+		return f, err
+	}
+	f.byType, err = analyzer.CompareCost(ctx, domain.CostDimensionResourceType, period, previous, nil)
+	if err != nil {
+		return f, err
+	}
+	f.byResource, err = analyzer.CompareCost(ctx, domain.CostDimensionResourceID, period, previous, nil)
+	if err != nil {
+		return f, err
+	}
+
+	// the month and quarter end estimates do not depend on the horizon
+	forecast, err := workspace.GetCostForecast(ctx, ws, period.End, 1, "", analyzer, b.settings.Forecast)
+	if err != nil {
+		logger.Warn().Err(err).Msg("forecast unavailable, leaving it out of the report")
+	} else if len(forecast.Forecasts) > 0 {
+		f.forecast = &forecast.Forecasts[0]
+	}
+
+	if explorer, err := b.explorer.GetWorkspaceExplorer(ctx, ws); err != nil {
+		logger.Warn().Err(err).Msg("workspace explorer unavailable, leaving budgets out of the report")
+	} else if f.budgets, err = explorer.GetBudgetControls(ctx); err != nil {
+		logger.Warn().Err(err).Msg("budgets unavailable, leaving them out of the report")
+	}
+
+	if b.auditHistory != nil {
+		f.findings, err = b.openFindings(ctx, ws, period.End)
+		if err != nil {
+			logger.Warn().Err(err).Msg("audit history unavailable, leaving findings out of the report")
+		} else {
+			f.audited = true
+		}
+	}
+
+	return f, nil
+}
+
+// openFindings returns the findings of the latest audit run of every scope recorded before asOf
+func (b *builder) openFindings(
+	ctx context.Context,
+	ws domain.Workspace,
+	asOf time.Time,
+) ([]domain.AuditFinding, error) {
+	runs, err := b.auditHistory.List(ctx, domain.AuditHistoryFilter{Workspace: ws.Name, To: &asOf})
+	if err != nil {
+		return nil, err
+	}
+
+	// runs are ordered by creation, latest first
+	scopes := make(map[string]bool)
+	seen := make(map[string]bool)
+	var findings []domain.AuditFinding
+	for _, run := range runs {
+		if scopes[run.Scope] {
+			continue
+		}
+		scopes[run.Scope] = true
+		for _, finding := range run.Report.Findings {
+			if seen[finding.Id] {
+				continue
+			}
+			seen[finding.Id] = true
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+func reportTitle(workspaces []domain.Workspace, allWorkspaces bool) string {
+	switch {
+	case allWorkspaces:
+		return "Cost report: all workspaces"
+	case len(workspaces) == 1:
+		return "Cost report: " + workspaces[0].Name
+	default:
+		names := make([]string, 0, len(workspaces))
+		for _, ws := range workspaces {
+			names = append(names, ws.Name)
+		}
+		return "Cost report: " + strings.Join(names, ", ")
+	}
+}
+
+func spendSection(figures []workspaceFigures, previous domain.TimePeriod, currency string) domain.ReportSection {
+	total, previousTotal := 0.0, 0.0
+	for _, f := range figures {
+		total += f.byType.TotalCost
+		previousTotal += f.byType.BaselineTotalCost
+	}
+
+	section := domain.ReportSection{
+		Title: SectionSpend,
+		Summary: map[string]interface{}{
+			"total":          total,
+			"previous_total": previousTotal,
+			"change":         total - previousTotal,
+			"currency":       currency,
+		},
+		Metadata: map[string]interface{}{
+			"previous_period_start": previous.Start,
+			"previous_period_end":   previous.End,
+		},
+	}
+	if change := domain.DeltaPercent(total, previousTotal); change != nil {
+		section.Summary["change_percent"] = *change
+	}
+
+	if len(figures) > 1 {
+		sorted := append([]workspaceFigures(nil), figures...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].byType.TotalCost > sorted[j].byType.TotalCost })
+		for _, f := range sorted {
+			section.Details = append(section.Details, domain.ReportDetail{
+				Name:        f.workspace.Name,
+				Value:       f.byType.TotalCost,
+				Unit:        currency,
+				Description: describeChange(f.byType.BaselineTotalCost, f.byType.DeltaPercent),
+			})
+		}
+	}
+	return section
+}
+
+func resourceTypeSection(figures []workspaceFigures, total float64, currency string) domain.ReportSection {
+	costs := make(map[string]*domain.CostComparison)
+	var keys []string
+	for _, f := range figures {
+		for _, item := range f.byType.Items {
+			c, ok := costs[item.Key]
+			if !ok {
+				c = &domain.CostComparison{Key: item.Key}
+				costs[item.Key] = c
+				keys = append(keys, item.Key)
+			}
+			c.Cost += item.Cost
+			c.BaselineCost += item.BaselineCost
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return costs[keys[i]].Cost > costs[keys[j]].Cost })
+
+	section := domain.ReportSection{
+		Title:   SectionResourceTypes,
+		Summary: map[string]interface{}{"resource_types": len(keys)},
+	}
+	for _, key := range keys {
+		c := costs[key]
+		description := describeChange(c.BaselineCost, domain.DeltaPercent(c.Cost, c.BaselineCost))
+		if total > 0 {
+			description = fmt.Sprintf("%.1f%% of total, %s", c.Cost/total*100, description)
+		}
+		section.Details = append(section.Details, domain.ReportDetail{
+			Name:        key,
+			Value:       c.Cost,
+			Unit:        currency,
+			Description: description,
+		})
+	}
+	return section
+}
+
+func topMoversSection(figures []workspaceFigures, n int, currency string) domain.ReportSection {
+	type mover struct {
+		workspace string
+		item      domain.CostComparison
+	}
+	var movers []mover
+	for _, f := range figures {
+		for _, item := range f.byResource.Items {
+			if item.Delta != 0 {
+				movers = append(movers, mover{workspace: f.workspace.Name, item: item})
+			}
+		}
+	}
+	sort.SliceStable(movers, func(i, j int) bool {
+		return math.Abs(movers[i].item.Delta) > math.Abs(movers[j].item.Delta)
+	})
+
+	section := domain.ReportSection{
+		Title:   SectionTopMovers,
+		Summary: map[string]interface{}{"changed_resources": len(movers)},
+	}
+	if len(movers) > n {
+		movers = movers[:n]
+	}
+	for _, m := range movers {
+		name := m.item.Key
+		if len(figures) > 1 {
+			name = m.workspace + " / " + name
+		}
+		section.Details = append(section.Details, domain.ReportDetail{
+			Name:  name,
+			Value: m.item.Delta,
+			Unit:  currency,
+			Description: fmt.Sprintf("%s %s, %.2f to %.2f %s", m.item.ResourceType, m.item.Status,
+				m.item.BaselineCost, m.item.Cost, currency),
+		})
+	}
+	return section
+}
+
+func findingsSection(figures []workspaceFigures, n int) domain.ReportSection {
+	type finding struct {
+		workspace string
+		domain.AuditFinding
+	}
+	var findings []finding
+	var unaudited []string
+	for _, f := range figures {
+		if !f.audited {
+			unaudited = append(unaudited, f.workspace.Name)
+			continue
+		}
+		for _, af := range f.findings {
+			findings = append(findings, finding{workspace: f.workspace.Name, AuditFinding: af})
+		}
+	}
+
+	// savings of a resource are not additive across its findings, the largest estimate counts
+	bySeverity := map[string]int{}
+	largest := map[string]float64{}
+	currency := ""
+	for _, f := range findings {
+		bySeverity[string(adapters.MapSeverityDomainToApi(f.Severity))]++
+		key := f.workspace + "/" + f.Resource.Service + "/" + f.Resource.Name
+		largest[key] = math.Max(largest[key], f.EstimatedMonthlySavings.Amount)
+		if currency == "" {
+			currency = f.EstimatedMonthlySavings.Currency
+		}
+	}
+	savings := 0.0
+	for _, amount := range largest {
+		savings += amount
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].EstimatedMonthlySavings.Amount != findings[j].EstimatedMonthlySavings.Amount {
+			return findings[i].EstimatedMonthlySavings.Amount > findings[j].EstimatedMonthlySavings.Amount
+		}
+		return findings[i].Severity > findings[j].Severity
+	})
+
+	section := domain.ReportSection{
+		Title: SectionFindings,
+		Summary: map[string]interface{}{
+			"open_findings":             len(findings),
+			"high":                      bySeverity[string(api.SeverityHigh)],
+			"medium":                    bySeverity[string(api.SeverityMedium)],
+			"low":                       bySeverity[string(api.SeverityLow)],
+			"estimated_monthly_savings": savings,
+		},
+	}
+	if len(unaudited) > 0 {
+		section.Metadata = map[string]interface{}{"unavailable_workspaces": unaudited}
+	}
+	if len(findings) > n {
+		findings = findings[:n]
+	}
+	for _, f := range findings {
+		name := strings.TrimSpace(f.Resource.Service + " " + f.Resource.Name)
+		if len(figures) > 1 {
+			name = f.workspace + " / " + name
+		}
+		section.Details = append(section.Details, domain.ReportDetail{
+			Name:  name,
+			Value: f.EstimatedMonthlySavings.Amount,
+			Unit:  currency + "/month",
+			Description: fmt.Sprintf("%s severity %s: %s", adapters.MapSeverityDomainToApi(f.Severity), f.Issue,
+				f.Recommendation),
+		})
+	}
+	return section
+}
+
+func budgetSection(figures []workspaceFigures, currency string) domain.ReportSection {
+	type budgetSpend struct {
+		budget    domain.Budget
+		actual    float64
+		projected float64
+		measured  bool
+	}
+	spends := make(map[string]*budgetSpend)
+	var ids []string
+	var unavailable []string
+	for _, f := range figures {
+		if f.budgets == nil {
+			unavailable = append(unavailable, f.workspace.Name)
+			continue
+		}
+		for _, budget := range f.budgets.Budgets {
+			s, ok := spends[budget.ID]
+			if !ok {
+				s = &budgetSpend{budget: budget, measured: !budget.IsTagScoped()}
+				spends[budget.ID] = s
+				ids = append(ids, budget.ID)
+			}
+			if f.forecast == nil {
+				s.measured = false
+				continue
+			}
+			s.actual += f.forecast.MonthEnd.Actual
+			s.projected += f.forecast.MonthEnd.Value
+		}
+	}
+	sort.Strings(ids)
+
+	statuses := map[string]int{}
+	section := domain.ReportSection{Title: SectionBudgets}
+	for _, id := range ids {
+		s := spends[id]
+		status := budgetStatus(s.budget, s.actual, s.projected, s.measured)
+		statuses[status]++
+
+		detail := domain.ReportDetail{Name: s.budget.Name, Unit: currency, Description: status}
+		if s.measured {
+			detail.Value = s.projected
+			detail.Description = fmt.Sprintf("%s, %.2f spent this month, %.2f projected", status, s.actual, s.projected)
+			if s.budget.MonthlyLimit > 0 {
+				detail.Description += fmt.Sprintf(" of %.2f limit", s.budget.MonthlyLimit)
+			}
+		}
+		section.Details = append(section.Details, detail)
+	}
+
+	section.Summary = map[string]interface{}{
+		"budgets":               len(ids),
+		BudgetStatusExceeded:    statuses[BudgetStatusExceeded],
+		BudgetStatusAtRisk:      statuses[BudgetStatusAtRisk],
+		BudgetStatusOnTrack:     statuses[BudgetStatusOnTrack],
+		BudgetStatusNoLimit:     statuses[BudgetStatusNoLimit],
+		BudgetStatusNotMeasured: statuses[BudgetStatusNotMeasured],
+	}
+	section.Metadata = map[string]interface{}{
+		"scope": "spend of the reported workspaces covered by the budget, projected to the end of the month",
+	}
+	if len(unavailable) > 0 {
+		section.Metadata["unavailable_workspaces"] = unavailable
+	}
+	return section
+}
+
+func budgetStatus(budget domain.Budget, actual, projected float64, measured bool) string {
+	switch {
+	case budget.MonthlyLimit == 0:
+		return BudgetStatusNoLimit
+	case !measured:
+		return BudgetStatusNotMeasured
+	case actual >= budget.MonthlyLimit:
+		return BudgetStatusExceeded
+	case projected > budget.MonthlyLimit:
+		return BudgetStatusAtRisk
+	default:
+		return BudgetStatusOnTrack
+	}
+}
+
+func forecastSection(
+	figures []workspaceFigures,
+	asOf time.Time,
+	settings workspace.ForecastSettings,
+	currency string,
+) domain.ReportSection {
+	var monthEnd, quarterEnd domain.ForecastEstimate
+	var unavailable []string
+	section := domain.ReportSection{Title: SectionForecast}
+	for _, f := range figures {
+		if f.forecast == nil {
+			unavailable = append(unavailable, f.workspace.Name)
+			continue
+		}
+		addEstimate(&monthEnd, f.forecast.MonthEnd)
+		addEstimate(&quarterEnd, f.forecast.QuarterEnd)
+		section.Details = append(section.Details, domain.ReportDetail{
+			Name:  f.workspace.Name,
+			Value: f.forecast.MonthEnd.Value,
+			Unit:  currency,
+			Description: fmt.Sprintf("month end %.2f to %.2f, quarter end %.2f (%.2f to %.2f)",
+				f.forecast.MonthEnd.Lower, f.forecast.MonthEnd.Upper,
+				f.forecast.QuarterEnd.Value, f.forecast.QuarterEnd.Lower, f.forecast.QuarterEnd.Upper),
+		})
+	}
+
+	section.Summary = map[string]interface{}{
+		"month_end":         monthEnd.Value,
+		"month_end_lower":   monthEnd.Lower,
+		"month_end_upper":   monthEnd.Upper,
+		"quarter_end":       quarterEnd.Value,
+		"quarter_end_lower": quarterEnd.Lower,
+		"quarter_end_upper": quarterEnd.Upper,
+		"currency":          currency,
+	}
+	section.Metadata = map[string]interface{}{
+		"as_of":            asOf,
+		"confidence_level": settings.ConfidenceLevel,
+	}
+	if len(unavailable) > 0 {
+		section.Metadata["unavailable_workspaces"] = unavailable
+	}
+	return section
+}
+
+// addEstimate adds up the estimates of several workspaces, the bounds are summed as a conservative interval
+func addEstimate(total *domain.ForecastEstimate, estimate domain.ForecastEstimate) {
+	total.End = estimate.End
+	total.Actual += estimate.Actual
+	total.Value += estimate.Value
+	total.Lower += estimate.Lower
+	total.Upper += estimate.Upper
+}
+
+func describeChange(previous float64, changePercent *float64) string {
+	if changePercent == nil {
+		return "no spend in the previous period"
+	}
+	return fmt.Sprintf("%+.1f%% from %.2f in the previous period", *changePercent, previous)
+}
@@ -0,0 +1,357 @@
+package reporting
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAccountExplorer struct {
+	mock.Mock
+}
+
+func (m *mockAccountExplorer) ListWorkspaces(ctx context.Context) ([]domain.Workspace, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Workspace), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetWorkspaceExplorer(
+	ctx context.Context,
+	ws domain.Workspace,
+) (workspace.Explorer, error) {
+	args := m.Called(ctx, ws)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.Explorer), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetWorkspaceCostManagerCached(
+	ctx context.Context,
+	ws domain.Workspace,
+) (workspace.CostManager, error) {
+	args := m.Called(ctx, ws)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.CostManager), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetWorkspaceCostManagerRemote(
+	ctx context.Context,
+	ws domain.Workspace,
+) (workspace.CostManager, error) {
+	args := m.Called(ctx, ws)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.CostManager), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetWorkspaceCostAnalyzer(
+	ctx context.Context,
+	ws domain.Workspace,
+) (workspace.CostAnalyzer, error) {
+	args := m.Called(ctx, ws)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.CostAnalyzer), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetAccountCostManagerRemote(ctx context.Context) (workspace.CostManager, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(workspace.CostManager), args.Error(1)
+}
+
+func (m *mockAccountExplorer) GetWorkspaceID(ctx context.Context, ws domain.Workspace) (string, error) {
+	args := m.Called(ctx, ws)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockAccountExplorer) Close() error {
+	return nil
+}
+
+type mockCostAnalyzer struct {
+	mock.Mock
+}
+
+func (m *mockCostAnalyzer) GetDailyCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	startTime, endTime time.Time,
+) ([]domain.DailyCost, error) {
+	args := m.Called(ctx, dimension, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DailyCost), args.Error(1)
+}
+
+func (m *mockCostAnalyzer) CompareCost(
+	ctx context.Context,
+	dimension domain.CostDimension,
+	period, baseline domain.TimePeriod,
+	filters []domain.CostFilter,
+) (domain.CostComparisonReport, error) {
+	args := m.Called(ctx, dimension, period, baseline, filters)
+	return args.Get(0).(domain.CostComparisonReport), args.Error(1)
+}
+
+func (m *mockCostAnalyzer) ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	args := m.Called(ctx, startTime, endTime)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// mockWorkspaceExplorer only implements the budget controls read by the report builder
+type mockWorkspaceExplorer struct {
+	workspace.Explorer
+	mock.Mock
+}
+
+func (m *mockWorkspaceExplorer) GetBudgetControls(ctx context.Context) (*domain.BudgetControls, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BudgetControls), args.Error(1)
+}
+
+type mockAuditHistory struct {
+	mock.Mock
+}
+
+func (m *mockAuditHistory) Record(ctx context.Context, report *domain.AuditReport) (domain.AuditRun, error) {
+	args := m.Called(ctx, report)
+	return args.Get(0).(domain.AuditRun), args.Error(1)
+}
+
+func (m *mockAuditHistory) List(ctx context.Context, filter domain.AuditHistoryFilter) ([]domain.AuditRun, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditRun), args.Error(1)
+}
+
+func findSection(t *testing.T, report domain.Report, title string) domain.ReportSection {
+	t.Helper()
+	for _, section := range report.Sections {
+		if section.Title == title {
+			return section
+		}
+	}
+	require.Failf(t, "section not found", "section %q", title)
+	return domain.ReportSection{}
+}
+
+func TestBuild(t *testing.T) {
+	ws := domain.Workspace{Name: "prod"}
+	period := domain.TimePeriod{
+		Start:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC),
+		Duration: 31,
+	}
+	previous := domain.TimePeriod{
+		Start:    time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC),
+		End:      period.Start,
+		Duration: 31,
+	}
+
+	var daily []domain.DailyCost
+	for day := period.End.AddDate(0, 0, -90); day.Before(period.End); day = day.AddDate(0, 0, 1) {
+		daily = append(daily, domain.DailyCost{Date: day, Key: "warehouse", TotalCost: 10, Currency: "USD"})
+	}
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("CompareCost", mock.Anything, domain.CostDimensionResourceType, period, previous,
+		[]domain.CostFilter(nil)).
+		Return(domain.CostComparisonReport{
+			TotalCost:         300,
+			BaselineTotalCost: 200,
+			Currency:          "USD",
+			Items: []domain.CostComparison{
+				{Key: "cluster", Cost: 100, BaselineCost: 100},
+				{Key: "warehouse", Cost: 200, BaselineCost: 100, Delta: 100},
+			},
+		}, nil)
+	analyzer.On("CompareCost", mock.Anything, domain.CostDimensionResourceID, period, previous,
+		[]domain.CostFilter(nil)).
+		Return(domain.CostComparisonReport{
+			Currency: "USD",
+			Items: []domain.CostComparison{
+				{Key: "cl-1", ResourceType: "cluster", Cost: 80, BaselineCost: 100, Delta: -20},
+				{Key: "wh-1", ResourceType: "warehouse", Cost: 200, BaselineCost: 100, Delta: 100},
+				{Key: "cl-2", ResourceType: "cluster", Cost: 20, BaselineCost: 0, Delta: 20},
+				{Key: "wh-2", ResourceType: "warehouse", Cost: 5, BaselineCost: 5},
+			},
+		}, nil)
+	analyzer.On("GetDailyCost", mock.Anything, domain.CostDimensionResourceType, mock.Anything, period.End).
+		Return(daily, nil)
+
+	wsExplorer := new(mockWorkspaceExplorer)
+	wsExplorer.On("GetBudgetControls", mock.Anything).Return(&domain.BudgetControls{
+		WorkspaceID: 42,
+		Budgets: []domain.Budget{
+			{ID: "b1", Name: "tight", MonthlyLimit: 250},
+			{ID: "b2", Name: "loose", MonthlyLimit: 1000},
+			{ID: "b3", Name: "team", MonthlyLimit: 100, Tags: map[string][]string{"team": {"bi"}}},
+			{ID: "b4", Name: "unlimited"},
+		},
+	}, nil)
+
+	explorer := new(mockAccountExplorer)
+	explorer.On("ListWorkspaces", mock.Anything).Return([]domain.Workspace{ws}, nil)
+	explorer.On("GetWorkspaceCostAnalyzer", mock.Anything, ws).Return(analyzer, nil)
+	explorer.On("GetWorkspaceExplorer", mock.Anything, ws).Return(wsExplorer, nil)
+
+	finding := func(id, resource string, severity domain.Severity, savings float64) domain.AuditFinding {
+		return domain.AuditFinding{
+			Id:                      id,
+			Resource:                domain.ResourceDef{Service: "warehouse", Name: resource},
+			Issue:                   "issue_" + id,
+			Recommendation:          "fix " + id,
+			Severity:                severity,
+			EstimatedMonthlySavings: domain.SavingsEstimate{Amount: savings, Currency: "USD"},
+		}
+	}
+	history := new(mockAuditHistory)
+	history.On("List", mock.Anything, domain.AuditHistoryFilter{Workspace: "prod", To: &period.End}).
+		Return([]domain.AuditRun{
+			{Scope: "warehouse", Report: domain.AuditReport{Findings: []domain.AuditFinding{
+				finding("f1", "wh-1", domain.SeverityHigh, 50),
+				finding("f2", "wh-1", domain.SeverityLow, 20),
+			}}},
+			{Scope: "cluster", Report: domain.AuditReport{Findings: []domain.AuditFinding{
+				finding("f4", "cl-1", domain.SeverityMedium, 0),
+				finding("f1", "wh-1", domain.SeverityHigh, 50),
+			}}},
+			// older run of a scope already seen
+			{Scope: "warehouse", Report: domain.AuditReport{Findings: []domain.AuditFinding{
+				finding("f3", "wh-2", domain.SeverityHigh, 500),
+			}}},
+		}, nil)
+
+	settings := DefaultSettings()
+	settings.TopMovers = 2
+	report, err := NewBuilder(explorer, history, settings).Build(context.Background(), period, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Cost report: all workspaces", report.Title)
+	assert.Equal(t, 300.0, report.TotalAmount)
+	assert.Equal(t, "USD", report.Currency)
+	titles := make([]string, 0, len(report.Sections))
+	for _, section := range report.Sections {
+		titles = append(titles, section.Title)
+	}
+	assert.Equal(t, []string{
+		SectionSpend, SectionResourceTypes, SectionTopMovers, SectionFindings, SectionBudgets, SectionForecast,
+	}, titles)
+
+	spend := findSection(t, report, SectionSpend)
+	assert.Equal(t, 100.0, spend.Summary["change"])
+	assert.Equal(t, 50.0, spend.Summary["change_percent"])
+	assert.Empty(t, spend.Details)
+
+	types := findSection(t, report, SectionResourceTypes)
+	require.Len(t, types.Details, 2)
+	assert.Equal(t, "warehouse", types.Details[0].Name)
+	assert.Equal(t, "66.7% of total, +100.0% from 100.00 in the previous period", types.Details[0].Description)
+
+	movers := findSection(t, report, SectionTopMovers)
+	assert.Equal(t, 3, movers.Summary["changed_resources"])
+	require.Len(t, movers.Details, 2)
+	assert.Equal(t, "wh-1", movers.Details[0].Name)
+	assert.Equal(t, 100.0, movers.Details[0].Value)
+	assert.Equal(t, -20.0, movers.Details[1].Value)
+
+	findings := findSection(t, report, SectionFindings)
+	assert.Equal(t, 3, findings.Summary["open_findings"])
+	assert.Equal(t, 1, findings.Summary["high"])
+	assert.Equal(t, 1, findings.Summary["medium"])
+	assert.Equal(t, 1, findings.Summary["low"])
+	assert.Equal(t, 50.0, findings.Summary["estimated_monthly_savings"])
+	require.Len(t, findings.Details, 3)
+	assert.Equal(t, "high severity issue_f1: fix f1", findings.Details[0].Description)
+
+	budgets := findSection(t, report, SectionBudgets)
+	assert.Equal(t, 4, budgets.Summary["budgets"])
+	statuses := map[string]string{}
+	for _, detail := range budgets.Details {
+		statuses[detail.Name] = detail.Description
+	}
+	assert.Contains(t, statuses["tight"], BudgetStatusAtRisk)
+	assert.Contains(t, statuses["loose"], BudgetStatusOnTrack)
+	assert.Equal(t, BudgetStatusNotMeasured, statuses["team"])
+	assert.True(t, strings.HasPrefix(statuses["unlimited"], BudgetStatusNoLimit))
+
+	forecast := findSection(t, report, SectionForecast)
+	assert.InDelta(t, 310.0, forecast.Summary["month_end"], 1)
+	assert.InDelta(t, 920.0, forecast.Summary["quarter_end"], 1)
+	assert.NotContains(t, forecast.Metadata, "unavailable_workspaces")
+
+	explorer.AssertExpectations(t)
+	analyzer.AssertExpectations(t)
+	history.AssertExpectations(t)
+}
+
+func TestBuildOptionalSourcesUnavailable(t *testing.T) {
+	ws := domain.Workspace{Name: "dev"}
+	period := domain.TimePeriod{
+		Start:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC),
+		Duration: 7,
+	}
+
+	analyzer := new(mockCostAnalyzer)
+	analyzer.On("CompareCost", mock.Anything, mock.Anything, period, mock.Anything, mock.Anything).
+		Return(domain.CostComparisonReport{TotalCost: 70, Currency: "USD"}, nil)
+	analyzer.On("GetDailyCost", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.DailyCost{}, nil)
+
+	explorer := new(mockAccountExplorer)
+	explorer.On("GetWorkspaceCostAnalyzer", mock.Anything, ws).Return(analyzer, nil)
+	explorer.On("GetWorkspaceExplorer", mock.Anything, ws).Return(nil, assert.AnError)
+
+	report, err := NewBuilder(explorer, nil, DefaultSettings()).
+		Build(context.Background(), period, []domain.Workspace{ws})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Cost report: dev", report.Title)
+	for _, section := range report.Sections {
+		assert.NotEqual(t, SectionFindings, section.Title)
+	}
+	assert.Equal(t, []string{"dev"}, findSection(t, report, SectionBudgets).Metadata["unavailable_workspaces"])
+	assert.Equal(t, []string{"dev"}, findSection(t, report, SectionForecast).Metadata["unavailable_workspaces"])
+	assert.Nil(t, findSection(t, report, SectionSpend).Summary["change_percent"])
+
+	explorer.AssertNotCalled(t, "ListWorkspaces", mock.Anything)
+}
+
+func TestBuildCostUnavailable(t *testing.T) {
+	ws := domain.Workspace{Name: "dev"}
+	period := domain.TimePeriod{
+		Start: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC),
+	}
+
+	explorer := new(mockAccountExplorer)
+	explorer.On("GetWorkspaceCostAnalyzer", mock.Anything, ws).Return(nil, assert.AnError)
+
+	_, err := NewBuilder(explorer, nil, DefaultSettings()).Build(context.Background(), period, []domain.Workspace{ws})
+	assert.ErrorContains(t, err, "failed to collect cost of workspace dev")
+}
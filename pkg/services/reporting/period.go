@@ -0,0 +1,78 @@
+package reporting
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+)
+
+// Named report periods, the last complete calendar week, month or quarter
+const (
+	PeriodWeek    = "week"
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+)
+
+var (
+	monthPeriodPattern   = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+	quarterPeriodPattern = regexp.MustCompile(`^(\d{4})-[Qq]([1-4])$`)
+)
+
+// ParsePeriod returns the time period named by period as of now. A period is either week, month or quarter
+// for the last complete one, a month such as 2025-07 or a quarter such as 2025-Q3. Periods still in progress
+// end today.
+func ParsePeriod(period string, now time.Time) (domain.TimePeriod, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var start, end time.Time
+	switch {
+	case period == PeriodWeek:
+		// weeks start on Monday
+		end = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		start = end.AddDate(0, 0, -7)
+	case period == PeriodMonth:
+		end = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		start = end.AddDate(0, -1, 0)
+	case period == PeriodQuarter:
+		end = quarterStart(today)
+		start = end.AddDate(0, -3, 0)
+	case monthPeriodPattern.MatchString(period):
+		m := monthPeriodPattern.FindStringSubmatch(period)
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return domain.TimePeriod{}, fmt.Errorf("invalid month in period %q", period)
+		}
+		start = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	case quarterPeriodPattern.MatchString(period):
+		m := quarterPeriodPattern.FindStringSubmatch(period)
+		year, _ := strconv.Atoi(m[1])
+		quarter, _ := strconv.Atoi(m[2])
+		start = time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 3, 0)
+	default:
+		return domain.TimePeriod{}, fmt.Errorf(
+			"invalid period %q. Expected week, month, quarter, YYYY-MM or YYYY-Qn", period)
+	}
+
+	if !start.Before(today) {
+		return domain.TimePeriod{}, fmt.Errorf("period %q has not started yet", period)
+	}
+	if end.After(today) {
+		end = today
+	}
+
+	return domain.TimePeriod{
+		Start:    start,
+		End:      end,
+		Duration: int(end.Sub(start).Hours() / 24),
+	}, nil
+}
+
+func quarterStart(t time.Time) time.Time {
+	return time.Date(t.Year(), ((t.Month()-1)/3)*3+1, 1, 0, 0, 0, 0, time.UTC)
+}
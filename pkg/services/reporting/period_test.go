@@ -0,0 +1,46 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 8, 13, 15, 30, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		period       string
+		start, end   time.Time
+		durationDays int
+	}{
+		{period: "week", start: date(2025, 8, 4), end: date(2025, 8, 11), durationDays: 7},
+		{period: "month", start: date(2025, 7, 1), end: date(2025, 8, 1), durationDays: 31},
+		{period: "quarter", start: date(2025, 4, 1), end: date(2025, 7, 1), durationDays: 91},
+		{period: "2025-06", start: date(2025, 6, 1), end: date(2025, 7, 1), durationDays: 30},
+		{period: "2025-Q1", start: date(2025, 1, 1), end: date(2025, 4, 1), durationDays: 90},
+		// periods in progress end today
+		{period: "2025-08", start: date(2025, 8, 1), end: date(2025, 8, 13), durationDays: 12},
+		{period: "2025-q3", start: date(2025, 7, 1), end: date(2025, 8, 13), durationDays: 43},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			period, err := ParsePeriod(tt.period, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.start, period.Start)
+			assert.Equal(t, tt.end, period.End)
+			assert.Equal(t, tt.durationDays, period.Duration)
+		})
+	}
+
+	for _, invalid := range []string{"year", "2025-13", "2025-Q5", "2025-09", ""} {
+		_, err := ParsePeriod(invalid, now)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/de-tools/data-atlas/pkg/services/account"
+	databricksusage "github.com/de-tools/data-atlas/pkg/store/databrickssql/usage"
+	"github.com/rs/zerolog"
+)
+
+// SyncMode is how usage is read from the account billing
+type SyncMode string
+
+const (
+	// SyncModeWorkspace runs a workflow per workspace reading the usage of that workspace
+	SyncModeWorkspace SyncMode = "workspace"
+	// SyncModeAccount runs a single workflow reading the billing of the account once
+	// and storing every record under its workspace. The workflow keeps a single cursor, so records of
+	// workspaces without a profile are dropped and a workspace added later is not backfilled.
+	SyncModeAccount SyncMode = "account"
+)
+
+// AccountWorkflow is the workflow name tracking the account-wide sync
+const AccountWorkflow = "__account__"
+
+// ParseSyncMode parses the sync mode name, an empty name is the workspace mode
+func ParseSyncMode(name string) (SyncMode, error) {
+	switch SyncMode(name) {
+	case "", SyncModeWorkspace:
+		return SyncModeWorkspace, nil
+	case SyncModeAccount:
+		return SyncModeAccount, nil
+	default:
+		return "", fmt.Errorf("invalid sync mode %q. Expected workspace or account", name)
+	}
+}
+
+// workspaceResolver maps the workspace ids of account-wide usage records to the names of the configured workspaces
+type workspaceResolver struct {
+	explorer account.Explorer
+
+	mu    sync.Mutex
+	names map[string]string
+}
+
+func newWorkspaceResolver(explorer account.Explorer) *workspaceResolver {
+	return &workspaceResolver{explorer: explorer, names: make(map[string]string)}
+}
+
+// refresh resolves the ids of the configured workspaces. The ids that could be resolved are kept
+// when some workspaces fail to resolve, the failures being returned.
+func (w *workspaceResolver) refresh(ctx context.Context) error {
+	workspaces, err := w.explorer.ListWorkspaces(ctx)
+	if err != nil {
+		return fmt.Errorf("list workspaces: %w", err)
+	}
+
+	var errs []error
+	names := make(map[string]string, len(workspaces))
+	for _, ws := range workspaces {
+		id, err := w.explorer.GetWorkspaceID(ctx, ws)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("resolve id of workspace %s: %w", ws.Name, err))
+			continue
+		}
+		names[id] = ws.Name
+	}
+
+	w.mu.Lock()
+	w.names = names
+	w.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// route groups the records by workspace name. The workspaces are resolved again once per batch when
+// a record belongs to an unknown workspace. Records of workspaces that are not configured are dropped,
+// but when the workspaces could not all be resolved the record may belong to one of them, so the batch
+// fails and is read again on the next run instead of being dropped.
+func (w *workspaceResolver) route(ctx context.Context, records []store.UsageRecord) (map[string][]store.UsageRecord, error) {
+	refreshed := false
+	var refreshErr error
+	dropped := 0
+	result := make(map[string][]store.UsageRecord)
+	for _, record := range records {
+		id := record.Metadata[databricksusage.WorkspaceIDMetadataKey]
+		name, ok := w.lookup(id)
+		if !ok && !refreshed {
+			refreshed = true
+			refreshErr = w.refresh(ctx)
+			name, ok = w.lookup(id)
+		}
+		if !ok {
+			if refreshErr != nil {
+				return nil, fmt.Errorf("failed to resolve workspace of id %s: %w", id, refreshErr)
+			}
+			dropped++
+			continue
+		}
+		result[name] = append(result[name], record)
+	}
+
+	if dropped > 0 {
+		zerolog.Ctx(ctx).Debug().Int("dropped_records", dropped).
+			Msg("sync, dropped records of workspaces that are not configured")
+	}
+	return result, nil
+}
+
+func (w *workspaceResolver) lookup(id string) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	name, ok := w.names[id]
+	return name, ok
+}
@@ -0,0 +1,231 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/domain"
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/de-tools/data-atlas/pkg/services/account"
+	"github.com/de-tools/data-atlas/pkg/services/account/workspace"
+	databricksusage "github.com/de-tools/data-atlas/pkg/store/databrickssql/usage"
+	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	duckdbusage "github.com/de-tools/data-atlas/pkg/store/duckdb/usage"
+	duckdbworkflow "github.com/de-tools/data-atlas/pkg/store/duckdb/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExplorer serves the workspaces of ids and reads the account usage from costManager
+type fakeExplorer struct {
+	account.Explorer
+	ids         map[string]string // workspace name -> id
+	listErr     error
+	idErrs      map[string]error // workspace name -> error resolving its id
+	costManager workspace.CostManager
+	refreshes   int
+}
+
+func (e *fakeExplorer) ListWorkspaces(context.Context) ([]domain.Workspace, error) {
+	e.refreshes++
+	if e.listErr != nil {
+		return nil, e.listErr
+	}
+	workspaces := make([]domain.Workspace, 0, len(e.ids))
+	for name := range e.ids {
+		workspaces = append(workspaces, domain.Workspace{Name: name})
+	}
+	return workspaces, nil
+}
+
+func (e *fakeExplorer) GetWorkspaceID(_ context.Context, ws domain.Workspace) (string, error) {
+	if err := e.idErrs[ws.Name]; err != nil {
+		return "", err
+	}
+	id, ok := e.ids[ws.Name]
+	if !ok {
+		return "", fmt.Errorf("workspace %s not found", ws.Name)
+	}
+	return id, nil
+}
+
+func (e *fakeExplorer) GetAccountCostManagerRemote(context.Context) (workspace.CostManager, error) {
+	return e.costManager, nil
+}
+
+// fakeCostManager returns the records started in the requested period
+type fakeCostManager struct {
+	workspace.CostManager
+	records []domain.ResourceCost
+}
+
+func (m *fakeCostManager) GetUsageStats(context.Context, *time.Time) (*domain.UsageStats, error) {
+	return &domain.UsageStats{RecordsCount: int64(len(m.records))}, nil
+}
+
+func (m *fakeCostManager) GetUsage(_ context.Context, startTime, endTime time.Time) ([]domain.ResourceCost, error) {
+	var records []domain.ResourceCost
+	for _, record := range m.records {
+		if !record.StartTime.Before(startTime) && record.StartTime.Before(endTime) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func usageRecord(id, workspaceID string, start time.Time) store.UsageRecord {
+	return store.UsageRecord{
+		ID:        id,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Metadata:  map[string]string{databricksusage.WorkspaceIDMetadataKey: workspaceID},
+	}
+}
+
+func resourceCost(id, workspaceID string, start time.Time) domain.ResourceCost {
+	return domain.ResourceCost{
+		ID:        id,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Resource: domain.ResourceDef{
+			Service:  "job",
+			Name:     "job-" + id,
+			Metadata: map[string]string{databricksusage.WorkspaceIDMetadataKey: workspaceID},
+		},
+		Costs: []domain.CostComponent{{Type: "compute", Value: 1, Unit: "DBU", Rate: 0.22, Currency: "USD"}},
+	}
+}
+
+func TestParseSyncMode(t *testing.T) {
+	for name, expected := range map[string]SyncMode{
+		"":          SyncModeWorkspace,
+		"workspace": SyncModeWorkspace,
+		"account":   SyncModeAccount,
+	} {
+		mode, err := ParseSyncMode(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, mode, name)
+	}
+
+	_, err := ParseSyncMode("Account")
+	assert.ErrorContains(t, err, `invalid sync mode "Account"`)
+}
+
+func TestWorkspaceResolverRoute(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	explorer := &fakeExplorer{ids: map[string]string{"dev": "1", "prod": "2"}}
+	resolver := newWorkspaceResolver(explorer)
+
+	routed, err := resolver.route(ctx, []store.UsageRecord{
+		usageRecord("a", "1", start),
+		usageRecord("b", "2", start),
+		usageRecord("c", "1", start),
+		usageRecord("d", "999", start),
+		usageRecord("e", "998", start),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]store.UsageRecord{
+		"dev":  {usageRecord("a", "1", start), usageRecord("c", "1", start)},
+		"prod": {usageRecord("b", "2", start)},
+	}, routed, "records of workspaces without a profile are dropped")
+	assert.Equal(t, 1, explorer.refreshes, "the workspaces are resolved once per batch")
+
+	routed, err = resolver.route(ctx, []store.UsageRecord{usageRecord("f", "2", start)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]store.UsageRecord{"prod": {usageRecord("f", "2", start)}}, routed)
+	assert.Equal(t, 1, explorer.refreshes, "known workspaces are not resolved again")
+
+	explorer.ids["staging"] = "999"
+	routed, err = resolver.route(ctx, []store.UsageRecord{usageRecord("g", "999", start)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]store.UsageRecord{"staging": {usageRecord("g", "999", start)}}, routed,
+		"workspaces added to the config are resolved on their first record")
+	assert.Equal(t, 2, explorer.refreshes)
+}
+
+func TestWorkspaceResolverRoute_ResolutionFailure(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	explorer := &fakeExplorer{ids: map[string]string{"dev": "1", "prod": "2"}}
+	resolver := newWorkspaceResolver(explorer)
+
+	explorer.listErr = fmt.Errorf("connection reset")
+	_, err := resolver.route(ctx, []store.UsageRecord{usageRecord("a", "1", start)})
+	assert.ErrorContains(t, err, "connection reset", "records are not dropped when the workspaces cannot be listed")
+
+	explorer.listErr = nil
+	explorer.idErrs = map[string]error{"prod": fmt.Errorf("token expired")}
+	_, err = resolver.route(ctx, []store.UsageRecord{usageRecord("a", "1", start), usageRecord("b", "2", start)})
+	assert.ErrorContains(t, err, "resolve id of workspace prod: token expired",
+		"records of a workspace whose id cannot be resolved are not dropped")
+
+	routed, err := resolver.route(ctx, []store.UsageRecord{usageRecord("c", "1", start)})
+	require.NoError(t, err, "the resolved workspaces are kept")
+	assert.Equal(t, map[string][]store.UsageRecord{"dev": {usageRecord("c", "1", start)}}, routed)
+
+	explorer.idErrs = nil
+	routed, err = resolver.route(ctx, []store.UsageRecord{usageRecord("d", "2", start), usageRecord("e", "999", start)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]store.UsageRecord{"prod": {usageRecord("d", "2", start)}}, routed,
+		"records are dropped once every workspace is resolved")
+}
+
+func TestControllerAccountMode(t *testing.T) {
+	ctx := context.Background()
+	db, err := duckdb.NewDB(duckdb.Settings{DbPath: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	workflowStore, err := duckdbworkflow.NewStore(db)
+	require.NoError(t, err)
+	usageStore, err := duckdbusage.NewStore(db)
+	require.NoError(t, err)
+
+	cursor := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	_, err = workflowStore.CreateWorkflow(ctx, store.WorkflowIdentity{Workspace: AccountWorkflow})
+	require.NoError(t, err)
+	require.NoError(t, workflowStore.UpdateWorkflow(ctx, store.WorkflowIdentity{Workspace: AccountWorkflow}, cursor))
+
+	explorer := &fakeExplorer{
+		ids: map[string]string{"dev": "1", "prod": "2"},
+		costManager: &fakeCostManager{records: []domain.ResourceCost{
+			resourceCost("synced-before", "1", cursor.Add(-time.Hour)),
+			resourceCost("dev-1", "1", cursor),
+			resourceCost("prod-1", "2", cursor.Add(10*time.Minute)),
+			resourceCost("unknown-1", "999", cursor.Add(20*time.Minute)),
+		}},
+	}
+	ctrl := NewController(db, explorer, workflowStore, usageStore, Settings{
+		Mode:   SyncModeAccount,
+		Runner: RunnerConfig{BatchInterval: time.Hour, SleepInterval: time.Millisecond},
+	})
+	require.NoError(t, ctrl.Start(ctx, "dev"), "starting a workspace starts the account workflow")
+	require.NoError(t, ctrl.Start(ctx, "prod"))
+
+	next := cursor.Add(time.Hour)
+	require.Eventually(t, func() bool {
+		workflows, err := workflowStore.ListWorkflows(ctx, []string{AccountWorkflow})
+		return err == nil && len(workflows) == 1 && workflows[0].LastProcessedAt != nil &&
+			workflows[0].LastProcessedAt.Equal(next)
+	}, 5*time.Second, 5*time.Millisecond, "the account cursor moves past the synced batch")
+	require.NoError(t, ctrl.Cancel(ctx, AccountWorkflow))
+
+	workflows, err := workflowStore.ListWorkflows(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, workflows, 1, "workspaces have no cursor of their own in account mode")
+
+	for name, expected := range map[string][]string{"dev": {"dev-1"}, "prod": {"prod-1"}} {
+		workspaceStore, err := duckdbusage.NewWorkspaceStore(db, name)
+		require.NoError(t, err)
+		records, err := workspaceStore.GetUsage(ctx, cursor.Add(-2*time.Hour), next)
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(records))
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		assert.Equal(t, expected, ids, name)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/de-tools/data-atlas/pkg/models/domain"
 	"github.com/de-tools/data-atlas/pkg/store/duckdb/usage"
 	"github.com/de-tools/data-atlas/pkg/store/duckdb/workflow"
+	"github.com/rs/zerolog"
 )
 
 type Controller interface {
@@ -26,11 +27,38 @@ type workflowDescriptor struct {
 	runner     *Runner
 }
 
+// Settings configures the usage sync
+type Settings struct {
+	Mode SyncMode
+	// Runner is the schedule of the syncs without a schedule of their own, including the account sync
+	Runner RunnerConfig
+	// Workspaces are the schedules of workspace syncs by workspace name
+	Workspaces map[string]RunnerConfig
+}
+
+// DefaultSettings returns the default sync settings, every workspace is synced on its own
+func DefaultSettings() Settings {
+	return Settings{
+		Mode:   SyncModeWorkspace,
+		Runner: DefaultRunnerConfig(),
+	}
+}
+
+// runnerConfig returns the schedule of the workflow
+func (s Settings) runnerConfig(workflow string) RunnerConfig {
+	if config, ok := s.Workspaces[workflow]; ok {
+		return config
+	}
+	return s.Runner
+}
+
 type DefaultController struct {
 	workflowStore      workflow.Store
 	db                 *sql.DB
 	explorer           account.Explorer
 	embeddedUsageStore usage.Store
+	mode               SyncMode
+	settings           Settings
 
 	mu        sync.Mutex
 	workflows map[string]workflowDescriptor
@@ -41,12 +69,15 @@ func NewController(
 	explorer account.Explorer,
 	workflowStore workflow.Store,
 	embeddedUsageStore usage.Store,
+	settings Settings,
 ) *DefaultController {
 	ctrl := &DefaultController{
 		db:                 db,
 		workflowStore:      workflowStore,
 		explorer:           explorer,
 		embeddedUsageStore: embeddedUsageStore,
+		mode:               settings.Mode,
+		settings:           settings,
 		workflows:          make(map[string]workflowDescriptor),
 	}
 
@@ -54,6 +85,13 @@ func NewController(
 }
 
 func (ctrl *DefaultController) Init(ctx context.Context, syncEnabled bool) error {
+	if ctrl.mode == SyncModeAccount {
+		if !syncEnabled {
+			return nil
+		}
+		return ctrl.Start(ctx, AccountWorkflow)
+	}
+
 	workflows, err := ctrl.workflowStore.ListWorkflows(ctx, []string{})
 	if err != nil {
 		return err
@@ -61,6 +99,9 @@ func (ctrl *DefaultController) Init(ctx context.Context, syncEnabled bool) error
 
 	if syncEnabled {
 		for _, wf := range workflows {
+			if wf.Workspace == AccountWorkflow {
+				continue
+			}
 			ctrl.startWorkflow(ctx, wf)
 		}
 	}
@@ -68,7 +109,16 @@ func (ctrl *DefaultController) Init(ctx context.Context, syncEnabled bool) error
 	return nil
 }
 
+// Start starts the sync of the workspace. In account mode every workspace is synced
+// by the account workflow, which is started when it is not running yet.
 func (ctrl *DefaultController) Start(ctx context.Context, workspace string) error {
+	if ctrl.mode == SyncModeAccount {
+		workspace = AccountWorkflow
+		if ctrl.isRunning(AccountWorkflow) {
+			return nil
+		}
+	}
+
 	wf, err := ctrl.workflowStore.CreateWorkflow(ctx, store.WorkflowIdentity{
 		Workspace: workspace,
 	})
@@ -94,19 +144,92 @@ func (ctrl *DefaultController) Cancel(_ context.Context, workspace string) error
 	return nil
 }
 
+// ProfilesChanged stops the syncs of removed workspaces and restarts the syncs of changed ones,
+// so they use the new config. In account mode the account sync is restarted on any workspace change
+// so records are routed to the current workspaces.
+func (ctrl *DefaultController) ProfilesChanged(ctx context.Context, changes domain.ProfileChanges) {
+	logger := zerolog.Ctx(ctx)
+
+	if ctrl.mode == SyncModeAccount {
+		if changes.IsEmpty() || !ctrl.isRunning(AccountWorkflow) {
+			return
+		}
+		for _, profile := range changes.Added {
+			if profile.Type == domain.ProfileTypeWorkspace {
+				logger.Warn().Msgf("workspace %s is synced from now on, its earlier usage is not backfilled "+
+					"in account mode", profile.Name)
+			}
+		}
+		ctrl.restart(ctx, AccountWorkflow)
+		return
+	}
+
+	for _, profile := range changes.Removed {
+		if profile.Type != domain.ProfileTypeWorkspace || !ctrl.isRunning(profile.Name) {
+			continue
+		}
+		if err := ctrl.Cancel(ctx, profile.Name); err != nil {
+			logger.Warn().Err(err).Msgf("failed to stop sync of removed workspace %s", profile.Name)
+			continue
+		}
+		logger.Info().Msgf("sync of workspace %s stopped, its profile was removed", profile.Name)
+	}
+	for _, profile := range changes.Changed {
+		if profile.Type != domain.ProfileTypeWorkspace || !ctrl.isRunning(profile.Name) {
+			continue
+		}
+		ctrl.restart(ctx, profile.Name)
+	}
+}
+
+// restart stops the running workflow and starts it again from its last processed record
+func (ctrl *DefaultController) restart(ctx context.Context, workspace string) {
+	logger := zerolog.Ctx(ctx)
+
+	if err := ctrl.Cancel(ctx, workspace); err != nil {
+		logger.Warn().Err(err).Msgf("failed to stop sync of %s", workspace)
+		return
+	}
+	if err := ctrl.Start(ctx, workspace); err != nil {
+		logger.Error().Err(err).Msgf("failed to restart sync of %s", workspace)
+		return
+	}
+	logger.Info().Msgf("sync of %s restarted with the reloaded config", workspace)
+}
+
+func (ctrl *DefaultController) isRunning(workspace string) bool {
+	ctrl.mu.Lock()
+	defer ctrl.mu.Unlock()
+	_, ok := ctrl.workflows[workspace]
+	return ok
+}
+
 func (ctrl *DefaultController) startWorkflow(ctx context.Context, wf *store.Workflow) error {
 	ctrl.mu.Lock()
 	defer ctrl.mu.Unlock()
 
 	ctx, cancel := context.WithCancel(ctx)
 
-	costExplorer, err := ctrl.explorer.GetWorkspaceCostManagerRemote(ctx, domain.Workspace{Name: wf.Workspace})
-	if err != nil {
-		cancel()
-		return err
+	var runner *Runner
+	if wf.Workspace == AccountWorkflow {
+		costManager, err := ctrl.explorer.GetAccountCostManagerRemote(ctx)
+		if err != nil {
+			cancel()
+			return err
+		}
+		resolver := newWorkspaceResolver(ctrl.explorer)
+		runner = NewAccountRunner(wf, ctrl.db, ctrl.workflowStore, costManager, ctrl.embeddedUsageStore,
+			ctrl.settings.runnerConfig(wf.Workspace), resolver.route)
+	} else {
+		costManager, err := ctrl.explorer.GetWorkspaceCostManagerRemote(ctx, domain.Workspace{Name: wf.Workspace})
+		if err != nil {
+			cancel()
+			return err
+		}
+		runner = NewRunner(wf, ctrl.db, ctrl.workflowStore, costManager, ctrl.embeddedUsageStore,
+			ctrl.settings.runnerConfig(wf.Workspace))
 	}
 
-	runner := NewRunner(wf, ctrl.db, ctrl.workflowStore, costExplorer, ctrl.embeddedUsageStore)
 	ctrl.workflows[wf.Workspace] = workflowDescriptor{
 		cancelFunc: cancel,
 		wf:         wf,
@@ -16,34 +16,67 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// RouteFunc assigns usage records to the workspaces they are stored under. An error fails the batch,
+// which is read again on the next run
+type RouteFunc func(ctx context.Context, records []store.UsageRecord) (map[string][]store.UsageRecord, error)
+
 type Runner struct {
 	workflow      *store.Workflow
 	db            *sql.DB
 	workflowStore workflow.Store
 	costManager   workspace.CostManager
 	usageStore    usage.Store
+	route         RouteFunc
 	done          chan struct{}
 	progress      chan RunnerProgress
 	config        RunnerConfig
 }
 
+// RunnerConfig is the schedule of a sync, every SleepInterval the usage of the next BatchInterval is read
 type RunnerConfig struct {
 	BatchInterval time.Duration
 	SleepInterval time.Duration
 }
 
+// DefaultRunnerConfig returns the default sync schedule
+func DefaultRunnerConfig() RunnerConfig {
+	return RunnerConfig{
+		BatchInterval: 365 * 24 * time.Hour,
+		SleepInterval: 1 * time.Minute,
+	}
+}
+
 type RunnerProgress struct {
 	ProcessedRecords int64
 	TotalRecords     int64
 	LastProcessedAt  time.Time
 }
 
+// NewRunner creates the runner syncing the usage of the workflow workspace
 func NewRunner(
 	wf *store.Workflow,
 	db *sql.DB,
 	workflowStore workflow.Store,
 	costManager workspace.CostManager,
 	usageStore usage.Store,
+	config RunnerConfig,
+) *Runner {
+	route := func(_ context.Context, records []store.UsageRecord) (map[string][]store.UsageRecord, error) {
+		return map[string][]store.UsageRecord{wf.Workspace: records}, nil
+	}
+	return NewAccountRunner(wf, db, workflowStore, costManager, usageStore, config, route)
+}
+
+// NewAccountRunner creates a runner syncing account-wide usage, records are stored under the workspaces
+// route assigns them to and the workflow only tracks the sync progress
+func NewAccountRunner(
+	wf *store.Workflow,
+	db *sql.DB,
+	workflowStore workflow.Store,
+	costManager workspace.CostManager,
+	usageStore usage.Store,
+	config RunnerConfig,
+	route RouteFunc,
 ) *Runner {
 	return &Runner{
 		workflow:      wf,
@@ -51,12 +84,10 @@ func NewRunner(
 		workflowStore: workflowStore,
 		costManager:   costManager,
 		usageStore:    usageStore,
+		route:         route,
 		done:          make(chan struct{}),
 		progress:      make(chan RunnerProgress, 100),
-		config: RunnerConfig{
-			BatchInterval: 365 * 24 * time.Hour,
-			SleepInterval: 1 * time.Minute,
-		},
+		config:        config,
 	}
 }
 
@@ -97,8 +128,7 @@ func (r *Runner) Run(ctx context.Context) {
 		case <-ctx.Done():
 			logger.Info().Msg("Workflow sync stopped")
 			return
-		default:
-			time.Sleep(r.config.SleepInterval)
+		case <-time.After(r.config.SleepInterval):
 
 			endTime := startTime.Add(r.config.BatchInterval)
 
@@ -148,6 +178,12 @@ func (r *Runner) Run(ctx context.Context) {
 func (r *Runner) updateWorkflow(ctx context.Context, ws string, endTime time.Time, records []store.UsageRecord) error {
 	logger := zerolog.Ctx(ctx).With().Str("workspace", r.workflow.Workspace).Logger()
 
+	routed, err := r.route(ctx, records)
+	if err != nil {
+		logger.Error().Err(err).Msg("sync, failed to route usage records")
+		return err
+	}
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		logger.Error().Err(err).Msg("sync, failed to instantiate transaction")
@@ -159,9 +195,11 @@ func (r *Runner) updateWorkflow(ctx context.Context, ws string, endTime time.Tim
 
 	ctxWithTx := duckdb.WithTransaction(ctx, tx)
 	// Store records in DuckDB
-	if err := r.usageStore.Add(ctxWithTx, ws, records); err != nil {
-		logger.Error().Err(err).Msg("sync, failed to store usage records")
-		return err
+	for workspace, workspaceRecords := range routed {
+		if err := r.usageStore.Add(ctxWithTx, workspace, workspaceRecords); err != nil {
+			logger.Error().Err(err).Str("target_workspace", workspace).Msg("sync, failed to store usage records")
+			return err
+		}
 	}
 
 	// Update workflow state in DuckDB
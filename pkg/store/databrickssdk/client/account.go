@@ -8,11 +8,17 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/databricks/databricks-sdk-go"
+	"github.com/databricks/databricks-sdk-go/common/environment"
 	"github.com/databricks/databricks-sdk-go/config"
+	"github.com/databricks/databricks-sdk-go/config/experimental/auth"
+	"github.com/databricks/databricks-sdk-go/service/billing"
 	"github.com/databricks/databricks-sdk-go/service/provisioning"
 	"github.com/rs/zerolog"
+	"golang.org/x/oauth2"
 )
 
 type AccessToken struct {
@@ -27,8 +33,8 @@ type Client struct {
 	config *config.Config
 }
 
-// NewAccountClient is not currently in use, but I left it here for reference.
-// As we might want to provide cross-workspace functionality in the future.
+// NewAccountClient creates a client of the account APIs, it is used to read account budgets
+// and to discover the workspaces of the account.
 func NewAccountClient(cfg *config.Config) (*Client, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config is nil")
@@ -61,20 +67,106 @@ func (ac *Client) ListWorkspaces(ctx context.Context) ([]provisioning.Workspace,
 	return workspaces, nil
 }
 
+// ListBudgets retrieves the budget configurations of the account
+func (ac *Client) ListBudgets(ctx context.Context) ([]billing.BudgetConfiguration, error) {
+	budgets, err := ac.client.Budgets.ListAll(ctx, billing.ListBudgetConfigurationsRequest{})
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("failed to list budgets")
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// ListBudgetPolicies retrieves the budget policies of the account
+func (ac *Client) ListBudgetPolicies(ctx context.Context) ([]billing.BudgetPolicy, error) {
+	policies, err := ac.client.BudgetPolicy.ListAll(ctx, billing.ListBudgetPoliciesRequest{})
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("failed to list budget policies")
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// CanMintWorkspaceTokens reports whether the account profile has the OAuth client credentials
+// GetWorkspaceToken mints workspace tokens with
+func (ac *Client) CanMintWorkspaceTokens() bool {
+	return ac.config.ClientID != "" && ac.config.ClientSecret != ""
+}
+
+// WorkspaceHost returns the URL of the workspace, resolved from the cloud of the workspace
+func (ac *Client) WorkspaceHost(workspace provisioning.Workspace) string {
+	return workspaceHost(ac.config.Environment(), workspace)
+}
+
+// workspaceHost returns the URL of the workspace in the DNS zone of its cloud. The DNS zone of the account
+// is kept when the workspace is in the same cloud, e.g. for government clouds, and the public one is used otherwise.
+func workspaceHost(accountEnv environment.DatabricksEnvironment, workspace provisioning.Workspace) string {
+	var cloud environment.Cloud
+	switch strings.ToLower(workspace.Cloud) {
+	case "aws":
+		cloud = environment.CloudAWS
+	case "azure":
+		cloud = environment.CloudAzure
+	case "gcp":
+		cloud = environment.CloudGCP
+	default:
+		cloud = accountEnv.Cloud
+	}
+
+	if cloud == accountEnv.Cloud {
+		return accountEnv.DeploymentURL(workspace.DeploymentName)
+	}
+	switch cloud {
+	case environment.CloudAzure:
+		return fmt.Sprintf("https://%s.azuredatabricks.net", workspace.DeploymentName)
+	case environment.CloudGCP:
+		return fmt.Sprintf("https://%s.gcp.databricks.com", workspace.DeploymentName)
+	default:
+		return fmt.Sprintf("https://%s.cloud.databricks.com", workspace.DeploymentName)
+	}
+}
+
+// WorkspaceConfig returns the config of a workspace of the account. Its tokens are minted with
+// GetWorkspaceToken when needed and cached until they expire.
+func (ac *Client) WorkspaceConfig(workspace provisioning.Workspace) *config.Config {
+	tokenSource := auth.TokenSourceFn(func(ctx context.Context) (*oauth2.Token, error) {
+		token, err := ac.GetWorkspaceToken(ctx, workspace)
+		if err != nil {
+			return nil, err
+		}
+		return &oauth2.Token{
+			AccessToken: token.Token,
+			TokenType:   token.Type,
+			Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+		}, nil
+	})
+
+	return &config.Config{
+		Host:        ac.WorkspaceHost(workspace),
+		Credentials: config.NewTokenSourceStrategy("account-oauth-m2m", tokenSource),
+	}
+}
+
 // GetWorkspaceToken - retrieves an access token for the specified workspace.
 // With Account level config we might not have all the tokens for workspaces, and
 // we might need to get a workspace token programmatically.
 func (ac *Client) GetWorkspaceToken(ctx context.Context, workspace provisioning.Workspace) (*AccessToken, error) {
 	logger := zerolog.Ctx(ctx)
 
+	if !ac.CanMintWorkspaceTokens() {
+		return nil, fmt.Errorf("account profile has no OAuth client credentials to mint workspace tokens with")
+	}
+
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 	data.Set("scope", "all-apis")
 
-	// TODO: pick url based on the type of the cloud provider in the workspace.
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
-		fmt.Sprintf("https://%s.cloud.databricks.com/oidc/v1/token", workspace.DeploymentName),
+		ac.WorkspaceHost(workspace)+"/oidc/v1/token",
 		bytes.NewBufferString(data.Encode()),
 	)
 	if err != nil {
@@ -103,6 +195,10 @@ func (ac *Client) GetWorkspaceToken(ctx context.Context, workspace provisioning.
 		logger.Warn().Err(err).Msg("failed to read workspace token response")
 		return nil, err
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get token of workspace %s: status %d: %s",
+			workspace.WorkspaceName, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
 
 	var token AccessToken
 	err = json.Unmarshal(body, &token)
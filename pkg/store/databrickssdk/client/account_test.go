@@ -0,0 +1,66 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/databricks/databricks-sdk-go/common/environment"
+	"github.com/databricks/databricks-sdk-go/service/provisioning"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceHost(t *testing.T) {
+	aws := environment.DatabricksEnvironment{Cloud: environment.CloudAWS, DnsZone: ".cloud.databricks.com"}
+	awsGov := environment.DatabricksEnvironment{Cloud: environment.CloudAWS, DnsZone: ".cloud.databricks.us"}
+	azure := environment.DatabricksEnvironment{Cloud: environment.CloudAzure, DnsZone: ".azuredatabricks.net"}
+	gcp := environment.DatabricksEnvironment{Cloud: environment.CloudGCP, DnsZone: ".gcp.databricks.com"}
+
+	tests := []struct {
+		name      string
+		env       environment.DatabricksEnvironment
+		workspace provisioning.Workspace
+		expected  string
+	}{
+		{
+			name:      "aws workspace",
+			env:       aws,
+			workspace: provisioning.Workspace{Cloud: "aws", DeploymentName: "dbc-1234"},
+			expected:  "https://dbc-1234.cloud.databricks.com",
+		},
+		{
+			name:      "azure workspace",
+			env:       azure,
+			workspace: provisioning.Workspace{Cloud: "azure", DeploymentName: "adb-1234.5"},
+			expected:  "https://adb-1234.5.azuredatabricks.net",
+		},
+		{
+			name:      "gcp workspace",
+			env:       gcp,
+			workspace: provisioning.Workspace{Cloud: "gcp", DeploymentName: "1234.5"},
+			expected:  "https://1234.5.gcp.databricks.com",
+		},
+		{
+			name:      "cloud of the account when the workspace has none",
+			env:       gcp,
+			workspace: provisioning.Workspace{DeploymentName: "1234.5"},
+			expected:  "https://1234.5.gcp.databricks.com",
+		},
+		{
+			name:      "dns zone of the account in the same cloud",
+			env:       awsGov,
+			workspace: provisioning.Workspace{Cloud: "aws", DeploymentName: "dbc-1234"},
+			expected:  "https://dbc-1234.cloud.databricks.us",
+		},
+		{
+			name:      "public dns zone in another cloud",
+			env:       aws,
+			workspace: provisioning.Workspace{Cloud: "gcp", DeploymentName: "1234.5"},
+			expected:  "https://1234.5.gcp.databricks.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, workspaceHost(tt.env, tt.workspace))
+		})
+	}
+}
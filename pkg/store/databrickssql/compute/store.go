@@ -0,0 +1,157 @@
+package compute
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+// errUnknownWorkspace is returned instead of reading the telemetry of the whole account
+var errUnknownWorkspace = errors.New("the workspace id is unknown")
+
+// Store reads compute telemetry from the system.compute schema
+type Store interface {
+	GetClusterUtilization(ctx context.Context, startTime, endTime time.Time) ([]store.ClusterUtilization, error)
+	GetWarehouseEvents(ctx context.Context, startTime, endTime time.Time) ([]store.WarehouseEvent, error)
+}
+
+type computeStore struct {
+	db          *sql.DB
+	workspaceID string
+}
+
+// NewStore creates a store of the compute of the workspace,
+// the compute tables hold the telemetry of the whole account
+func NewStore(db *sql.DB, workspaceID string) Store {
+	return &computeStore{db: db, workspaceID: workspaceID}
+}
+
+// GetClusterUtilization aggregates the per-minute node metrics of every cluster in the period
+func (c *computeStore) GetClusterUtilization(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]store.ClusterUtilization, error) {
+	logger := zerolog.Ctx(ctx)
+	if c.workspaceID == "" {
+		return nil, errUnknownWorkspace
+	}
+
+	query := `
+		SELECT
+			cluster_id,
+			COUNT(DISTINCT instance_id) AS node_count,
+			SUM(unix_timestamp(end_time) - unix_timestamp(start_time)) / 3600.0 AS node_hours,
+			AVG(cpu_user_percent + cpu_system_percent) AS avg_cpu_percent,
+			MAX(cpu_user_percent + cpu_system_percent) AS peak_cpu_percent,
+			AVG(mem_used_percent) AS avg_memory_percent,
+			MAX(mem_used_percent) AS peak_memory_percent
+		FROM
+			system.compute.node_timeline
+		WHERE
+			workspace_id = ?
+			AND start_time >= ? AND start_time < ?
+		GROUP BY
+			cluster_id
+	`
+
+	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
+	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
+
+	rows, err := c.db.QueryContext(ctx, query, c.workspaceID, startTimeFormatted, endTimeFormatted)
+	if err != nil {
+		return nil, fmt.Errorf("node timeline query failed: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close node timeline query rows")
+		}
+	}(rows)
+
+	var utilization []store.ClusterUtilization
+	for rows.Next() {
+		var u store.ClusterUtilization
+		if err := rows.Scan(
+			&u.ClusterID,
+			&u.NodeCount,
+			&u.NodeHours,
+			&u.AvgCPUPercent,
+			&u.PeakCPUPercent,
+			&u.AvgMemoryPercent,
+			&u.PeakMemoryPercent,
+		); err != nil {
+			return nil, err
+		}
+		utilization = append(utilization, u)
+	}
+
+	logger.Debug().Int("clusters", len(utilization)).Msg("retrieved cluster utilization")
+
+	return utilization, rows.Err()
+}
+
+// GetWarehouseEvents returns the warehouse events of the period ordered by warehouse and time,
+// the last event before the period is included to know the state of each warehouse when the period starts
+func (c *computeStore) GetWarehouseEvents(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]store.WarehouseEvent, error) {
+	logger := zerolog.Ctx(ctx)
+	if c.workspaceID == "" {
+		return nil, errUnknownWorkspace
+	}
+
+	query := `
+		SELECT warehouse_id, event_type, cluster_count, event_time
+		FROM (
+			SELECT warehouse_id, event_type, cluster_count, event_time
+			FROM system.compute.warehouse_events
+			WHERE workspace_id = ? AND event_time >= ? AND event_time < ?
+			UNION ALL
+			SELECT warehouse_id, event_type, cluster_count, event_time
+			FROM system.compute.warehouse_events
+			WHERE workspace_id = ? AND event_time < ?
+			QUALIFY ROW_NUMBER() OVER (PARTITION BY warehouse_id ORDER BY event_time DESC) = 1
+		)
+		ORDER BY
+			warehouse_id, event_time
+	`
+
+	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
+	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
+
+	rows, err := c.db.QueryContext(ctx, query,
+		c.workspaceID, startTimeFormatted, endTimeFormatted, c.workspaceID, startTimeFormatted)
+	if err != nil {
+		return nil, fmt.Errorf("warehouse events query failed: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close warehouse events query rows")
+		}
+	}(rows)
+
+	var events []store.WarehouseEvent
+	for rows.Next() {
+		var e store.WarehouseEvent
+		if err := rows.Scan(
+			&e.WarehouseID,
+			&e.EventType,
+			&e.ClusterCount,
+			&e.EventTime,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	logger.Debug().Int("events", len(events)).Msg("retrieved warehouse events")
+
+	return events, rows.Err()
+}
@@ -0,0 +1,130 @@
+package lakeflow
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+// errUnknownWorkspace is returned instead of reading the runs of the whole account
+var errUnknownWorkspace = errors.New("the workspace id is unknown")
+
+// Store reads job run history from the system.lakeflow schema
+type Store interface {
+	GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]store.JobRun, error)
+}
+
+type lakeflowStore struct {
+	db          *sql.DB
+	workspaceID string
+}
+
+// NewStore creates a store of the job runs of the workspace,
+// the lakeflow tables hold the runs of the whole account
+func NewStore(db *sql.DB, workspaceID string) Store {
+	return &lakeflowStore{db: db, workspaceID: workspaceID}
+}
+
+// GetJobRuns returns the runs started in the period with their outcome and number of task retries
+func (l *lakeflowStore) GetJobRuns(ctx context.Context, startTime, endTime time.Time) ([]store.JobRun, error) {
+	logger := zerolog.Ctx(ctx)
+	if l.workspaceID == "" {
+		return nil, errUnknownWorkspace
+	}
+
+	// The timelines are sliced into hourly rows, the result state is only set on the last row of a run.
+	// Job and run ids are only unique within a workspace.
+	query := `
+		WITH runs AS (
+			SELECT
+				workspace_id,
+				job_id,
+				run_id,
+				MAX(run_name) AS run_name,
+				MAX(trigger_type) AS trigger_type,
+				MIN(period_start_time) AS start_time,
+				MAX(period_end_time) AS end_time,
+				MAX(result_state) AS result_state,
+				MAX(termination_code) AS termination_code
+			FROM
+				system.lakeflow.job_run_timeline
+			WHERE
+				workspace_id = ?
+				AND period_start_time >= ? AND period_start_time < ?
+			GROUP BY
+				workspace_id, job_id, run_id
+		),
+		retries AS (
+			SELECT
+				workspace_id,
+				job_run_id,
+				COUNT(DISTINCT run_id) - COUNT(DISTINCT task_key) AS retries
+			FROM
+				system.lakeflow.job_task_run_timeline
+			WHERE
+				workspace_id = ?
+				AND period_start_time >= ? AND period_start_time < ?
+			GROUP BY
+				workspace_id, job_run_id
+		)
+		SELECT
+			runs.job_id,
+			runs.run_id,
+			COALESCE(runs.run_name, ''),
+			COALESCE(runs.trigger_type, ''),
+			runs.start_time,
+			runs.end_time,
+			COALESCE(runs.result_state, ''),
+			COALESCE(runs.termination_code, ''),
+			COALESCE(retries.retries, 0)
+		FROM
+			runs
+		LEFT JOIN
+			retries ON runs.workspace_id = retries.workspace_id AND runs.run_id = retries.job_run_id
+		ORDER BY
+			runs.start_time
+	`
+
+	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
+	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
+
+	rows, err := l.db.QueryContext(ctx, query,
+		l.workspaceID, startTimeFormatted, endTimeFormatted, l.workspaceID, startTimeFormatted, endTimeFormatted)
+	if err != nil {
+		return nil, fmt.Errorf("job run timeline query failed: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close job run timeline query rows")
+		}
+	}(rows)
+
+	var runs []store.JobRun
+	for rows.Next() {
+		var r store.JobRun
+		if err := rows.Scan(
+			&r.JobID,
+			&r.RunID,
+			&r.RunName,
+			&r.TriggerType,
+			&r.StartTime,
+			&r.EndTime,
+			&r.ResultState,
+			&r.TerminationCode,
+			&r.Retries,
+		); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+
+	logger.Debug().Int("runs", len(runs)).Msg("retrieved job runs")
+
+	return runs, rows.Err()
+}
@@ -2,6 +2,7 @@ package pricing
 
 import (
 	"context"
+	"strings"
 )
 
 type Price struct {
@@ -13,15 +14,52 @@ type Store interface {
 	GetSkuPrice(ctx context.Context, sku string) Price
 }
 
+// Settings overlays the list price of SKUs
+type Settings struct {
+	CurrencyCode string
+	DefaultPrice float64
+	// Skus are the prices by SKU name, a name ending with "*" matches every SKU with that prefix
+	Skus map[string]float64
+}
+
+// DefaultSettings returns the price used for every SKU without an overlay
+func DefaultSettings() Settings {
+	return Settings{
+		CurrencyCode: "USD",
+		DefaultPrice: 0.22,
+	}
+}
+
 type pricingStore struct {
+	settings Settings
 }
 
 func NewStore() Store {
-	return &pricingStore{}
+	return NewStoreWithSettings(DefaultSettings())
+}
+
+func NewStoreWithSettings(settings Settings) Store {
+	return &pricingStore{settings: settings}
 }
 
-func (p *pricingStore) GetSkuPrice(_ context.Context, _ string) Price {
+func (p *pricingStore) GetSkuPrice(_ context.Context, sku string) Price {
 	// TODO: replace with actual pricing retrieval logic
 	// see billing.list_prices table in the databricks db
-	return Price{PricePerUnit: 0.22, CurrencyCode: "USD"}
+	price := Price{PricePerUnit: p.settings.DefaultPrice, CurrencyCode: p.settings.CurrencyCode}
+	if exact, ok := p.settings.Skus[sku]; ok {
+		price.PricePerUnit = exact
+		return price
+	}
+
+	// the longest matching prefix wins
+	longest := -1
+	for pattern, value := range p.settings.Skus {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if !ok || !strings.HasPrefix(sku, prefix) || len(prefix) <= longest {
+			continue
+		}
+		longest = len(prefix)
+		price.PricePerUnit = value
+	}
+	return price
 }
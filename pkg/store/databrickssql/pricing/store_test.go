@@ -0,0 +1,36 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSkuPrice(t *testing.T) {
+	store := NewStoreWithSettings(Settings{
+		CurrencyCode: "EUR",
+		DefaultPrice: 0.2,
+		Skus: map[string]float64{
+			"PREMIUM_JOBS_COMPUTE":   0.15,
+			"PREMIUM_*":              0.5,
+			"PREMIUM_SQL_*":          0.7,
+			"PREMIUM_SQL_PRO_*":      0.55,
+			"ENTERPRISE_ALL_PURPOSE": 0.65,
+		},
+	})
+
+	for sku, expected := range map[string]float64{
+		"PREMIUM_JOBS_COMPUTE":                    0.15,
+		"PREMIUM_ALL_PURPOSE_COMPUTE":             0.5,
+		"PREMIUM_SQL_COMPUTE":                     0.7,
+		"PREMIUM_SQL_PRO_COMPUTE_US_EAST":         0.55,
+		"STANDARD_JOBS_COMPUTE":                   0.2,
+		"ENTERPRISE_ALL_PURPOSE_COMPUTE_(PHOTON)": 0.2,
+	} {
+		assert.Equal(t, Price{PricePerUnit: expected, CurrencyCode: "EUR"}, store.GetSkuPrice(context.Background(), sku), sku)
+	}
+
+	assert.Equal(t, Price{PricePerUnit: 0.22, CurrencyCode: "USD"},
+		NewStore().GetSkuPrice(context.Background(), "PREMIUM_JOBS_COMPUTE"))
+}
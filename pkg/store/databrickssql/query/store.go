@@ -0,0 +1,170 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+// errUnknownWorkspace is returned instead of reading the queries of the whole account
+var errUnknownWorkspace = errors.New("the workspace id is unknown")
+
+// Store reads SQL warehouse query history from the system.query schema
+type Store interface {
+	GetWarehouseQueryStats(ctx context.Context, startTime, endTime time.Time) ([]store.WarehouseQueryStats, error)
+	GetWarehouseActiveMinutes(ctx context.Context, startTime, endTime time.Time) ([]store.WarehouseActiveMinute, error)
+}
+
+type queryStore struct {
+	db          *sql.DB
+	workspaceID string
+}
+
+// NewStore creates a store of the query history of the workspace,
+// the history holds the queries of the whole account
+func NewStore(db *sql.DB, workspaceID string) Store {
+	return &queryStore{db: db, workspaceID: workspaceID}
+}
+
+// GetWarehouseQueryStats aggregates the queries started on every warehouse in the period
+func (q *queryStore) GetWarehouseQueryStats(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]store.WarehouseQueryStats, error) {
+	logger := zerolog.Ctx(ctx)
+	if q.workspaceID == "" {
+		return nil, errUnknownWorkspace
+	}
+
+	// the history also holds queries of serverless notebooks and jobs, only warehouse queries are aggregated
+	query := `
+		SELECT
+			compute.warehouse_id,
+			COUNT(*) AS query_count,
+			COUNT_IF(execution_status = 'FAILED') AS failed_queries,
+			PERCENTILE(total_duration_ms, 0.5) / 1000.0 AS p50_duration_seconds,
+			PERCENTILE(total_duration_ms, 0.95) / 1000.0 AS p95_duration_seconds,
+			MAX(total_duration_ms) / 1000.0 AS max_duration_seconds,
+			AVG(COALESCE(waiting_for_compute_duration_ms, 0) + COALESCE(waiting_at_capacity_duration_ms, 0)) / 1000.0 AS avg_queued_seconds,
+			SUM(COALESCE(execution_duration_ms, 0)) / 3600000.0 AS execution_hours,
+			CAST(SUM(COALESCE(read_bytes, 0)) AS BIGINT) AS read_bytes,
+			CAST(SUM(COALESCE(spilled_local_bytes, 0)) AS BIGINT) AS spilled_bytes
+		FROM
+			system.query.history
+		WHERE
+			workspace_id = ?
+			AND compute.warehouse_id IS NOT NULL
+			AND start_time >= ? AND start_time < ?
+		GROUP BY
+			compute.warehouse_id
+	`
+
+	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
+	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
+
+	rows, err := q.db.QueryContext(ctx, query, q.workspaceID, startTimeFormatted, endTimeFormatted)
+	if err != nil {
+		return nil, fmt.Errorf("query history query failed: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close query history rows")
+		}
+	}(rows)
+
+	var stats []store.WarehouseQueryStats
+	for rows.Next() {
+		var s store.WarehouseQueryStats
+		if err := rows.Scan(
+			&s.WarehouseID,
+			&s.QueryCount,
+			&s.FailedQueries,
+			&s.P50DurationSeconds,
+			&s.P95DurationSeconds,
+			&s.MaxDurationSeconds,
+			&s.AvgQueuedSeconds,
+			&s.ExecutionHours,
+			&s.ReadBytes,
+			&s.SpilledBytes,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	logger.Debug().Int("warehouses", len(stats)).Msg("retrieved warehouse query stats")
+
+	return stats, rows.Err()
+}
+
+// GetWarehouseActiveMinutes returns the minutes in which every warehouse was executing at least one query,
+// ordered by warehouse and minute. Bucketing by minute bounds the result by the period length
+// however many queries ran.
+func (q *queryStore) GetWarehouseActiveMinutes(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]store.WarehouseActiveMinute, error) {
+	logger := zerolog.Ctx(ctx)
+	if q.workspaceID == "" {
+		return nil, errUnknownWorkspace
+	}
+
+	// queries still running at the end of the period have no end time yet
+	query := `
+		SELECT DISTINCT
+			warehouse_id,
+			minute
+		FROM (
+			SELECT
+				compute.warehouse_id AS warehouse_id,
+				EXPLODE(SEQUENCE(
+					DATE_TRUNC('MINUTE', GREATEST(start_time, CAST(? AS TIMESTAMP))),
+					DATE_TRUNC('MINUTE', LEAST(COALESCE(end_time, CAST(? AS TIMESTAMP)), CAST(? AS TIMESTAMP))),
+					INTERVAL 1 MINUTE
+				)) AS minute
+			FROM
+				system.query.history
+			WHERE
+				workspace_id = ?
+				AND compute.warehouse_id IS NOT NULL
+				AND start_time < ?
+				AND (end_time IS NULL OR end_time >= ?)
+		)
+		ORDER BY
+			warehouse_id, minute
+	`
+
+	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
+	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
+
+	rows, err := q.db.QueryContext(ctx, query,
+		startTimeFormatted, endTimeFormatted, endTimeFormatted, q.workspaceID, endTimeFormatted, startTimeFormatted)
+	if err != nil {
+		return nil, fmt.Errorf("query activity query failed: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close query activity rows")
+		}
+	}(rows)
+
+	var minutes []store.WarehouseActiveMinute
+	for rows.Next() {
+		var m store.WarehouseActiveMinute
+		if err := rows.Scan(&m.WarehouseID, &m.Minute); err != nil {
+			return nil, err
+		}
+		minutes = append(minutes, m)
+	}
+
+	logger.Debug().Int("minutes", len(minutes)).Msg("retrieved warehouse query activity")
+
+	return minutes, rows.Err()
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/sqltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	periodStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd   = time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+)
+
+func TestGetWarehouseQueryStats_RestrictedToWorkspace(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	_, err := NewStore(recorder.DB(), "1234").GetWarehouseQueryStats(context.Background(), periodStart, periodEnd)
+	require.NoError(t, err)
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 1)
+	assert.Equal(t, []any{"1234"}, queries[0].ArgsOf("workspace_id = ?"))
+	assert.Equal(t, []any{"1234", "2024-01-01 00:00:00", "2024-01-08 00:00:00"}, queries[0].Args)
+}
+
+func TestGetWarehouseQueryStats_UnknownWorkspace(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	_, err := NewStore(recorder.DB(), "").GetWarehouseQueryStats(context.Background(), periodStart, periodEnd)
+	assert.ErrorIs(t, err, errUnknownWorkspace)
+	assert.Empty(t, recorder.Queries())
+}
+
+func TestGetWarehouseActiveMinutes_RestrictedToWorkspace(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	_, err := NewStore(recorder.DB(), "1234").GetWarehouseActiveMinutes(context.Background(), periodStart, periodEnd)
+	require.NoError(t, err)
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 1)
+	assert.Equal(t, []any{"1234"}, queries[0].ArgsOf("workspace_id = ?"))
+	assert.Equal(t, []any{
+		"2024-01-01 00:00:00", "2024-01-08 00:00:00", "2024-01-08 00:00:00",
+		"1234", "2024-01-08 00:00:00", "2024-01-01 00:00:00",
+	}, queries[0].Args)
+}
+
+func TestGetWarehouseActiveMinutes_UnknownWorkspace(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	_, err := NewStore(recorder.DB(), "").GetWarehouseActiveMinutes(context.Background(), periodStart, periodEnd)
+	assert.ErrorIs(t, err, errUnknownWorkspace)
+	assert.Empty(t, recorder.Queries())
+}
@@ -0,0 +1,98 @@
+package serving
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+// errUnknownWorkspace is returned instead of reading the logs of the whole account
+var errUnknownWorkspace = errors.New("the workspace id is unknown")
+
+// Store reads model serving request logs from the system.serving schema
+type Store interface {
+	GetEndpointUsage(ctx context.Context, startTime, endTime time.Time) ([]store.ServingEndpointUsage, error)
+}
+
+type servingStore struct {
+	db          *sql.DB
+	workspaceID string
+}
+
+// NewStore creates a store of the serving endpoints of the workspace,
+// the serving tables hold the logs of the whole account
+func NewStore(db *sql.DB, workspaceID string) Store {
+	return &servingStore{db: db, workspaceID: workspaceID}
+}
+
+// GetEndpointUsage counts the requests and the hours with traffic of every endpoint in the period
+func (s *servingStore) GetEndpointUsage(
+	ctx context.Context,
+	startTime, endTime time.Time,
+) ([]store.ServingEndpointUsage, error) {
+	logger := zerolog.Ctx(ctx)
+	if s.workspaceID == "" {
+		return nil, errUnknownWorkspace
+	}
+
+	// served_entities keeps a row per config version, one endpoint per served entity is enough for the join
+	query := `
+		SELECT
+			e.endpoint_id,
+			e.endpoint_name,
+			COUNT(*) AS requests,
+			COUNT_IF(u.status_code >= 400) AS error_requests,
+			COUNT(DISTINCT date_trunc('HOUR', u.request_time)) AS active_hours
+		FROM
+			system.serving.endpoint_usage u
+		JOIN (
+			SELECT
+				served_entity_id,
+				MAX(endpoint_id) AS endpoint_id,
+				MAX(endpoint_name) AS endpoint_name
+			FROM
+				system.serving.served_entities
+			WHERE
+				workspace_id = ?
+			GROUP BY
+				served_entity_id
+		) e ON u.served_entity_id = e.served_entity_id
+		WHERE
+			u.workspace_id = ?
+			AND u.request_time >= ? AND u.request_time < ?
+		GROUP BY
+			e.endpoint_id, e.endpoint_name
+	`
+
+	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
+	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
+
+	rows, err := s.db.QueryContext(ctx, query, s.workspaceID, s.workspaceID, startTimeFormatted, endTimeFormatted)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint usage query failed: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close endpoint usage query rows")
+		}
+	}(rows)
+
+	var usage []store.ServingEndpointUsage
+	for rows.Next() {
+		var u store.ServingEndpointUsage
+		if err := rows.Scan(&u.EndpointID, &u.EndpointName, &u.Requests, &u.ErrorRequests, &u.ActiveHours); err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+
+	logger.Debug().Int("endpoints", len(usage)).Msg("retrieved serving endpoint usage")
+
+	return usage, rows.Err()
+}
@@ -0,0 +1,35 @@
+package serving
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/store/databrickssql/sqltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	periodStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd   = time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+)
+
+func TestGetEndpointUsage_RestrictedToWorkspace(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	_, err := NewStore(recorder.DB(), "1234").GetEndpointUsage(context.Background(), periodStart, periodEnd)
+	require.NoError(t, err)
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 1)
+	// both the served entities and the usage are filtered
+	assert.Equal(t, []any{"1234", "1234"}, queries[0].ArgsOf("workspace_id = ?"))
+	assert.Equal(t, []any{"1234", "1234", "2024-01-01 00:00:00", "2024-01-08 00:00:00"}, queries[0].Args)
+}
+
+func TestGetEndpointUsage_UnknownWorkspace(t *testing.T) {
+	recorder := &sqltest.Recorder{}
+	_, err := NewStore(recorder.DB(), "").GetEndpointUsage(context.Background(), periodStart, periodEnd)
+	assert.ErrorIs(t, err, errUnknownWorkspace)
+	assert.Empty(t, recorder.Queries())
+}
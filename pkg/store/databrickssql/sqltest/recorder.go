@@ -0,0 +1,82 @@
+// Package sqltest records the queries the Databricks SQL stores run, so their parameters can be
+// checked without a warehouse
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Query is a query run through a Recorder with its arguments
+type Query struct {
+	SQL  string
+	Args []any
+}
+
+// Recorder is a connector recording the queries run through it, every query returns no rows
+type Recorder struct {
+	mu      sync.Mutex
+	queries []Query
+}
+
+// DB returns a connection pool running its queries through the recorder
+func (r *Recorder) DB() *sql.DB {
+	return sql.OpenDB(r)
+}
+
+// Queries returns the recorded queries in the order they ran
+func (r *Recorder) Queries() []Query {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Query(nil), r.queries...)
+}
+
+func (r *Recorder) Connect(context.Context) (driver.Conn, error) { return &conn{recorder: r}, nil }
+func (r *Recorder) Driver() driver.Driver                        { return nil }
+
+// ArgsOf returns the arguments bound to the placeholder following every occurrence of clause, e.g. "workspace_id = ?"
+func (q Query) ArgsOf(clause string) []any {
+	var args []any
+	for i := strings.Index(q.SQL, clause); i >= 0; {
+		position := strings.Count(q.SQL[:i+len(clause)], "?") - 1
+		if position < len(q.Args) {
+			args = append(args, q.Args[position])
+		}
+		next := strings.Index(q.SQL[i+len(clause):], clause)
+		if next < 0 {
+			break
+		}
+		i += len(clause) + next
+	}
+	return args
+}
+
+type conn struct {
+	recorder *Recorder
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	values := make([]any, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	c.recorder.mu.Lock()
+	c.recorder.queries = append(c.recorder.queries, Query{SQL: query, Args: values})
+	c.recorder.mu.Unlock()
+	return emptyRows{}, nil
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *conn) Close() error                        { return nil }
+func (c *conn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string           { return nil }
+func (emptyRows) Close() error                { return nil }
+func (emptyRows) Next(_ []driver.Value) error { return io.EOF }
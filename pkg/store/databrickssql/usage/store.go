@@ -3,6 +3,7 @@ package usage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -14,6 +15,9 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// WorkspaceIDMetadataKey is the metadata key of the id of the workspace a usage record belongs to
+const WorkspaceIDMetadataKey = "workspace_id"
+
 type Store interface {
 	GetResourcesUsage(
 		ctx context.Context,
@@ -28,8 +32,11 @@ type Store interface {
 type usageStore struct {
 	db           *sql.DB
 	pricingStore pricing.Store
+	workspaceID  string // empty for the usage of every workspace of the account
 }
 
+// NewStore creates a store of the usage of every workspace of the account,
+// records keep their workspace id in the workspace_id metadata
 func NewStore(
 	db *sql.DB,
 	pricingStore pricing.Store,
@@ -40,6 +47,28 @@ func NewStore(
 	}
 }
 
+// NewWorkspaceStore creates a store of the usage of a single workspace,
+// system.billing.usage is account-wide so every query is filtered by the workspace id
+func NewWorkspaceStore(
+	db *sql.DB,
+	pricingStore pricing.Store,
+	workspaceID string,
+) Store {
+	return &usageStore{
+		db:           db,
+		pricingStore: pricingStore,
+		workspaceID:  workspaceID,
+	}
+}
+
+// workspaceFilter returns the condition and the arguments restricting a query to the workspace of the store
+func (u *usageStore) workspaceFilter() (string, []any) {
+	if u.workspaceID == "" {
+		return "", nil
+	}
+	return " AND workspace_id = ?", []any{u.workspaceID}
+}
+
 func (u *usageStore) GetUsageStats(ctx context.Context, startTime *time.Time) (*store.UsageStats, error) {
 	logger := zerolog.Ctx(ctx)
 
@@ -53,13 +82,17 @@ func (u *usageStore) GetUsageStats(ctx context.Context, startTime *time.Time) (*
 
 	var totalRecords int64
 	var earliestRecord sql.NullTime
-	var err error
+	// usage_start_time is never NULL, the first condition only anchors the optional ones
+	query += " WHERE usage_start_time IS NOT NULL"
+	var args []any
 	if startTime != nil {
-		query += " WHERE usage_start_time > ?"
-		err = u.db.QueryRowContext(ctx, query, startTime).Scan(&totalRecords, &earliestRecord)
-	} else {
-		err = u.db.QueryRowContext(ctx, query).Scan(&totalRecords, &earliestRecord)
+		query += " AND usage_start_time > ?"
+		args = append(args, startTime)
 	}
+	filter, filterArgs := u.workspaceFilter()
+	query += filter
+	args = append(args, filterArgs...)
+	err := u.db.QueryRowContext(ctx, query, args...).Scan(&totalRecords, &earliestRecord)
 
 	if err != nil {
 		return nil, fmt.Errorf("get usage stats failed: %w", err)
@@ -89,6 +122,7 @@ func (u *usageStore) GetUsage(
 ) ([]store.UsageRecord, error) {
 	logger := zerolog.Ctx(ctx)
 
+	filter, filterArgs := u.workspaceFilter()
 	query := `
 		SELECT
 			record_id as id,
@@ -104,11 +138,15 @@ func (u *usageStore) GetUsage(
 			usage_end_time,
 			usage_quantity,
 			usage_unit,
-			sku_name
+			sku_name,
+			to_json(custom_tags) AS custom_tags,
+			usage_metadata.job_id AS job_id,
+			usage_metadata.job_run_id AS job_run_id,
+			CAST(workspace_id AS STRING) AS workspace_id
 		FROM
 		    system.billing.usage
 		WHERE
-		    usage_start_time >= ? AND usage_start_time < ?
+		    usage_start_time >= ? AND usage_start_time < ?` + filter + `
 		ORDER BY
 		    usage_start_time
 		DESC
@@ -117,7 +155,7 @@ func (u *usageStore) GetUsage(
 	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
 	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
 
-	rows, err := u.db.Query(query, startTimeFormatted, endTimeFormatted)
+	rows, err := u.db.QueryContext(ctx, query, append([]any{startTimeFormatted, endTimeFormatted}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("usage query failed: %w", err)
 	}
@@ -134,8 +172,12 @@ func (u *usageStore) GetUsage(
 			id, resourceID, resourceType, usageType, unit, sku string
 			start, end                                         time.Time
 			qty                                                float64
+			customTags, jobID, jobRunID, workspaceID           sql.NullString
 		)
-		if err := rows.Scan(&id, &resourceID, &resourceType, &usageType, &start, &end, &qty, &unit, &sku); err != nil {
+		if err := rows.Scan(
+			&id, &resourceID, &resourceType, &usageType, &start, &end, &qty, &unit, &sku, &customTags, &jobID, &jobRunID,
+			&workspaceID,
+		); err != nil {
 			return nil, err
 		}
 
@@ -145,17 +187,15 @@ func (u *usageStore) GetUsage(
 			ID:           id,
 			ResourceID:   resourceID,
 			ResourceType: resourceType,
-			Metadata: map[string]string{
-				"usage_type":    usageType,
-				"resource_type": resourceType,
-			},
-			StartTime: start,
-			EndTime:   end,
-			Quantity:  qty,
-			Unit:      unit,
-			SKU:       sku,
-			Rate:      price.PricePerUnit,
-			Currency:  price.CurrencyCode,
+			Metadata:     usageMetadata(usageType, resourceType, jobID, jobRunID, workspaceID),
+			Tags:         parseCustomTags(ctx, customTags),
+			StartTime:    start,
+			EndTime:      end,
+			Quantity:     qty,
+			Unit:         unit,
+			SKU:          sku,
+			Rate:         price.PricePerUnit,
+			Currency:     price.CurrencyCode,
 		})
 	}
 
@@ -180,6 +220,7 @@ func (u *usageStore) GetResourcesUsage(
 		conditions = append(conditions, fmt.Sprintf("%s IS NOT NULL", idField))
 	}
 
+	filter, filterArgs := u.workspaceFilter()
 	query := `
 		SELECT
 			record_id as id,
@@ -195,18 +236,22 @@ func (u *usageStore) GetResourcesUsage(
 			usage_end_time,
 			usage_quantity,
 			usage_unit,
-			sku_name
+			sku_name,
+			to_json(custom_tags) AS custom_tags,
+			usage_metadata.job_id AS job_id,
+			usage_metadata.job_run_id AS job_run_id,
+			CAST(workspace_id AS STRING) AS workspace_id
 		FROM system.billing.usage
 		WHERE (` + strings.Join(conditions, " OR ") + `)
 			AND usage_start_time >= ?
-			AND usage_start_time < ?
+			AND usage_start_time < ?` + filter + `
 		ORDER BY usage_start_time DESC
 	`
 
 	startTimeFormatted := startTime.Format("2006-01-02 15:04:05")
 	endTimeFormatted := endTime.Format("2006-01-02 15:04:05")
 
-	rows, err := u.db.Query(query, startTimeFormatted, endTimeFormatted)
+	rows, err := u.db.QueryContext(ctx, query, append([]any{startTimeFormatted, endTimeFormatted}, filterArgs...)...)
 	if err != nil {
 		return nil, fmt.Errorf("usage query failed: %w", err)
 	}
@@ -223,8 +268,12 @@ func (u *usageStore) GetResourcesUsage(
 			id, resourceID, resourceType, usageType, unit, sku string
 			start, end                                         time.Time
 			qty                                                float64
+			customTags, jobID, jobRunID, workspaceID           sql.NullString
 		)
-		if err := rows.Scan(&id, &resourceID, &resourceType, &usageType, &start, &end, &qty, &unit, &sku); err != nil {
+		if err := rows.Scan(
+			&id, &resourceID, &resourceType, &usageType, &start, &end, &qty, &unit, &sku, &customTags, &jobID, &jobRunID,
+			&workspaceID,
+		); err != nil {
 			return nil, err
 		}
 
@@ -234,23 +283,53 @@ func (u *usageStore) GetResourcesUsage(
 			ID:           id,
 			ResourceID:   resourceID,
 			ResourceType: resourceType,
-			Metadata: map[string]string{
-				"usage_type":    usageType,
-				"resource_type": resourceType,
-			},
-			StartTime: start,
-			EndTime:   end,
-			Quantity:  qty,
-			Unit:      unit,
-			SKU:       sku,
-			Rate:      price.PricePerUnit,
-			Currency:  price.CurrencyCode,
+			Metadata:     usageMetadata(usageType, resourceType, jobID, jobRunID, workspaceID),
+			Tags:         parseCustomTags(ctx, customTags),
+			StartTime:    start,
+			EndTime:      end,
+			Quantity:     qty,
+			Unit:         unit,
+			SKU:          sku,
+			Rate:         price.PricePerUnit,
+			Currency:     price.CurrencyCode,
 		})
 	}
 
 	return records, nil
 }
 
+// usageMetadata builds the record metadata, job and run ids are kept for every record billed to a job
+// since the record is attributed to a single resource type
+func usageMetadata(usageType, resourceType string, jobID, jobRunID, workspaceID sql.NullString) map[string]string {
+	metadata := map[string]string{
+		"usage_type":    usageType,
+		"resource_type": resourceType,
+	}
+	if jobID.Valid && jobID.String != "" {
+		metadata["job_id"] = jobID.String
+	}
+	if jobRunID.Valid && jobRunID.String != "" {
+		metadata["job_run_id"] = jobRunID.String
+	}
+	if workspaceID.Valid && workspaceID.String != "" {
+		metadata[WorkspaceIDMetadataKey] = workspaceID.String
+	}
+	return metadata
+}
+
+// parseCustomTags decodes the JSON encoded custom_tags map, malformed tags are dropped
+func parseCustomTags(ctx context.Context, raw sql.NullString) map[string]string {
+	if !raw.Valid || raw.String == "" {
+		return nil
+	}
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(raw.String), &tags); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("failed to parse custom tags")
+		return nil
+	}
+	return tags
+}
+
 func buildCoalesceList(resourceTypes []string, suffix string) string {
 	var fields []string
 	for _, rt := range resourceTypes {
@@ -0,0 +1,274 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+type Store interface {
+	AddRun(ctx context.Context, run store.AuditRun) error
+	// GetLatestRun returns the most recent run of the scope in the workspace, nil when there is none
+	GetLatestRun(ctx context.Context, workspace, scope string) (*store.AuditRun, error)
+	ListRuns(ctx context.Context, filter store.AuditRunFilter) ([]store.AuditRun, error)
+}
+
+type auditStore struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) (Store, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &auditStore{db: db}, nil
+}
+
+const selectRuns = `
+	SELECT
+		id, COALESCE(previous_run_id, ''), workspace, resource_type, scope, period_start, period_end, created_at,
+		CAST(summary AS VARCHAR), CAST(settings AS VARCHAR), CAST(rules AS VARCHAR)
+	FROM
+		audit_runs`
+
+func (a *auditStore) AddRun(ctx context.Context, run store.AuditRun) error {
+	summary, err := json.Marshal(run.Summary)
+	if err != nil {
+		return fmt.Errorf("marshal summary: %w", err)
+	}
+	settings, err := json.Marshal(run.Settings)
+	if err != nil {
+		return fmt.Errorf("marshal settings: %w", err)
+	}
+	rules, err := json.Marshal(run.Rules)
+	if err != nil {
+		return fmt.Errorf("marshal rules: %w", err)
+	}
+
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_runs (
+			id, previous_run_id, workspace, resource_type, scope, period_start, period_end, created_at,
+			summary, settings, rules
+		) VALUES (?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.PreviousRunID, run.Workspace, run.ResourceType, run.Scope, run.PeriodStart, run.PeriodEnd,
+		run.CreatedAt, string(summary), string(settings), string(rules),
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit run: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO audit_findings (
+			run_id, finding_id, status, issue, severity, description, recommendation,
+			platform, service, resource_name, resource_description, metadata,
+			savings_amount, savings_currency, savings_confidence, rule
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, finding := range run.Findings {
+		metadata, err := json.Marshal(finding.Metadata)
+		if err != nil {
+			return fmt.Errorf("marshal metadata: %w", err)
+		}
+		_, err = stmt.ExecContext(ctx,
+			run.ID,
+			finding.ID,
+			finding.Status,
+			finding.Issue,
+			finding.Severity,
+			finding.Description,
+			finding.Recommendation,
+			finding.Platform,
+			finding.Service,
+			finding.ResourceName,
+			finding.ResourceDescription,
+			string(metadata),
+			finding.SavingsAmount,
+			finding.SavingsCurrency,
+			finding.SavingsConfidence,
+			finding.Rule,
+		)
+		if err != nil {
+			return fmt.Errorf("insert audit finding: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (a *auditStore) GetLatestRun(ctx context.Context, workspace, scope string) (*store.AuditRun, error) {
+	row := a.db.QueryRowContext(ctx,
+		selectRuns+` WHERE workspace = ? AND scope = ? ORDER BY created_at DESC LIMIT 1`,
+		workspace, scope,
+	)
+	run, err := scanRun(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query latest audit run: %w", err)
+	}
+
+	findings, err := a.listFindings(ctx, []string{run.ID})
+	if err != nil {
+		return nil, err
+	}
+	run.Findings = findings[run.ID]
+	return &run, nil
+}
+
+func (a *auditStore) ListRuns(ctx context.Context, filter store.AuditRunFilter) ([]store.AuditRun, error) {
+	logger := zerolog.Ctx(ctx)
+
+	var conditions []string
+	var args []any
+	if filter.Workspace != "" {
+		conditions = append(conditions, "workspace = ?")
+		args = append(args, filter.Workspace)
+	}
+	if filter.ResourceType != "" {
+		conditions = append(conditions, "list_contains(string_split(resource_type, ','), ?)")
+		args = append(args, filter.ResourceType)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+
+	query := selectRuns
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit runs: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close audit runs query rows")
+		}
+	}(rows)
+
+	runs := make([]store.AuditRun, 0)
+	var ids []string
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit run: %w", err)
+		}
+		runs = append(runs, run)
+		ids = append(ids, run.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	findings, err := a.listFindings(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		runs[i].Findings = findings[runs[i].ID]
+	}
+	return runs, nil
+}
+
+// listFindings returns the findings of the runs keyed by run id
+func (a *auditStore) listFindings(ctx context.Context, runIDs []string) (map[string][]store.AuditFinding, error) {
+	logger := zerolog.Ctx(ctx)
+	findings := make(map[string][]store.AuditFinding)
+	if len(runIDs) == 0 {
+		return findings, nil
+	}
+
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT
+			run_id, finding_id, status, issue, severity, description, recommendation,
+			platform, service, resource_name, resource_description, CAST(metadata AS VARCHAR),
+			COALESCE(savings_amount, 0), COALESCE(savings_currency, ''), COALESCE(savings_confidence, ''),
+			COALESCE(rule, '')
+		FROM
+			audit_findings
+		WHERE
+			run_id = ANY ($1)
+		ORDER BY
+			run_id, severity DESC, finding_id`, runIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query audit findings: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close audit findings query rows")
+		}
+	}(rows)
+
+	for rows.Next() {
+		var (
+			runID       string
+			f           store.AuditFinding
+			metadataRaw []byte
+		)
+		if err := rows.Scan(&runID, &f.ID, &f.Status, &f.Issue, &f.Severity, &f.Description, &f.Recommendation,
+			&f.Platform, &f.Service, &f.ResourceName, &f.ResourceDescription, &metadataRaw,
+			&f.SavingsAmount, &f.SavingsCurrency, &f.SavingsConfidence, &f.Rule); err != nil {
+			return nil, fmt.Errorf("scan audit finding: %w", err)
+		}
+		if len(metadataRaw) > 0 {
+			_ = json.Unmarshal(metadataRaw, &f.Metadata)
+		}
+		findings[runID] = append(findings[runID], f)
+	}
+	return findings, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRun(row rowScanner) (store.AuditRun, error) {
+	var (
+		run                               store.AuditRun
+		summaryRaw, settingsRaw, rulesRaw []byte
+	)
+	err := row.Scan(&run.ID, &run.PreviousRunID, &run.Workspace, &run.ResourceType, &run.Scope,
+		&run.PeriodStart, &run.PeriodEnd, &run.CreatedAt, &summaryRaw, &settingsRaw, &rulesRaw)
+	if err != nil {
+		return store.AuditRun{}, err
+	}
+	if len(summaryRaw) > 0 {
+		_ = json.Unmarshal(summaryRaw, &run.Summary)
+	}
+	if len(settingsRaw) > 0 {
+		_ = json.Unmarshal(settingsRaw, &run.Settings)
+	}
+	if len(rulesRaw) > 0 {
+		_ = json.Unmarshal(rulesRaw, &run.Rules)
+	}
+	return run, nil
+}
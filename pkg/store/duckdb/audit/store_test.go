@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStore(t *testing.T) Store {
+	db, err := duckdb.NewDB(duckdb.Settings{DbPath: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	s, err := NewStore(db)
+	require.NoError(t, err)
+	return s
+}
+
+func auditRun(id, previousID, workspace, scope string, createdAt time.Time, findingIDs ...string) store.AuditRun {
+	run := store.AuditRun{
+		ID:            id,
+		PreviousRunID: previousID,
+		Workspace:     workspace,
+		ResourceType:  "cluster,job",
+		Scope:         scope,
+		PeriodStart:   createdAt.AddDate(0, 0, -7),
+		PeriodEnd:     createdAt,
+		CreatedAt:     createdAt,
+		Summary:       map[string]any{"total_findings": len(findingIDs)},
+		Settings:      map[string]any{"cluster": map[string]any{"low_cpu_percent": 20.0}},
+		Rules:         []store.AuditRuleResult{{Rule: "cluster_runtime", ResourceType: "cluster", Findings: len(findingIDs)}},
+	}
+	for _, findingID := range findingIDs {
+		run.Findings = append(run.Findings, store.AuditFinding{
+			ID:           findingID,
+			Status:       "new",
+			Issue:        "long_runtime",
+			Severity:     1,
+			Platform:     "Databricks",
+			Service:      "cluster",
+			ResourceName: findingID,
+			Metadata:     map[string]string{"cluster_name": "etl"},
+
+			SavingsAmount:     12.5,
+			SavingsCurrency:   "USD",
+			SavingsConfidence: "low",
+			Rule:              "cluster_runtime",
+		})
+	}
+	return run
+}
+
+func TestNewStore(t *testing.T) {
+	s, err := NewStore(nil)
+	assert.Error(t, err)
+	assert.Nil(t, s)
+}
+
+func TestStore_Runs(t *testing.T) {
+	ctx := context.Background()
+	s := setupStore(t)
+	now := time.Date(2025, 7, 8, 12, 0, 0, 0, time.UTC)
+
+	latest, err := s.GetLatestRun(ctx, "ws-1", "cluster")
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	require.NoError(t, s.AddRun(ctx, auditRun("run-1", "", "ws-1", "cluster", now.Add(-time.Hour), "c1", "c2")))
+	require.NoError(t, s.AddRun(ctx, auditRun("run-2", "run-1", "ws-1", "cluster", now, "c2")))
+	require.NoError(t, s.AddRun(ctx, auditRun("run-3", "", "ws-2", "cluster", now)))
+
+	t.Run("latest run of the scope", func(t *testing.T) {
+		latest, err := s.GetLatestRun(ctx, "ws-1", "cluster")
+		require.NoError(t, err)
+		require.NotNil(t, latest)
+		assert.Equal(t, "run-2", latest.ID)
+		assert.Equal(t, "run-1", latest.PreviousRunID)
+		assert.Equal(t, now, latest.CreatedAt)
+		assert.Equal(t, 1.0, latest.Summary["total_findings"])
+		assert.Equal(t, map[string]any{"cluster": map[string]any{"low_cpu_percent": 20.0}}, latest.Settings)
+		assert.Equal(t, []store.AuditRuleResult{{Rule: "cluster_runtime", ResourceType: "cluster", Findings: 1}}, latest.Rules)
+		require.Len(t, latest.Findings, 1)
+		assert.Equal(t, "c2", latest.Findings[0].ID)
+		assert.Equal(t, map[string]string{"cluster_name": "etl"}, latest.Findings[0].Metadata)
+		assert.Equal(t, 12.5, latest.Findings[0].SavingsAmount)
+		assert.Equal(t, "USD", latest.Findings[0].SavingsCurrency)
+		assert.Equal(t, "low", latest.Findings[0].SavingsConfidence)
+		assert.Equal(t, "cluster_runtime", latest.Findings[0].Rule)
+
+		other, err := s.GetLatestRun(ctx, "ws-1", "job")
+		require.NoError(t, err)
+		assert.Nil(t, other)
+	})
+
+	t.Run("list runs", func(t *testing.T) {
+		runs, err := s.ListRuns(ctx, store.AuditRunFilter{})
+		require.NoError(t, err)
+		assert.Len(t, runs, 3)
+
+		runs, err = s.ListRuns(ctx, store.AuditRunFilter{Workspace: "ws-1", ResourceType: "job"})
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+		assert.Equal(t, "run-2", runs[0].ID)
+		assert.Len(t, runs[1].Findings, 2)
+
+		from := now.Add(-time.Minute)
+		runs, err = s.ListRuns(ctx, store.AuditRunFilter{Workspace: "ws-1", From: &from})
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		assert.Equal(t, "run-2", runs[0].ID)
+
+		runs, err = s.ListRuns(ctx, store.AuditRunFilter{ResourceType: "warehouse"})
+		require.NoError(t, err)
+		assert.Empty(t, runs)
+
+		runs, err = s.ListRuns(ctx, store.AuditRunFilter{Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, runs, 1)
+	})
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+type SuppressionStore interface {
+	AddSuppression(ctx context.Context, suppression store.AuditSuppression) error
+	// ListSuppressions returns the suppressions of the workspace and those of every workspace,
+	// all suppressions when workspace is empty
+	ListSuppressions(ctx context.Context, workspace string) ([]store.AuditSuppression, error)
+	DeleteSuppression(ctx context.Context, id string) error
+}
+
+type suppressionStore struct {
+	db *sql.DB
+}
+
+func NewSuppressionStore(db *sql.DB) (SuppressionStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &suppressionStore{db: db}, nil
+}
+
+func (s *suppressionStore) AddSuppression(ctx context.Context, suppression store.AuditSuppression) error {
+	var expiresAt sql.NullTime
+	if suppression.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *suppression.ExpiresAt, Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_suppressions (
+			id, workspace, finding_id, issue, resource_pattern, action, reason, owner, created_at, expires_at
+		) VALUES (?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, ?, ?)`,
+		suppression.ID,
+		suppression.Workspace,
+		suppression.FindingID,
+		suppression.Issue,
+		suppression.ResourcePattern,
+		suppression.Action,
+		suppression.Reason,
+		suppression.Owner,
+		suppression.CreatedAt,
+		expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit suppression: %w", err)
+	}
+	return nil
+}
+
+func (s *suppressionStore) ListSuppressions(ctx context.Context, workspace string) ([]store.AuditSuppression, error) {
+	logger := zerolog.Ctx(ctx)
+	query := `
+		SELECT
+			id, COALESCE(workspace, ''), COALESCE(finding_id, ''), COALESCE(issue, ''), COALESCE(resource_pattern, ''),
+			action, reason, owner, created_at, expires_at
+		FROM
+			audit_suppressions`
+
+	var err error
+	var rows *sql.Rows
+	if workspace != "" {
+		rows, err = s.db.QueryContext(ctx, query+` WHERE workspace IS NULL OR workspace = ? ORDER BY created_at`, workspace)
+	} else {
+		rows, err = s.db.QueryContext(ctx, query+` ORDER BY created_at`)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query audit suppressions: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close audit suppressions query rows")
+		}
+	}(rows)
+
+	suppressions := make([]store.AuditSuppression, 0)
+	for rows.Next() {
+		var (
+			sup       store.AuditSuppression
+			expiresAt sql.NullTime
+		)
+		err := rows.Scan(&sup.ID, &sup.Workspace, &sup.FindingID, &sup.Issue, &sup.ResourcePattern,
+			&sup.Action, &sup.Reason, &sup.Owner, &sup.CreatedAt, &expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit suppression: %w", err)
+		}
+		if expiresAt.Valid {
+			sup.ExpiresAt = &expiresAt.Time
+		}
+		suppressions = append(suppressions, sup)
+	}
+	return suppressions, rows.Err()
+}
+
+func (s *suppressionStore) DeleteSuppression(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM audit_suppressions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete audit suppression: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("audit suppression %s: %w", id, sql.ErrNoRows)
+	}
+	return nil
+}
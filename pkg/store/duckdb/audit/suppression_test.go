@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuppressionStore(t *testing.T) {
+	ctx := context.Background()
+	db, err := duckdb.NewDB(duckdb.Settings{DbPath: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	s, err := NewSuppressionStore(db)
+	require.NoError(t, err)
+
+	createdAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.AddDate(0, 1, 0)
+	require.NoError(t, s.AddSuppression(ctx, store.AuditSuppression{
+		ID: "s1", FindingID: "wh-1_auto_stop_disabled", Action: "acknowledge",
+		Reason: "always on for dashboards", Owner: "bi-team", CreatedAt: createdAt,
+	}))
+	require.NoError(t, s.AddSuppression(ctx, store.AuditSuppression{
+		ID: "s2", Workspace: "ws-1", Issue: "long_runtime", ResourcePattern: "etl-*", Action: "suppress",
+		Reason: "streaming clusters", Owner: "data-eng", CreatedAt: createdAt.Add(time.Hour), ExpiresAt: &expiresAt,
+	}))
+	require.NoError(t, s.AddSuppression(ctx, store.AuditSuppression{
+		ID: "s3", Workspace: "ws-2", Issue: "gpu_cost", Action: "suppress",
+		Reason: "training", Owner: "ml-team", CreatedAt: createdAt.Add(2 * time.Hour),
+	}))
+
+	all, err := s.ListSuppressions(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	suppressions, err := s.ListSuppressions(ctx, "ws-1")
+	require.NoError(t, err)
+	require.Len(t, suppressions, 2)
+	assert.Equal(t, "s1", suppressions[0].ID)
+	assert.Empty(t, suppressions[0].Workspace)
+	assert.Nil(t, suppressions[0].ExpiresAt)
+	assert.Equal(t, "etl-*", suppressions[1].ResourcePattern)
+	require.NotNil(t, suppressions[1].ExpiresAt)
+	assert.Equal(t, expiresAt, *suppressions[1].ExpiresAt)
+
+	require.NoError(t, s.DeleteSuppression(ctx, "s2"))
+	assert.ErrorIs(t, s.DeleteSuppression(ctx, "s2"), sql.ErrNoRows)
+
+	suppressions, err = s.ListSuppressions(ctx, "ws-1")
+	require.NoError(t, err)
+	assert.Len(t, suppressions, 1)
+}
@@ -0,0 +1,143 @@
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/rs/zerolog"
+)
+
+type Store interface {
+	AddSubscription(ctx context.Context, subscription store.ReportSubscription) error
+	GetSubscription(ctx context.Context, id string) (*store.ReportSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]store.ReportSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	// MarkSent records when the report of the subscription was last sent
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+}
+
+type subscriptionStore struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) (Store, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	return &subscriptionStore{db: db}, nil
+}
+
+const selectSubscriptions = `
+	SELECT
+		id, recipient, COALESCE(workspaces, ''), cadence, format, created_at, last_sent_at
+	FROM
+		report_subscriptions`
+
+func (s *subscriptionStore) AddSubscription(ctx context.Context, subscription store.ReportSubscription) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO report_subscriptions (id, recipient, workspaces, cadence, format, created_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?)`,
+		subscription.ID,
+		subscription.Recipient,
+		strings.Join(subscription.Workspaces, ","),
+		subscription.Cadence,
+		subscription.Format,
+		subscription.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert report subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *subscriptionStore) GetSubscription(ctx context.Context, id string) (*store.ReportSubscription, error) {
+	subscription, err := scanSubscription(s.db.QueryRowContext(ctx, selectSubscriptions+` WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query report subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+func (s *subscriptionStore) ListSubscriptions(ctx context.Context) ([]store.ReportSubscription, error) {
+	logger := zerolog.Ctx(ctx)
+
+	rows, err := s.db.QueryContext(ctx, selectSubscriptions+` ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("query report subscriptions: %w", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			logger.Warn().Err(err).Msg("failed to close report subscriptions query rows")
+		}
+	}(rows)
+
+	subscriptions := make([]store.ReportSubscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan report subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (s *subscriptionStore) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM report_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete report subscription: %w", err)
+	}
+	return checkAffected(result, id)
+}
+
+func (s *subscriptionStore) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE report_subscriptions SET last_sent_at = ? WHERE id = ?`, sentAt, id)
+	if err != nil {
+		return fmt.Errorf("update report subscription: %w", err)
+	}
+	return checkAffected(result, id)
+}
+
+func checkAffected(result sql.Result, id string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("report subscription %s: %w", id, sql.ErrNoRows)
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row rowScanner) (store.ReportSubscription, error) {
+	var (
+		subscription store.ReportSubscription
+		workspaces   string
+		lastSentAt   sql.NullTime
+	)
+	err := row.Scan(&subscription.ID, &subscription.Recipient, &workspaces, &subscription.Cadence,
+		&subscription.Format, &subscription.CreatedAt, &lastSentAt)
+	if err != nil {
+		return store.ReportSubscription{}, err
+	}
+	if workspaces != "" {
+		subscription.Workspaces = strings.Split(workspaces, ",")
+	}
+	if lastSentAt.Valid {
+		subscription.LastSentAt = &lastSentAt.Time
+	}
+	return subscription, nil
+}
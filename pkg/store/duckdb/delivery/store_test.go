@@ -0,0 +1,62 @@
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/de-tools/data-atlas/pkg/models/store"
+	"github.com/de-tools/data-atlas/pkg/store/duckdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	db, err := duckdb.NewDB(duckdb.Settings{DbPath: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	s, err := NewStore(db)
+	require.NoError(t, err)
+
+	createdAt := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.AddSubscription(ctx, store.ReportSubscription{
+		ID: "s1", Recipient: "finance@example.com", Cadence: "monthly", Format: "html", CreatedAt: createdAt,
+	}))
+	require.NoError(t, s.AddSubscription(ctx, store.ReportSubscription{
+		ID: "s2", Recipient: "bi-lead@example.com", Workspaces: []string{"bi", "bi-dev"}, Cadence: "weekly",
+		Format: "markdown", CreatedAt: createdAt.Add(time.Hour),
+	}))
+
+	subscriptions, err := s.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 2)
+	assert.Equal(t, "s1", subscriptions[0].ID)
+	assert.Empty(t, subscriptions[0].Workspaces)
+	assert.Nil(t, subscriptions[0].LastSentAt)
+	assert.Equal(t, []string{"bi", "bi-dev"}, subscriptions[1].Workspaces)
+
+	sentAt := createdAt.AddDate(0, 0, 6)
+	require.NoError(t, s.MarkSent(ctx, "s2", sentAt))
+	subscription, err := s.GetSubscription(ctx, "s2")
+	require.NoError(t, err)
+	require.NotNil(t, subscription)
+	require.NotNil(t, subscription.LastSentAt)
+	assert.Equal(t, sentAt, *subscription.LastSentAt)
+
+	missing, err := s.GetSubscription(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+	assert.ErrorIs(t, s.MarkSent(ctx, "missing", sentAt), sql.ErrNoRows)
+
+	require.NoError(t, s.DeleteSubscription(ctx, "s1"))
+	assert.ErrorIs(t, s.DeleteSubscription(ctx, "s1"), sql.ErrNoRows)
+
+	subscriptions, err = s.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, subscriptions, 1)
+}
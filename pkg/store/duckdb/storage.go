@@ -24,6 +24,7 @@ const UsageTableSchema = `
 		resource_id VARCHAR,
 		resource_type VARCHAR,
 		metadata JSON,
+		tags JSON,
 		quantity DOUBLE,
 		unit VARCHAR,
 		sku VARCHAR,
@@ -35,9 +36,105 @@ const UsageTableSchema = `
 	);
 `
 
+// UsageTableTagsMigration adds the tags column to usage tables created before tags were synced
+const UsageTableTagsMigration = `
+	ALTER TABLE usage_records ADD COLUMN IF NOT EXISTS tags JSON;
+`
+
+// AuditRunsSchema stores every audit report, scope identifies the resource types and rules audited
+const AuditRunsSchema = `
+	CREATE TABLE IF NOT EXISTS audit_runs (
+		id VARCHAR NOT NULL,
+		previous_run_id VARCHAR,
+		workspace VARCHAR NOT NULL,
+		resource_type VARCHAR NOT NULL,
+		scope VARCHAR NOT NULL,
+		period_start TIMESTAMP,
+		period_end TIMESTAMP,
+		created_at TIMESTAMP NOT NULL,
+		summary JSON,
+		settings JSON,
+		rules JSON,
+		PRIMARY KEY (id)
+	);
+`
+
+// AuditFindingsSchema stores the findings of audit runs with their status against the previous run
+const AuditFindingsSchema = `
+	CREATE TABLE IF NOT EXISTS audit_findings (
+		run_id VARCHAR NOT NULL,
+		finding_id VARCHAR NOT NULL,
+		status VARCHAR NOT NULL,
+		issue VARCHAR,
+		severity INTEGER,
+		description VARCHAR,
+		recommendation VARCHAR,
+		platform VARCHAR,
+		service VARCHAR,
+		resource_name VARCHAR,
+		resource_description VARCHAR,
+		metadata JSON,
+		savings_amount DOUBLE,
+		savings_currency VARCHAR,
+		savings_confidence VARCHAR,
+		rule VARCHAR,
+		PRIMARY KEY (run_id, finding_id)
+	);
+`
+
+// AuditFindingsSavingsMigration adds the savings estimate columns to findings tables created before savings were estimated
+const AuditFindingsSavingsMigration = `
+	ALTER TABLE audit_findings ADD COLUMN IF NOT EXISTS savings_amount DOUBLE;
+	ALTER TABLE audit_findings ADD COLUMN IF NOT EXISTS savings_currency VARCHAR;
+	ALTER TABLE audit_findings ADD COLUMN IF NOT EXISTS savings_confidence VARCHAR;
+`
+
+// AuditFindingsRuleMigration adds the rule column to findings tables created before findings recorded their rule
+const AuditFindingsRuleMigration = `
+	ALTER TABLE audit_findings ADD COLUMN IF NOT EXISTS rule VARCHAR;
+`
+
+// AuditSuppressionsSchema stores acknowledged and suppressed audit findings
+const AuditSuppressionsSchema = `
+	CREATE TABLE IF NOT EXISTS audit_suppressions (
+		id VARCHAR NOT NULL,
+		workspace VARCHAR,
+		finding_id VARCHAR,
+		issue VARCHAR,
+		resource_pattern VARCHAR,
+		action VARCHAR NOT NULL,
+		reason VARCHAR NOT NULL,
+		owner VARCHAR NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		expires_at TIMESTAMP,
+		PRIMARY KEY (id)
+	);
+`
+
+// ReportSubscriptionsSchema stores the recipients of scheduled cost reports, workspaces are comma separated
+const ReportSubscriptionsSchema = `
+	CREATE TABLE IF NOT EXISTS report_subscriptions (
+		id VARCHAR NOT NULL,
+		recipient VARCHAR NOT NULL,
+		workspaces VARCHAR,
+		cadence VARCHAR NOT NULL,
+		format VARCHAR NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		last_sent_at TIMESTAMP,
+		PRIMARY KEY (id)
+	);
+`
+
 var bootQueries = []string{
 	WorkflowState,
 	UsageTableSchema,
+	UsageTableTagsMigration,
+	AuditRunsSchema,
+	AuditFindingsSchema,
+	AuditFindingsSavingsMigration,
+	AuditFindingsRuleMigration,
+	AuditSuppressionsSchema,
+	ReportSubscriptionsSchema,
 }
 
 type Settings struct {
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/de-tools/data-atlas/pkg/store/duckdb"
@@ -17,6 +18,53 @@ type Store interface {
 	GetResourcesUsage(ctx context.Context, resources []string, startTime, endTime time.Time) ([]store.UsageRecord, error)
 	GetUsage(ctx context.Context, startTime, endTime time.Time) ([]store.UsageRecord, error)
 	GetUsageStats(ctx context.Context, startTime *time.Time) (*store.UsageStats, error)
+	GetDailyCost(ctx context.Context, dimension string, startTime, endTime time.Time) ([]store.DailyUsageAggregate, error)
+	CompareCost(
+		ctx context.Context,
+		dimension string,
+		startTime, endTime time.Time,
+		baselineStart, baselineEnd time.Time,
+		filters []store.DimensionFilter,
+	) ([]store.CostComparison, error)
+	ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error)
+}
+
+// dimensionColumns maps the supported grouping dimensions to usage_records columns
+var dimensionColumns = map[string]string{
+	"resource_id":   "resource_id",
+	"resource_type": "resource_type",
+	"sku":           "sku",
+	"usage_type":    "json_extract_string(metadata, '$.usage_type')",
+}
+
+// tagDimensionPrefix prefixes the tag key of tag dimensions, e.g. tag:team
+const tagDimensionPrefix = "tag:"
+
+// dimensionExpression returns the SQL expression of a grouping dimension
+func dimensionExpression(dimension string) (string, error) {
+	if column, ok := dimensionColumns[dimension]; ok {
+		return column, nil
+	}
+	key, ok := strings.CutPrefix(dimension, tagDimensionPrefix)
+	if !ok || key == "" || strings.ContainsAny(key, `"\`) {
+		return "", fmt.Errorf("unsupported cost dimension: %s", dimension)
+	}
+	return fmt.Sprintf(`json_extract_string(tags, '$."%s"')`, strings.ReplaceAll(key, "'", "''")), nil
+}
+
+// filterConditions returns the SQL conditions and arguments restricting usage records to the filters
+func filterConditions(filters []store.DimensionFilter) (string, []interface{}, error) {
+	var conditions strings.Builder
+	args := make([]interface{}, 0, len(filters))
+	for _, f := range filters {
+		expr, err := dimensionExpression(f.Dimension)
+		if err != nil {
+			return "", nil, err
+		}
+		fmt.Fprintf(&conditions, " AND COALESCE(%s, '') = ?", expr)
+		args = append(args, f.Value)
+	}
+	return conditions.String(), args, nil
 }
 
 type usageStore struct {
@@ -55,10 +103,10 @@ func (u *usageStore) Add(ctx context.Context, workspace string, records []store.
 	tx := duckdb.GetTransaction(ctx)
 	query := `
 		INSERT INTO usage_records (
-			id, workspace, resource_id, resource_type, metadata, quantity, unit,
+			id, workspace, resource_id, resource_type, metadata, tags, quantity, unit,
 			sku, rate, currency, start_time, end_time
 		) VALUES (
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 		)`
 
 	var stmt *sql.Stmt
@@ -79,6 +127,10 @@ func (u *usageStore) Add(ctx context.Context, workspace string, records []store.
 		if err != nil {
 			return fmt.Errorf("marshal metadata: %w", err)
 		}
+		tags, err := json.Marshal(record.Tags)
+		if err != nil {
+			return fmt.Errorf("marshal tags: %w", err)
+		}
 
 		_, err = stmt.ExecContext(ctx,
 			record.ID,
@@ -86,6 +138,7 @@ func (u *usageStore) Add(ctx context.Context, workspace string, records []store.
 			record.ResourceID,
 			record.ResourceType,
 			metadata,
+			tags,
 			record.Quantity,
 			record.Unit,
 			record.SKU,
@@ -115,7 +168,7 @@ func (u *usageStore) GetUsage(ctx context.Context, startTime, endTime time.Time)
 		return nil, err
 	}
 	query := `
-		SELECT id, resource_id, resource_type, CAST(metadata AS VARCHAR) AS metadata, quantity, unit, sku, rate, currency, start_time, end_time
+		SELECT id, resource_id, resource_type, CAST(metadata AS VARCHAR) AS metadata, CAST(tags AS VARCHAR) AS tags, quantity, unit, sku, rate, currency, start_time, end_time
 		FROM usage_records
 		WHERE workspace = ? AND start_time >= ? AND start_time < ?
 		ORDER BY start_time DESC
@@ -146,7 +199,7 @@ func (u *usageStore) GetResourcesUsage(ctx context.Context, resources []string,
 	args = append([]interface{}{u.workspace, startTime, endTime}, toInterfaceSlice(resources)...)
 
 	query := fmt.Sprintf(`
-		SELECT id, resource_id, resource_type, CAST(metadata AS VARCHAR) AS metadata, quantity, unit, sku, rate, currency, start_time, end_time
+		SELECT id, resource_id, resource_type, CAST(metadata AS VARCHAR) AS metadata, CAST(tags AS VARCHAR) AS tags, quantity, unit, sku, rate, currency, start_time, end_time
 		FROM usage_records
 		WHERE workspace = ? AND start_time >= ? AND start_time < ? AND resource_type IN (%s)
 		ORDER BY start_time DESC
@@ -183,27 +236,193 @@ func (u *usageStore) GetUsageStats(ctx context.Context, startTime *time.Time) (*
 	return &store.UsageStats{RecordsCount: total, FirstRecordTime: first}, nil
 }
 
+// GetDailyCost returns cost aggregated per day and per value of the given dimension
+func (u *usageStore) GetDailyCost(
+	ctx context.Context,
+	dimension string,
+	startTime, endTime time.Time,
+) ([]store.DailyUsageAggregate, error) {
+	if err := u.ensureWorkspace(); err != nil {
+		return nil, err
+	}
+	column, err := dimensionExpression(dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			CAST(start_time AS DATE) AS day,
+			COALESCE(%s, '') AS dimension_value,
+			COALESCE(ANY_VALUE(resource_type), '') AS resource_type,
+			SUM(quantity) AS total_usage,
+			SUM(quantity * rate) AS total_cost,
+			COALESCE(ANY_VALUE(unit), '') AS unit,
+			COALESCE(ANY_VALUE(currency), '') AS currency
+		FROM usage_records
+		WHERE workspace = ? AND start_time >= ? AND start_time < ?
+		GROUP BY day, dimension_value
+		ORDER BY day, dimension_value
+	`, column)
+
+	rows, err := u.db.QueryContext(ctx, query, u.workspace, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("query daily cost: %w", err)
+	}
+	defer rows.Close()
+
+	aggregates := make([]store.DailyUsageAggregate, 0)
+	for rows.Next() {
+		var agg store.DailyUsageAggregate
+		if err := rows.Scan(
+			&agg.Date,
+			&agg.Resource,
+			&agg.ResourceType,
+			&agg.TotalUsage,
+			&agg.TotalCost,
+			&agg.Unit,
+			&agg.Currency,
+		); err != nil {
+			return nil, fmt.Errorf("scan daily cost: %w", err)
+		}
+		aggregates = append(aggregates, agg)
+	}
+	return aggregates, rows.Err()
+}
+
+// CompareCost returns cost per value of the given dimension in the period and in the baseline period
+func (u *usageStore) CompareCost(
+	ctx context.Context,
+	dimension string,
+	startTime, endTime time.Time,
+	baselineStart, baselineEnd time.Time,
+	filters []store.DimensionFilter,
+) ([]store.CostComparison, error) {
+	if err := u.ensureWorkspace(); err != nil {
+		return nil, err
+	}
+	column, err := dimensionExpression(dimension)
+	if err != nil {
+		return nil, err
+	}
+	conditions, filterArgs, err := filterConditions(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	windowQuery := fmt.Sprintf(`
+		SELECT
+			COALESCE(%s, '') AS dimension_value,
+			ANY_VALUE(resource_type) AS resource_type,
+			SUM(quantity * rate) AS cost,
+			ANY_VALUE(currency) AS currency
+		FROM usage_records
+		WHERE workspace = ? AND start_time >= ? AND start_time < ?%s
+		GROUP BY dimension_value
+	`, column, conditions)
+
+	query := `
+		WITH period_cost AS (` + windowQuery + `), baseline_cost AS (` + windowQuery + `)
+		SELECT
+			COALESCE(p.dimension_value, b.dimension_value) AS dimension_value,
+			COALESCE(p.resource_type, b.resource_type, '') AS resource_type,
+			COALESCE(p.cost, 0) AS cost,
+			COALESCE(b.cost, 0) AS baseline_cost,
+			COALESCE(p.currency, b.currency, '') AS currency,
+			p.dimension_value IS NOT NULL AS in_period,
+			b.dimension_value IS NOT NULL AS in_baseline
+		FROM period_cost p
+		FULL OUTER JOIN baseline_cost b ON p.dimension_value = b.dimension_value
+		ORDER BY ABS(COALESCE(p.cost, 0) - COALESCE(b.cost, 0)) DESC, dimension_value
+	`
+
+	args := append([]interface{}{u.workspace, startTime, endTime}, filterArgs...)
+	args = append(args, u.workspace, baselineStart, baselineEnd)
+	args = append(args, filterArgs...)
+
+	rows, err := u.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query cost comparison: %w", err)
+	}
+	defer rows.Close()
+
+	comparisons := make([]store.CostComparison, 0)
+	for rows.Next() {
+		var c store.CostComparison
+		if err := rows.Scan(
+			&c.Resource,
+			&c.ResourceType,
+			&c.Cost,
+			&c.BaselineCost,
+			&c.Currency,
+			&c.InPeriod,
+			&c.InBaseline,
+		); err != nil {
+			return nil, fmt.Errorf("scan cost comparison: %w", err)
+		}
+		comparisons = append(comparisons, c)
+	}
+	return comparisons, rows.Err()
+}
+
+// ListTagKeys returns the custom tag keys of the usage records in the period, by the cost of the tagged usage
+func (u *usageStore) ListTagKeys(ctx context.Context, startTime, endTime time.Time) ([]string, error) {
+	if err := u.ensureWorkspace(); err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT tag_key
+		FROM (
+			SELECT UNNEST(json_keys(tags)) AS tag_key, quantity * rate AS cost
+			FROM usage_records
+			WHERE workspace = ? AND start_time >= ? AND start_time < ? AND json_type(tags) = 'OBJECT'
+		)
+		GROUP BY tag_key
+		ORDER BY SUM(cost) DESC, tag_key
+	`
+	rows, err := u.db.QueryContext(ctx, query, u.workspace, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("query tag keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan tag key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
 func scanUsageRows(rows *sql.Rows) ([]store.UsageRecord, error) {
 	records := make([]store.UsageRecord, 0)
 	for rows.Next() {
 		var (
 			id, resourceID, resourceType, unit, sku, currency string
-			metadataRaw                                       []byte
+			metadataRaw, tagsRaw                              []byte
 			qty, rate                                         float64
 			start, end                                        time.Time
 		)
-		if err := rows.Scan(&id, &resourceID, &resourceType, &metadataRaw, &qty, &unit, &sku, &rate, &currency, &start, &end); err != nil {
+		if err := rows.Scan(&id, &resourceID, &resourceType, &metadataRaw, &tagsRaw, &qty, &unit, &sku, &rate, &currency, &start, &end); err != nil {
 			return nil, err
 		}
 		md := map[string]string{}
 		if len(metadataRaw) > 0 {
 			_ = json.Unmarshal(metadataRaw, &md)
 		}
+		var tags map[string]string
+		if len(tagsRaw) > 0 {
+			_ = json.Unmarshal(tagsRaw, &tags)
+		}
 		records = append(records, store.UsageRecord{
 			ID:           id,
 			ResourceID:   resourceID,
 			ResourceType: resourceType,
 			Metadata:     md,
+			Tags:         tags,
 			Quantity:     qty,
 			Unit:         unit,
 			SKU:          sku,
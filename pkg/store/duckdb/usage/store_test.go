@@ -110,3 +110,173 @@ func TestUsageStore_Add(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestUsageStore_GetDailyCost(t *testing.T) {
+	f := setupFixture(t)
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	records := []store.UsageRecord{
+		{
+			ID: "r1", ResourceID: "wh-1", ResourceType: "warehouse", Quantity: 2, Rate: 0.5, SKU: "SQL",
+			Unit: "DBU", Currency: "USD", StartTime: day.Add(time.Hour), EndTime: day.Add(2 * time.Hour),
+		},
+		{
+			ID: "r2", ResourceID: "wh-1", ResourceType: "warehouse", Quantity: 4, Rate: 0.5, SKU: "SQL",
+			Unit: "DBU", Currency: "USD", StartTime: day.Add(5 * time.Hour), EndTime: day.Add(6 * time.Hour),
+		},
+		{
+			ID: "r3", ResourceID: "job-1", ResourceType: "job", Quantity: 10, Rate: 0.1, SKU: "JOBS",
+			Unit: "DBU", Currency: "USD", StartTime: day.Add(25 * time.Hour), EndTime: day.Add(26 * time.Hour),
+		},
+	}
+	require.NoError(t, f.store.Add(ctx, "ws", records))
+	require.NoError(t, f.store.Add(ctx, "other-ws", records))
+
+	readStore, err := NewWorkspaceStore(f.db, "ws")
+	require.NoError(t, err)
+
+	t.Run("group by resource id", func(t *testing.T) {
+		aggs, err := readStore.GetDailyCost(ctx, "resource_id", day, day.AddDate(0, 0, 2))
+		require.NoError(t, err)
+		require.Len(t, aggs, 2)
+
+		assert.Equal(t, "wh-1", aggs[0].Resource)
+		assert.Equal(t, "warehouse", aggs[0].ResourceType)
+		assert.True(t, day.Equal(aggs[0].Date))
+		assert.InDelta(t, 6.0, aggs[0].TotalUsage, 0.0001)
+		assert.InDelta(t, 3.0, aggs[0].TotalCost, 0.0001)
+
+		assert.Equal(t, "job-1", aggs[1].Resource)
+		assert.True(t, day.AddDate(0, 0, 1).Equal(aggs[1].Date))
+		assert.InDelta(t, 1.0, aggs[1].TotalCost, 0.0001)
+	})
+
+	t.Run("group by sku", func(t *testing.T) {
+		aggs, err := readStore.GetDailyCost(ctx, "sku", day, day.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.Len(t, aggs, 1)
+		assert.Equal(t, "SQL", aggs[0].Resource)
+	})
+
+	t.Run("unsupported dimension", func(t *testing.T) {
+		_, err := readStore.GetDailyCost(ctx, "resource_id; DROP TABLE usage_records", day, day.AddDate(0, 0, 1))
+		assert.Error(t, err)
+	})
+}
+
+func TestUsageStore_CompareCost(t *testing.T) {
+	f := setupFixture(t)
+	ctx := context.Background()
+	baselineStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodStart := baselineStart.AddDate(0, 0, 7)
+
+	records := []store.UsageRecord{
+		// wh-1 is present in both windows and grows from 5 to 20
+		{ID: "b1", ResourceID: "wh-1", ResourceType: "warehouse", Quantity: 10, Rate: 0.5, Currency: "USD", StartTime: baselineStart},
+		{ID: "p1", ResourceID: "wh-1", ResourceType: "warehouse", Quantity: 40, Rate: 0.5, Currency: "USD", StartTime: periodStart},
+		// job-1 disappeared, job-2 is new
+		{ID: "b2", ResourceID: "job-1", ResourceType: "job", Quantity: 3, Rate: 1, Currency: "USD", StartTime: baselineStart},
+		{ID: "p2", ResourceID: "job-2", ResourceType: "job", Quantity: 8, Rate: 1, Currency: "USD", StartTime: periodStart},
+	}
+	require.NoError(t, f.store.Add(ctx, "ws", records))
+
+	readStore, err := NewWorkspaceStore(f.db, "ws")
+	require.NoError(t, err)
+
+	comparisons, err := readStore.CompareCost(ctx, "resource_id",
+		periodStart, periodStart.AddDate(0, 0, 7), baselineStart, periodStart, nil)
+	require.NoError(t, err)
+	require.Len(t, comparisons, 3)
+
+	// ordered by absolute delta
+	assert.Equal(t, store.CostComparison{
+		Resource: "wh-1", ResourceType: "warehouse", Cost: 20, BaselineCost: 5,
+		Currency: "USD", InPeriod: true, InBaseline: true,
+	}, comparisons[0])
+	assert.Equal(t, "job-2", comparisons[1].Resource)
+	assert.True(t, comparisons[1].InPeriod)
+	assert.False(t, comparisons[1].InBaseline)
+	assert.Equal(t, "job-1", comparisons[2].Resource)
+	assert.False(t, comparisons[2].InPeriod)
+	assert.InDelta(t, 3.0, comparisons[2].BaselineCost, 0.0001)
+}
+
+func TestUsageStore_CompareCost_TagsAndFilters(t *testing.T) {
+	f := setupFixture(t)
+	ctx := context.Background()
+	baselineStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodStart := baselineStart.AddDate(0, 0, 7)
+	periodEnd := periodStart.AddDate(0, 0, 7)
+
+	records := []store.UsageRecord{
+		{
+			ID: "b1", ResourceID: "job-1", ResourceType: "job", SKU: "JOBS", Quantity: 10, Rate: 1, Currency: "USD",
+			Metadata: map[string]string{"usage_type": "COMPUTE_TIME"}, Tags: map[string]string{"team": "data"},
+			StartTime: baselineStart,
+		},
+		{
+			ID: "p1", ResourceID: "job-1", ResourceType: "job", SKU: "JOBS", Quantity: 30, Rate: 1, Currency: "USD",
+			Metadata: map[string]string{"usage_type": "COMPUTE_TIME"}, Tags: map[string]string{"team": "data"},
+			StartTime: periodStart,
+		},
+		{
+			ID: "p2", ResourceID: "job-2", ResourceType: "job", SKU: "JOBS", Quantity: 5, Rate: 1, Currency: "USD",
+			Metadata: map[string]string{"usage_type": "STORAGE_SPACE"}, Tags: map[string]string{"team": "ml", "env": "dev"},
+			StartTime: periodStart,
+		},
+		{
+			// no tags at all
+			ID: "p3", ResourceID: "wh-1", ResourceType: "warehouse", SKU: "SQL", Quantity: 2, Rate: 1, Currency: "USD",
+			StartTime: periodStart,
+		},
+	}
+	require.NoError(t, f.store.Add(ctx, "ws", records))
+
+	readStore, err := NewWorkspaceStore(f.db, "ws")
+	require.NoError(t, err)
+
+	t.Run("group by tag", func(t *testing.T) {
+		comparisons, err := readStore.CompareCost(ctx, "tag:team", periodStart, periodEnd, baselineStart, periodStart, nil)
+		require.NoError(t, err)
+		require.Len(t, comparisons, 3)
+		assert.Equal(t, "data", comparisons[0].Resource)
+		assert.InDelta(t, 20.0, comparisons[0].Cost-comparisons[0].BaselineCost, 0.0001)
+		assert.Equal(t, "ml", comparisons[1].Resource)
+		assert.Equal(t, "", comparisons[2].Resource, "untagged usage is grouped under an empty value")
+	})
+
+	t.Run("group by usage type with filters", func(t *testing.T) {
+		filters := []store.DimensionFilter{{Dimension: "resource_type", Value: "job"}, {Dimension: "tag:team", Value: "ml"}}
+		comparisons, err := readStore.CompareCost(ctx, "usage_type", periodStart, periodEnd, baselineStart, periodStart, filters)
+		require.NoError(t, err)
+		require.Len(t, comparisons, 1)
+		assert.Equal(t, "STORAGE_SPACE", comparisons[0].Resource)
+		assert.InDelta(t, 5.0, comparisons[0].Cost, 0.0001)
+	})
+
+	t.Run("unsupported dimension", func(t *testing.T) {
+		_, err := readStore.CompareCost(ctx, "workspace", periodStart, periodEnd, baselineStart, periodStart, nil)
+		assert.Error(t, err)
+		_, err = readStore.CompareCost(ctx, "sku", periodStart, periodEnd, baselineStart, periodStart,
+			[]store.DimensionFilter{{Dimension: `tag:x"') OR 1=1 --`, Value: ""}})
+		assert.Error(t, err)
+	})
+
+	t.Run("list tag keys", func(t *testing.T) {
+		keys, err := readStore.ListTagKeys(ctx, baselineStart, periodEnd)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team", "env"}, keys, "the keys of the most costly usage come first")
+	})
+
+	t.Run("tags round trip", func(t *testing.T) {
+		usage, err := readStore.GetUsage(ctx, periodStart, periodEnd)
+		require.NoError(t, err)
+		tagsByID := map[string]map[string]string{}
+		for _, u := range usage {
+			tagsByID[u.ID] = u.Tags
+		}
+		assert.Equal(t, map[string]string{"team": "ml", "env": "dev"}, tagsByID["p2"])
+		assert.Empty(t, tagsByID["p3"])
+	})
+}